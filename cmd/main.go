@@ -1,21 +1,32 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"moon/internal/config"
 	"moon/internal/database"
+	"moon/internal/domain/organization"
 	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+	"moon/internal/domain/report"
 	"moon/internal/domain/user"
 	httpHandler "moon/internal/handler/http"
+	"moon/internal/job"
 	"moon/internal/middleware"
 	"moon/internal/repository"
 	"moon/internal/usecase"
 	"moon/pkg/logger"
+	"moon/pkg/mailer"
+	"moon/pkg/storage"
+	"moon/pkg/tracing"
+	"moon/pkg/version"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -35,7 +46,25 @@ func main() {
 	}
 
 	log := logger.GetLogger()
-	log.Info("Starting Moon API", zap.String("version", cfg.App.Version))
+	log.Info("Starting Moon API", zap.String("version", version.Resolve(cfg.App.Version)), zap.String("commit", version.Commit))
+	log.Info("Effective configuration", zap.Any("config", cfg.Summary()))
+
+	// Start tracing before anything that creates spans
+	shutdownTracing := func(context.Context) error { return nil }
+	if cfg.Tracing.Enabled {
+		shutdown, err := tracing.Init(context.Background(), tracing.Options{
+			ServiceName:    cfg.App.Name,
+			ServiceVersion: version.Resolve(cfg.App.Version),
+			Endpoint:       cfg.Tracing.Endpoint,
+			Insecure:       cfg.Tracing.Insecure,
+			SampleRatio:    cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			log.Fatal("Failed to initialize tracing", zap.Error(err))
+		}
+		shutdownTracing = shutdown
+		log.Info("Tracing enabled", zap.String("endpoint", cfg.Tracing.Endpoint))
+	}
 
 	// Set Gin mode
 	gin.SetMode(cfg.App.Mode)
@@ -46,21 +75,47 @@ func main() {
 	}
 	log.Info("Connected to database successfully")
 
+	// Connect to Redis
+	if err := database.ConnectRedis(cfg); err != nil {
+		log.Warn("Redis unavailable, starting in degraded mode", zap.Error(err))
+	} else {
+		log.Info("Connected to redis successfully")
+	}
+
 	// Auto migrate
 	db := database.GetDB()
-	if err := db.AutoMigrate(&user.User{}, &post.Post{}); err != nil {
+	models := []interface{}{&organization.Organization{}, &user.User{}, &user.NotificationPreferences{}, &post.Post{}, &post.Collaborator{}, &post.Tag{}, &post.PostTag{}, &user.RefreshToken{}, &report.Report{}, &product.Category{}, &product.Product{}}
+	if err := db.AutoMigrate(models...); err != nil {
 		log.Fatal("Failed to migrate database", zap.Error(err))
 	}
 	log.Info("Database migration completed")
 
+	// Check the migrated schema is usable before serving traffic. A failure keeps the server
+	// up for debugging, but /readyz reports it so no traffic is routed here.
+	if cfg.Database.SelfTest {
+		if err := database.RunSelfTest(context.Background(), models...); err != nil {
+			log.Error("Database self-test failed, reporting unready", zap.Error(err))
+		} else {
+			log.Info("Database self-test passed", zap.Int("models", len(models)))
+		}
+	}
+
 	// Setup router
 	r := setupRouter()
 
+	// Start background workers
+	workers := job.NewManager()
+	workers.Add("scheduler", setupScheduler())
+	workers.Start(context.Background())
+
 	// Start server
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.App.Port),
+		Handler: r,
+	}
 	go func() {
-		addr := fmt.Sprintf(":%d", cfg.App.Port)
-		log.Info("Server starting", zap.String("address", addr))
-		if err := r.Run(addr); err != nil && err != http.ErrServerClosed {
+		log.Info("Server starting", zap.String("address", srv.Addr))
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
@@ -72,98 +127,300 @@ func main() {
 
 	log.Info("Shutting down server...")
 
+	// Drain in-flight requests and background workers before closing their connections
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), time.Duration(cfg.App.ShutdownTimeoutSeconds)*time.Second)
+	defer cancelShutdown()
+	if err := drain(shutdownCtx, srv, workers); err != nil {
+		log.Error("Error draining server", zap.Error(err))
+	}
+
 	// Close database connection
 	if err := database.CloseDatabase(); err != nil {
 		log.Error("Error closing database", zap.Error(err))
 	}
 
+	// Close Redis connection
+	if err := database.CloseRedis(); err != nil {
+		log.Error("Error closing redis", zap.Error(err))
+	}
+
+	// Flush buffered spans
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		log.Error("Error shutting down tracing", zap.Error(err))
+	}
+
 	log.Info("Server exited")
 }
 
+func newMailer(cfg *config.Config) mailer.Mailer {
+	if !cfg.Mail.Enabled {
+		return mailer.NewLogMailer()
+	}
+	return mailer.NewSMTPMailer(mailer.Config{
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		From:     cfg.Mail.From,
+	})
+}
+
+// drain stops accepting requests and waits for in-flight ones to finish, then stops the
+// background workers, until ctx is done
+func drain(ctx context.Context, srv *http.Server, workers *job.Manager) error {
+	var errs []error
+	if err := srv.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("server: %w", err))
+	}
+	if err := workers.Stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("background workers: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func setupScheduler() *job.Scheduler {
+	cfg := config.GetConfig()
+	db := database.GetDB()
+
+	postUseCase := usecase.NewPostUseCase(
+		repository.NewPostRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewCategoryRepository(db),
+		repository.NewTransactor(db),
+		cfg,
+	)
+
+	scheduler := job.NewScheduler(database.GetCache())
+	if cfg.Post.DraftArchival.Enabled {
+		scheduler.Register(job.NewDraftArchivalJob(postUseCase, cfg.Post.DraftArchival))
+	}
+	if cfg.Post.ScheduledPublishing.Enabled {
+		scheduler.Register(job.NewScheduledPublishJob(postUseCase, cfg.Post.ScheduledPublishing))
+	}
+	if cfg.Backup.Enabled {
+		backupUseCase := usecase.NewBackupUseCase(
+			repository.NewPostRepository(db),
+			repository.NewUserRepository(db),
+			storage.NewLocal(cfg.Backup.Dir),
+			cfg,
+		)
+		scheduler.Register(job.NewContentBackupJob(backupUseCase, cfg.Backup))
+	}
+
+	return scheduler
+}
+
+// newEngine returns the gin engine with the configured trusted proxies. Only their
+// X-Forwarded-For header is believed, so clients can't choose the IP that rate limits and
+// concurrency limits count them under.
+func newEngine(cfg *config.Config) (*gin.Engine, error) {
+	r := gin.Default()
+	if err := r.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 func setupRouter() *gin.Engine {
 	cfg := config.GetConfig()
 	db := database.GetDB()
+	redisCache := database.GetCache()
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	postRepo := repository.NewPostRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
 
 	// Initialize use cases
-	authUseCase := usecase.NewAuthUseCase(userRepo, cfg)
-	userUseCase := usecase.NewUserUseCase(userRepo)
-	postUseCase := usecase.NewPostUseCase(postRepo, userRepo)
+	authUseCase := usecase.NewAuthUseCase(userRepo, redisCache, newMailer(cfg), cfg)
+	transactor := repository.NewTransactor(db)
+	userUseCase := usecase.NewUserUseCase(userRepo, transactor, redisCache)
+	postUseCase := usecase.NewPostUseCase(postRepo, userRepo, categoryRepo, transactor, cfg)
+	dashboardUseCase := usecase.NewDashboardUseCase(userRepo, postRepo, redisCache)
+	reportUseCase := usecase.NewReportUseCase(reportRepo, postRepo, redisCache, cfg)
+	categoryUseCase := usecase.NewCategoryUseCase(categoryRepo)
+	productUseCase := usecase.NewProductUseCase(productRepo, categoryRepo, cfg)
 
 	// Initialize handlers
 	authHandler := httpHandler.NewAuthHandler(authUseCase)
 	userHandler := httpHandler.NewUserHandler(userUseCase)
 	postHandler := httpHandler.NewPostHandler(postUseCase)
+	dashboardHandler := httpHandler.NewDashboardHandler(dashboardUseCase)
+	reportHandler := httpHandler.NewReportHandler(reportUseCase)
+	categoryHandler := httpHandler.NewCategoryHandler(categoryUseCase)
+	productHandler := httpHandler.NewProductHandler(productUseCase)
+	readinessHandler := httpHandler.NewReadinessHandler(database.Ready)
 
-	r := gin.Default()
+	r, err := newEngine(cfg)
+	if err != nil {
+		logger.GetLogger().Fatal("Failed to set trusted proxies", zap.Error(err))
+	}
+	if cfg.HTTPS.Redirect {
+		r.Use(middleware.HTTPSRedirect())
+	}
+	r.Use(middleware.RequestID())
+	if cfg.ConcurrencyLimit.Enabled {
+		r.Use(middleware.ConcurrencyLimit())
+	}
+	if cfg.Tracing.Enabled {
+		r.Use(middleware.Tracing())
+	}
+	r.Use(middleware.JSONCase())
+	r.Use(middleware.Redaction())
 
 	// Health check
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "pong",
 			"status":  "ok",
+			"version": version.Resolve(cfg.App.Version),
+			"commit":  version.Commit,
 		})
 	})
 
+	// Registered outside the API group so checking the budget doesn't consume it
+	r.GET("/api/v1/rate-limit/status", middleware.RateLimitStatus(redisCache, authUseCase))
+
 	// API routes
 	api := r.Group("/api/v1")
 	{
 		// Public routes
 		api.GET("/health", func(c *gin.Context) {
+			redisStatus := "up"
+			if err := redisCache.Ping(c.Request.Context()); err != nil {
+				redisStatus = "degraded"
+			}
+
 			c.JSON(http.StatusOK, gin.H{
-				"status":  "healthy",
-				"version": "1.0.0",
+				"status":         "healthy",
+				"version":        version.Resolve(cfg.App.Version),
+				"commit":         version.Commit,
+				"uptime_seconds": int64(version.Uptime().Seconds()),
+				"redis":          redisStatus,
 			})
 		})
 
+		api.GET("/readyz", readinessHandler.Readyz)
+
+		// Everything below is rate limited per user or client IP
+		api.Use(middleware.RateLimit(redisCache, authUseCase))
+
+		// Confirmation links are opened from an email client without an organization header or
+		// token; the confirmation token alone identifies the user
+		api.GET("/auth/confirm-email", authHandler.ConfirmEmail)
+
+		// Everything below is scoped to the request's organization
+		api.Use(middleware.TenantScope(orgRepo))
+
 		// Public post routes
 		api.GET("/posts/published", postHandler.GetPublishedPosts)
-		api.GET("/posts/slug/:slug", postHandler.GetPostBySlug)
+		api.GET("/posts/slug/:slug", middleware.OptionalAuthMiddleware(authUseCase), postHandler.GetPostBySlug)
+		api.POST("/posts/batch-by-slug", middleware.OptionalAuthMiddleware(authUseCase), postHandler.GetPostsBySlugs)
+		api.GET("/posts/slug-preview", postHandler.PreviewSlug)
+		api.GET("/posts/archive", postHandler.GetArchive)
+		api.GET("/posts/resolve/:idOrSlug", middleware.OptionalAuthMiddleware(authUseCase), postHandler.ResolvePost)
+
+		// Public category routes
+		api.GET("/categories/with-post-counts", categoryHandler.GetCategoriesWithPostCounts)
+		api.GET("/categories/slug/:slug/posts", postHandler.GetPostsByCategorySlug)
+
+		// Public author routes
+		api.GET("/authors/:id/tags", postHandler.GetAuthorTags)
+
+		// Product routes; admins also see inactive products and manage the catalog
+		products := api.Group("/products")
+		{
+			products.GET("", middleware.OptionalAuthMiddleware(authUseCase), productHandler.GetProducts)
+			products.GET("/:id", middleware.OptionalAuthMiddleware(authUseCase), productHandler.GetProduct)
+			products.POST("", middleware.AuthMiddleware(authUseCase), middleware.RoleMiddleware("admin"), productHandler.CreateProduct)
+			products.PUT("/:id", middleware.AuthMiddleware(authUseCase), middleware.RoleMiddleware("admin"), productHandler.UpdateProduct)
+			products.DELETE("/:id", middleware.AuthMiddleware(authUseCase), middleware.RoleMiddleware("admin"), productHandler.DeleteProduct)
+		}
 
 		// Auth routes
 		auth := api.Group("/auth")
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
-			auth.POST("/logout", authHandler.Logout)
+			auth.POST("/logout", middleware.AuthMiddleware(authUseCase), authHandler.Logout)
 			auth.POST("/refresh", authHandler.RefreshToken)
 		}
 
 		// Protected routes
 		protected := api.Group("/")
-		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.AuthMiddleware(authUseCase))
 		{
 			// User profile routes
 			protected.GET("/profile", userHandler.GetProfile)
+			protected.PUT("/profile", userHandler.UpdateProfile)
+			protected.GET("/profile/permissions", userHandler.GetMyPermissions)
+			protected.GET("/profile/notifications", userHandler.GetNotificationPreferences)
+			protected.PUT("/profile/notifications", userHandler.UpdateNotificationPreferences)
+			protected.POST("/profile/email", authHandler.RequestEmailChange)
+			protected.PATCH("/profile/password", authHandler.ChangePassword)
+			protected.GET("/profile/stats", postHandler.GetMyStats)
 
 			// Post routes (authenticated users)
 			protected.POST("/posts", postHandler.CreatePost)
+			protected.POST("/posts/import/markdown", postHandler.ImportMarkdown)
+			protected.POST("/posts/suggest-tags", postHandler.SuggestTags)
 			protected.GET("/posts/:id", postHandler.GetPostByID)
 			protected.PUT("/posts/:id", postHandler.UpdatePost)
+			protected.PUT("/posts/by-slug/:slug", postHandler.UpsertPostBySlug)
 			protected.DELETE("/posts/:id", postHandler.DeletePost)
 			protected.GET("/posts", postHandler.GetAllPosts)
 			protected.GET("/posts/my", postHandler.GetMyPosts)
+			protected.GET("/posts/my/trashed", postHandler.GetMyTrashedPosts)
+			protected.GET("/posts/shared-with-me", postHandler.GetSharedWithMe)
+			protected.GET("/posts/changes", middleware.RoleMiddleware("admin"), postHandler.GetPostChanges)
+			protected.GET("/posts/:id/collaborators", postHandler.GetCollaborators)
+			protected.POST("/posts/:id/collaborators", postHandler.AddCollaborator)
+			protected.DELETE("/posts/:id/collaborators/:userId", postHandler.RemoveCollaborator)
+			protected.POST("/posts/:id/restore", postHandler.RestorePost)
 			protected.PATCH("/posts/:id/publish", postHandler.PublishPost)
 			protected.PATCH("/posts/:id/unpublish", postHandler.UnpublishPost)
+			protected.POST("/posts/:id/report", reportHandler.ReportPost)
 		}
 
 		// Admin routes
 		admin := api.Group("/admin")
-		admin.Use(middleware.AuthMiddleware())
+		admin.Use(middleware.AuthMiddleware(authUseCase))
 		admin.Use(middleware.RoleMiddleware("admin"))
 		{
+			// Dashboard
+			admin.GET("/dashboard", dashboardHandler.GetSummary)
+
 			// User management
 			admin.GET("/users", userHandler.GetAllUsers)
+			admin.POST("/users/names", userHandler.GetUserNames)
+			admin.GET("/users/pending", userHandler.GetPendingUsers)
+			admin.POST("/users/:id/approve", userHandler.ApproveUser)
+			admin.POST("/users/:id/reject", userHandler.RejectUser)
+			admin.POST("/users/merge", userHandler.MergeUsers)
 			admin.GET("/users/:id", userHandler.GetUserByID)
 			admin.PUT("/users/:id", userHandler.UpdateUser)
 			admin.DELETE("/users/:id", userHandler.DeleteUser)
 			admin.GET("/users/role/:role", userHandler.GetUsersByRole)
+			admin.POST("/users/:id/logout", userHandler.ForceLogout)
 
 			// Admin post management (all posts)
 			admin.GET("/posts", postHandler.GetAllPosts)
+			admin.GET("/posts/trash", postHandler.GetTrashedPosts)
+			admin.GET("/posts/scheduled", postHandler.GetScheduledPosts)
+			admin.GET("/posts/attention", postHandler.GetPostsNeedingAttention)
+			admin.POST("/posts/reset-views", postHandler.BulkResetViews)
+			admin.POST("/posts/bulk-visibility", postHandler.BulkUpdateVisibility)
+			admin.POST("/posts/:id/reset-views", postHandler.ResetViews)
+			admin.POST("/posts/:id/touch", postHandler.TouchPost)
+
+			// Content reports
+			admin.GET("/reports", reportHandler.GetReports)
+			admin.PATCH("/reports/:id/status", reportHandler.UpdateReportStatus)
 		}
 	}
 
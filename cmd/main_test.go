@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/job"
+	"moon/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// drainRecorder is a worker recording whether the in-flight request had finished by the
+// time it was stopped
+type drainRecorder struct {
+	requestDone          *atomic.Bool
+	stopped              atomic.Bool
+	stoppedAfterRequests atomic.Bool
+}
+
+func (w *drainRecorder) Start(ctx context.Context) {}
+
+func (w *drainRecorder) Stop(ctx context.Context) error {
+	w.stoppedAfterRequests.Store(w.requestDone.Load())
+	w.stopped.Store(true)
+	return nil
+}
+
+// startServer serves handler on a local port until the test ends
+func startServer(t *testing.T, handler http.Handler) (*http.Server, string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: handler}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+	return srv, "http://" + ln.Addr().String()
+}
+
+func TestDrainWaitsForInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var requestDone atomic.Bool
+	srv, url := startServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		requestDone.Store(true)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	worker := &drainRecorder{requestDone: &requestDone}
+	workers := job.NewManager()
+	workers.Add("recorder", worker)
+	workers.Start(context.Background())
+
+	responses := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			responses <- 0
+			return
+		}
+		resp.Body.Close()
+		responses <- resp.StatusCode
+	}()
+	<-started
+
+	drained := make(chan error, 1)
+	go func() { drained <- drain(context.Background(), srv, workers) }()
+
+	select {
+	case err := <-drained:
+		t.Fatalf("drain returned with a request in flight: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-drained; err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	if code := <-responses; code != http.StatusOK {
+		t.Errorf("expected the in-flight request to complete with 200, got %d", code)
+	}
+	if !worker.stopped.Load() || !worker.stoppedAfterRequests.Load() {
+		t.Error("expected the workers to be stopped after the in-flight request finished")
+	}
+
+	// The drained server accepts no new requests
+	if _, err := http.Get(url); err == nil {
+		t.Error("expected new requests to be refused after draining")
+	}
+}
+
+func TestDrainGivesUpAtTheDeadline(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	srv, url := startServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	var requestDone atomic.Bool
+	worker := &drainRecorder{requestDone: &requestDone}
+	workers := job.NewManager()
+	workers.Add("recorder", worker)
+	workers.Start(context.Background())
+
+	go func() {
+		if resp, err := http.Get(url); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := drain(ctx, srv, workers); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be reported, got %v", err)
+	}
+	if !worker.stopped.Load() {
+		t.Error("expected the workers to be stopped even though requests did not drain")
+	}
+}
+
+// Only a configured proxy can name the client IP, so a client can't pick its own
+func TestNewEngineTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	tests := []struct {
+		name    string
+		proxies []string
+		peer    string
+		want    string
+	}{
+		{"no proxies", nil, "203.0.113.7", "203.0.113.7"},
+		{"untrusted peer", []string{"10.0.0.0/8"}, "203.0.113.7", "203.0.113.7"},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.1.2.3", "198.51.100.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.App.TrustedProxies = tt.proxies
+			r, err := newEngine(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.peer + ":1234"
+			req.Header.Set("X-Forwarded-For", "198.51.100.9")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("expected client IP %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// A client sending its own X-Forwarded-For still shares its slots with its other requests
+func TestConcurrencyLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.GetConfig()
+	cfg.App.TrustedProxies = nil
+	cfg.ConcurrencyLimit = config.ConcurrencyLimitConfig{Enabled: true, PerIP: 1}
+
+	r, err := newEngine(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	r.Use(middleware.ConcurrencyLimit())
+	// Only the first request is held, so a failing check can't hang the test
+	r.GET("/slow", func(c *gin.Context) {
+		if c.GetHeader("X-Forwarded-For") == "" {
+			close(started)
+			<-release
+		}
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	done := make(chan int)
+	go func() { done <- serve("") }()
+	<-started
+
+	if code := serve("198.51.100.9"); code != http.StatusTooManyRequests {
+		t.Errorf("expected the spoofed request to share the held slot and get 429, got %d", code)
+	}
+
+	close(release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("expected the held request to finish with 200, got %d", code)
+	}
+}
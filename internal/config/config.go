@@ -2,19 +2,113 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+
+	"moon/pkg/imagepolicy"
+	"moon/pkg/moderation"
+	"moon/pkg/money"
+	"moon/pkg/slug"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	App      AppConfig      `yaml:"app"`
-	Database DatabaseConfig `yaml:"database"`
-	JWT      JWTConfig      `yaml:"jwt"`
-	Redis    RedisConfig    `yaml:"redis"`
-	Logger   LoggerConfig   `yaml:"logger"`
+	App              AppConfig              `yaml:"app"`
+	Database         DatabaseConfig         `yaml:"database"`
+	JWT              JWTConfig              `yaml:"jwt"`
+	Redis            RedisConfig            `yaml:"redis"`
+	Logger           LoggerConfig           `yaml:"logger"`
+	Post             PostConfig             `yaml:"post"`
+	Tenant           TenantConfig           `yaml:"tenant"`
+	Mail             MailConfig             `yaml:"mail"`
+	Account          AccountConfig          `yaml:"account"`
+	Pagination       PaginationConfig       `yaml:"pagination"`
+	RateLimit        RateLimitConfig        `yaml:"rate_limit"`
+	ConcurrencyLimit ConcurrencyLimitConfig `yaml:"concurrency_limit"`
+	Backup           BackupConfig           `yaml:"backup"`
+	RequestID        RequestIDConfig        `yaml:"request_id"`
+	Tracing          TracingConfig          `yaml:"tracing"`
+	Product          ProductConfig          `yaml:"product"`
+	HTTPS            HTTPSConfig            `yaml:"https"`
+	Moderation       ModerationConfig       `yaml:"moderation"`
+	Redaction        RedactionConfig        `yaml:"redaction"`
+}
+
+// RedactionConfig hides response fields from roles that are not allowed to see them
+type RedactionConfig struct {
+	Enabled bool            `yaml:"enabled"`
+	Rules   []RedactionRule `yaml:"rules"`
+}
+
+// RedactionRule removes Field, a snake_case JSON key matched at any depth, from responses
+// to every role not listed in Roles. Paths limits the rule to request paths with one of
+// the given prefixes; empty applies it everywhere.
+type RedactionRule struct {
+	Field string   `yaml:"field"`
+	Roles []string `yaml:"roles"`
+	Paths []string `yaml:"paths"`
+}
+
+// ModerationConfig controls the blocked-word filter applied to post titles and content
+type ModerationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Action is reject (422), mask (replace with asterisks) or flag (keep and list under the
+	// flagged attention rule)
+	Action string `yaml:"action"`
+	// Words are the blocked words per ISO 639-1 language; posts in other languages are not checked
+	Words map[string][]string `yaml:"words"`
+}
+
+// HTTPSConfig controls redirecting plain-HTTP requests that arrive through a
+// TLS-terminating proxy
+type HTTPSConfig struct {
+	Redirect bool `yaml:"redirect"`
+	// Host is the canonical host, with an optional port, redirects point to
+	Host string `yaml:"host"`
+	// TrustedProxies are the IPs or CIDRs whose X-Forwarded-Proto header is believed; empty
+	// trusts every peer, which is only safe when the app is not reachable directly
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// ExemptPaths are never redirected, so plain-HTTP health checks keep working
+	ExemptPaths []string `yaml:"exempt_paths"`
+}
+
+type ProductConfig struct {
+	// DefaultCurrency is the ISO 4217 code used for products created without a currency
+	DefaultCurrency string `yaml:"default_currency"`
+}
+
+// TracingConfig controls OpenTelemetry tracing of requests and database queries
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Endpoint is the host:port of an OTLP/HTTP collector
+	Endpoint string `yaml:"endpoint"`
+	Insecure bool   `yaml:"insecure"`
+	// SampleRatio is the fraction of new traces recorded, between 0 and 1
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// RequestIDConfig controls how requests are identified across services
+type RequestIDConfig struct {
+	// Headers are checked in order for an incoming ID; the first is also set on responses
+	Headers []string `yaml:"headers"`
+	// TraceParent uses the trace ID of a W3C traceparent header when no ID header is present
+	TraceParent bool `yaml:"trace_parent"`
+}
+
+// BackupConfig controls the job that periodically exports content to storage
+type BackupConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	IntervalMinutes int  `yaml:"interval_minutes"`
+	// Retention is how many backups are kept; older ones are deleted after each run
+	Retention    int  `yaml:"retention"`
+	IncludeUsers bool `yaml:"include_users"`
+	// Dir is the local directory backups are written to
+	Dir string `yaml:"dir"`
 }
 
 type AppConfig struct {
@@ -22,6 +116,14 @@ type AppConfig struct {
 	Version string `yaml:"version"`
 	Port    int    `yaml:"port"`
 	Mode    string `yaml:"mode"`
+	// JSONCase is the key style of JSON responses: snake (default) or camel
+	JSONCase string `yaml:"json_case"`
+	// ShutdownTimeoutSeconds bounds how long shutdown waits for in-flight requests and
+	// background workers before closing the database
+	ShutdownTimeoutSeconds int `yaml:"shutdown_timeout_seconds"`
+	// TrustedProxies are the IPs or CIDRs of the reverse proxies whose X-Forwarded-For header
+	// names the client IP. Empty trusts none, so the peer address is the client IP.
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
@@ -34,18 +136,38 @@ type DatabaseConfig struct {
 	Charset   string `yaml:"charset"`
 	ParseTime bool   `yaml:"parse_time"`
 	Loc       string `yaml:"loc"`
+	// SelfTest queries every migrated model on startup and fails readiness on errors
+	SelfTest bool `yaml:"self_test"`
 }
 
 type JWTConfig struct {
 	Secret    string `yaml:"secret"`
 	ExpiresIn int    `yaml:"expires_in"`
+	// RefreshExpiresIn is the lifetime of refresh tokens in hours
+	RefreshExpiresIn int `yaml:"refresh_expires_in"`
+	// CheckUserActive rejects tokens of users deactivated out of band (e.g. directly in the
+	// database); deactivation through the API already revokes their tokens
+	CheckUserActive bool `yaml:"check_user_active"`
 }
 
 type RedisConfig struct {
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	Password string `yaml:"password"`
-	DB       int    `yaml:"db"`
+	Host             string              `yaml:"host"`
+	Port             int                 `yaml:"port"`
+	Password         string              `yaml:"password"`
+	DB               int                 `yaml:"db"`
+	FailureThreshold int                 `yaml:"failure_threshold"`
+	CooldownSeconds  int                 `yaml:"cooldown_seconds"`
+	FailOpen         RedisFailOpenConfig `yaml:"fail_open"`
+}
+
+// RedisFailOpenConfig chooses, per feature, whether to allow (true) or deny (false)
+// requests when Redis is unavailable
+type RedisFailOpenConfig struct {
+	ReportRateLimit bool `yaml:"report_rate_limit"`
+	RateLimit       bool `yaml:"rate_limit"`
+	// TokenBlacklist lets logged out access tokens through while the blacklist can't be read;
+	// otherwise authenticated requests get 503 until Redis is back
+	TokenBlacklist bool `yaml:"token_blacklist"`
 }
 
 type LoggerConfig struct {
@@ -53,8 +175,337 @@ type LoggerConfig struct {
 	Format string `yaml:"format"`
 }
 
+type PaginationConfig struct {
+	DefaultLimit int `yaml:"default_limit"`
+	MaxLimit     int `yaml:"max_limit"`
+	// Endpoints overrides the default limit per endpoint name, e.g. "published_posts"
+	Endpoints map[string]int `yaml:"endpoints"`
+	// MaxResults caps the rows a non-streaming endpoint returns or pages past; 0 disables it
+	MaxResults int `yaml:"max_results"`
+}
+
+// RateLimitConfig caps requests per window, keyed by user ID for authenticated requests
+// and by client IP otherwise. A limit of 0 means unlimited.
+type RateLimitConfig struct {
+	Enabled       bool `yaml:"enabled"`
+	WindowSeconds int  `yaml:"window_seconds"`
+	Anonymous     int  `yaml:"anonymous"`
+	Authenticated int  `yaml:"authenticated"`
+	// Roles overrides the authenticated limit per role, e.g. admin: 0
+	Roles map[string]int `yaml:"roles"`
+}
+
+// ConcurrencyLimitConfig caps how many requests one client IP may have in flight at once.
+// Counters are kept in memory, so the cap applies to each instance separately.
+type ConcurrencyLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	PerIP   int  `yaml:"per_ip"`
+	// ExemptIPs are IPs or CIDRs that are never limited, e.g. internal health checkers
+	ExemptIPs []string `yaml:"exempt_ips"`
+}
+
+// MailConfig holds the SMTP settings; when disabled emails are only logged
+type MailConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+type AccountConfig struct {
+	// EmailChangeTTLMinutes is how long an email change confirmation link stays valid
+	EmailChangeTTLMinutes int `yaml:"email_change_ttl_minutes"`
+	// ConfirmEmailURL is the link sent to the new address; the token is appended as ?token=
+	ConfirmEmailURL string `yaml:"confirm_email_url"`
+	// UsernameLogin lets users log in with their username as well as their email
+	UsernameLogin bool `yaml:"username_login"`
+	// RequireApproval keeps new registrations inactive until an admin approves them
+	RequireApproval bool `yaml:"require_approval"`
+	// MaxSessions caps the active refresh tokens of a user; 0 means unlimited
+	MaxSessions int `yaml:"max_sessions"`
+	// SessionLimitMode is what a login at the cap does: evict_oldest revokes the oldest
+	// session, reject refuses the login
+	SessionLimitMode string `yaml:"session_limit_mode"`
+}
+
+// TenantConfig controls how requests are mapped to organizations
+type TenantConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Header     string `yaml:"header"`
+	BaseDomain string `yaml:"base_domain"`
+}
+
+type PostConfig struct {
+	// RequireCategory makes a category mandatory when creating, updating or publishing a post
+	RequireCategory bool `yaml:"require_category"`
+	// DefaultContentFormat is used when a post is created without a content format
+	DefaultContentFormat string `yaml:"default_content_format"`
+	// DefaultSort orders the published posts listing: published_at, view_count or hot
+	DefaultSort string `yaml:"default_sort"`
+	// MaxContentBytes caps the UTF-8 size of a post's content; the column is MEDIUMTEXT (16 MiB)
+	MaxContentBytes int `yaml:"max_content_bytes"`
+	// IncrementViewByDefault counts a view on single-post reads unless the request sets
+	// increment_view
+	IncrementViewByDefault bool `yaml:"increment_view_by_default"`
+	// MaxPostsPerAuthor caps how many non-deleted posts a user may own; 0 means unlimited.
+	// Editors and admins are exempt.
+	MaxPostsPerAuthor int `yaml:"max_posts_per_author"`
+	// PrivateVisibility decides who may read published posts that are not public:
+	// "authenticated" (any logged-in user) or "owner" (the author, editors and admins)
+	PrivateVisibility string `yaml:"private_visibility"`
+	// AuthorTrash lets authors list and restore their own deleted posts, not just admins
+	AuthorTrash     bool               `yaml:"author_trash"`
+	Slug            SlugConfig         `yaml:"slug"`
+	UnpublishStatus string             `yaml:"unpublish_status"`
+	Defaults        PostDefaultsConfig `yaml:"defaults"`
+	Search          SearchConfig       `yaml:"search"`
+	Tags            TagsConfig         `yaml:"tags"`
+	Import          ImportConfig       `yaml:"import"`
+	Languages       LanguagesConfig    `yaml:"languages"`
+	// PublishRequirements must be met by published posts; drafts may be incomplete
+	PublishRequirements PublishRequirementsConfig `yaml:"publish_requirements"`
+	DraftArchival       DraftArchivalConfig       `yaml:"draft_archival"`
+	ScheduledPublishing ScheduledPublishingConfig `yaml:"scheduled_publishing"`
+	DuplicateTitles     DuplicateTitlesConfig     `yaml:"duplicate_titles"`
+	ExternalImages      ExternalImagesConfig      `yaml:"external_images"`
+}
+
+// ExternalImagesConfig controls how images on other hosts embedded in post content are
+// rendered: allow, flag, strip or proxy. Unless allowed, readers who can't edit a post get
+// only the rendered HTML, not the raw content with the original image URLs.
+type ExternalImagesConfig struct {
+	Action string `yaml:"action"`
+	// AllowedHosts are not considered external; subdomains match too
+	AllowedHosts []string `yaml:"allowed_hosts"`
+	// ProxyURL is prefixed to proxied images, which pass their source as ?url=
+	ProxyURL string `yaml:"proxy_url"`
+}
+
+// DuplicateTitlesConfig controls the warning about near-duplicate titles on post creation
+type DuplicateTitlesConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxDistance is the largest edit distance between normalized titles still reported
+	MaxDistance int `yaml:"max_distance"`
+	// Candidates caps how many recent posts are compared
+	Candidates int `yaml:"candidates"`
+}
+
+// DraftArchivalConfig controls the background job that cleans up abandoned drafts
+type DraftArchivalConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	IntervalMinutes int    `yaml:"interval_minutes"`
+	AfterDays       int    `yaml:"after_days"`
+	Action          string `yaml:"action"` // archive or delete
+	DryRun          bool   `yaml:"dry_run"`
+}
+
+// ScheduledPublishingConfig controls the background job that publishes drafts once their
+// publish_at has passed
+type ScheduledPublishingConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	IntervalMinutes int  `yaml:"interval_minutes"`
+}
+
+type PublishRequirementsConfig struct {
+	// MinContentLength is the fewest characters of content, ignoring surrounding whitespace
+	MinContentLength int  `yaml:"min_content_length"`
+	Summary          bool `yaml:"summary"`
+	FeaturedImage    bool `yaml:"featured_image"`
+}
+
+// LanguagesConfig lists the ISO 639-1 languages posts may be written in
+type LanguagesConfig struct {
+	// Default is the site language, given to posts created without one
+	Default   string   `yaml:"default"`
+	Supported []string `yaml:"supported"`
+	// FromAcceptLanguage filters public listings by the Accept-Language header when the
+	// request has no lang parameter
+	FromAcceptLanguage bool `yaml:"from_accept_language"`
+}
+
+// ImportConfig caps markdown imports
+type ImportConfig struct {
+	// MaxUploadBytes caps the size of the uploaded file or zip archive
+	MaxUploadBytes int64 `yaml:"max_upload_bytes"`
+	// MaxFiles caps how many markdown files one import may contain
+	MaxFiles int `yaml:"max_files"`
+}
+
+type TagsConfig struct {
+	MaxPerPost int `yaml:"max_per_post"`
+	MaxLength  int `yaml:"max_length"`
+}
+
+type SearchConfig struct {
+	SnippetRadius int `yaml:"snippet_radius"` // characters kept on each side of a match
+	// StopWords are dropped from search queries; a query of only stop words is rejected
+	StopWords []string `yaml:"stop_words"`
+	// MinTermLength is the fewest characters a search term may have
+	MinTermLength int `yaml:"min_term_length"`
+	// EmptyQuery decides what a present but blank search parameter means: "all" ignores
+	// it, "none" matches no posts and "reject" fails the request
+	EmptyQuery string `yaml:"empty_query"`
+}
+
+// PostDefaultsConfig holds the values applied when a create request omits them.
+// A published private post is allowed; it is simply left out of public listings.
+type PostDefaultsConfig struct {
+	Status   string                      `yaml:"status"`
+	IsPublic bool                        `yaml:"is_public"`
+	Roles    map[string]RolePostDefaults `yaml:"roles"`
+}
+
+// RolePostDefaults overrides the global post defaults for a single role
+type RolePostDefaults struct {
+	Status   *string `yaml:"status"`
+	IsPublic *bool   `yaml:"is_public"`
+}
+
+type SlugConfig struct {
+	Separator string `yaml:"separator"`
+	Lowercase bool   `yaml:"lowercase"`
+}
+
+// maxContentColumnBytes is the capacity of the MEDIUMTEXT posts.content column
+const maxContentColumnBytes = 1<<24 - 1
+
 var appConfig *Config
 
+// defaultConfig holds the values used when a setting is absent from the config file
+func defaultConfig() *Config {
+	return &Config{
+		Tenant: TenantConfig{
+			Header: "X-Org-Slug",
+		},
+		Mail: MailConfig{
+			Port: 587,
+		},
+		JWT: JWTConfig{
+			RefreshExpiresIn: 720,
+		},
+		App: AppConfig{
+			JSONCase:               "snake",
+			ShutdownTimeoutSeconds: 30,
+		},
+		Database: DatabaseConfig{
+			SelfTest: true,
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit: 10,
+			MaxLimit:     100,
+			MaxResults:   10000,
+		},
+		Product: ProductConfig{
+			DefaultCurrency: "VND",
+		},
+		Tracing: TracingConfig{
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			SampleRatio: 1,
+		},
+		Moderation: ModerationConfig{
+			Action: moderation.ActionReject,
+		},
+		HTTPS: HTTPSConfig{
+			ExemptPaths: []string{"/ping", "/api/v1/health"},
+		},
+		RequestID: RequestIDConfig{
+			Headers:     []string{"X-Request-ID"},
+			TraceParent: true,
+		},
+		Backup: BackupConfig{
+			IntervalMinutes: 1440,
+			Retention:       7,
+			Dir:             "backups",
+		},
+		RateLimit: RateLimitConfig{
+			WindowSeconds: 60,
+			Anonymous:     60,
+			Authenticated: 300,
+		},
+		ConcurrencyLimit: ConcurrencyLimitConfig{
+			PerIP: 20,
+		},
+		Account: AccountConfig{
+			EmailChangeTTLMinutes: 60,
+			ConfirmEmailURL:       "http://localhost:8080/api/v1/auth/confirm-email",
+			UsernameLogin:         true,
+			SessionLimitMode:      "evict_oldest",
+		},
+		Redis: RedisConfig{
+			FailureThreshold: 3,
+			CooldownSeconds:  30,
+			FailOpen: RedisFailOpenConfig{
+				ReportRateLimit: true,
+				RateLimit:       true,
+			},
+		},
+		Post: PostConfig{
+			DefaultContentFormat:   "html",
+			DefaultSort:            "published_at",
+			MaxContentBytes:        1 << 20,
+			IncrementViewByDefault: true,
+			AuthorTrash:            true,
+			PrivateVisibility:      "authenticated",
+			Slug: SlugConfig{
+				Separator: "-",
+				Lowercase: true,
+			},
+			UnpublishStatus: "draft",
+			Defaults: PostDefaultsConfig{
+				Status:   "draft",
+				IsPublic: true,
+			},
+			Search: SearchConfig{
+				SnippetRadius: 80,
+				StopWords: []string{
+					"và", "của", "là", "các", "những", "được", "cho", "có", "với", "trong",
+					"này", "một", "không", "đã", "thì", "mà", "để", "từ", "khi", "cũng",
+					"như", "về", "ở", "tại", "nhưng", "hay", "hoặc", "rằng", "bị", "vì",
+				},
+				MinTermLength: 2,
+				EmptyQuery:    "all",
+			},
+			PublishRequirements: PublishRequirementsConfig{
+				MinContentLength: 1,
+			},
+			Import: ImportConfig{
+				MaxUploadBytes: 10 << 20,
+				MaxFiles:       100,
+			},
+			Languages: LanguagesConfig{
+				Default:            "vi",
+				Supported:          []string{"vi", "en"},
+				FromAcceptLanguage: true,
+			},
+			Tags: TagsConfig{
+				MaxPerPost: 10,
+				MaxLength:  30,
+			},
+			DraftArchival: DraftArchivalConfig{
+				IntervalMinutes: 60,
+				AfterDays:       90,
+				Action:          "archive",
+				DryRun:          true,
+			},
+			ScheduledPublishing: ScheduledPublishingConfig{
+				Enabled:         true,
+				IntervalMinutes: 1,
+			},
+			ExternalImages: ExternalImagesConfig{
+				Action: "allow",
+			},
+			DuplicateTitles: DuplicateTitlesConfig{
+				Enabled:     true,
+				MaxDistance: 3,
+				Candidates:  200,
+			},
+		},
+	}
+}
+
 func LoadConfig(configPath string) error {
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
@@ -67,14 +518,215 @@ func LoadConfig(configPath string) error {
 		return fmt.Errorf("error reading config file: %w", err)
 	}
 
-	// Parse YAML
-	if err := yaml.Unmarshal(data, &appConfig); err != nil {
+	// Parse YAML on top of the defaults
+	appConfig = defaultConfig()
+	if err := yaml.Unmarshal(data, appConfig); err != nil {
 		return fmt.Errorf("error parsing config file: %w", err)
 	}
 
 	// Override with environment variables
 	overrideWithEnvVars()
 
+	return validateConfig()
+}
+
+func validateConfig() error {
+	if !slug.IsValidSeparator(appConfig.Post.Slug.Separator) {
+		return fmt.Errorf("invalid slug separator %q", appConfig.Post.Slug.Separator)
+	}
+
+	if status := appConfig.Post.UnpublishStatus; status != "draft" && status != "archived" {
+		return fmt.Errorf("invalid unpublish status %q", status)
+	}
+
+	if !isValidPostStatus(appConfig.Post.Defaults.Status) {
+		return fmt.Errorf("invalid default post status %q", appConfig.Post.Defaults.Status)
+	}
+	if appConfig.Post.PublishRequirements.MinContentLength < 0 {
+		return fmt.Errorf("post publish_requirements min_content_length must not be negative")
+	}
+
+	if appConfig.Post.Import.MaxUploadBytes < 1 || appConfig.Post.Import.MaxFiles < 1 {
+		return fmt.Errorf("post import max_upload_bytes and max_files must be positive")
+	}
+
+	languages := appConfig.Post.Languages
+	for _, code := range languages.Supported {
+		if !isLanguageCode(code) {
+			return fmt.Errorf("invalid post language %q, expected an ISO 639-1 code", code)
+		}
+	}
+	if !slices.Contains(languages.Supported, languages.Default) {
+		return fmt.Errorf("post default language %q is not in the supported languages", languages.Default)
+	}
+
+	if appConfig.Post.Tags.MaxPerPost < 1 || appConfig.Post.Tags.MaxLength < 1 {
+		return fmt.Errorf("post tag limits must be positive")
+	}
+
+	if appConfig.JWT.RefreshExpiresIn < 1 {
+		return fmt.Errorf("jwt refresh_expires_in must be positive")
+	}
+
+	if appConfig.App.ShutdownTimeoutSeconds < 1 {
+		return fmt.Errorf("app shutdown_timeout_seconds must be positive")
+	}
+	for _, proxy := range appConfig.App.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("invalid app trusted proxy %q, expected an IP or CIDR", proxy)
+		}
+	}
+
+	if jsonCase := appConfig.App.JSONCase; jsonCase != "snake" && jsonCase != "camel" {
+		return fmt.Errorf("invalid json case %q", jsonCase)
+	}
+
+	if !money.IsSupported(appConfig.Product.DefaultCurrency) {
+		return fmt.Errorf("unsupported product default_currency: %s", appConfig.Product.DefaultCurrency)
+	}
+
+	if appConfig.Tracing.SampleRatio < 0 || appConfig.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample_ratio must be between 0 and 1")
+	}
+	if appConfig.Tracing.Enabled && appConfig.Tracing.Endpoint == "" {
+		return fmt.Errorf("tracing endpoint is required when tracing is enabled")
+	}
+
+	if !moderation.IsValidAction(appConfig.Moderation.Action) {
+		return fmt.Errorf("invalid moderation action %q, expected reject, mask or flag", appConfig.Moderation.Action)
+	}
+
+	if https := appConfig.HTTPS; https.Redirect && (https.Host == "" || strings.ContainsAny(https.Host, "/?#@")) {
+		return fmt.Errorf("https host must be set to a host name, with an optional port, when redirect is enabled")
+	}
+	for _, proxy := range appConfig.HTTPS.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("invalid https trusted proxy %q, expected an IP or CIDR", proxy)
+		}
+	}
+
+	if len(appConfig.RequestID.Headers) == 0 {
+		return fmt.Errorf("request_id headers must not be empty")
+	}
+	for _, header := range appConfig.RequestID.Headers {
+		if strings.TrimSpace(header) == "" {
+			return fmt.Errorf("request_id headers must not be blank")
+		}
+	}
+
+	backup := appConfig.Backup
+	if backup.IntervalMinutes < 1 || backup.Retention < 1 {
+		return fmt.Errorf("backup interval and retention must be positive")
+	}
+
+	rateLimit := appConfig.RateLimit
+	if rateLimit.WindowSeconds < 1 || rateLimit.Anonymous < 0 || rateLimit.Authenticated < 0 {
+		return fmt.Errorf("rate limit window must be positive and limits must not be negative")
+	}
+	for role, limit := range rateLimit.Roles {
+		if limit < 0 {
+			return fmt.Errorf("invalid rate limit %d for role %q", limit, role)
+		}
+	}
+
+	for _, rule := range appConfig.Redaction.Rules {
+		if strings.TrimSpace(rule.Field) == "" {
+			return fmt.Errorf("redaction rule field must not be blank")
+		}
+	}
+
+	concurrencyLimit := appConfig.ConcurrencyLimit
+	if concurrencyLimit.Enabled && concurrencyLimit.PerIP < 1 {
+		return fmt.Errorf("concurrency limit per_ip must be positive when enabled")
+	}
+	for _, ip := range concurrencyLimit.ExemptIPs {
+		if _, _, err := net.ParseCIDR(ip); err != nil && net.ParseIP(ip) == nil {
+			return fmt.Errorf("invalid concurrency limit exempt IP %q, expected an IP or CIDR", ip)
+		}
+	}
+
+	pagination := appConfig.Pagination
+	if pagination.DefaultLimit < 1 || pagination.MaxLimit < pagination.DefaultLimit {
+		return fmt.Errorf("pagination default limit must be positive and not exceed the max limit")
+	}
+	for endpoint, limit := range pagination.Endpoints {
+		if limit < 1 || limit > pagination.MaxLimit {
+			return fmt.Errorf("invalid pagination limit %d for endpoint %q", limit, endpoint)
+		}
+	}
+	if pagination.MaxResults != 0 && pagination.MaxResults < pagination.MaxLimit {
+		return fmt.Errorf("pagination max_results must be 0 or at least the max limit")
+	}
+
+	if format := appConfig.Post.DefaultContentFormat; format != "markdown" && format != "html" {
+		return fmt.Errorf("invalid default content format %q", format)
+	}
+
+	switch appConfig.Post.DefaultSort {
+	case "published_at", "view_count", "hot":
+	default:
+		return fmt.Errorf("invalid default post sort %q", appConfig.Post.DefaultSort)
+	}
+
+	if visibility := appConfig.Post.PrivateVisibility; visibility != "authenticated" && visibility != "owner" {
+		return fmt.Errorf("invalid private post visibility %q", visibility)
+	}
+
+	if appConfig.Account.MaxSessions < 0 {
+		return fmt.Errorf("account max_sessions must not be negative")
+	}
+
+	if mode := appConfig.Account.SessionLimitMode; mode != "evict_oldest" && mode != "reject" {
+		return fmt.Errorf("invalid account session_limit_mode %q, expected evict_oldest or reject", mode)
+	}
+
+	if appConfig.Post.Search.MinTermLength < 1 {
+		return fmt.Errorf("post search min_term_length must be at least 1")
+	}
+
+	if mode := appConfig.Post.Search.EmptyQuery; mode != "all" && mode != "none" && mode != "reject" {
+		return fmt.Errorf("invalid post search empty_query %q", mode)
+	}
+
+	if appConfig.Post.MaxPostsPerAuthor < 0 {
+		return fmt.Errorf("post max_posts_per_author must not be negative")
+	}
+
+	if max := appConfig.Post.MaxContentBytes; max < 1 || max > maxContentColumnBytes {
+		return fmt.Errorf("post max content bytes must be between 1 and %d", maxContentColumnBytes)
+	}
+
+	archival := appConfig.Post.DraftArchival
+	if archival.Action != "archive" && archival.Action != "delete" {
+		return fmt.Errorf("invalid draft archival action %q", archival.Action)
+	}
+	if archival.IntervalMinutes < 1 || archival.AfterDays < 1 {
+		return fmt.Errorf("draft archival interval and age must be positive")
+	}
+
+	if appConfig.Post.ScheduledPublishing.IntervalMinutes < 1 {
+		return fmt.Errorf("scheduled publishing interval must be positive")
+	}
+
+	images := appConfig.Post.ExternalImages
+	if !imagepolicy.IsValidAction(images.Action) {
+		return fmt.Errorf("invalid external images action %q", images.Action)
+	}
+	if images.Action == imagepolicy.ActionProxy && images.ProxyURL == "" {
+		return fmt.Errorf("external images proxy_url is required for the proxy action")
+	}
+
+	duplicates := appConfig.Post.DuplicateTitles
+	if duplicates.MaxDistance < 0 || duplicates.Candidates < 1 {
+		return fmt.Errorf("duplicate titles max distance must not be negative and candidates must be positive")
+	}
+
+	for role, defaults := range appConfig.Post.Defaults.Roles {
+		if defaults.Status != nil && !isValidPostStatus(*defaults.Status) {
+			return fmt.Errorf("invalid default post status %q for role %q", *defaults.Status, role)
+		}
+	}
+
 	return nil
 }
 
@@ -117,6 +769,11 @@ func overrideWithEnvVars() {
 			appConfig.JWT.ExpiresIn = e
 		}
 	}
+	if refreshExpiresIn := os.Getenv("JWT_REFRESH_EXPIRES_IN"); refreshExpiresIn != "" {
+		if e, err := strconv.Atoi(refreshExpiresIn); err == nil {
+			appConfig.JWT.RefreshExpiresIn = e
+		}
+	}
 
 	// Redis config
 	if host := os.Getenv("REDIS_HOST"); host != "" {
@@ -137,6 +794,15 @@ func overrideWithEnvVars() {
 	}
 }
 
+func isValidPostStatus(status string) bool {
+	return status == "draft" || status == "published" || status == "archived"
+}
+
 func GetConfig() *Config {
 	return appConfig
 }
+
+// isLanguageCode reports whether code looks like an ISO 639-1 code: two lowercase letters
+func isLanguageCode(code string) bool {
+	return len(code) == 2 && code[0] >= 'a' && code[0] <= 'z' && code[1] >= 'a' && code[1] <= 'z'
+}
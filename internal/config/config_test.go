@@ -0,0 +1,161 @@
+package config
+
+import "testing"
+
+func TestValidatePaginationEndpoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints map[string]int
+		wantErr   bool
+	}{
+		{"none", nil, false},
+		{"within the maximum", map[string]int{"reports": 50, "users": 100}, false},
+		{"zero", map[string]int{"reports": 0}, true},
+		{"above the maximum", map[string]int{"reports": 101}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := LoadConfig("../../configs/config.yaml"); err != nil {
+				t.Fatal(err)
+			}
+			appConfig.Pagination.MaxLimit = 100
+			appConfig.Pagination.Endpoints = tt.endpoints
+
+			if err := validateConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateMaxContentBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int
+		wantErr bool
+	}{
+		{"zero", 0, true},
+		{"one byte", 1, false},
+		{"column size", maxContentColumnBytes, false},
+		{"above the column size", maxContentColumnBytes + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := LoadConfig("../../configs/config.yaml"); err != nil {
+				t.Fatal(err)
+			}
+			appConfig.Post.MaxContentBytes = tt.max
+
+			if err := validateConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultSort(t *testing.T) {
+	tests := []struct {
+		sort    string
+		wantErr bool
+	}{
+		{"published_at", false},
+		{"view_count", false},
+		{"hot", false},
+		{"title", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			if err := LoadConfig("../../configs/config.yaml"); err != nil {
+				t.Fatal(err)
+			}
+			appConfig.Post.DefaultSort = tt.sort
+
+			if err := validateConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateEmptyQuery(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{"all", false},
+		{"none", false},
+		{"reject", false},
+		{"", true},
+		{"ignore", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if err := LoadConfig("../../configs/config.yaml"); err != nil {
+				t.Fatal(err)
+			}
+			appConfig.Post.Search.EmptyQuery = tt.mode
+
+			if err := validateConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"IP and CIDR", []string{"10.0.0.1", "172.16.0.0/12"}, false},
+		{"host name", []string{"proxy.internal"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := LoadConfig("../../configs/config.yaml"); err != nil {
+				t.Fatal(err)
+			}
+			appConfig.App.TrustedProxies = tt.proxies
+
+			if err := validateConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateSessionLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxSessions int
+		mode        string
+		wantErr     bool
+	}{
+		{"unlimited", 0, "evict_oldest", false},
+		{"evict oldest", 5, "evict_oldest", false},
+		{"reject", 5, "reject", false},
+		{"negative cap", -1, "reject", true},
+		{"unknown mode", 5, "evict_newest", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := LoadConfig("../../configs/config.yaml"); err != nil {
+				t.Fatal(err)
+			}
+			appConfig.Account.MaxSessions = tt.maxSessions
+			appConfig.Account.SessionLimitMode = tt.mode
+
+			if err := validateConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
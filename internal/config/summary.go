@@ -0,0 +1,44 @@
+package config
+
+// Summary returns the effective, non-secret configuration for the startup log. Passwords,
+// secrets and usernames are left out entirely rather than masked.
+func (c *Config) Summary() map[string]interface{} {
+	return map[string]interface{}{
+		"app": map[string]interface{}{
+			"name":                     c.App.Name,
+			"version":                  c.App.Version,
+			"mode":                     c.App.Mode,
+			"port":                     c.App.Port,
+			"shutdown_timeout_seconds": c.App.ShutdownTimeoutSeconds,
+			"trusted_proxies":          c.App.TrustedProxies,
+		},
+		"database": map[string]interface{}{
+			"driver": c.Database.Driver,
+			"host":   c.Database.Host,
+			"name":   c.Database.Name,
+		},
+		"redis_configured": c.Redis.Host != "",
+		"logger": map[string]interface{}{
+			"level":  c.Logger.Level,
+			"format": c.Logger.Format,
+		},
+		"features": map[string]bool{
+			"check_user_active":           c.JWT.CheckUserActive,
+			"report_rate_limit_fail_open": c.Redis.FailOpen.ReportRateLimit,
+			"token_blacklist_fail_open":   c.Redis.FailOpen.TokenBlacklist,
+			"require_category":            c.Post.RequireCategory,
+			"accept_language":             c.Post.Languages.FromAcceptLanguage,
+			"author_trash":                c.Post.AuthorTrash,
+			"draft_archival":              c.Post.DraftArchival.Enabled,
+			"tenant":                      c.Tenant.Enabled,
+			"mail":                        c.Mail.Enabled,
+			"username_login":              c.Account.UsernameLogin,
+			"require_approval":            c.Account.RequireApproval,
+			"session_limit":               c.Account.MaxSessions > 0,
+			"rate_limit":                  c.RateLimit.Enabled,
+			"tracing":                     c.Tracing.Enabled,
+			"https_redirect":              c.HTTPS.Redirect,
+			"moderation":                  c.Moderation.Enabled,
+		},
+	}
+}
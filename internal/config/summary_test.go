@@ -0,0 +1,46 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSummaryLeavesOutSecrets(t *testing.T) {
+	secrets := map[string]string{
+		"JWT_SECRET":     "jwt-secret-value",
+		"DB_USERNAME":    "db-username-value",
+		"DB_PASSWORD":    "db-password-value",
+		"REDIS_PASSWORD": "redis-password-value",
+	}
+	for name, value := range secrets {
+		t.Setenv(name, value)
+	}
+	if err := LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := GetConfig()
+	cfg.Mail.Username = "mail-username-value"
+	cfg.Mail.Password = "mail-password-value"
+
+	data, err := json.Marshal(cfg.Summary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := string(data)
+
+	for _, value := range []string{
+		cfg.JWT.Secret, cfg.Database.Username, cfg.Database.Password,
+		cfg.Redis.Password, cfg.Mail.Username, cfg.Mail.Password,
+	} {
+		if !strings.HasSuffix(value, "-value") {
+			t.Fatalf("secret %q was not loaded", value)
+		}
+		if strings.Contains(summary, value) {
+			t.Errorf("summary contains %q: %s", value, summary)
+		}
+	}
+	if !strings.Contains(summary, `"database"`) {
+		t.Errorf("expected the non-secret database settings in the summary: %s", summary)
+	}
+}
@@ -0,0 +1,43 @@
+package ctxutil
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserID returns the authenticated user's ID set by the auth middleware
+func UserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return 0, false
+	}
+	userID, ok := value.(uint)
+	return userID, ok
+}
+
+// Role returns the authenticated user's role set by the auth middleware
+func Role(c *gin.Context) (string, bool) {
+	value, exists := c.Get("role")
+	if !exists {
+		return "", false
+	}
+	role, ok := value.(string)
+	return role, ok
+}
+
+// RequestID returns the request ID set by the request ID middleware
+func RequestID(c *gin.Context) string {
+	return c.GetString("request_id")
+}
+
+// TokenID returns the ID (jti) of the access token the request was authenticated with;
+// tokens issued before IDs were added have none
+func TokenID(c *gin.Context) string {
+	return c.GetString("token_id")
+}
+
+// TokenExpiresAt returns when the access token the request was authenticated with expires
+func TokenExpiresAt(c *gin.Context) time.Time {
+	return c.GetTime("token_expires_at")
+}
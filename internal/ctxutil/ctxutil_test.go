@@ -0,0 +1,64 @@
+package ctxutil
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUserID(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{} // nil leaves the key unset
+		want   uint
+		wantOK bool
+	}{
+		{"set", uint(7), 7, true},
+		{"missing", nil, 0, false},
+		{"mistyped int", 7, 0, false},
+		{"mistyped string", "7", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			if tt.value != nil {
+				c.Set("user_id", tt.value)
+			}
+
+			got, ok := UserID(c)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("got (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{} // nil leaves the key unset
+		want   string
+		wantOK bool
+	}{
+		{"set", "admin", "admin", true},
+		{"missing", nil, "", false},
+		{"mistyped bytes", []byte("admin"), "", false},
+		{"mistyped int", 1, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			if tt.value != nil {
+				c.Set("role", tt.value)
+			}
+
+			got, ok := Role(c)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("got (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
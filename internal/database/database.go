@@ -3,6 +3,7 @@ package database
 import (
 	"fmt"
 	"moon/internal/config"
+	"moon/internal/tenant"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -44,6 +45,16 @@ func ConnectDatabase(cfg *config.Config) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := tenant.RegisterCallbacks(db); err != nil {
+		return fmt.Errorf("failed to register tenant callbacks: %w", err)
+	}
+
+	if cfg.Tracing.Enabled {
+		if err := registerTracingCallbacks(db); err != nil {
+			return fmt.Errorf("failed to register tracing callbacks: %w", err)
+		}
+	}
+
 	DB = db
 	return nil
 }
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"moon/internal/config"
+	"moon/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var Cache *cache.Cache
+
+// ConnectRedis sets up the Redis-backed cache. The cache is usable even when the
+// initial ping fails; it then starts out degraded and recovers once Redis is reachable.
+func ConnectRedis(cfg *config.Config) error {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+
+	Cache = cache.New(client, cache.Options{
+		FailureThreshold: cfg.Redis.FailureThreshold,
+		Cooldown:         time.Duration(cfg.Redis.CooldownSeconds) * time.Second,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return nil
+}
+
+func GetCache() *cache.Cache {
+	return Cache
+}
+
+func CloseRedis() error {
+	return Cache.Close()
+}
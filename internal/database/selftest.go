@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+var (
+	selfTestMu  sync.RWMutex
+	selfTestErr error
+)
+
+// RunSelfTest checks that the schema of every model is usable by reading one row with all
+// of its mapped columns selected explicitly, which fails on missing tables, missing columns
+// and missing privileges. The result is kept for Ready.
+func RunSelfTest(ctx context.Context, models ...interface{}) error {
+	var errs []error
+	for _, model := range models {
+		rows := reflect.New(reflect.SliceOf(reflect.TypeOf(model))).Interface()
+		err := DB.WithContext(ctx).
+			Session(&gorm.Session{QueryFields: true}).
+			Model(model).
+			Limit(1).
+			Find(rows).Error
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%T: %w", model, err))
+		}
+	}
+
+	err := errors.Join(errs...)
+
+	selfTestMu.Lock()
+	selfTestErr = err
+	selfTestMu.Unlock()
+
+	return err
+}
+
+// Ready reports whether the database can serve traffic: the startup self-test, if run,
+// passed and the connection answers a ping
+func Ready(ctx context.Context) error {
+	selfTestMu.RLock()
+	err := selfTestErr
+	selfTestMu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("schema self-test failed: %w", err)
+	}
+
+	if DB == nil {
+		return errors.New("database not connected")
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// brokenDriver is a database/sql driver whose queries fail with queryErr and whose pings
+// fail with pingErr
+type brokenDriver struct {
+	queryErr error
+	pingErr  error
+}
+
+func (d *brokenDriver) Open(name string) (driver.Conn, error) {
+	return &brokenConn{driver: d}, nil
+}
+
+func (d *brokenDriver) Connect(ctx context.Context) (driver.Conn, error) { return d.Open("") }
+
+func (d *brokenDriver) Driver() driver.Driver { return d }
+
+type brokenConn struct {
+	driver *brokenDriver
+}
+
+func (c *brokenConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *brokenConn) Close() error { return nil }
+
+func (c *brokenConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *brokenConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.driver.queryErr != nil {
+		return nil, c.driver.queryErr
+	}
+	return &emptyRows{}, nil
+}
+
+func (c *brokenConn) Ping(ctx context.Context) error {
+	return c.driver.pingErr
+}
+
+// emptyRows is a result without rows
+type emptyRows struct{}
+
+func (r *emptyRows) Columns() []string { return nil }
+
+func (r *emptyRows) Close() error { return nil }
+
+func (r *emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+type selfTestModel struct {
+	ID   uint
+	Name string
+}
+
+// useBrokenDB points DB at d for the duration of the test and clears the self-test result
+func useBrokenDB(t *testing.T, d *brokenDriver) {
+	t.Helper()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sql.OpenDB(d), SkipInitializeWithVersion: true}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := DB
+	DB = db
+	t.Cleanup(func() {
+		DB = saved
+		selfTestMu.Lock()
+		selfTestErr = nil
+		selfTestMu.Unlock()
+	})
+}
+
+func TestReady(t *testing.T) {
+	ctx := context.Background()
+	missingTable := errors.New("Error 1146: Table 'moon.self_test_models' doesn't exist")
+
+	tests := []struct {
+		name     string
+		driver   *brokenDriver
+		selfTest bool
+		wantErr  string
+	}{
+		{"healthy", &brokenDriver{}, true, ""},
+		{"broken schema", &brokenDriver{queryErr: missingTable}, true, "schema self-test failed"},
+		{"broken schema without self-test", &brokenDriver{queryErr: missingTable}, false, ""},
+		{"database down", &brokenDriver{pingErr: errors.New("connection refused")}, false, "connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useBrokenDB(t, tt.driver)
+
+			if tt.selfTest {
+				err := RunSelfTest(ctx, &selfTestModel{})
+				if (err != nil) != (tt.driver.queryErr != nil) {
+					t.Fatalf("unexpected self-test result: %v", err)
+				}
+			}
+
+			err := Ready(ctx)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected ready, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// A failed self-test keeps reporting unready even once the database answers again
+func TestReadyAfterFailedSelfTest(t *testing.T) {
+	ctx := context.Background()
+	d := &brokenDriver{queryErr: errors.New("Error 1054: Unknown column 'name'")}
+	useBrokenDB(t, d)
+
+	if err := RunSelfTest(ctx, &selfTestModel{}); err == nil {
+		t.Fatal("expected the self-test to fail")
+	}
+
+	d.queryErr = nil
+	if err := Ready(ctx); err == nil {
+		t.Error("expected the failed self-test to keep the database unready")
+	}
+}
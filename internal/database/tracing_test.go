@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestTracingCallbacks(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	otel.SetTracerProvider(provider)
+
+	tests := []struct {
+		name      string
+		driver    *brokenDriver
+		query     func(db *gorm.DB) error
+		wantError bool
+	}{
+		{"query", &brokenDriver{}, func(db *gorm.DB) error {
+			return db.Find(&[]selfTestModel{}).Error
+		}, false},
+		{"record not found", &brokenDriver{}, func(db *gorm.DB) error {
+			return db.First(&selfTestModel{}).Error
+		}, false},
+		{"failed query", &brokenDriver{queryErr: errors.New("Error 1146: Table doesn't exist")}, func(db *gorm.DB) error {
+			return db.Find(&[]selfTestModel{}).Error
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder.Reset()
+
+			db, err := gorm.Open(mysql.New(mysql.Config{Conn: sql.OpenDB(tt.driver), SkipInitializeWithVersion: true}), &gorm.Config{
+				DisableAutomaticPing: true,
+				Logger:               logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := registerTracingCallbacks(db); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+			tt.query(db.WithContext(ctx))
+			parent.End()
+
+			var span sdktrace.ReadOnlySpan
+			for _, s := range recorder.Ended() {
+				if s.Name() == "db.query" {
+					span = s
+				}
+			}
+			if span == nil {
+				t.Fatalf("expected a db.query span, got %v", recorder.Ended())
+			}
+
+			if span.SpanKind() != trace.SpanKindClient || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("expected a client span under the request span, got kind %v parent %v", span.SpanKind(), span.Parent().SpanID())
+			}
+			query := ""
+			for _, attr := range span.Attributes() {
+				if attr.Key == "db.query.text" {
+					query = attr.Value.AsString()
+				}
+			}
+			if !strings.HasPrefix(query, "SELECT") {
+				t.Errorf("expected the SQL on the span, got %q", query)
+			}
+			if isError := span.Status().Code == codes.Error; isError != tt.wantError {
+				t.Errorf("expected error status %v, got %v", tt.wantError, span.Status())
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package dashboard
+
+import "time"
+
+type UserStats struct {
+	Total    int64 `json:"total"`
+	Active   int64 `json:"active"`
+	Inactive int64 `json:"inactive"`
+}
+
+type PostStats struct {
+	Total    int64            `json:"total"`
+	ByStatus map[string]int64 `json:"by_status"`
+}
+
+type RecentUser struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RecentPost struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Status    string    `json:"status"`
+	AuthorID  uint      `json:"author_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SummaryResponse struct {
+	Users               UserStats    `json:"users"`
+	Posts               PostStats    `json:"posts"`
+	RecentRegistrations []RecentUser `json:"recent_registrations"`
+	RecentPosts         []RecentPost `json:"recent_posts"`
+	GeneratedAt         time.Time    `json:"generated_at"`
+}
@@ -0,0 +1,25 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when an organization does not exist
+var ErrNotFound = errors.New("organization not found")
+
+// Organization is a tenant owning its own users, posts and categories
+type Organization struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;size:100;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Repository interface - Domain layer
+type Repository interface {
+	GetByID(ctx context.Context, id uint) (*Organization, error)
+	GetBySlug(ctx context.Context, slug string) (*Organization, error)
+}
@@ -0,0 +1,67 @@
+package post
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangesFilter selects posts changed after Since for the incremental sync feed. A post's
+// change time is its updated_at, or its deleted_at once it has been soft-deleted.
+type ChangesFilter struct {
+	Since          time.Time
+	IncludeDeleted bool
+	After          *ChangesCursor // resume after this position; nil starts at Since
+}
+
+// ChangesCursor is a position in the change feed: the change time and ID of the last post
+// returned. Posts sharing a change time are ordered by ID, so no change is skipped.
+type ChangesCursor struct {
+	ChangedAt time.Time
+	ID        uint
+}
+
+// Encode returns the opaque cursor string handed to clients
+func (c ChangesCursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", c.ChangedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeChangesCursor parses a cursor produced by Encode
+func DecodeChangesCursor(s string) (*ChangesCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	changedAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	postID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &ChangesCursor{ChangedAt: time.Unix(0, changedAt).UTC(), ID: uint(postID)}, nil
+}
+
+// PostChange is one entry of the change feed. Deleted entries are tombstones: the post was
+// soft-deleted at DeletedAt and should be removed by the syncing system.
+type PostChange struct {
+	PostResponse
+	Deleted   bool       `json:"deleted"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type PostChangesResponse struct {
+	Changes    []PostChange `json:"changes"`
+	NextCursor string       `json:"next_cursor,omitempty"` // resumes after the last change; empty when none were returned
+	HasMore    bool         `json:"has_more"`
+}
@@ -0,0 +1,39 @@
+package post
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChangesCursorRoundTrip(t *testing.T) {
+	cursor := ChangesCursor{ChangedAt: time.Date(2026, 3, 1, 12, 30, 0, 123456789, time.UTC), ID: 42}
+
+	decoded, err := DecodeChangesCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decoded.ChangedAt.Equal(cursor.ChangedAt) || decoded.ID != cursor.ID {
+		t.Errorf("got %+v, want %+v", decoded, cursor)
+	}
+}
+
+func TestDecodeChangesCursorRejectsInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	for _, cursor := range []string{
+		"not base64!",
+		encode("123"),
+		encode("abc:1"),
+		encode("123:abc"),
+		encode("123:-1"),
+		encode("123:99999999999"),
+	} {
+		if _, err := DecodeChangesCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeChangesCursor(%q): expected ErrInvalidCursor, got %v", cursor, err)
+		}
+	}
+}
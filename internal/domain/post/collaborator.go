@@ -0,0 +1,39 @@
+package post
+
+import "time"
+
+// Collaborator permissions. Neither lets a collaborator delete or publish the post.
+const (
+	CollaboratorView = "view"
+	CollaboratorEdit = "edit"
+)
+
+// Collaborator grants a user other than the author access to a post
+type Collaborator struct {
+	PostID     uint      `json:"post_id" gorm:"primaryKey;autoIncrement:false"`
+	UserID     uint      `json:"user_id" gorm:"primaryKey;autoIncrement:false;index"`
+	Permission string    `json:"permission" gorm:"size:10;not null"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (Collaborator) TableName() string {
+	return "post_collaborators"
+}
+
+// CanEdit reports whether the collaborator may update the post
+func (c *Collaborator) CanEdit() bool {
+	return c.Permission == CollaboratorEdit
+}
+
+// AddCollaboratorRequest adds a collaborator, or changes the permission of an existing one
+type AddCollaboratorRequest struct {
+	UserID     uint   `json:"user_id" binding:"required"`
+	Permission string `json:"permission" binding:"required,oneof=view edit"`
+}
+
+type CollaboratorResponse struct {
+	UserID     uint      `json:"user_id"`
+	Name       string    `json:"name"`
+	Permission string    `json:"permission"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package post
+
+import "errors"
+
+// Errors returned by the post use case. Handlers map them to HTTP statuses through
+// response.FromError, so their messages are part of the API.
+var (
+	ErrNotFound               = errors.New("post not found")
+	ErrPermissionDenied       = errors.New("permission denied")
+	ErrTitleRequired          = errors.New("title is required")
+	ErrContentRequired        = errors.New("content is required")
+	ErrContentTooLarge        = errors.New("content too large")
+	ErrContentNotUTF8         = errors.New("content is not valid UTF-8")
+	ErrBlockedWords           = errors.New("content contains blocked words")
+	ErrCategoryRequired       = errors.New("category is required")
+	ErrInvalidCategory        = errors.New("invalid category")
+	ErrInvalidSlug            = errors.New("invalid slug")
+	ErrSlugInUse              = errors.New("slug already in use")
+	ErrInvalidStatus          = errors.New("invalid status")
+	ErrInvalidUnpublishStatus = errors.New("invalid unpublish status")
+	ErrInvalidSort            = errors.New("invalid sort")
+	ErrUnsupportedLanguage    = errors.New("unsupported language")
+	ErrInvalidArchiveMonth    = errors.New("invalid year or month")
+	ErrInvalidAttentionRule   = errors.New("invalid attention rule")
+	ErrPostLimitReached       = errors.New("post limit reached")
+	ErrCollaboratorNotFound   = errors.New("collaborator not found")
+	ErrAuthorIsCollaborator   = errors.New("author cannot be a collaborator")
+	ErrNoMarkdownFiles        = errors.New("no markdown files found")
+	ErrTooManyFiles           = errors.New("too many files")
+	ErrInvalidCursor          = errors.New("invalid cursor")
+)
@@ -0,0 +1,70 @@
+package post
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrNoFrontMatter = errors.New("missing front matter")
+
+// ImportFile is one uploaded markdown file
+type ImportFile struct {
+	Name string
+	Data []byte
+}
+
+// FrontMatter is the YAML header of an imported markdown file
+type FrontMatter struct {
+	Title   string     `yaml:"title"`
+	Slug    string     `yaml:"slug"`
+	Date    *time.Time `yaml:"date"`
+	Tags    []string   `yaml:"tags"`
+	Status  string     `yaml:"status"`
+	Summary string     `yaml:"summary"`
+}
+
+// ImportResult reports the outcome for a single imported file
+type ImportResult struct {
+	File   string `json:"file"`
+	PostID uint   `json:"post_id,omitempty"`
+	Slug   string `json:"slug,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ImportResponse struct {
+	Imported int            `json:"imported"`
+	Failed   int            `json:"failed"`
+	Results  []ImportResult `json:"results"`
+}
+
+// ParseMarkdownFile splits a file into its YAML front matter, delimited by "---" lines at
+// the top of the file, and the markdown body that follows
+func ParseMarkdownFile(data []byte) (*FrontMatter, string, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	rest, ok := bytes.CutPrefix(data, []byte("---\n"))
+	if !ok {
+		return nil, "", ErrNoFrontMatter
+	}
+
+	header, body, ok := bytes.Cut(rest, []byte("\n---\n"))
+	if !ok {
+		// The closing delimiter may be the last line of the file
+		header, ok = bytes.CutSuffix(rest, []byte("\n---"))
+		if !ok {
+			return nil, "", ErrNoFrontMatter
+		}
+		body = nil
+	}
+
+	var meta FrontMatter
+	if err := yaml.Unmarshal(header, &meta); err != nil {
+		return nil, "", err
+	}
+
+	return &meta, string(bytes.TrimSpace(body)), nil
+}
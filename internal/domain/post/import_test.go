@@ -0,0 +1,74 @@
+package post
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseMarkdownFile(t *testing.T) {
+	date := time.Date(2021, 5, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		data     string
+		wantMeta FrontMatter
+		wantBody string
+		wantErr  error // nil with wantFail expects a YAML error
+		wantFail bool
+	}{
+		{
+			name:     "all fields",
+			data:     "---\ntitle: Hello\nslug: hello-world\ndate: 2021-05-04\ntags: [go, web]\nstatus: published\nsummary: Short\n---\n# Hello\n\nBody\n",
+			wantMeta: FrontMatter{Title: "Hello", Slug: "hello-world", Date: &date, Tags: []string{"go", "web"}, Status: "published", Summary: "Short"},
+			wantBody: "# Hello\n\nBody",
+		},
+		{
+			name:     "tags as a list",
+			data:     "---\ntitle: Hello\ntags:\n  - go\n  - Đồ ăn\n---\nBody",
+			wantMeta: FrontMatter{Title: "Hello", Tags: []string{"go", "Đồ ăn"}},
+			wantBody: "Body",
+		},
+		{
+			name:     "windows line endings and byte order mark",
+			data:     "\ufeff---\r\ntitle: Hello\r\n---\r\nBody\r\n",
+			wantMeta: FrontMatter{Title: "Hello"},
+			wantBody: "Body",
+		},
+		{
+			name:     "closing delimiter at the end of the file",
+			data:     "---\ntitle: Hello\n---",
+			wantMeta: FrontMatter{Title: "Hello"},
+		},
+		{name: "no front matter", data: "# Hello\n\nBody", wantErr: ErrNoFrontMatter, wantFail: true},
+		{name: "unclosed front matter", data: "---\ntitle: Hello\nBody", wantErr: ErrNoFrontMatter, wantFail: true},
+		{name: "invalid YAML", data: "---\ntitle: [unclosed\n---\nBody", wantFail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, body, err := ParseMarkdownFile([]byte(tt.data))
+			if tt.wantFail {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if meta.Title != tt.wantMeta.Title || meta.Slug != tt.wantMeta.Slug || meta.Status != tt.wantMeta.Status ||
+				meta.Summary != tt.wantMeta.Summary || !slices.Equal(meta.Tags, tt.wantMeta.Tags) {
+				t.Errorf("expected front matter %+v, got %+v", tt.wantMeta, meta)
+			}
+			if (meta.Date == nil) != (tt.wantMeta.Date == nil) || (meta.Date != nil && !meta.Date.Equal(*tt.wantMeta.Date)) {
+				t.Errorf("expected date %v, got %v", tt.wantMeta.Date, meta.Date)
+			}
+			if body != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
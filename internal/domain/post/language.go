@@ -0,0 +1,34 @@
+package post
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MatchLanguage returns the supported language an Accept-Language header prefers most, or
+// "" when it accepts none of them. Only the primary subtag is compared, so "en-US" matches
+// "en"; ties keep the header's order and a wildcard is ignored.
+func MatchLanguage(header string, supported []string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !slices.Contains(supported, primary) {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = primary, q
+		}
+	}
+	return best
+}
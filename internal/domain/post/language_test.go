@@ -0,0 +1,34 @@
+package post
+
+import "testing"
+
+func TestMatchLanguage(t *testing.T) {
+	supported := []string{"vi", "en"}
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"single", "en", "en"},
+		{"region subtag", "en-US", "en"},
+		{"case insensitive", "EN-gb", "en"},
+		{"first supported in order", "fr, vi, en", "vi"},
+		{"highest quality", "en;q=0.5, vi;q=0.8", "vi"},
+		{"ties keep header order", "en;q=0.8, vi;q=0.8", "en"},
+		{"default quality is 1", "vi;q=0.9, en", "en"},
+		{"unsupported only", "fr, de", ""},
+		{"wildcard ignored", "*", ""},
+		{"malformed quality skipped", "en;q=abc, vi;q=0.1", "vi"},
+		{"zero quality never chosen", "en;q=0", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchLanguage(tt.header, supported); got != tt.want {
+				t.Errorf("MatchLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
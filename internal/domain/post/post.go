@@ -2,65 +2,94 @@ package post
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type Post struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Title       string         `json:"title" gorm:"not null"`
-	Content     string         `json:"content" gorm:"type:text"`
-	Summary     *string        `json:"summary" gorm:"type:text"`
-	Slug        string         `json:"slug" gorm:"uniqueIndex;not null"`
-	Status      string         `json:"status" gorm:"default:'draft'"` // draft, published, archived
-	CategoryID  *uint          `json:"category_id"`
-	AuthorID    uint           `json:"author_id" gorm:"not null"`
-	FeaturedImg *string        `json:"featured_img"`
-	ViewCount   int            `json:"view_count" gorm:"default:0"`
-	IsPublic    bool           `json:"is_public" gorm:"default:true"`
-	PublishedAt *time.Time     `json:"published_at"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	OrgID         *uint          `json:"org_id,omitempty" gorm:"uniqueIndex:idx_posts_org_slug"`
+	Title         string         `json:"title" gorm:"not null"`
+	Content       string         `json:"content" gorm:"type:mediumtext"`
+	ContentFormat string         `json:"content_format" gorm:"size:20;default:'html'"` // markdown or html
+	ContentHTML   *string        `json:"-" gorm:"type:mediumtext"`                     // served HTML, cached on write
+	Language      string         `json:"language" gorm:"size:2;index"`                 // ISO 639-1 code
+	Summary       *string        `json:"summary" gorm:"type:text"`
+	Slug          string         `json:"slug" gorm:"uniqueIndex:idx_posts_org_slug;not null"`
+	Status        string         `json:"status" gorm:"default:'draft'"` // draft, published, archived
+	CategoryID    *uint          `json:"category_id"`
+	AuthorID      uint           `json:"author_id" gorm:"not null"`
+	FeaturedImg   *string        `json:"featured_img"`
+	ViewCount     int            `json:"view_count" gorm:"default:0"`
+	IsPublic      bool           `json:"is_public" gorm:"default:true"`
+	AllowComments bool           `json:"allow_comments" gorm:"default:true"`
+	Flagged       bool           `json:"flagged" gorm:"index"` // contains blocked words, for moderators to review
+	PublishedAt   *time.Time     `json:"published_at"`
+	PublishAt     *time.Time     `json:"publish_at" gorm:"index"` // scheduled publish time for drafts
+	ScheduledBy   *uint          `json:"scheduled_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 type CreatePostRequest struct {
-	Title       string  `json:"title" binding:"required,min=1,max=200"`
-	Content     string  `json:"content" binding:"required"`
-	Summary     *string `json:"summary"`
-	CategoryID  *uint   `json:"category_id"`
-	FeaturedImg *string `json:"featured_img"`
-	IsPublic    *bool   `json:"is_public"`
-	Status      *string `json:"status" binding:"omitempty,oneof=draft published archived"`
+	Title         string     `json:"title" binding:"required,min=1,max=200"`
+	Content       string     `json:"content" binding:"required"`
+	ContentFormat *string    `json:"content_format" binding:"omitempty,oneof=markdown html"`
+	Language      *string    `json:"language" binding:"omitempty,len=2"`
+	Summary       *string    `json:"summary"`
+	CategoryID    *uint      `json:"category_id"`
+	FeaturedImg   *string    `json:"featured_img"`
+	IsPublic      *bool      `json:"is_public"`
+	AllowComments *bool      `json:"allow_comments"`
+	Status        *string    `json:"status" binding:"omitempty,oneof=draft published archived"`
+	PublishAt     *time.Time `json:"publish_at"`
+	Tags          []string   `json:"tags"`
+	// Force skips the check for existing posts with a similar title
+	Force bool `json:"force"`
 }
 
 type UpdatePostRequest struct {
-	Title       *string `json:"title" binding:"omitempty,min=1,max=200"`
-	Content     *string `json:"content"`
-	Summary     *string `json:"summary"`
-	CategoryID  *uint   `json:"category_id"`
-	FeaturedImg *string `json:"featured_img"`
-	IsPublic    *bool   `json:"is_public"`
-	Status      *string `json:"status" binding:"omitempty,oneof=draft published archived"`
+	Title         *string    `json:"title" binding:"omitempty,min=1,max=200"`
+	Content       *string    `json:"content"`
+	ContentFormat *string    `json:"content_format" binding:"omitempty,oneof=markdown html"`
+	Language      *string    `json:"language" binding:"omitempty,len=2"`
+	Summary       *string    `json:"summary"`
+	CategoryID    *uint      `json:"category_id"`
+	FeaturedImg   *string    `json:"featured_img"`
+	IsPublic      *bool      `json:"is_public"`
+	AllowComments *bool      `json:"allow_comments"`
+	Status        *string    `json:"status" binding:"omitempty,oneof=draft published archived"`
+	PublishAt     *time.Time `json:"publish_at"`
+	Tags          *[]string  `json:"tags"` // replaces all tags; an empty list removes them
 }
 
 type PostResponse struct {
-	ID          uint       `json:"id"`
-	Title       string     `json:"title"`
-	Content     string     `json:"content"`
-	Summary     string     `json:"summary"`
-	Slug        string     `json:"slug"`
-	Status      string     `json:"status"`
-	CategoryID  *uint      `json:"category_id"`
-	AuthorID    uint       `json:"author_id"`
-	AuthorName  string     `json:"author_name"`
-	FeaturedImg string     `json:"featured_img"`
-	ViewCount   int        `json:"view_count"`
-	IsPublic    bool       `json:"is_public"`
-	PublishedAt *time.Time `json:"published_at"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID            uint       `json:"id"`
+	Title         string     `json:"title"`
+	Content       string     `json:"content"` // empty for readers who can't edit when the image policy applies
+	ContentFormat string     `json:"content_format"`
+	ContentHTML   string     `json:"content_html,omitempty"` // HTML to serve: rendered markdown, or HTML content rewritten by the image policy
+	Language      string     `json:"language"`
+	Summary       string     `json:"summary"`
+	Slug          string     `json:"slug"`
+	Status        string     `json:"status"`
+	CategoryID    *uint      `json:"category_id"`
+	AuthorID      uint       `json:"author_id"`
+	AuthorName    string     `json:"author_name"`
+	FeaturedImg   string     `json:"featured_img"`
+	ViewCount     int        `json:"view_count"`
+	IsPublic      bool       `json:"is_public"`
+	AllowComments bool       `json:"allow_comments"`
+	Flagged       bool       `json:"flagged,omitempty"`
+	PublishedAt   *time.Time `json:"published_at"`
+	PublishAt     *time.Time `json:"publish_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Tags          []string   `json:"tags"`
+	Snippet       string     `json:"snippet,omitempty"` // highlighted excerpt, only set for search results
 }
 
 type PostsListResponse struct {
@@ -71,12 +100,200 @@ type PostsListResponse struct {
 	TotalPages int            `json:"total_pages"`
 }
 
+// PublishedCursorResponse is one page of the keyset-paginated published listing.
+// NextCursor is the ID of the last post, or null when there are no more posts.
+type PublishedCursorResponse struct {
+	Posts      []PostResponse `json:"posts"`
+	Limit      int            `json:"limit"`
+	NextCursor *uint          `json:"next_cursor"`
+}
+
+// TrashedPostResponse is only returned from trash listings
+type TrashedPostResponse struct {
+	PostResponse
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashedPostsListResponse struct {
+	Posts      []TrashedPostResponse `json:"posts"`
+	Total      int64                 `json:"total"`
+	Page       int                   `json:"page"`
+	Limit      int                   `json:"limit"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+type ScheduledPostResponse struct {
+	ID              uint      `json:"id"`
+	Title           string    `json:"title"`
+	Slug            string    `json:"slug"`
+	AuthorID        uint      `json:"author_id"`
+	AuthorName      string    `json:"author_name"`
+	PublishAt       time.Time `json:"publish_at"`
+	ScheduledBy     *uint     `json:"scheduled_by"`
+	ScheduledByName string    `json:"scheduled_by_name"`
+}
+
+type ScheduledPostsListResponse struct {
+	Posts      []ScheduledPostResponse `json:"posts"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	Limit      int                     `json:"limit"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// ScheduledPublishResult reports a run of the scheduled publisher
+type ScheduledPublishResult struct {
+	Published []uint
+	// Skipped are due drafts left unpublished because they miss publish requirements. They
+	// are unscheduled so later runs don't pick them up again.
+	Skipped []uint
+}
+
+// ErrNotPublishable is returned when a post misses fields required for publishing
+var ErrNotPublishable = errors.New("post is not ready to publish")
+
+// ArchiveMonth is the number of published public posts in one calendar month
+type ArchiveMonth struct {
+	Year  int   `json:"year"`
+	Month int   `json:"month"`
+	Count int64 `json:"count"`
+}
+
+// SlugPreview is the slug a title would get and whether it is currently free
+type SlugPreview struct {
+	Slug      string `json:"slug"`
+	Available bool   `json:"available"`
+}
+
+// DuplicateCandidate is an existing post whose title closely matches a new post's title
+type DuplicateCandidate struct {
+	ID       uint   `json:"id"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+	Status   string `json:"status"`
+	Distance int    `json:"distance"` // edits between the normalized titles
+}
+
+// PostsBySlugRequest lists the slugs of posts to fetch in one request
+type PostsBySlugRequest struct {
+	Slugs []string `json:"slugs" binding:"required,min=1,max=100"`
+}
+
+// PostsBySlugResponse holds the visible posts found, in the order their slugs were requested
+type PostsBySlugResponse struct {
+	Posts []PostResponse `json:"posts"`
+}
+
+// ResetViewsRequest lists the posts whose view counts should be reset
+type ResetViewsRequest struct {
+	PostIDs []uint `json:"post_ids" binding:"required,min=1,max=100"`
+}
+
+// ResetViewsResponse reports how many posts had their view count reset
+type ResetViewsResponse struct {
+	Reset int64 `json:"reset"`
+}
+
+// BulkVisibilityRequest sets is_public on several posts
+type BulkVisibilityRequest struct {
+	PostIDs  []uint `json:"post_ids" binding:"required,min=1,max=100"`
+	IsPublic *bool  `json:"is_public" binding:"required"`
+}
+
+// BulkVisibilityResult is the outcome for one post of a bulk visibility change
+type BulkVisibilityResult struct {
+	PostID  uint   `json:"post_id"`
+	Changed bool   `json:"changed"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BulkVisibilityResponse struct {
+	Changed int                    `json:"changed"`
+	Failed  int                    `json:"failed"`
+	Results []BulkVisibilityResult `json:"results"`
+}
+
+// AuthorStats summarizes an author's posts
+type AuthorStats struct {
+	TotalPosts     int64 `json:"total_posts"`
+	PublishedPosts int64 `json:"published_posts"`
+}
+
+// Rules for the editorial attention worklist
+const (
+	AttentionStaleDrafts = "stale-drafts"
+	AttentionNoViews     = "no-views"
+	AttentionIncomplete  = "incomplete"
+	AttentionFlagged     = "flagged" // contains blocked words
+)
+
+// AttentionFilter selects posts matching an attention rule
+type AttentionFilter struct {
+	Rule string
+	// Before is the cutoff for the stale-drafts and no-views rules
+	Before time.Time
+}
+
+// ScheduledFilter restricts scheduled posts to a publish time range
+type ScheduledFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
 type PostFilter struct {
 	Status     *string `json:"status"`
 	CategoryID *uint   `json:"category_id"`
 	AuthorID   *uint   `json:"author_id"`
 	IsPublic   *bool   `json:"is_public"`
+	Language   *string `json:"language"`
+	Tag        *string `json:"tag"`    // tag slug
 	Search     *string `json:"search"` // Search in title and content
+	// SearchFields limits which columns Search matches; empty means all
+	SearchFields string `json:"search_fields"`
+	// SearchTerms, when set, replaces Search: every term must match, in any order
+	SearchTerms []string `json:"-"`
+	// VisibleTo, when set, limits the results to the posts that viewer may read
+	VisibleTo *Viewer `json:"-"`
+}
+
+// Viewer describes who reads a post listing. Published public posts are readable by
+// everyone, the rest only by their author and collaborators.
+type Viewer struct {
+	UserID uint // 0 for anonymous viewers
+	// PrivatePublished also makes published posts that aren't public readable
+	PrivatePublished bool
+}
+
+// Orders of the published posts listing
+const (
+	SortPublishedAt = "published_at"
+	SortViewCount   = "view_count"
+	SortHot         = "hot" // views weighted by recency
+)
+
+// IsValidSort reports whether sort is an accepted published posts order
+func IsValidSort(sort string) bool {
+	switch sort {
+	case SortPublishedAt, SortViewCount, SortHot:
+		return true
+	}
+	return false
+}
+
+// Columns matched by PostFilter.Search
+const (
+	SearchFieldsTitle   = "title"
+	SearchFieldsContent = "content"
+	SearchFieldsAll     = "all"
+)
+
+// IsValidSearchFields reports whether fields is an accepted search_fields value
+func IsValidSearchFields(fields string) bool {
+	switch fields {
+	case SearchFieldsTitle, SearchFieldsContent, SearchFieldsAll:
+		return true
+	}
+	return false
 }
 
 // Repository interface - Domain layer
@@ -89,7 +306,50 @@ type Repository interface {
 	GetAll(ctx context.Context, filter PostFilter, limit, offset int) ([]*Post, error)
 	GetTotalCount(ctx context.Context, filter PostFilter) (int64, error)
 	GetByAuthor(ctx context.Context, authorID uint, limit, offset int) ([]*Post, error)
+	CountByAuthor(ctx context.Context, authorID uint) (int64, error)
+	CountPublishedByAuthor(ctx context.Context, authorID uint) (int64, error)
 	GetByCategory(ctx context.Context, categoryID uint, limit, offset int) ([]*Post, error)
-	GetPublished(ctx context.Context, limit, offset int) ([]*Post, error)
+	GetAfterID(ctx context.Context, afterID uint, limit int) ([]*Post, error)
+	GetBySlugs(ctx context.Context, slugs []string) ([]*Post, error)
+	GetBySlugForUpdate(ctx context.Context, slug string) (*Post, error)
+	GetByTitleLength(ctx context.Context, minLength, maxLength int, viewer *Viewer, limit int) ([]*Post, error)
+	GetPublished(ctx context.Context, sort, language string, limit, offset int) ([]*Post, error)
+	GetPublishedLastModified(ctx context.Context) (*time.Time, error)
+	GetPublishedAfter(ctx context.Context, cursorID uint, language string, limit int) ([]*Post, error)
+	GetArchiveCounts(ctx context.Context) ([]*ArchiveMonth, error)
+	GetPublishedInMonth(ctx context.Context, year, month, limit, offset int) ([]*Post, error)
+	CountPublishedInMonth(ctx context.Context, year, month int) (int64, error)
 	IncrementViewCount(ctx context.Context, id uint) error
+	Touch(ctx context.Context, id uint) error
+	GetDeleted(ctx context.Context, limit, offset int) ([]*Post, error)
+	GetDeletedCount(ctx context.Context) (int64, error)
+	GetDeletedByAuthor(ctx context.Context, authorID uint, limit, offset int) ([]*Post, error)
+	GetDeletedByAuthorCount(ctx context.Context, authorID uint) (int64, error)
+	GetDeletedByID(ctx context.Context, id uint) (*Post, error)
+	Restore(ctx context.Context, id uint, slug string) error
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+	GetScheduled(ctx context.Context, filter ScheduledFilter, limit, offset int) ([]*Post, error)
+	GetScheduledCount(ctx context.Context, filter ScheduledFilter) (int64, error)
+	ResetViewCounts(ctx context.Context, ids []uint) (int64, error)
+	GetNeedingAttention(ctx context.Context, filter AttentionFilter, limit, offset int) ([]*Post, error)
+	GetNeedingAttentionCount(ctx context.Context, filter AttentionFilter) (int64, error)
+	ArchiveStaleDrafts(ctx context.Context, ids []uint, before time.Time) (int64, error)
+	GetDuePosts(ctx context.Context, now time.Time, limit int) ([]*Post, error)
+	PublishScheduled(ctx context.Context, id uint, now time.Time) (bool, error)
+	Unschedule(ctx context.Context, id uint, now time.Time) error
+	DeleteStaleDrafts(ctx context.Context, ids []uint, before time.Time) (int64, error)
+	SaveCollaborator(ctx context.Context, collaborator *Collaborator) error
+	DeleteCollaborator(ctx context.Context, postID, userID uint) error
+	GetCollaborator(ctx context.Context, postID, userID uint) (*Collaborator, error)
+	GetCollaborators(ctx context.Context, postID uint) ([]*Collaborator, error)
+	GetSharedWith(ctx context.Context, userID uint, limit, offset int) ([]*Post, error)
+	CountSharedWith(ctx context.Context, userID uint) (int64, error)
+	GetChanges(ctx context.Context, filter ChangesFilter, limit int) ([]*Post, error)
+	ReassignAuthor(ctx context.Context, fromUserID, toUserID uint) (int64, error)
+	ReassignCollaborations(ctx context.Context, fromUserID, toUserID uint) (int64, error)
+	UpsertTags(ctx context.Context, names []string) ([]Tag, error)
+	SetPostTags(ctx context.Context, postID uint, tagIDs []uint) error
+	GetTagNames(ctx context.Context, postIDs []uint) (map[uint][]string, error)
+	GetTagUsage(ctx context.Context, slugs []string) ([]TagUsage, error)
+	GetAuthorTags(ctx context.Context, authorID uint, limit int) ([]TagUsage, error)
 }
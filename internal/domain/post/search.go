@@ -0,0 +1,40 @@
+package post
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidSearch = errors.New("invalid search")
+
+// SearchTerms splits a search query into lowercase terms, dropping stop words and
+// duplicates. A query left with no terms, or with a term shorter than minLength
+// characters, is rejected so it can't degrade into a scan of every post.
+func SearchTerms(query string, stopWords []string, minLength int) ([]string, error) {
+	stop := make(map[string]bool, len(stopWords))
+	for _, word := range stopWords {
+		stop[strings.ToLower(word)] = true
+	}
+
+	seen := make(map[string]bool)
+	var terms []string
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if stop[term] || seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		if utf8.RuneCountInString(term) < minLength {
+			return nil, fmt.Errorf("%w: search terms must be at least %d characters, %q is too short", ErrInvalidSearch, minLength, term)
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: query contains only common words, add a more specific term", ErrInvalidSearch)
+	}
+
+	return terms, nil
+}
@@ -0,0 +1,46 @@
+package post
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	stopWords := []string{"và", "của", "The"}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "single term", query: "Golang", want: []string{"golang"}},
+		{name: "stop words dropped", query: "bánh và phở của hà nội", want: []string{"bánh", "phở", "hà", "nội"}},
+		{name: "stop words ignore case", query: "THE gopher", want: []string{"gopher"}},
+		{name: "duplicates dropped", query: "go Go GO lang", want: []string{"go", "lang"}},
+		{name: "extra whitespace", query: "  go \t lang  ", want: []string{"go", "lang"}},
+		{name: "only stop words", query: "và của the", wantErr: true},
+		{name: "term too short", query: "a gopher", wantErr: true},
+		{name: "short stop word is dropped before the length check", query: "và gopher", want: []string{"gopher"}},
+		{name: "length counts characters, not bytes", query: "đồ", want: []string{"đồ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SearchTerms(tt.query, stopWords, 2)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSearch) {
+					t.Errorf("expected %v, got %v (%v)", ErrInvalidSearch, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SearchTerms(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
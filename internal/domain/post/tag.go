@@ -0,0 +1,212 @@
+package post
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"moon/pkg/similarity"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrTooManyTags = errors.New("too many tags")
+	ErrInvalidTag  = errors.New("invalid tag")
+)
+
+// Tag is a label shared by the posts of an organization. Tags are matched by slug, so
+// "Go Lang" and "go-lang" are the same tag; the spelling it was first created with is kept
+// as its name.
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	OrgID     *uint     `json:"org_id,omitempty" gorm:"uniqueIndex:idx_tags_org_slug"`
+	Name      string    `json:"name" gorm:"size:100;not null"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex:idx_tags_org_slug;size:120;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostTag attaches a tag to a post
+type PostTag struct {
+	PostID uint `json:"post_id" gorm:"primaryKey;autoIncrement:false"`
+	TagID  uint `json:"tag_id" gorm:"primaryKey;autoIncrement:false;index"`
+}
+
+func (PostTag) TableName() string {
+	return "post_tags"
+}
+
+// tagSlugMaxLength is the size of the tags.slug column, in characters
+const tagSlugMaxLength = 120
+
+// TagSlug returns the key tags are matched by: the name lowercased in NFC form, with runs
+// of spaces, hyphens and underscores collapsed to a single hyphen. Letters outside ASCII
+// are kept, so "đồ ăn" and "ăn" stay distinct tags.
+func TagSlug(name string) string {
+	words := strings.FieldsFunc(norm.NFC.String(strings.ToLower(name)), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_'
+	})
+	key := strings.Join(words, "-")
+	if utf8.RuneCountInString(key) > tagSlugMaxLength {
+		key = strings.TrimRight(string([]rune(key)[:tagSlugMaxLength]), "-")
+	}
+	return key
+}
+
+// NormalizeTags trims tags, drops empty and case-insensitive duplicates (keeping the
+// first spelling) and validates them against the given limits. Tags may contain
+// letters, digits, spaces, hyphens and underscores.
+func NormalizeTags(tags []string, maxTags, maxLength int) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(tag), " ")
+		if tag == "" {
+			continue
+		}
+
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if maxLength > 0 && utf8.RuneCountInString(tag) > maxLength {
+			return nil, fmt.Errorf("%w: %q exceeds %d characters", ErrInvalidTag, tag, maxLength)
+		}
+
+		for _, r := range tag {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_' {
+				return nil, fmt.Errorf("%w: %q contains %q", ErrInvalidTag, tag, r)
+			}
+		}
+
+		normalized = append(normalized, tag)
+	}
+
+	if maxTags > 0 && len(normalized) > maxTags {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyTags, maxTags)
+	}
+
+	return normalized, nil
+}
+
+var markupPattern = regexp.MustCompile(`<[^>]*>`)
+
+// SuggestTagsRequest carries the draft to suggest tags for; nothing is stored
+type SuggestTagsRequest struct {
+	Title   string `json:"title"`
+	Content string `json:"content" binding:"required"`
+	Limit   int    `json:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// TagSuggestion is a candidate tag and its score: its weighted number of occurrences,
+// boosted when the tag is already in use
+type TagSuggestion struct {
+	Tag   string `json:"tag"`
+	Score int    `json:"score"`
+	// Posts is how many posts already use the tag; 0 for new tags
+	Posts int64 `json:"posts"`
+}
+
+// TagUsage is an existing tag and the number of posts using it
+type TagUsage struct {
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Posts int64  `json:"posts"`
+}
+
+// AuthorTagsResponse lists the tags of an author's published public posts, most used first
+type AuthorTagsResponse struct {
+	AuthorID uint       `json:"author_id"`
+	Tags     []TagUsage `json:"tags"`
+}
+
+type SuggestTagsResponse struct {
+	Tags []TagSuggestion `json:"tags"`
+}
+
+// SuggestTags ranks the words of a post by frequency, title words counting double. Markup,
+// stop words, numbers and words outside [minLength, maxLength] characters are skipped.
+// Ties keep the order in which words first appear.
+func SuggestTags(title, content string, stopWords []string, minLength, maxLength, limit int) []TagSuggestion {
+	stop := make(map[string]bool, len(stopWords))
+	for _, word := range stopWords {
+		stop[strings.ToLower(word)] = true
+	}
+
+	scores := make(map[string]int)
+	var order []string
+	count := func(text string, weight int) {
+		for _, word := range strings.Fields(similarity.Normalize(markupPattern.ReplaceAllString(text, " "))) {
+			length := utf8.RuneCountInString(word)
+			if stop[word] || length < minLength || (maxLength > 0 && length > maxLength) || isNumber(word) {
+				continue
+			}
+			if _, ok := scores[word]; !ok {
+				order = append(order, word)
+			}
+			scores[word] += weight
+		}
+	}
+	count(title, 2)
+	count(content, 1)
+
+	suggestions := make([]TagSuggestion, len(order))
+	for i, word := range order {
+		suggestions[i] = TagSuggestion{Tag: word, Score: scores[word]}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// BoostExistingTags raises suggestions matching an existing tag by how widely it is used:
+// the score is multiplied by one plus the bit length of the tag's post count, so a tag on
+// one post doubles, on three posts triples, and so on. Matches take the existing tag's
+// name. The result is re-ranked and cut to limit.
+func BoostExistingTags(suggestions []TagSuggestion, usage []TagUsage, limit int) []TagSuggestion {
+	bySlug := make(map[string]TagUsage, len(usage))
+	for _, u := range usage {
+		bySlug[u.Slug] = u
+	}
+
+	for i, suggestion := range suggestions {
+		u, ok := bySlug[TagSlug(suggestion.Tag)]
+		if !ok {
+			continue
+		}
+		suggestions[i].Tag = u.Name
+		suggestions[i].Posts = u.Posts
+		suggestions[i].Score *= 1 + bits.Len64(uint64(u.Posts))
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
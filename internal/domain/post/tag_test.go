@@ -0,0 +1,85 @@
+package post
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTagSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{name: "lowercased", tag: "Golang", want: "golang"},
+		{name: "spaces, hyphens and underscores collapsed", tag: " Go  - _Lang ", want: "go-lang"},
+		{name: "vietnamese letters kept", tag: "Đồ Ăn", want: "đồ-ăn"},
+		{name: "single vietnamese word", tag: "đồ", want: "đồ"},
+		{name: "decomposed input composed", tag: "ăn", want: "ăn"},
+		{name: "cyrillic", tag: "Новости", want: "новости"},
+		{name: "cjk", tag: "日本 料理", want: "日本-料理"},
+		{name: "only separators", tag: " - _ ", want: ""},
+		{name: "truncated to the column size", tag: strings.Repeat("ă", 130), want: strings.Repeat("ă", 120)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TagSlug(tt.tag); got != tt.want {
+				t.Errorf("TagSlug(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagSlugKeepsNonLatinTagsDistinct(t *testing.T) {
+	tags := []string{"đồ ăn", "ăn", "đồ", "do", "an", "日本", "中国"}
+
+	seen := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key := TagSlug(tag)
+		if key == "" {
+			t.Errorf("TagSlug(%q) is empty", tag)
+			continue
+		}
+		if other, ok := seen[key]; ok {
+			t.Errorf("TagSlug(%q) = TagSlug(%q) = %q", tag, other, key)
+		}
+		seen[key] = tag
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		tags      []string
+		maxTags   int
+		maxLength int
+		want      []string
+		wantErr   error
+	}{
+		{name: "trimmed and spaces collapsed", tags: []string{"  go ", "web   dev"}, want: []string{"go", "web dev"}},
+		{name: "duplicates dropped, first spelling kept", tags: []string{"Go", "go", "GO"}, want: []string{"Go"}},
+		{name: "empty tags dropped", tags: []string{"", "  ", "go"}, want: []string{"go"}},
+		{name: "letters, digits, hyphens and underscores", tags: []string{"Đồ ăn", "web-dev_2"}, want: []string{"Đồ ăn", "web-dev_2"}},
+		{name: "punctuation rejected", tags: []string{"go!"}, wantErr: ErrInvalidTag},
+		{name: "too long", tags: []string{"golang"}, maxLength: 5, wantErr: ErrInvalidTag},
+		{name: "length counted in characters", tags: []string{"ănăn"}, maxLength: 4, want: []string{"ănăn"}},
+		{name: "too many", tags: []string{"a", "b", "c"}, maxTags: 2, wantErr: ErrTooManyTags},
+		{name: "duplicates do not count towards the limit", tags: []string{"a", "A", "b"}, maxTags: 2, want: []string{"a", "b"}},
+		{name: "no limits", tags: []string{"a", "b", "c"}, want: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTags(tt.tags, tt.maxTags, tt.maxLength)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NormalizeTags(%q) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
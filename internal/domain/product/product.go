@@ -1,16 +1,35 @@
 package product
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"moon/pkg/money"
+	"moon/pkg/slug"
+
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrNotFound is returned when a product does not exist or is hidden from the caller
+	ErrNotFound = errors.New("product not found")
+	// ErrCategoryNotFound is returned when a category does not exist or is inactive
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrInvalidPriceRange   = errors.New("min_price must not exceed max_price")
+	ErrInvalidQuantity     = errors.New("quantity must be positive")
+	ErrInsufficientStock   = errors.New("insufficient stock")
+)
+
 type Product struct {
 	ID          uint           `json:"id" gorm:"primaryKey"`
+	OrgID       *uint          `json:"org_id,omitempty" gorm:"index"`
 	Name        string         `json:"name" gorm:"not null"`
 	Description string         `json:"description"`
-	Price       float64        `json:"price" gorm:"not null"`
+	Price       int64          `json:"price" gorm:"not null"` // in minor units of Currency, e.g. cents
+	Currency    string         `json:"currency" gorm:"size:3;not null"`
 	Stock       int            `json:"stock" gorm:"default:0"`
 	CategoryID  uint           `json:"category_id"`
 	Category    Category       `json:"category" gorm:"foreignKey:CategoryID"`
@@ -22,7 +41,9 @@ type Product struct {
 
 type Category struct {
 	ID          uint           `json:"id" gorm:"primaryKey"`
+	OrgID       *uint          `json:"org_id,omitempty" gorm:"uniqueIndex:idx_categories_org_slug"`
 	Name        string         `json:"name" gorm:"not null"`
+	Slug        string         `json:"slug" gorm:"uniqueIndex:idx_categories_org_slug;size:120"`
 	Description string         `json:"description"`
 	IsActive    bool           `json:"is_active" gorm:"default:true"`
 	CreatedAt   time.Time      `json:"created_at"`
@@ -31,21 +52,67 @@ type Category struct {
 	Products    []Product      `json:"products,omitempty" gorm:"foreignKey:CategoryID"`
 }
 
+// BeforeCreate derives the slug from the name when none is set, appending a counter
+// (-2, -3, ...) until it is unique. Soft-deleted categories keep their slugs reserved, and
+// the check runs outside the tenant scope since slugs are unique across organizations.
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	if c.Slug != "" {
+		return nil
+	}
+
+	categorySlug, err := uniqueCategorySlug(c.Name, func(candidate string) (bool, error) {
+		var count int64
+		lookup := tx.Session(&gorm.Session{NewDB: true, Context: context.Background()})
+		err := lookup.Unscoped().Model(&Category{}).Where("slug = ?", candidate).Count(&count).Error
+		return count > 0, err
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Slug = categorySlug
+	return nil
+}
+
+// uniqueCategorySlug derives a slug from name, appending a counter until taken reports it free
+func uniqueCategorySlug(name string, taken func(candidate string) (bool, error)) (string, error) {
+	base := slug.Generate(name, slug.Options{Separator: "-", Lowercase: true, MaxLength: 100})
+	if base == "" {
+		base = "category"
+	}
+
+	candidate := base
+	for i := 2; ; i++ {
+		exists, err := taken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// CreateProductRequest takes the price in minor units; Currency defaults to the configured
+// default currency
 type CreateProductRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Stock       int     `json:"stock" binding:"gte=0"`
-	CategoryID  uint    `json:"category_id" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Price       int64  `json:"price" binding:"required,gt=0"`
+	Currency    string `json:"currency" binding:"omitempty,len=3"`
+	Stock       int    `json:"stock" binding:"gte=0"`
+	CategoryID  uint   `json:"category_id" binding:"required"`
 }
 
 type UpdateProductRequest struct {
-	Name        *string  `json:"name"`
-	Description *string  `json:"description"`
-	Price       *float64 `json:"price"`
-	Stock       *int     `json:"stock"`
-	CategoryID  *uint    `json:"category_id"`
-	IsActive    *bool    `json:"is_active"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Price       *int64  `json:"price" binding:"omitempty,gt=0"`
+	Currency    *string `json:"currency" binding:"omitempty,len=3"`
+	Stock       *int    `json:"stock" binding:"omitempty,gte=0"`
+	CategoryID  *uint   `json:"category_id"`
+	IsActive    *bool   `json:"is_active"`
 }
 
 type CreateCategoryRequest struct {
@@ -60,23 +127,88 @@ type UpdateCategoryRequest struct {
 }
 
 type ProductResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Stock       int       `json:"stock"`
-	CategoryID  uint      `json:"category_id"`
-	Category    Category  `json:"category"`
-	IsActive    bool      `json:"is_active"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             uint      `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	Price          int64     `json:"price"` // minor units
+	Currency       string    `json:"currency"`
+	PriceFormatted string    `json:"price_formatted"`
+	Stock          int       `json:"stock"`
+	CategoryID     uint      `json:"category_id"`
+	Category       Category  `json:"category"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ToResponse maps a product to its API representation, formatting the price for display
+func (p *Product) ToResponse() ProductResponse {
+	return ProductResponse{
+		ID:             p.ID,
+		Name:           p.Name,
+		Description:    p.Description,
+		Price:          p.Price,
+		Currency:       p.Currency,
+		PriceFormatted: money.Format(p.Price, p.Currency),
+		Stock:          p.Stock,
+		CategoryID:     p.CategoryID,
+		Category:       p.Category,
+		IsActive:       p.IsActive,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+type ProductsListResponse struct {
+	Products   []ProductResponse `json:"products"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// ProductFilter narrows product listings; prices are in minor units and only comparable
+// within a single currency
+type ProductFilter struct {
+	CategoryID *uint   `json:"category_id"`
+	Currency   *string `json:"currency"`
+	MinPrice   *int64  `json:"min_price"`
+	MaxPrice   *int64  `json:"max_price"`
+	IsActive   *bool   `json:"is_active"`
 }
 
 type CategoryResponse struct {
 	ID          uint      `json:"id"`
 	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
 	Description string    `json:"description"`
 	IsActive    bool      `json:"is_active"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+type CategoryWithPostCount struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	PostCount   int64  `json:"post_count"`
+}
+
+// Repository interface - Domain layer
+type Repository interface {
+	Create(ctx context.Context, product *Product) error
+	GetByID(ctx context.Context, id uint) (*Product, error)
+	Update(ctx context.Context, product *Product) error
+	Delete(ctx context.Context, id uint) error
+	DecrementStock(ctx context.Context, id uint, qty int) (bool, error)
+	GetAll(ctx context.Context, filter ProductFilter, limit, offset int) ([]*Product, error)
+	GetTotalCount(ctx context.Context, filter ProductFilter) (int64, error)
+}
+
+// CategoryRepository interface - Domain layer
+type CategoryRepository interface {
+	GetByID(ctx context.Context, id uint) (*Category, error)
+	GetBySlug(ctx context.Context, slug string) (*Category, error)
+	GetWithPostCounts(ctx context.Context, nonEmpty bool) ([]*CategoryWithPostCount, error)
+}
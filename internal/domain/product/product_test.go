@@ -0,0 +1,53 @@
+package product
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestUniqueCategorySlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		taken []string
+		want  string
+	}{
+		{"free", "Go Lang", nil, "go-lang"},
+		{"taken", "Go Lang", []string{"go-lang"}, "go-lang-2"},
+		{"counter taken", "Go Lang", []string{"go-lang", "go-lang-2"}, "go-lang-3"},
+		{"only others taken", "News", []string{"news-2", "sports"}, "news"},
+		{"nothing to slug", "!!!", nil, "category"},
+		{"fallback taken", "", []string{"category"}, "category-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uniqueCategorySlug(tt.input, func(candidate string) (bool, error) {
+				return slices.Contains(tt.taken, candidate), nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUniqueCategorySlugLookupError(t *testing.T) {
+	failure := errors.New("connection lost")
+	if _, err := uniqueCategorySlug("News", func(string) (bool, error) { return false, failure }); !errors.Is(err, failure) {
+		t.Errorf("expected %v, got %v", failure, err)
+	}
+}
+
+func TestToResponsePrice(t *testing.T) {
+	p := &Product{ID: 1, Name: "Mug", Price: 1999, Currency: "USD"}
+
+	resp := p.ToResponse()
+	if resp.Price != 1999 || resp.Currency != "USD" || resp.PriceFormatted != "$19.99" {
+		t.Errorf("expected the raw amount, its currency and the formatted price, got %+v", resp)
+	}
+}
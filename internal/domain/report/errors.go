@@ -0,0 +1,10 @@
+package report
+
+import "errors"
+
+// Errors returned by the report use case
+var (
+	ErrNotFound       = errors.New("report not found")
+	ErrTooManyReports = errors.New("too many reports")
+	ErrUnavailable    = errors.New("reporting temporarily unavailable")
+)
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"context"
+	"time"
+)
+
+type Report struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	OrgID      *uint      `json:"org_id,omitempty" gorm:"index"`
+	TargetType string     `json:"target_type" gorm:"not null;uniqueIndex:idx_reports_reporter_target"` // post
+	TargetID   uint       `json:"target_id" gorm:"not null;uniqueIndex:idx_reports_reporter_target"`
+	ReporterID uint       `json:"reporter_id" gorm:"not null;uniqueIndex:idx_reports_reporter_target"`
+	Reason     string     `json:"reason" gorm:"type:text;not null"`
+	Status     string     `json:"status" gorm:"default:'open';index"` // open, reviewed, dismissed
+	ReviewedBy *uint      `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type CreateReportRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=1000"`
+}
+
+type UpdateReportStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=open reviewed dismissed"`
+}
+
+type ReportsListResponse struct {
+	Reports    []Report `json:"reports"`
+	Total      int64    `json:"total"`
+	Page       int      `json:"page"`
+	Limit      int      `json:"limit"`
+	TotalPages int      `json:"total_pages"`
+}
+
+type ReportFilter struct {
+	Status     *string `json:"status"`
+	TargetType *string `json:"target_type"`
+}
+
+// Repository interface - Domain layer
+type Repository interface {
+	Create(ctx context.Context, report *Report) error
+	GetByID(ctx context.Context, id uint) (*Report, error)
+	GetByReporterAndTarget(ctx context.Context, reporterID uint, targetType string, targetID uint) (*Report, error)
+	Update(ctx context.Context, report *Report) error
+	GetAll(ctx context.Context, filter ReportFilter, limit, offset int) ([]*Report, error)
+	GetTotalCount(ctx context.Context, filter ReportFilter) (int64, error)
+	ReassignReporter(ctx context.Context, fromUserID, toUserID uint) (int64, error)
+}
@@ -0,0 +1,24 @@
+package transaction
+
+import (
+	"context"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+	"moon/internal/domain/report"
+	"moon/internal/domain/user"
+)
+
+// Repositories holds repository instances bound to a single transaction
+type Repositories struct {
+	Users      user.Repository
+	Posts      post.Repository
+	Reports    report.Repository
+	Categories product.CategoryRepository
+}
+
+// Transactor runs a function atomically. Every write made through the given repositories
+// is committed together when fn returns nil and rolled back when it returns an error.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
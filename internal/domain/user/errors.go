@@ -0,0 +1,27 @@
+package user
+
+import "errors"
+
+// Errors returned by the user and auth use cases
+var (
+	ErrNotFound           = errors.New("user not found")
+	ErrEmailExists        = errors.New("user with this email already exists")
+	ErrEmailInUse         = errors.New("email already in use")
+	ErrUsernameInUse      = errors.New("username already in use")
+	ErrSameEmail          = errors.New("new email must differ from the current email")
+	ErrWrongPassword      = errors.New("current password is incorrect")
+	ErrSamePassword       = errors.New("new password must differ from the current password")
+	ErrInvalidUsername    = errors.New("invalid username")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrIdentifierRequired = errors.New("email or username is required")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrInvalidRefresh     = errors.New("invalid or expired refresh token")
+	ErrDeactivated        = errors.New("user account is deactivated")
+	ErrPendingApproval    = errors.New("account pending approval")
+	ErrNotPendingApproval = errors.New("user is not pending approval")
+	ErrMergeSameUser      = errors.New("cannot merge a user into itself")
+	ErrLastAdmin          = errors.New("cannot merge away the last admin")
+	ErrSessionLimit       = errors.New("active session limit reached")
+)
@@ -0,0 +1,55 @@
+package user
+
+// Permissions exposed to clients so they can render role-aware UI
+const (
+	PermissionProfileRead    = "profile:read"
+	PermissionPostsCreate    = "posts:create"
+	PermissionPostsRead      = "posts:read"
+	PermissionPostsUpdateOwn = "posts:update:own"
+	PermissionPostsDeleteOwn = "posts:delete:own"
+	PermissionPostsUpdateAny = "posts:update:any"
+	PermissionPostsDeleteAny = "posts:delete:any"
+	PermissionUsersRead      = "users:read"
+	PermissionUsersUpdate    = "users:update"
+	PermissionUsersDelete    = "users:delete"
+	PermissionUsersLogout    = "users:logout"
+)
+
+var userPermissions = []string{
+	PermissionProfileRead,
+	PermissionPostsCreate,
+	PermissionPostsRead,
+	PermissionPostsUpdateOwn,
+	PermissionPostsDeleteOwn,
+}
+
+// Editors hold the user permissions for now; the role exists so it can be assigned
+var editorPermissions = append([]string{}, userPermissions...)
+
+var adminPermissions = append(append([]string{}, userPermissions...),
+	PermissionPostsUpdateAny,
+	PermissionPostsDeleteAny,
+	PermissionUsersRead,
+	PermissionUsersUpdate,
+	PermissionUsersDelete,
+	PermissionUsersLogout,
+)
+
+// PermissionsForRole returns the effective permission set of a role
+func PermissionsForRole(role string) []string {
+	switch role {
+	case RoleAdmin:
+		return append([]string{}, adminPermissions...)
+	case RoleEditor:
+		return append([]string{}, editorPermissions...)
+	case RoleUser:
+		return append([]string{}, userPermissions...)
+	default:
+		return []string{}
+	}
+}
+
+type PermissionsResponse struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
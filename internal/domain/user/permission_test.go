@@ -0,0 +1,54 @@
+package user
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPermissionsForRole(t *testing.T) {
+	tests := []struct {
+		role     string
+		includes []string
+		excludes []string
+	}{
+		{RoleUser, []string{PermissionProfileRead, PermissionPostsCreate, PermissionPostsUpdateOwn}, []string{PermissionPostsUpdateAny, PermissionUsersRead}},
+		{RoleEditor, []string{PermissionPostsUpdateOwn, PermissionPostsDeleteOwn}, []string{PermissionPostsUpdateAny, PermissionPostsDeleteAny, PermissionUsersRead}},
+		{RoleAdmin, []string{PermissionPostsDeleteAny, PermissionUsersRead, PermissionUsersUpdate, PermissionUsersDelete, PermissionUsersLogout}, nil},
+		{"unknown", nil, []string{PermissionProfileRead}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			got := PermissionsForRole(tt.role)
+			for _, p := range tt.includes {
+				if !slices.Contains(got, p) {
+					t.Errorf("expected %s to have %s, got %v", tt.role, p, got)
+				}
+			}
+			for _, p := range tt.excludes {
+				if slices.Contains(got, p) {
+					t.Errorf("expected %s not to have %s", tt.role, p)
+				}
+			}
+		})
+	}
+
+	// Each role has every permission of the roles below it
+	for _, p := range PermissionsForRole(RoleUser) {
+		if !slices.Contains(PermissionsForRole(RoleEditor), p) {
+			t.Errorf("expected editors to have the user permission %s", p)
+		}
+	}
+	for _, p := range PermissionsForRole(RoleEditor) {
+		if !slices.Contains(PermissionsForRole(RoleAdmin), p) {
+			t.Errorf("expected admins to have the editor permission %s", p)
+		}
+	}
+}
+
+func TestPermissionsForRoleReturnsACopy(t *testing.T) {
+	PermissionsForRole(RoleUser)[0] = "tampered"
+	if PermissionsForRole(RoleUser)[0] == "tampered" {
+		t.Error("expected callers not to be able to change a role's permissions")
+	}
+}
@@ -0,0 +1,29 @@
+package user
+
+import "time"
+
+// RefreshToken is a long-lived token exchanged for a new access token. Each one is used
+// once: refreshing revokes it and issues a replacement.
+type RefreshToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	TokenHash string `gorm:"size:64;uniqueIndex;not null"` // SHA-256 of the token; the token itself is never stored
+	// TokenVersion is the user's token version at issue, so revoking a user's tokens also
+	// invalidates their refresh tokens
+	TokenVersion int       `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	Revoked      bool      `gorm:"not null;default:false"`
+	CreatedAt    time.Time
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
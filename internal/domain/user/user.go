@@ -2,25 +2,58 @@ package user
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+const (
+	RoleUser   = "user"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// NormalizeRole lowercases a role and reports whether it is a known role
+func NormalizeRole(role string) (string, bool) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	switch role {
+	case RoleUser, RoleEditor, RoleAdmin:
+		return role, true
+	default:
+		return "", false
+	}
+}
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]{3,30}$`)
+
+// IsValidUsername reports whether username is 3-30 letters, digits or underscores
+func IsValidUsername(username string) bool {
+	return usernamePattern.MatchString(username)
+}
+
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"-" gorm:"not null"`
-	Name      string         `json:"name" gorm:"not null"`
-	Phone     *string        `json:"phone" gorm:"not null"`
-	Address   *string        `json:"address" gorm:"not null"`
-	Lat       *float64       `json:"lat" gorm:"not null"`
-	Lng       *float64       `json:"lng" gorm:"not null"`
-	Role      string         `json:"role" gorm:"default:'user'"`
-	IsActive  bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                   uint           `json:"id" gorm:"primaryKey"`
+	OrgID                *uint          `json:"org_id,omitempty" gorm:"uniqueIndex:idx_users_org_email;uniqueIndex:idx_users_org_username"`
+	Email                string         `json:"email" gorm:"uniqueIndex:idx_users_org_email;not null"`
+	Username             *string        `json:"username" gorm:"uniqueIndex:idx_users_org_username;size:30"`
+	Password             string         `json:"-" gorm:"not null"`
+	Name                 string         `json:"name" gorm:"not null"`
+	Phone                *string        `json:"phone" gorm:"not null"`
+	Address              *string        `json:"address" gorm:"not null"`
+	Lat                  *float64       `json:"lat" gorm:"not null"`
+	Lng                  *float64       `json:"lng" gorm:"not null"`
+	Role                 string         `json:"role" gorm:"default:'user'"`
+	IsActive             bool           `json:"is_active" gorm:"default:true"`
+	PendingApproval      bool           `json:"pending_approval" gorm:"default:false;index"` // registered while approval was required
+	TokenVersion         int            `json:"-" gorm:"default:0"`                          // bumped to revoke all issued tokens
+	PendingEmail         *string        `json:"-"`                                           // applied once the new address is confirmed
+	EmailChangeTokenHash *string        `json:"-" gorm:"index;size:64"`
+	EmailChangeExpiresAt *time.Time     `json:"-"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 type CreateUserRequest struct {
@@ -39,28 +72,57 @@ type UpdateUserRequest struct {
 	Role     string  `json:"role"`
 }
 
+type ChangeEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// LoginRequest identifies the user by Identifier (email or username) or, for older
+// clients, by Email
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Identifier string `json:"identifier"`
+	Email      string `json:"email" binding:"omitempty,email"`
+	Password   string `json:"password" binding:"required"`
+}
+
+// UpdateProfileRequest holds the fields users may change on their own profile
+type UpdateProfileRequest struct {
+	Name     *string  `json:"name" binding:"omitempty,min=1"`
+	Username *string  `json:"username"`
+	Phone    *string  `json:"phone"`
+	Address  *string  `json:"address"`
+	Lat      *float64 `json:"lat"`
+	Lng      *float64 `json:"lng"`
 }
 
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	User         UserResponse `json:"user"`
+	// EvictedSessions is how many of the user's oldest sessions the login signed out to
+	// stay within the session cap
+	EvictedSessions int `json:"evicted_sessions,omitempty"`
 }
 
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Phone     string    `json:"phone"`
-	Address   string    `json:"address"`
-	Lat       float64   `json:"lat"`
-	Lng       float64   `json:"lng"`
-	Role      string    `json:"role"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       uint    `json:"id"`
+	Email    string  `json:"email"`
+	Username string  `json:"username,omitempty"`
+	Name     string  `json:"name"`
+	Phone    string  `json:"phone"`
+	Address  string  `json:"address"`
+	Lat      float64 `json:"lat"`
+	Lng      float64 `json:"lng"`
+	Role     string  `json:"role"`
+	IsActive bool    `json:"is_active"`
+	// PendingApproval is set until an admin approves the registration
+	PendingApproval bool      `json:"pending_approval,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 type UsersListResponse struct {
@@ -72,23 +134,109 @@ type UsersListResponse struct {
 }
 
 type AdminUpdateUserRequest struct {
+	Email    *string  `json:"email" binding:"omitempty,email"`
 	Name     *string  `json:"name"`
 	Phone    *string  `json:"phone"`
 	Address  *string  `json:"address"`
 	Lat      *float64 `json:"lat"`
 	Lng      *float64 `json:"lng"`
 	IsActive *bool    `json:"is_active"`
-	Role     *string  `json:"role" binding:"omitempty,oneof=user admin"`
+	Role     *string  `json:"role"`
+}
+
+// Notification categories users can opt out of
+const (
+	NotifyCommentReplies = "comment_replies"
+	NotifyPostPublished  = "post_published"
+	NotifyMarketing      = "marketing"
+)
+
+// NotificationPreferences records which optional emails a user receives. Users without a
+// stored row get DefaultNotificationPreferences.
+type NotificationPreferences struct {
+	UserID         uint      `json:"-" gorm:"primaryKey;autoIncrement:false"`
+	CommentReplies bool      `json:"comment_replies" gorm:"not null"`
+	PostPublished  bool      `json:"post_published" gorm:"not null"`
+	Marketing      bool      `json:"marketing" gorm:"not null"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// DefaultNotificationPreferences opts users into activity emails and out of marketing
+func DefaultNotificationPreferences(userID uint) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:         userID,
+		CommentReplies: true,
+		PostPublished:  true,
+		Marketing:      false,
+	}
+}
+
+// Allows reports whether the user wants emails of the given category. Unknown categories
+// are transactional (e.g. security notices) and always allowed.
+func (p *NotificationPreferences) Allows(category string) bool {
+	switch category {
+	case NotifyCommentReplies:
+		return p.CommentReplies
+	case NotifyPostPublished:
+		return p.PostPublished
+	case NotifyMarketing:
+		return p.Marketing
+	default:
+		return true
+	}
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	CommentReplies *bool `json:"comment_replies"`
+	PostPublished  *bool `json:"post_published"`
+	Marketing      *bool `json:"marketing"`
+}
+
+// NamesRequest lists the users whose display names should be looked up
+type NamesRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=100"`
+}
+
+// MergeUsersRequest folds the duplicate account SourceID into TargetID
+type MergeUsersRequest struct {
+	SourceID uint `json:"source_id" binding:"required"`
+	TargetID uint `json:"target_id" binding:"required"`
+}
+
+// MergeUsersResponse reports how much of the source account moved to the target
+type MergeUsersResponse struct {
+	SourceID            uint  `json:"source_id"`
+	TargetID            uint  `json:"target_id"`
+	PostsMoved          int64 `json:"posts_moved"`
+	CollaborationsMoved int64 `json:"collaborations_moved"`
+	ReportsMoved        int64 `json:"reports_moved"`
 }
 
 // Repository interface - Domain layer
 type Repository interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, id uint) (*User, error)
+	GetByIDForUpdate(ctx context.Context, id uint) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]*User, error)
+	GetAfterID(ctx context.Context, afterID uint, limit int) ([]*User, error)
+	GetNotificationPreferences(ctx context.Context, userID uint) (*NotificationPreferences, error)
+	SaveNotificationPreferences(ctx context.Context, prefs *NotificationPreferences) error
+	GetByEmailChangeToken(ctx context.Context, tokenHash string) (*User, error)
 	Update(ctx context.Context, user *User) error
 	Delete(ctx context.Context, id uint) error
 	GetAll(ctx context.Context, limit, offset int) ([]*User, error)
+	GetPendingApproval(ctx context.Context, limit, offset int) ([]*User, error)
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id uint) (bool, error)
+	RevokeRefreshTokens(ctx context.Context, userID uint) error
+	GetActiveRefreshTokens(ctx context.Context, userID uint, tokenVersion int, now time.Time) ([]*RefreshToken, error)
+	CountPendingApproval(ctx context.Context) (int64, error)
 	GetTotalCount(ctx context.Context) (int64, error)
 	GetByRole(ctx context.Context, role string, limit, offset int) ([]*User, error)
+	GetCountByRole(ctx context.Context, role string) (int64, error)
+	IncrementTokenVersion(ctx context.Context, id uint) error
+	CountByActive(ctx context.Context) (map[bool]int64, error)
 }
@@ -0,0 +1,26 @@
+package user
+
+import "testing"
+
+func TestNormalizeRole(t *testing.T) {
+	tests := []struct {
+		role   string
+		want   string
+		wantOK bool
+	}{
+		{"user", RoleUser, true},
+		{"Editor", RoleEditor, true},
+		{" ADMIN ", RoleAdmin, true},
+		{"superuser", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			got, ok := NormalizeRole(tt.role)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("NormalizeRole(%q) = %q, %v, want %q, %v", tt.role, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
+	"moon/internal/ctxutil"
 	"moon/internal/domain/user"
+	"moon/internal/handler/response"
 	"moon/internal/usecase"
 	"moon/pkg/logger"
 
@@ -50,19 +53,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	userResponse, err := h.authUseCase.Register(c.Request.Context(), req)
 	if err != nil {
 		h.logger.Error("Registration failed", zap.Error(err), zap.String("email", req.Email))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "user with this email already exists" {
-			statusCode = http.StatusConflict
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
-		})
+		response.FromError(c, err)
 		return
 	}
 
 	h.logger.Info("User registered successfully", zap.String("email", req.Email), zap.Uint("user_id", userResponse.ID))
+	message := "User registered successfully"
+	if userResponse.PendingApproval {
+		message = "User registered successfully, pending admin approval"
+	}
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "User registered successfully",
+		"message": message,
 		"data":    userResponse,
 	})
 }
@@ -77,6 +78,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success 200 {object} user.LoginResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -92,35 +94,233 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	loginResponse, err := h.authUseCase.Login(c.Request.Context(), req)
 	if err != nil {
-		h.logger.Error("Login failed", zap.Error(err), zap.String("email", req.Email))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "invalid email or password" || err.Error() == "user account is deactivated" {
-			statusCode = http.StatusUnauthorized
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
-		})
+		h.logger.Error("Login failed", zap.Error(err), zap.String("identifier", req.Identifier), zap.String("email", req.Email))
+		response.FromError(c, err)
 		return
 	}
 
-	h.logger.Info("User logged in successfully", zap.String("email", req.Email), zap.Uint("user_id", loginResponse.User.ID))
+	h.logger.Info("User logged in successfully", zap.String("email", loginResponse.User.Email), zap.Uint("user_id", loginResponse.User.ID))
+	if loginResponse.EvictedSessions > 0 {
+		h.logger.Info("Signed out oldest sessions over the session cap", zap.Uint("user_id", loginResponse.User.ID), zap.Int("evicted", loginResponse.EvictedSessions))
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Login successful",
 		"data":    loginResponse,
 	})
 }
 
-// RefreshToken handles token refresh (optional - can be implemented later)
+// RequestEmailChange handles starting an email change for the current user
+// @Summary Request email change
+// @Description Send a confirmation link to a new email address; the current email stays active until it is confirmed
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body user.ChangeEmailRequest true "New email"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/email [post]
+func (h *AuthHandler) RequestEmailChange(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	var req user.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	err := h.authUseCase.RequestEmailChange(c.Request.Context(), userID, req)
+	if err != nil {
+		h.logger.Error("Failed to request email change", zap.Error(err), zap.Uint("user_id", userID))
+		// The token's user no longer exists, so the caller is not authenticated
+		if errors.Is(err, user.ErrNotFound) {
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Email change requested", zap.Uint("user_id", userID))
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Confirmation link sent to the new email address",
+	})
+}
+
+// ChangePassword handles changing the current user's password
+// @Summary Change password
+// @Description Change the password after verifying the current one; signs the user out of all sessions
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body user.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/password [patch]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	var req user.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	err := h.authUseCase.ChangePassword(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		h.logger.Error("Failed to change password", zap.Error(err), zap.Uint("user_id", userID))
+		// The token's user no longer exists, so the caller is not authenticated
+		if errors.Is(err, user.ErrNotFound) {
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Password changed", zap.Uint("user_id", userID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password changed successfully, please log in again",
+	})
+}
+
+// ConfirmEmail handles confirming a pending email change
+// @Summary Confirm email change
+// @Description Apply a pending email change and sign the user out of all sessions
+// @Tags auth
+// @Produce json
+// @Param token query string true "Confirmation token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/confirm-email [get]
+func (h *AuthHandler) ConfirmEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Token is required",
+		})
+		return
+	}
+
+	err := h.authUseCase.ConfirmEmailChange(c.Request.Context(), token)
+	if err != nil {
+		h.logger.Error("Failed to confirm email change", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Email change confirmed")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email updated successfully, please log in again",
+	})
+}
+
+// RefreshToken handles exchanging a refresh token for new tokens
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and a new refresh token. The presented refresh token is revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body user.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} user.LoginResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	// TODO: Implement refresh token logic
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"error": "Refresh token not implemented yet",
+	var req user.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	loginResponse, err := h.authUseCase.RefreshToken(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		h.logger.Error("Token refresh failed", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Tokens refreshed", zap.Uint("user_id", loginResponse.User.ID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Token refreshed successfully",
+		"data":    loginResponse,
 	})
 }
 
-// Logout handles user logout (optional - for token blacklisting)
+// Logout handles revoking the access token of the request and its refresh token
+// @Summary Logout user
+// @Description Revoke the current access token until it expires, and the refresh token of the session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body user.LogoutRequest true "Refresh token of the session"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// TODO: Implement logout logic (token blacklisting)
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	var req user.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	err := h.authUseCase.Logout(c.Request.Context(), userID, ctxutil.TokenID(c), ctxutil.TokenExpiresAt(c), req.RefreshToken)
+	if err != nil {
+		h.logger.Error("Logout failed", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("User logged out", zap.Uint("user_id", userID))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Logged out successfully",
 	})
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+
+	"moon/internal/handler/response"
+	"moon/internal/usecase"
+	"moon/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type CategoryHandler struct {
+	categoryUseCase usecase.CategoryUseCase
+	logger          *zap.Logger
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryUseCase usecase.CategoryUseCase) *CategoryHandler {
+	return &CategoryHandler{
+		categoryUseCase: categoryUseCase,
+		logger:          logger.GetLogger(),
+	}
+}
+
+// GetCategoriesWithPostCounts handles listing categories with their published post counts
+// @Summary Get categories with post counts
+// @Description Get active categories with the number of published public posts in each
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param non_empty query bool false "Exclude categories without published posts"
+// @Success 200 {array} product.CategoryWithPostCount
+// @Failure 500 {object} map[string]interface{}
+// @Router /categories/with-post-counts [get]
+func (h *CategoryHandler) GetCategoriesWithPostCounts(c *gin.Context) {
+	nonEmpty := c.DefaultQuery("non_empty", "false") == "true"
+
+	categories, err := h.categoryUseCase.GetCategoriesWithPostCounts(c.Request.Context(), nonEmpty)
+	if err != nil {
+		h.logger.Error("Failed to get categories with post counts", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved categories with post counts", zap.Int("count", len(categories)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Categories retrieved successfully",
+		"data":    categories,
+	})
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+
+	"moon/internal/handler/response"
+	"moon/internal/usecase"
+	"moon/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DashboardHandler struct {
+	dashboardUseCase usecase.DashboardUseCase
+	logger           *zap.Logger
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(dashboardUseCase usecase.DashboardUseCase) *DashboardHandler {
+	return &DashboardHandler{
+		dashboardUseCase: dashboardUseCase,
+		logger:           logger.GetLogger(),
+	}
+}
+
+// GetSummary handles getting the admin dashboard summary
+// @Summary Get dashboard summary
+// @Description Get aggregated user and post statistics for the admin dashboard (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} dashboard.SummaryResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/dashboard [get]
+func (h *DashboardHandler) GetSummary(c *gin.Context) {
+	summary, err := h.dashboardUseCase.GetSummary(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to get dashboard summary", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved dashboard summary")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dashboard summary retrieved successfully",
+		"data":    summary,
+	})
+}
@@ -0,0 +1,13 @@
+package http
+
+import (
+	"moon/internal/handler/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorResponse builds the JSON body for a failed request whose status is decided by
+// the handler; use response.FromError when the status should follow the domain error.
+func errorResponse(err error) gin.H {
+	return response.ErrorBody(err)
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"strconv"
+
+	"moon/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// paginationParams reads page and limit from the query string. Without a valid limit, the
+// endpoint's configured default applies, falling back to the global default; limits above
+// the configured maximum are clamped to it. An invalid page reads as the first page.
+func paginationParams(c *gin.Context, endpoint string) (int, int) {
+	cfg := config.GetConfig().Pagination
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := cfg.DefaultLimit
+	if endpointLimit, ok := cfg.Endpoints[endpoint]; ok {
+		limit = endpointLimit
+	}
+	if requested, err := strconv.Atoi(c.Query("limit")); err == nil && requested > 0 {
+		limit = requested
+	}
+	if cfg.MaxLimit > 0 && limit > cfg.MaxLimit {
+		limit = cfg.MaxLimit
+	}
+
+	return page, limit
+}
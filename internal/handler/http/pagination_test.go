@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/report"
+	"moon/internal/domain/user"
+	"moon/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPaginationParams(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	pagination := &config.GetConfig().Pagination
+	saved := *pagination
+	t.Cleanup(func() { *pagination = saved })
+	pagination.DefaultLimit = 10
+	pagination.MaxLimit = 100
+	pagination.Endpoints = map[string]int{"reports": 50}
+
+	tests := []struct {
+		name      string
+		endpoint  string
+		query     string
+		wantPage  int
+		wantLimit int
+	}{
+		{"global default", "users", "", 1, 10},
+		{"endpoint default", "reports", "", 1, 50},
+		{"explicit limit", "reports", "?page=3&limit=5", 3, 5},
+		{"limit above the maximum", "users", "?limit=500", 1, 100},
+		{"non-numeric limit", "reports", "?limit=abc", 1, 50},
+		{"zero limit", "users", "?limit=0", 1, 10},
+		{"negative limit", "users", "?limit=-5", 1, 10},
+		{"non-numeric page", "users", "?page=two", 1, 10},
+		{"negative page", "users", "?page=-1", 1, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var page, limit int
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				page, limit = paginationParams(c, tt.endpoint)
+			})
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/"+tt.query, nil))
+
+			if page != tt.wantPage || limit != tt.wantLimit {
+				t.Errorf("expected page %d limit %d, got page %d limit %d", tt.wantPage, tt.wantLimit, page, limit)
+			}
+		})
+	}
+}
+
+// limitRecorder records the limit each listing use case is called with
+type limitRecorder struct {
+	usecase.UserUseCase
+	usecase.ReportUseCase
+	limit int
+}
+
+func (r *limitRecorder) GetAllUsers(ctx context.Context, page, limit int) (*user.UsersListResponse, error) {
+	r.limit = limit
+	return &user.UsersListResponse{}, nil
+}
+
+func (r *limitRecorder) GetReports(ctx context.Context, filter report.ReportFilter, page, limit int) (*report.ReportsListResponse, error) {
+	r.limit = limit
+	return &report.ReportsListResponse{}, nil
+}
+
+// The handlers pass their endpoint name, so each gets the default configured for it
+func TestEndpointPaginationDefaults(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	pagination := config.GetConfig().Pagination
+
+	tests := []struct {
+		name    string
+		handler func(uc *limitRecorder) gin.HandlerFunc
+		want    int
+	}{
+		{"reports", func(uc *limitRecorder) gin.HandlerFunc { return NewReportHandler(uc).GetReports }, pagination.Endpoints["reports"]},
+		{"users", func(uc *limitRecorder) gin.HandlerFunc { return NewUserHandler(uc).GetAllUsers }, pagination.DefaultLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want == 0 {
+				t.Fatal("expected a configured default")
+			}
+
+			uc := &limitRecorder{}
+			router := gin.New()
+			router.GET("/", tt.handler(uc))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if uc.limit != tt.want {
+				t.Errorf("expected limit %d, got %d", tt.want, uc.limit)
+			}
+		})
+	}
+}
@@ -1,10 +1,22 @@
 package http
 
 import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
+	"moon/internal/config"
+	"moon/internal/ctxutil"
 	"moon/internal/domain/post"
+	"moon/internal/handler/response"
 	"moon/internal/usecase"
 	"moon/pkg/logger"
 
@@ -27,7 +39,7 @@ func NewPostHandler(postUseCase usecase.PostUseCase) *PostHandler {
 
 // CreatePost handles creating a new post
 // @Summary Create a new post
-// @Description Create a new post (authenticated users)
+// @Description Create a new post (authenticated users). Existing posts with a closely matching title are listed under possible_duplicates; force skips the check.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -35,6 +47,7 @@ func NewPostHandler(postUseCase usecase.PostUseCase) *PostHandler {
 // @Success 201 {object} post.PostResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /posts [post]
 func (h *PostHandler) CreatePost(c *gin.Context) {
@@ -48,8 +61,8 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
 		h.logger.Error("User ID not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
@@ -57,20 +70,38 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
-	postResponse, err := h.postUseCase.CreatePost(c.Request.Context(), req, userID.(uint))
+	userRole, _ := ctxutil.Role(c)
+
+	// Look for similar titles before creating, so the new post doesn't match itself. The
+	// check only warns: the post is created either way.
+	var duplicates []post.DuplicateCandidate
+	if !req.Force {
+		var err error
+		duplicates, err = h.postUseCase.FindDuplicateTitles(c.Request.Context(), req.Title, userID, userRole)
+		if err != nil {
+			h.logger.Error("Failed to check duplicate titles", zap.Error(err), zap.Uint("user_id", userID))
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	postResponse, err := h.postUseCase.CreatePost(c.Request.Context(), req, userID, userRole)
 	if err != nil {
-		h.logger.Error("Failed to create post", zap.Error(err), zap.Any("user_id", userID))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		h.logger.Error("Failed to create post", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
 		return
 	}
 
-	h.logger.Info("Post created successfully", zap.Uint("post_id", postResponse.ID), zap.Any("user_id", userID))
-	c.JSON(http.StatusCreated, gin.H{
+	h.logger.Info("Post created successfully", zap.Uint("post_id", postResponse.ID), zap.Uint("user_id", userID))
+	body := gin.H{
 		"message": "Post created successfully",
 		"data":    postResponse,
-	})
+	}
+	if len(duplicates) > 0 {
+		h.logger.Info("Possible duplicate post", zap.Uint("post_id", postResponse.ID), zap.Int("matches", len(duplicates)))
+		body["possible_duplicates"] = duplicates
+	}
+	c.JSON(http.StatusCreated, body)
 }
 
 // GetPostByID handles getting a post by ID
@@ -80,7 +111,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
-// @Param increment_view query bool false "Increment view count"
+// @Param increment_view query bool false "Increment view count (default post.increment_view_by_default)"
 // @Success 200 {object} post.PostResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -97,18 +128,15 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 		return
 	}
 
-	incrementView := c.DefaultQuery("increment_view", "true") == "true"
+	incrementView := incrementViewParam(c)
 
-	postResponse, err := h.postUseCase.GetPostByID(c.Request.Context(), uint(id), incrementView)
+	userID, _ := ctxutil.UserID(c)
+	userRole, _ := ctxutil.Role(c)
+
+	postResponse, err := h.postUseCase.GetPostByID(c.Request.Context(), uint(id), incrementView, userID, userRole)
 	if err != nil {
 		h.logger.Error("Failed to get post", zap.Error(err), zap.Uint64("id", id))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "post not found" {
-			statusCode = http.StatusNotFound
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
-		})
+		response.FromError(c, err)
 		return
 	}
 
@@ -121,12 +149,12 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 
 // GetPostBySlug handles getting a post by slug
 // @Summary Get post by slug
-// @Description Get a specific post by slug
+// @Description Get a specific post by slug; drafts and private posts require a token
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param slug path string true "Post slug"
-// @Param increment_view query bool false "Increment view count"
+// @Param increment_view query bool false "Increment view count (default post.increment_view_by_default)"
 // @Success 200 {object} post.PostResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -142,22 +170,311 @@ func (h *PostHandler) GetPostBySlug(c *gin.Context) {
 		return
 	}
 
-	incrementView := c.DefaultQuery("increment_view", "true") == "true"
+	incrementView := incrementViewParam(c)
+
+	// The route is public; the token, when sent, only widens what can be seen
+	userID, _ := ctxutil.UserID(c)
+	userRole, _ := ctxutil.Role(c)
 
-	postResponse, err := h.postUseCase.GetPostBySlug(c.Request.Context(), slug, incrementView)
+	postResponse, err := h.postUseCase.GetPostBySlug(c.Request.Context(), slug, incrementView, userID, userRole)
 	if err != nil {
 		h.logger.Error("Failed to get post by slug", zap.Error(err), zap.String("slug", slug))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "post not found" {
-			statusCode = http.StatusNotFound
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved post by slug", zap.String("slug", slug))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post retrieved successfully",
+		"data":    postResponse,
+	})
+}
+
+// GetPostsBySlugs handles fetching several posts by slug
+// @Summary Get posts by slugs
+// @Description Get up to 100 posts by slug in the requested order; missing slugs and posts the caller may not see are skipped
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param request body post.PostsBySlugRequest true "Post slugs"
+// @Success 200 {object} post.PostsBySlugResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/batch-by-slug [post]
+func (h *PostHandler) GetPostsBySlugs(c *gin.Context) {
+	var req post.PostsBySlugRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// The route is public; the token, when sent, only widens what can be seen
+	userID, _ := ctxutil.UserID(c)
+	userRole, _ := ctxutil.Role(c)
+
+	postsResponse, err := h.postUseCase.GetPostsBySlugs(c.Request.Context(), req.Slugs, userID, userRole)
+	if err != nil {
+		h.logger.Error("Failed to get posts by slugs", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved posts by slugs",
+		zap.Int("requested", len(req.Slugs)),
+		zap.Int("found", len(postsResponse.Posts)),
+	)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Posts retrieved successfully",
+		"data":    postsResponse,
+	})
+}
+
+// ImportMarkdown handles importing posts from markdown files with YAML front matter
+// @Summary Import markdown posts
+// @Description Create posts owned by the current user from a .md file or a .zip of .md files. Front matter may set title, slug, date, tags, status and summary.
+// @Tags posts
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Markdown file or zip archive"
+// @Success 200 {object} post.ImportResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/import/markdown [post]
+func (h *PostHandler) ImportMarkdown(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	userRole, _ := ctxutil.Role(c)
+	importCfg := config.GetConfig().Post.Import
+
+	// Leave room for the multipart envelope around the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importCfg.MaxUploadBytes+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": "Upload too large",
+			})
+			return
 		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "A markdown file or zip archive is required",
+			"details": err.Error(),
+		})
+		return
+	}
+	if fileHeader.Size > importCfg.MaxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": "Upload too large",
 		})
 		return
 	}
 
-	h.logger.Info("Retrieved post by slug", zap.String("slug", slug))
+	files, err := readImportFiles(fileHeader, importCfg.MaxUploadBytes, importCfg.MaxFiles)
+	if err != nil {
+		h.logger.Error("Failed to read import upload", zap.Error(err), zap.String("file", fileHeader.Filename))
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	importResponse, err := h.postUseCase.ImportMarkdown(c.Request.Context(), files, userID, userRole)
+	if err != nil {
+		h.logger.Error("Failed to import markdown", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Imported markdown posts", zap.Uint("user_id", userID), zap.Int("imported", importResponse.Imported), zap.Int("failed", importResponse.Failed))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Import completed",
+		"data":    importResponse,
+	})
+}
+
+// incrementViewParam reads the increment_view query parameter, falling back to the
+// configured default when it is absent or not a boolean
+func incrementViewParam(c *gin.Context) bool {
+	if incrementView, err := strconv.ParseBool(c.Query("increment_view")); err == nil {
+		return incrementView
+	}
+	return config.GetConfig().Post.IncrementViewByDefault
+}
+
+// listingLanguage reads the lang query parameter of a public listing. Without it, the
+// language preferred by the Accept-Language header is used when enabled; "" means all.
+func listingLanguage(c *gin.Context) string {
+	if lang := c.Query("lang"); lang != "" {
+		return strings.ToLower(lang)
+	}
+
+	languages := config.GetConfig().Post.Languages
+	if !languages.FromAcceptLanguage {
+		return ""
+	}
+	// The response depends on the header, so caches must not share it across languages
+	c.Header("Vary", "Accept-Language")
+	return post.MatchLanguage(c.GetHeader("Accept-Language"), languages.Supported)
+}
+
+// readImportFiles returns the markdown files in an upload, which is either a single file or
+// a zip archive. Extracted content is capped at maxBytes in total to defuse zip bombs.
+func readImportFiles(fileHeader *multipart.FileHeader, maxBytes int64, maxFiles int) ([]post.ImportFile, error) {
+	f, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(path.Ext(fileHeader.Filename), ".zip") {
+		return []post.ImportFile{{Name: fileHeader.Filename, Data: data}}, nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("invalid zip archive")
+	}
+
+	var files []post.ImportFile
+	remaining := maxBytes
+	for _, entry := range archive.File {
+		ext := strings.ToLower(path.Ext(entry.Name))
+		if entry.FileInfo().IsDir() || (ext != ".md" && ext != ".markdown") || strings.HasPrefix(entry.Name, "__MACOSX/") {
+			continue
+		}
+		if len(files) == maxFiles {
+			return nil, errors.New("too many files")
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", entry.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, remaining+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name, err)
+		}
+		if int64(len(content)) > remaining {
+			return nil, errors.New("archive contents too large")
+		}
+		remaining -= int64(len(content))
+
+		files = append(files, post.ImportFile{Name: entry.Name, Data: content})
+	}
+
+	return files, nil
+}
+
+// PreviewSlug handles previewing the slug generated for a title
+// @Summary Preview post slug
+// @Description Get the slug a post with this title would receive and whether it is available
+// @Tags posts
+// @Produce json
+// @Param title query string true "Post title"
+// @Success 200 {object} post.SlugPreview
+// @Failure 400 {object} map[string]interface{}
+// @Router /posts/slug-preview [get]
+func (h *PostHandler) PreviewSlug(c *gin.Context) {
+	preview, err := h.postUseCase.PreviewSlug(c.Request.Context(), c.Query("title"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": preview,
+	})
+}
+
+// SuggestTags handles suggesting tags for a draft
+// @Summary Suggest tags
+// @Description Rank the most frequent words of a draft's title and content as tag suggestions, boosting words that are already popular tags; nothing is saved
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param request body post.SuggestTagsRequest true "Draft title and content"
+// @Success 200 {object} post.SuggestTagsResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /posts/suggest-tags [post]
+func (h *PostHandler) SuggestTags(c *gin.Context) {
+	var req post.SuggestTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	suggestions, err := h.postUseCase.SuggestTags(c.Request.Context(), req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": suggestions,
+	})
+}
+
+// ResolvePost handles getting a post by either ID or slug
+// @Summary Resolve post by ID or slug
+// @Description Get a post by numeric ID or slug; drafts and private posts require a token
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param idOrSlug path string true "Post ID or slug"
+// @Param increment_view query bool false "Increment view count (default post.increment_view_by_default)"
+// @Success 200 {object} post.PostResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/resolve/{idOrSlug} [get]
+func (h *PostHandler) ResolvePost(c *gin.Context) {
+	idOrSlug := c.Param("idOrSlug")
+	if idOrSlug == "" {
+		h.logger.Error("Empty post identifier")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Post ID or slug is required",
+		})
+		return
+	}
+
+	incrementView := incrementViewParam(c)
+
+	// The route is public; the token, when sent, only widens what can be seen
+	userID, _ := ctxutil.UserID(c)
+	userRole, _ := ctxutil.Role(c)
+
+	postResponse, err := h.postUseCase.ResolvePost(c.Request.Context(), idOrSlug, incrementView, userID, userRole)
+	if err != nil {
+		h.logger.Error("Failed to resolve post", zap.Error(err), zap.String("id_or_slug", idOrSlug))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Resolved post", zap.String("id_or_slug", idOrSlug), zap.Uint("id", postResponse.ID))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Post retrieved successfully",
 		"data":    postResponse,
@@ -177,6 +494,7 @@ func (h *PostHandler) GetPostBySlug(c *gin.Context) {
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /posts/{id} [put]
 func (h *PostHandler) UpdatePost(c *gin.Context) {
@@ -200,8 +518,8 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
 		h.logger.Error("User ID not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
@@ -209,24 +527,79 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	userRole, _ := c.Get("role")
+	userRole, _ := ctxutil.Role(c)
 
-	postResponse, err := h.postUseCase.UpdatePost(c.Request.Context(), uint(id), req, userID.(uint), userRole.(string))
+	postResponse, err := h.postUseCase.UpdatePost(c.Request.Context(), uint(id), req, userID, userRole)
 	if err != nil {
-		h.logger.Error("Failed to update post", zap.Error(err), zap.Uint64("id", id), zap.Any("user_id", userID))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "post not found" {
-			statusCode = http.StatusNotFound
-		} else if err.Error() == "permission denied" {
-			statusCode = http.StatusForbidden
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
+		h.logger.Error("Failed to update post", zap.Error(err), zap.Uint64("id", id), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Updated post", zap.Uint64("id", id), zap.Uint("user_id", userID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post updated successfully",
+		"data":    postResponse,
+	})
+}
+
+// UpsertPostBySlug handles creating or updating a post identified by its slug
+// @Summary Create or update post by slug
+// @Description Create a post under the given slug, or update the post that has it (own posts only unless editor or admin). The slug is kept when the title changes.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param slug path string true "Post slug"
+// @Param request body post.CreatePostRequest true "Post data"
+// @Success 200 {object} post.PostResponse
+// @Success 201 {object} post.PostResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/by-slug/{slug} [put]
+func (h *PostHandler) UpsertPostBySlug(c *gin.Context) {
+	postSlug := c.Param("slug")
+
+	var req post.CreatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	userRole, _ := ctxutil.Role(c)
+
+	postResponse, created, err := h.postUseCase.UpsertPostBySlug(c.Request.Context(), postSlug, req, userID, userRole)
+	if err != nil {
+		h.logger.Error("Failed to upsert post", zap.Error(err), zap.String("slug", postSlug), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	if created {
+		h.logger.Info("Post created by slug", zap.String("slug", postSlug), zap.Uint("post_id", postResponse.ID), zap.Uint("user_id", userID))
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Post created successfully",
+			"data":    postResponse,
 		})
 		return
 	}
 
-	h.logger.Info("Updated post", zap.Uint64("id", id), zap.Any("user_id", userID))
+	h.logger.Info("Post updated by slug", zap.String("slug", postSlug), zap.Uint("post_id", postResponse.ID), zap.Uint("user_id", userID))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Post updated successfully",
 		"data":    postResponse,
@@ -258,8 +631,8 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
 		h.logger.Error("User ID not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
@@ -267,24 +640,16 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	userRole, _ := c.Get("role")
+	userRole, _ := ctxutil.Role(c)
 
-	err = h.postUseCase.DeletePost(c.Request.Context(), uint(id), userID.(uint), userRole.(string))
+	err = h.postUseCase.DeletePost(c.Request.Context(), uint(id), userID, userRole)
 	if err != nil {
-		h.logger.Error("Failed to delete post", zap.Error(err), zap.Uint64("id", id), zap.Any("user_id", userID))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "post not found" {
-			statusCode = http.StatusNotFound
-		} else if err.Error() == "permission denied" {
-			statusCode = http.StatusForbidden
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
-		})
+		h.logger.Error("Failed to delete post", zap.Error(err), zap.Uint64("id", id), zap.Uint("user_id", userID))
+		response.FromError(c, err)
 		return
 	}
 
-	h.logger.Info("Deleted post", zap.Uint64("id", id), zap.Any("user_id", userID))
+	h.logger.Info("Deleted post", zap.Uint64("id", id), zap.Uint("user_id", userID))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Post deleted successfully",
 	})
@@ -292,7 +657,7 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 
 // GetAllPosts handles getting all posts with filtering
 // @Summary Get all posts
-// @Description Get all posts with filtering and pagination
+// @Description Get the posts the caller may read with filtering and pagination; other users' drafts and hidden posts are left out for non-admins
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -302,14 +667,16 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 // @Param category_id query int false "Category ID"
 // @Param author_id query int false "Author ID"
 // @Param is_public query bool false "Is public"
-// @Param search query string false "Search in title and content"
+// @Param search query string false "Search in title and content; a blank value follows post.search.empty_query"
+// @Param search_fields query string false "Columns to search" Enums(title, content, all) default(all)
+// @Param lang query string false "ISO 639-1 language"
+// @Param tag query string false "Tag name or slug"
 // @Success 200 {object} post.PostsListResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /posts [get]
 func (h *PostHandler) GetAllPosts(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, limit := paginationParams(c, "posts")
 
 	// Build filter
 	filter := post.PostFilter{}
@@ -338,16 +705,37 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 		}
 	}
 
-	if search := c.Query("search"); search != "" {
+	if lang := c.Query("lang"); lang != "" {
+		lang = strings.ToLower(lang)
+		filter.Language = &lang
+	}
+
+	if tag := c.Query("tag"); tag != "" {
+		tagSlug := post.TagSlug(tag)
+		filter.Tag = &tagSlug
+	}
+
+	// A blank search is passed on too; what it means is configurable
+	if search, ok := c.GetQuery("search"); ok {
 		filter.Search = &search
 	}
 
-	postsResponse, err := h.postUseCase.GetAllPosts(c.Request.Context(), filter, page, limit)
+	filter.SearchFields = c.DefaultQuery("search_fields", post.SearchFieldsAll)
+	if !post.IsValidSearchFields(filter.SearchFields) {
+		h.logger.Error("Invalid search fields", zap.String("search_fields", filter.SearchFields))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid search_fields, expected title, content or all",
+		})
+		return
+	}
+
+	userID, _ := ctxutil.UserID(c)
+	userRole, _ := ctxutil.Role(c)
+
+	postsResponse, err := h.postUseCase.GetAllPosts(c.Request.Context(), filter, userID, userRole, page, limit)
 	if err != nil {
 		h.logger.Error("Failed to get posts", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		response.FromError(c, err)
 		return
 	}
 
@@ -371,8 +759,8 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 // @Failure 500 {object} map[string]interface{}
 // @Router /posts/my [get]
 func (h *PostHandler) GetMyPosts(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
 		h.logger.Error("User ID not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
@@ -380,25 +768,56 @@ func (h *PostHandler) GetMyPosts(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, limit := paginationParams(c, "my_posts")
 
-	postsResponse, err := h.postUseCase.GetMyPosts(c.Request.Context(), userID.(uint), page, limit)
+	postsResponse, err := h.postUseCase.GetMyPosts(c.Request.Context(), userID, page, limit)
 	if err != nil {
-		h.logger.Error("Failed to get user posts", zap.Error(err), zap.Any("user_id", userID))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		h.logger.Error("Failed to get user posts", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
 		return
 	}
 
-	h.logger.Info("Retrieved user posts", zap.Any("user_id", userID), zap.Int("count", len(postsResponse.Posts)))
+	h.logger.Info("Retrieved user posts", zap.Uint("user_id", userID), zap.Int("count", len(postsResponse.Posts)))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Posts retrieved successfully",
 		"data":    postsResponse,
 	})
 }
 
+// GetMyStats handles getting post counts for the current user
+// @Summary Get my post stats
+// @Description Get the number of posts and published posts of the authenticated user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} post.AuthorStats
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/stats [get]
+func (h *PostHandler) GetMyStats(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	stats, err := h.postUseCase.GetAuthorStats(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get post stats", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved post stats", zap.Uint("user_id", userID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post stats retrieved successfully",
+		"data":    stats,
+	})
+}
+
 // GetPublishedPosts handles getting published posts (public endpoint)
 // @Summary Get published posts
 // @Description Get all published and public posts
@@ -407,19 +826,57 @@ func (h *PostHandler) GetMyPosts(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Number of items per page" default(10)
+// @Param cursor query string false "Keyset pagination: next_cursor of the previous page, empty for the first page. Replaces page and only supports sort=published_at"
+// @Param sort query string false "Order, defaults to post.default_sort" Enums(published_at, view_count, hot)
+// @Param lang query string false "ISO 639-1 language; defaults to the Accept-Language header when post.languages.from_accept_language is on"
+// @Param Accept-Language header string false "Preferred languages, used when lang is absent"
+// @Param If-Modified-Since header string false "Return 304 if no post has changed since this time; only with sort=published_at"
 // @Success 200 {object} post.PostsListResponse
+// @Success 200 {object} post.PublishedCursorResponse
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /posts/published [get]
 func (h *PostHandler) GetPublishedPosts(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, limit := paginationParams(c, "published_posts")
+
+	sort := c.Query("sort")
+	if sort != "" && !post.IsValidSort(sort) {
+		h.logger.Error("Invalid sort", zap.String("sort", sort))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid sort, expected published_at, view_count or hot",
+		})
+		return
+	}
+
+	language := listingLanguage(c)
+
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		h.getPublishedPostsCursor(c, cursor, sort, language, limit)
+		return
+	}
+
+	lastModified, err := h.postUseCase.GetPublishedLastModified(c.Request.Context(), sort)
+	if err != nil {
+		h.logger.Error("Failed to get published posts last modified time", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	if lastModified != nil {
+		// HTTP dates have second precision
+		modified := lastModified.UTC().Truncate(time.Second)
+		if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !modified.After(since) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.Header("Last-Modified", modified.Format(http.TimeFormat))
+	}
 
-	postsResponse, err := h.postUseCase.GetPublishedPosts(c.Request.Context(), page, limit)
+	postsResponse, err := h.postUseCase.GetPublishedPosts(c.Request.Context(), sort, language, page, limit)
 	if err != nil {
 		h.logger.Error("Failed to get published posts", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		response.FromError(c, err)
 		return
 	}
 
@@ -430,7 +887,176 @@ func (h *PostHandler) GetPublishedPosts(c *gin.Context) {
 	})
 }
 
-// PublishPost handles publishing a post
+// getPublishedPostsCursor serves the keyset-paginated variant of GetPublishedPosts
+func (h *PostHandler) getPublishedPostsCursor(c *gin.Context, cursor, sort, language string, limit int) {
+	if sort != "" && sort != post.SortPublishedAt {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Cursor pagination only supports sort=published_at",
+		})
+		return
+	}
+
+	var cursorID uint64
+	if cursor != "" {
+		var err error
+		cursorID, err = strconv.ParseUint(cursor, 10, 32)
+		if err != nil {
+			h.logger.Error("Invalid cursor", zap.String("cursor", cursor))
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid cursor",
+			})
+			return
+		}
+	}
+
+	postsResponse, err := h.postUseCase.GetPublishedPostsCursor(c.Request.Context(), uint(cursorID), language, limit)
+	if err != nil {
+		h.logger.Error("Failed to get published posts", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved published posts", zap.Int("count", len(postsResponse.Posts)), zap.Uint64("cursor", cursorID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Posts retrieved successfully",
+		"data":    postsResponse,
+	})
+}
+
+// GetArchive handles the month-by-month archive of published posts (public endpoint)
+// @Summary Get post archive
+// @Description Get the number of published public posts per month, newest first. With year and month, also get that month's posts.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param year query int false "Year of the month to list posts for"
+// @Param month query int false "Month (1-12) to list posts for"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/archive [get]
+func (h *PostHandler) GetArchive(c *gin.Context) {
+	months, err := h.postUseCase.GetArchive(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to get post archive", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	data := gin.H{
+		"months": months,
+	}
+
+	yearStr, monthStr := c.Query("year"), c.Query("month")
+	if yearStr != "" || monthStr != "" {
+		year, yearErr := strconv.Atoi(yearStr)
+		month, monthErr := strconv.Atoi(monthStr)
+		if yearErr != nil || monthErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "year and month must both be numbers",
+			})
+			return
+		}
+
+		page, limit := paginationParams(c, "archive_posts")
+
+		postsResponse, err := h.postUseCase.GetArchivePosts(c.Request.Context(), year, month, page, limit)
+		if err != nil {
+			h.logger.Error("Failed to get archive posts", zap.Error(err), zap.Int("year", year), zap.Int("month", month))
+			response.FromError(c, err)
+			return
+		}
+		data["posts"] = postsResponse
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Archive retrieved successfully",
+		"data":    data,
+	})
+}
+
+// GetAuthorTags handles listing the tags an author uses (public endpoint)
+// @Summary Get author tags
+// @Description Get the tags of an author's published public posts with how many posts use each, most used first. Drafts and private posts are not counted.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Author ID"
+// @Param limit query int false "Maximum number of tags, all when omitted"
+// @Success 200 {object} post.AuthorTagsResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /authors/{id}/tags [get]
+func (h *PostHandler) GetAuthorTags(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid author ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid author ID",
+		})
+		return
+	}
+
+	var limit int
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be a positive number",
+			})
+			return
+		}
+	}
+
+	tagsResponse, err := h.postUseCase.GetAuthorTags(c.Request.Context(), uint(id), limit)
+	if err != nil {
+		h.logger.Error("Failed to get author tags", zap.Error(err), zap.Uint64("author_id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Author tags retrieved successfully",
+		"data":    tagsResponse,
+	})
+}
+
+// GetPostsByCategorySlug handles getting published posts of a category (public endpoint)
+// @Summary Get posts by category slug
+// @Description Get published and public posts of an active category identified by its slug
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} post.PostsListResponse
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /categories/slug/{slug}/posts [get]
+func (h *PostHandler) GetPostsByCategorySlug(c *gin.Context) {
+	categorySlug := c.Param("slug")
+	page, limit := paginationParams(c, "category_posts")
+
+	postsResponse, err := h.postUseCase.GetPostsByCategorySlug(c.Request.Context(), categorySlug, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get category posts", zap.Error(err), zap.String("slug", categorySlug))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved category posts", zap.String("slug", categorySlug), zap.Int("count", len(postsResponse.Posts)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Posts retrieved successfully",
+		"data":    postsResponse,
+	})
+}
+
+// PublishPost handles publishing a post
 // @Summary Publish post
 // @Description Publish a post (author or admin only)
 // @Tags posts
@@ -442,6 +1068,7 @@ func (h *PostHandler) GetPublishedPosts(c *gin.Context) {
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /posts/{id}/publish [patch]
 func (h *PostHandler) PublishPost(c *gin.Context) {
@@ -455,8 +1082,8 @@ func (h *PostHandler) PublishPost(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
 		h.logger.Error("User ID not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
@@ -464,24 +1091,16 @@ func (h *PostHandler) PublishPost(c *gin.Context) {
 		return
 	}
 
-	userRole, _ := c.Get("role")
+	userRole, _ := ctxutil.Role(c)
 
-	postResponse, err := h.postUseCase.PublishPost(c.Request.Context(), uint(id), userID.(uint), userRole.(string))
+	postResponse, err := h.postUseCase.PublishPost(c.Request.Context(), uint(id), userID, userRole)
 	if err != nil {
-		h.logger.Error("Failed to publish post", zap.Error(err), zap.Uint64("id", id), zap.Any("user_id", userID))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "post not found" {
-			statusCode = http.StatusNotFound
-		} else if err.Error() == "permission denied" {
-			statusCode = http.StatusForbidden
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
-		})
+		h.logger.Error("Failed to publish post", zap.Error(err), zap.Uint64("id", id), zap.Uint("user_id", userID))
+		response.FromError(c, err)
 		return
 	}
 
-	h.logger.Info("Published post", zap.Uint64("id", id), zap.Any("user_id", userID))
+	h.logger.Info("Published post", zap.Uint64("id", id), zap.Uint("user_id", userID))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Post published successfully",
 		"data":    postResponse,
@@ -495,6 +1114,7 @@ func (h *PostHandler) PublishPost(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
+// @Param status query string false "Target status (defaults to config)" Enums(draft, archived)
 // @Success 200 {object} post.PostResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
@@ -513,8 +1133,338 @@ func (h *PostHandler) UnpublishPost(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	userRole, _ := ctxutil.Role(c)
+
+	targetStatus := c.Query("status")
+
+	postResponse, err := h.postUseCase.UnpublishPost(c.Request.Context(), uint(id), userID, userRole, targetStatus)
+	if err != nil {
+		h.logger.Error("Failed to unpublish post", zap.Error(err), zap.Uint64("id", id), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Unpublished post", zap.Uint64("id", id), zap.Uint("user_id", userID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post unpublished successfully",
+		"data":    postResponse,
+	})
+}
+
+// GetTrashedPosts handles getting soft-deleted posts (admin only)
+// @Summary Get trashed posts
+// @Description Get soft-deleted posts ordered by deletion time (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} post.TrashedPostsListResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/posts/trash [get]
+func (h *PostHandler) GetTrashedPosts(c *gin.Context) {
+	page, limit := paginationParams(c, "trashed_posts")
+
+	postsResponse, err := h.postUseCase.GetTrashedPosts(c.Request.Context(), page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get trashed posts", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved trashed posts", zap.Int("count", len(postsResponse.Posts)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Trashed posts retrieved successfully",
+		"data":    postsResponse,
+	})
+}
+
+// GetMyTrashedPosts handles getting the current user's soft-deleted posts
+// @Summary Get my trashed posts
+// @Description Get soft-deleted posts of the authenticated user ordered by deletion time
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} post.TrashedPostsListResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/my/trashed [get]
+func (h *PostHandler) GetMyTrashedPosts(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	page, limit := paginationParams(c, "my_trashed_posts")
+
+	postsResponse, err := h.postUseCase.GetMyTrashedPosts(c.Request.Context(), userID, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get user trashed posts", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved user trashed posts", zap.Uint("user_id", userID), zap.Int("count", len(postsResponse.Posts)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Trashed posts retrieved successfully",
+		"data":    postsResponse,
+	})
+}
+
+// RestorePost handles restoring a soft-deleted post
+// @Summary Restore post
+// @Description Restore a soft-deleted post (own posts only, admins any)
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} post.PostResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/{id}/restore [post]
+func (h *PostHandler) RestorePost(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid post ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid post ID",
+		})
+		return
+	}
+
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	userRole, _ := ctxutil.Role(c)
+
+	postResponse, err := h.postUseCase.RestorePost(c.Request.Context(), uint(id), userID, userRole)
+	if err != nil {
+		h.logger.Error("Failed to restore post", zap.Error(err), zap.Uint64("id", id), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Post restored", zap.Uint64("id", id), zap.Uint("user_id", userID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post restored successfully",
+		"data":    postResponse,
+	})
+}
+
+// GetSharedWithMe handles listing posts the current user collaborates on
+// @Summary Get posts shared with me
+// @Description Get posts the authenticated user has been added to as a collaborator
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} post.PostsListResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/shared-with-me [get]
+func (h *PostHandler) GetSharedWithMe(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	page, limit := paginationParams(c, "shared_posts")
+
+	postsResponse, err := h.postUseCase.GetSharedWithMe(c.Request.Context(), userID, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get shared posts", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved shared posts", zap.Uint("user_id", userID), zap.Int("count", len(postsResponse.Posts)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Posts retrieved successfully",
+		"data":    postsResponse,
+	})
+}
+
+// GetCollaborators handles listing a post's collaborators
+// @Summary Get post collaborators
+// @Description Get the collaborators of a post (author, collaborators, editors and admins)
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {array} post.CollaboratorResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/{id}/collaborators [get]
+func (h *PostHandler) GetCollaborators(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid post ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid post ID",
+		})
+		return
+	}
+
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	userRole, _ := ctxutil.Role(c)
+
+	collaborators, err := h.postUseCase.GetCollaborators(c.Request.Context(), uint(id), userID, userRole)
+	if err != nil {
+		h.logger.Error("Failed to get collaborators", zap.Error(err), zap.Uint64("id", id), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Collaborators retrieved successfully",
+		"data":    collaborators,
+	})
+}
+
+// AddCollaborator handles adding a collaborator to a post or changing their permission
+// @Summary Add post collaborator
+// @Description Give a user view or edit access to a post (author, editors and admins)
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body post.AddCollaboratorRequest true "Collaborator"
+// @Success 200 {object} post.CollaboratorResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/{id}/collaborators [post]
+func (h *PostHandler) AddCollaborator(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid post ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid post ID",
+		})
+		return
+	}
+
+	var req post.AddCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	userRole, _ := ctxutil.Role(c)
+
+	collaborator, err := h.postUseCase.AddCollaborator(c.Request.Context(), uint(id), req, userID, userRole)
+	if err != nil {
+		h.logger.Error("Failed to add collaborator", zap.Error(err), zap.Uint64("id", id), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Collaborator saved", zap.Uint64("id", id), zap.Uint("collaborator_id", req.UserID), zap.String("permission", req.Permission))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Collaborator saved successfully",
+		"data":    collaborator,
+	})
+}
+
+// RemoveCollaborator handles revoking a collaborator's access to a post
+// @Summary Remove post collaborator
+// @Description Revoke a collaborator's access (author, editors, admins, or the collaborator themselves)
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param userId path int true "Collaborator user ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/{id}/collaborators/{userId} [delete]
+func (h *PostHandler) RemoveCollaborator(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid post ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid post ID",
+		})
+		return
+	}
+
+	collaboratorStr := c.Param("userId")
+	collaboratorID, err := strconv.ParseUint(collaboratorStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid user ID", zap.String("user_id", collaboratorStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
 		h.logger.Error("User ID not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
@@ -522,26 +1472,362 @@ func (h *PostHandler) UnpublishPost(c *gin.Context) {
 		return
 	}
 
-	userRole, _ := c.Get("role")
+	userRole, _ := ctxutil.Role(c)
 
-	postResponse, err := h.postUseCase.UnpublishPost(c.Request.Context(), uint(id), userID.(uint), userRole.(string))
+	err = h.postUseCase.RemoveCollaborator(c.Request.Context(), uint(id), uint(collaboratorID), userID, userRole)
 	if err != nil {
-		h.logger.Error("Failed to unpublish post", zap.Error(err), zap.Uint64("id", id), zap.Any("user_id", userID))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "post not found" {
-			statusCode = http.StatusNotFound
-		} else if err.Error() == "permission denied" {
-			statusCode = http.StatusForbidden
+		h.logger.Error("Failed to remove collaborator", zap.Error(err), zap.Uint64("id", id), zap.Uint64("collaborator_id", collaboratorID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Collaborator removed", zap.Uint64("id", id), zap.Uint64("collaborator_id", collaboratorID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Collaborator removed successfully",
+	})
+}
+
+// GetPostChanges handles the incremental sync feed (admin only)
+// @Summary Get post changes
+// @Description Get posts created, updated or (optionally) deleted after a time, in change order with cursor pagination (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param since query string false "Only changes after this time (RFC3339); required without cursor"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include_deleted query bool false "Include soft-deleted posts as tombstones" default(false)
+// @Param limit query int false "Number of changes per page" default(10)
+// @Success 200 {object} post.PostChangesResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/changes [get]
+func (h *PostHandler) GetPostChanges(c *gin.Context) {
+	_, limit := paginationParams(c, "post_changes")
+
+	filter := post.ChangesFilter{
+		IncludeDeleted: c.DefaultQuery("include_deleted", "false") == "true",
+	}
+
+	sinceStr := c.Query("since")
+	cursorStr := c.Query("cursor")
+	if sinceStr == "" && cursorStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Either 'since' or 'cursor' is required",
+		})
+		return
+	}
+
+	if sinceStr != "" {
+		since, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			h.logger.Error("Invalid since time", zap.String("since", sinceStr))
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid 'since' time, expected RFC3339",
+			})
+			return
+		}
+		filter.Since = since
+	}
+
+	if cursorStr != "" {
+		cursor, err := post.DecodeChangesCursor(cursorStr)
+		if err != nil {
+			h.logger.Error("Invalid changes cursor", zap.String("cursor", cursorStr))
+			response.FromError(c, err)
+			return
 		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
+		filter.After = cursor
+	}
+
+	changesResponse, err := h.postUseCase.GetPostChanges(c.Request.Context(), filter, limit)
+	if err != nil {
+		h.logger.Error("Failed to get post changes", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved post changes", zap.Int("count", len(changesResponse.Changes)), zap.Bool("has_more", changesResponse.HasMore))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post changes retrieved successfully",
+		"data":    changesResponse,
+	})
+}
+
+// GetScheduledPosts handles listing drafts scheduled for future publication (admin only)
+// @Summary Get scheduled posts
+// @Description Get drafts with a future publish time, ordered by scheduled time (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param from query string false "Only posts scheduled at or after this time (RFC3339)"
+// @Param to query string false "Only posts scheduled at or before this time (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} post.ScheduledPostsListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/posts/scheduled [get]
+func (h *PostHandler) GetScheduledPosts(c *gin.Context) {
+	page, limit := paginationParams(c, "scheduled_posts")
+
+	filter := post.ScheduledFilter{}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			h.logger.Error("Invalid from time", zap.String("from", fromStr))
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid 'from' time, expected RFC3339",
+			})
+			return
+		}
+		filter.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			h.logger.Error("Invalid to time", zap.String("to", toStr))
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid 'to' time, expected RFC3339",
+			})
+			return
+		}
+		filter.To = &to
+	}
+
+	postsResponse, err := h.postUseCase.GetScheduledPosts(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get scheduled posts", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved scheduled posts", zap.Int("count", len(postsResponse.Posts)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Scheduled posts retrieved successfully",
+		"data":    postsResponse,
+	})
+}
+
+// GetPostsNeedingAttention handles the editorial worklist (admin only)
+// @Summary Get posts needing attention
+// @Description List stale drafts, published posts without views, posts missing a summary or featured image, or posts flagged for blocked words (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param rule query string true "Attention rule" Enums(stale-drafts, no-views, incomplete, flagged)
+// @Param days query int false "Age threshold in days (stale-drafts default 30, no-views default 7)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} post.PostsListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/posts/attention [get]
+func (h *PostHandler) GetPostsNeedingAttention(c *gin.Context) {
+	page, limit := paginationParams(c, "attention_posts")
+	days, _ := strconv.Atoi(c.Query("days"))
+	rule := c.Query("rule")
+
+	postsResponse, err := h.postUseCase.GetPostsNeedingAttention(c.Request.Context(), rule, days, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get posts needing attention", zap.Error(err), zap.String("rule", rule))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved posts needing attention", zap.String("rule", rule), zap.Int("count", len(postsResponse.Posts)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Posts needing attention retrieved successfully",
+		"data":    postsResponse,
+	})
+}
+
+// TouchPost handles bumping a post's updated time (admin only)
+// @Summary Touch post
+// @Description Update only the post's updated_at so it resurfaces as recently updated (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} post.PostResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/posts/{id}/touch [post]
+func (h *PostHandler) TouchPost(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid post ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid post ID",
+		})
+		return
+	}
+
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
 		})
 		return
 	}
 
-	h.logger.Info("Unpublished post", zap.Uint64("id", id), zap.Any("user_id", userID))
+	userRole, _ := ctxutil.Role(c)
+
+	postResponse, err := h.postUseCase.TouchPost(c.Request.Context(), uint(id), userID, userRole)
+	if err != nil {
+		h.logger.Error("Failed to touch post", zap.Error(err), zap.Uint64("id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Post touched", zap.Uint64("id", id), zap.Uint("user_id", userID))
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Post unpublished successfully",
+		"message": "Post touched successfully",
 		"data":    postResponse,
 	})
 }
+
+// ResetViews handles resetting the view count of a post (admin only)
+// @Summary Reset post views
+// @Description Set the view count of a post to zero (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/posts/{id}/reset-views [post]
+func (h *PostHandler) ResetViews(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid post ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid post ID",
+		})
+		return
+	}
+
+	adminID, _ := ctxutil.UserID(c)
+
+	err = h.postUseCase.ResetViewCount(c.Request.Context(), uint(id))
+	if err != nil {
+		h.logger.Error("Failed to reset post views", zap.Error(err), zap.Uint64("id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Reset post views", zap.Uint64("id", id), zap.Uint("admin_id", adminID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post views reset successfully",
+	})
+}
+
+// BulkUpdateVisibility handles making several posts public or private (admin only)
+// @Summary Bulk update post visibility
+// @Description Set is_public on up to 100 posts in one transaction, reporting the result per post (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body post.BulkVisibilityRequest true "Post IDs and visibility"
+// @Success 200 {object} post.BulkVisibilityResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/posts/bulk-visibility [post]
+func (h *PostHandler) BulkUpdateVisibility(c *gin.Context) {
+	var req post.BulkVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	adminID, _ := ctxutil.UserID(c)
+	adminRole, _ := ctxutil.Role(c)
+
+	result, err := h.postUseCase.BulkUpdateVisibility(c.Request.Context(), req.PostIDs, *req.IsPublic, adminID, adminRole)
+	if err != nil {
+		h.logger.Error("Failed to update post visibility", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	for _, r := range result.Results {
+		if r.Changed {
+			h.logger.Info("Post visibility changed",
+				zap.Uint("post_id", r.PostID),
+				zap.Bool("is_public", *req.IsPublic),
+				zap.Uint("admin_id", adminID),
+			)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post visibility updated",
+		"data":    result,
+	})
+}
+
+// BulkResetViews handles resetting the view counts of several posts (admin only)
+// @Summary Bulk reset post views
+// @Description Set the view count of the given posts to zero (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body post.ResetViewsRequest true "Post IDs"
+// @Success 200 {object} post.ResetViewsResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/posts/reset-views [post]
+func (h *PostHandler) BulkResetViews(c *gin.Context) {
+	var req post.ResetViewsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	adminID, _ := ctxutil.UserID(c)
+
+	result, err := h.postUseCase.ResetViewCounts(c.Request.Context(), req.PostIDs)
+	if err != nil {
+		h.logger.Error("Failed to reset post views", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Bulk reset post views",
+		zap.Uints("post_ids", req.PostIDs),
+		zap.Int64("reset", result.Reset),
+		zap.Uint("admin_id", adminID),
+	)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post views reset successfully",
+		"data":    result,
+	})
+}
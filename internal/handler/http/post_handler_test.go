@@ -0,0 +1,545 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+	"moon/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// A missing or mistyped user ID in context is answered with 401 instead of panicking. The
+// handler has no use case, so reaching it would fail the test.
+func TestCreatePostWithoutUserInContext(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	tests := []struct {
+		name   string
+		userID interface{} // nil leaves the key unset
+	}{
+		{"missing", nil},
+		{"mistyped", "7"},
+		{"mistyped signed", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewPostHandler(nil)
+			router := gin.New()
+			router.POST("/posts", func(c *gin.Context) {
+				if tt.userID != nil {
+					c.Set("user_id", tt.userID)
+				}
+				c.Set("role", "user")
+				handler.CreatePost(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(`{"title":"A","content":"B"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("expected 401, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestListingLanguage(t *testing.T) {
+	if err := config.LoadConfig("../../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	languages := &config.GetConfig().Post.Languages
+	saved := *languages
+	t.Cleanup(func() { *languages = saved })
+	languages.Supported = []string{"vi", "en"}
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		fromHeader     bool
+		want           string
+		wantVary       bool
+	}{
+		{"explicit lang", "?lang=EN", "vi", true, "en", false},
+		{"header fallback", "", "fr, en-US;q=0.9, vi;q=0.5", true, "en", true},
+		{"header with no supported language", "", "fr", true, "", true},
+		{"no header", "", "", true, "", true},
+		{"header ignored when disabled", "", "en", false, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			languages.FromAcceptLanguage = tt.fromHeader
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/posts/published"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			if got := listingLanguage(c); got != tt.want {
+				t.Errorf("got language %q, want %q", got, tt.want)
+			}
+			if vary := w.Header().Get("Vary") == "Accept-Language"; vary != tt.wantVary {
+				t.Errorf("expected Vary: Accept-Language set to be %v", tt.wantVary)
+			}
+		})
+	}
+}
+
+// filterRecorder records the filter the post listing is called with
+type filterRecorder struct {
+	usecase.PostUseCase
+	filter *post.PostFilter
+}
+
+func (r *filterRecorder) GetAllPosts(ctx context.Context, filter post.PostFilter, viewerID uint, viewerRole string, page, limit int) (*post.PostsListResponse, error) {
+	r.filter = &filter
+	return &post.PostsListResponse{}, nil
+}
+
+func TestGetAllPostsSearchFields(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantCode   int
+		wantFields string
+	}{
+		{"default", "?search=go", http.StatusOK, post.SearchFieldsAll},
+		{"title", "?search=go&search_fields=title", http.StatusOK, post.SearchFieldsTitle},
+		{"content", "?search=go&search_fields=content", http.StatusOK, post.SearchFieldsContent},
+		{"all", "?search=go&search_fields=all", http.StatusOK, post.SearchFieldsAll},
+		{"unknown", "?search=go&search_fields=summary", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &filterRecorder{}
+			router := gin.New()
+			router.GET("/posts", NewPostHandler(uc).GetAllPosts)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts"+tt.query, nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				if uc.filter != nil {
+					t.Error("expected an invalid request not to reach the use case")
+				}
+				return
+			}
+			if uc.filter == nil || uc.filter.SearchFields != tt.wantFields {
+				t.Errorf("expected search fields %q, got %+v", tt.wantFields, uc.filter)
+			}
+		})
+	}
+}
+
+// A present search parameter is passed on even when blank, since what it means is configured
+func TestGetAllPostsBlankSearchParam(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	empty, spaces, term := "", "  ", "go"
+
+	tests := []struct {
+		name       string
+		query      string
+		wantSearch *string
+	}{
+		{"absent", "", nil},
+		{"empty", "?search=", &empty},
+		{"whitespace", "?search=%20%20", &spaces},
+		{"term", "?search=go", &term},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &filterRecorder{}
+			router := gin.New()
+			router.GET("/posts", NewPostHandler(uc).GetAllPosts)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts"+tt.query, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			got := uc.filter.Search
+			if (got == nil) != (tt.wantSearch == nil) || (got != nil && *got != *tt.wantSearch) {
+				t.Errorf("expected search %v, got %v", tt.wantSearch, got)
+			}
+		})
+	}
+}
+
+// listingRepo holds posts for the listing and applies the filters it is called with the
+// way the SQL does
+type listingRepo struct {
+	post.Repository
+	posts []*post.Post
+}
+
+func (r *listingRepo) GetAll(ctx context.Context, filter post.PostFilter, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if filter.Status != nil && p.Status != *filter.Status ||
+			filter.AuthorID != nil && p.AuthorID != *filter.AuthorID ||
+			filter.IsPublic != nil && p.IsPublic != *filter.IsPublic {
+			continue
+		}
+		if v := filter.VisibleTo; v != nil {
+			published := p.Status == "published"
+			if !(published && (p.IsPublic || v.PrivatePublished)) && (v.UserID == 0 || p.AuthorID != v.UserID) {
+				continue
+			}
+		}
+		posts = append(posts, p)
+	}
+	return posts, nil
+}
+
+func (r *listingRepo) GetTotalCount(ctx context.Context, filter post.PostFilter) (int64, error) {
+	posts, err := r.GetAll(ctx, filter, 0, 0)
+	return int64(len(posts)), err
+}
+
+func (r *listingRepo) GetTagNames(ctx context.Context, postIDs []uint) (map[uint][]string, error) {
+	return map[uint][]string{}, nil
+}
+
+// noUsers finds no users, so authors are listed as unknown
+type noUsers struct {
+	user.Repository
+}
+
+func (noUsers) GetByIDs(ctx context.Context, ids []uint) ([]*user.User, error) {
+	return nil, nil
+}
+
+// Filters can't reach another user's drafts or private posts through the protected listing
+func TestGetAllPostsHidesOtherUsersPosts(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.GetConfig()
+	cfg.Post.PrivateVisibility = "owner"
+
+	const authorID, otherUserID = 1, 2
+	repo := &listingRepo{posts: []*post.Post{
+		{ID: 1, Title: "Draft", Status: "draft", AuthorID: authorID},
+		{ID: 2, Title: "Private", Status: "published", IsPublic: false, AuthorID: authorID},
+		{ID: 3, Title: "Public", Status: "published", IsPublic: true, AuthorID: authorID},
+	}}
+	handler := NewPostHandler(usecase.NewPostUseCase(repo, noUsers{}, nil, nil, &cfg))
+
+	tests := []struct {
+		name    string
+		userID  uint
+		role    string
+		query   string
+		wantIDs []uint
+	}{
+		{"other user's drafts", otherUserID, user.RoleUser, "?status=draft&author_id=1", nil},
+		{"other user's private posts", otherUserID, user.RoleUser, "?is_public=false", nil},
+		{"other user's posts", otherUserID, user.RoleUser, "?author_id=1", []uint{3}},
+		{"own drafts", authorID, user.RoleUser, "?status=draft", []uint{1}},
+		{"admin", otherUserID, user.RoleAdmin, "?author_id=1", []uint{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/posts", func(c *gin.Context) {
+				c.Set("user_id", tt.userID)
+				c.Set("role", tt.role)
+			}, handler.GetAllPosts)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts"+tt.query, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var body struct {
+				Data post.PostsListResponse `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			var ids []uint
+			for _, p := range body.Data.Posts {
+				ids = append(ids, p.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) || body.Data.Total != int64(len(tt.wantIDs)) {
+				t.Errorf("expected posts %v, got %v (total %d)", tt.wantIDs, ids, body.Data.Total)
+			}
+		})
+	}
+}
+
+// publishedListing serves the published listing with a fixed last modified time
+type publishedListing struct {
+	usecase.PostUseCase
+	lastModified *time.Time
+	listed       bool
+}
+
+func (p *publishedListing) GetPublishedLastModified(ctx context.Context, sort string) (*time.Time, error) {
+	return p.lastModified, nil
+}
+
+func (p *publishedListing) GetPublishedPosts(ctx context.Context, sort, language string, page, limit int) (*post.PostsListResponse, error) {
+	p.listed = true
+	return &post.PostsListResponse{}, nil
+}
+
+func TestGetPublishedPostsIfModifiedSince(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2026, 3, 1, 9, 30, 15, 500, time.UTC)
+	header := modified.Truncate(time.Second).Format(http.TimeFormat)
+
+	tests := []struct {
+		name             string
+		lastModified     *time.Time
+		ifModifiedSince  string
+		wantCode         int
+		wantLastModified string
+	}{
+		{"no condition", &modified, "", http.StatusOK, header},
+		{"unchanged", &modified, header, http.StatusNotModified, ""},
+		{"newer condition", &modified, modified.Add(time.Hour).Format(http.TimeFormat), http.StatusNotModified, ""},
+		{"changed since", &modified, modified.Add(-time.Hour).Format(http.TimeFormat), http.StatusOK, header},
+		{"invalid condition", &modified, "yesterday", http.StatusOK, header},
+		{"no last modified time", nil, header, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &publishedListing{lastModified: tt.lastModified}
+			router := gin.New()
+			router.GET("/posts/published", NewPostHandler(uc).GetPublishedPosts)
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/published", nil)
+			if tt.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Last-Modified"); got != tt.wantLastModified {
+				t.Errorf("expected Last-Modified %q, got %q", tt.wantLastModified, got)
+			}
+			if notModified := tt.wantCode == http.StatusNotModified; uc.listed == notModified {
+				t.Errorf("expected the listing to be fetched only when modified, fetched %v", uc.listed)
+			}
+			if tt.wantCode == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("expected an empty 304 body, got %q", w.Body.String())
+			}
+		})
+	}
+}
+
+// viewRecorder records whether single-post reads asked to count a view
+type viewRecorder struct {
+	usecase.PostUseCase
+	incrementView []bool
+}
+
+func (v *viewRecorder) GetPostByID(ctx context.Context, id uint, incrementView bool, viewerID uint, viewerRole string) (*post.PostResponse, error) {
+	v.incrementView = append(v.incrementView, incrementView)
+	return &post.PostResponse{ID: id}, nil
+}
+
+func (v *viewRecorder) GetPostBySlug(ctx context.Context, slug string, incrementView bool, viewerID uint, viewerRole string) (*post.PostResponse, error) {
+	v.incrementView = append(v.incrementView, incrementView)
+	return &post.PostResponse{Slug: slug}, nil
+}
+
+func (v *viewRecorder) ResolvePost(ctx context.Context, idOrSlug string, incrementView bool, viewerID uint, viewerRole string) (*post.PostResponse, error) {
+	v.incrementView = append(v.incrementView, incrementView)
+	return &post.PostResponse{Slug: idOrSlug}, nil
+}
+
+func TestIncrementViewDefault(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.GetConfig()
+	saved := cfg.Post.IncrementViewByDefault
+	t.Cleanup(func() { cfg.Post.IncrementViewByDefault = saved })
+
+	tests := []struct {
+		name          string
+		byDefault     bool
+		query         string
+		wantIncrement bool
+	}{
+		{"default on", true, "", true},
+		{"default off", false, "", false},
+		{"override off", true, "?increment_view=false", false},
+		{"override on", false, "?increment_view=true", true},
+		{"numeric override", false, "?increment_view=1", true},
+		{"invalid value uses the default", false, "?increment_view=maybe", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Post.IncrementViewByDefault = tt.byDefault
+
+			uc := &viewRecorder{}
+			handler := NewPostHandler(uc)
+			router := gin.New()
+			router.GET("/posts/:id", handler.GetPostByID)
+			router.GET("/posts/slug/:slug", handler.GetPostBySlug)
+			router.GET("/p/:idOrSlug", handler.ResolvePost)
+
+			for _, path := range []string{"/posts/1", "/posts/slug/hello", "/p/hello"} {
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+tt.query, nil))
+				if w.Code != http.StatusOK {
+					t.Fatalf("%s: expected 200, got %d: %s", path, w.Code, w.Body.String())
+				}
+			}
+
+			want := []bool{tt.wantIncrement, tt.wantIncrement, tt.wantIncrement}
+			if !slices.Equal(uc.incrementView, want) {
+				t.Errorf("expected increment_view %v on every endpoint, got %v", want, uc.incrementView)
+			}
+		})
+	}
+}
+
+// visibilityRecorder records the IDs of a bulk visibility change
+type visibilityRecorder struct {
+	usecase.PostUseCase
+	ids []uint
+}
+
+func (v *visibilityRecorder) BulkUpdateVisibility(ctx context.Context, ids []uint, isPublic bool, userID uint, userRole string) (*post.BulkVisibilityResponse, error) {
+	v.ids = ids
+	return &post.BulkVisibilityResponse{}, nil
+}
+
+func TestBulkUpdateVisibilityCapsIDs(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	ids := func(n int) string {
+		parts := make([]string, n)
+		for i := range parts {
+			parts[i] = strconv.Itoa(i + 1)
+		}
+		return `{"post_ids":[` + strings.Join(parts, ",") + `],"is_public":true}`
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"at the cap", ids(100), http.StatusOK},
+		{"over the cap", ids(101), http.StatusBadRequest},
+		{"empty", `{"post_ids":[],"is_public":true}`, http.StatusBadRequest},
+		{"missing is_public", `{"post_ids":[1]}`, http.StatusBadRequest},
+		{"making private", `{"post_ids":[1],"is_public":false}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &visibilityRecorder{}
+			router := gin.New()
+			router.POST("/admin/posts/bulk-visibility", NewPostHandler(uc).BulkUpdateVisibility)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/posts/bulk-visibility", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if (uc.ids != nil) != (tt.wantCode == http.StatusOK) {
+				t.Errorf("expected the update to run only for valid requests, got %v", uc.ids)
+			}
+		})
+	}
+}
+
+// authorTagsRecorder records the limit the author tags are requested with
+type authorTagsRecorder struct {
+	usecase.PostUseCase
+	limit *int
+}
+
+func (r *authorTagsRecorder) GetAuthorTags(ctx context.Context, authorID uint, limit int) (*post.AuthorTagsResponse, error) {
+	r.limit = &limit
+	return &post.AuthorTagsResponse{AuthorID: authorID}, nil
+}
+
+func TestGetAuthorTagsLimit(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	tests := []struct {
+		name      string
+		path      string
+		wantCode  int
+		wantLimit int
+	}{
+		{"all tags", "/authors/1/tags", http.StatusOK, 0},
+		{"limited", "/authors/1/tags?limit=5", http.StatusOK, 5},
+		{"zero limit", "/authors/1/tags?limit=0", http.StatusBadRequest, 0},
+		{"not a number", "/authors/1/tags?limit=many", http.StatusBadRequest, 0},
+		{"invalid author", "/authors/me/tags", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &authorTagsRecorder{}
+			router := gin.New()
+			router.GET("/authors/:id/tags", NewPostHandler(uc).GetAuthorTags)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				if uc.limit != nil {
+					t.Error("expected an invalid request not to reach the use case")
+				}
+				return
+			}
+			if uc.limit == nil || *uc.limit != tt.wantLimit {
+				t.Errorf("expected limit %d, got %v", tt.wantLimit, uc.limit)
+			}
+		})
+	}
+}
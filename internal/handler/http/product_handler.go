@@ -0,0 +1,257 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"moon/internal/ctxutil"
+	"moon/internal/domain/product"
+	"moon/internal/handler/response"
+	"moon/internal/usecase"
+	"moon/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ProductHandler struct {
+	productUseCase usecase.ProductUseCase
+	logger         *zap.Logger
+}
+
+// NewProductHandler creates a new product handler
+func NewProductHandler(productUseCase usecase.ProductUseCase) *ProductHandler {
+	return &ProductHandler{
+		productUseCase: productUseCase,
+		logger:         logger.GetLogger(),
+	}
+}
+
+// CreateProduct handles product creation (admin only)
+// @Summary Create product
+// @Description Create a product in an active category; price is in minor units of the currency
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param request body product.CreateProductRequest true "Product data"
+// @Success 201 {object} product.ProductResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /products [post]
+func (h *ProductHandler) CreateProduct(c *gin.Context) {
+	var req product.CreateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	productResponse, err := h.productUseCase.CreateProduct(c.Request.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to create product", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Product created successfully", zap.Uint("id", productResponse.ID))
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Product created successfully",
+		"data":    productResponse,
+	})
+}
+
+// GetProduct handles getting a product by ID
+// @Summary Get product by ID
+// @Description Get a product by ID; inactive products are only visible to admins
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} product.ProductResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /products/{id} [get]
+func (h *ProductHandler) GetProduct(c *gin.Context) {
+	id, ok := h.productID(c)
+	if !ok {
+		return
+	}
+
+	role, _ := ctxutil.Role(c)
+
+	productResponse, err := h.productUseCase.GetProduct(c.Request.Context(), id, role == "admin")
+	if err != nil {
+		h.logger.Error("Failed to get product", zap.Error(err), zap.Uint("id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Product retrieved successfully",
+		"data":    productResponse,
+	})
+}
+
+// UpdateProduct handles product updates (admin only)
+// @Summary Update product
+// @Description Update a product; omitted fields are left unchanged
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body product.UpdateProductRequest true "Product data"
+// @Success 200 {object} product.ProductResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /products/{id} [put]
+func (h *ProductHandler) UpdateProduct(c *gin.Context) {
+	id, ok := h.productID(c)
+	if !ok {
+		return
+	}
+
+	var req product.UpdateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	productResponse, err := h.productUseCase.UpdateProduct(c.Request.Context(), id, req)
+	if err != nil {
+		h.logger.Error("Failed to update product", zap.Error(err), zap.Uint("id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Product updated successfully", zap.Uint("id", id))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Product updated successfully",
+		"data":    productResponse,
+	})
+}
+
+// DeleteProduct handles product deletion (admin only)
+// @Summary Delete product
+// @Description Soft delete a product
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /products/{id} [delete]
+func (h *ProductHandler) DeleteProduct(c *gin.Context) {
+	id, ok := h.productID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.productUseCase.DeleteProduct(c.Request.Context(), id); err != nil {
+		h.logger.Error("Failed to delete product", zap.Error(err), zap.Uint("id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Product deleted successfully", zap.Uint("id", id))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Product deleted successfully",
+	})
+}
+
+// GetProducts handles listing products with filtering and pagination
+// @Summary Get products
+// @Description Get products with filtering and pagination; only admins can list inactive products
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param category_id query int false "Category ID"
+// @Param currency query string false "ISO 4217 currency code"
+// @Param min_price query int false "Minimum price in minor units"
+// @Param max_price query int false "Maximum price in minor units"
+// @Param is_active query bool false "Active status (admin only)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} product.ProductsListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /products [get]
+func (h *ProductHandler) GetProducts(c *gin.Context) {
+	page, limit := paginationParams(c, "products")
+
+	// Build filter
+	filter := product.ProductFilter{}
+
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		if categoryID, err := strconv.ParseUint(categoryIDStr, 10, 32); err == nil {
+			categoryIDUint := uint(categoryID)
+			filter.CategoryID = &categoryIDUint
+		}
+	}
+
+	if currency := c.Query("currency"); currency != "" {
+		filter.Currency = &currency
+	}
+
+	if minPrice, err := strconv.ParseInt(c.Query("min_price"), 10, 64); err == nil {
+		filter.MinPrice = &minPrice
+	}
+
+	if maxPrice, err := strconv.ParseInt(c.Query("max_price"), 10, 64); err == nil {
+		filter.MaxPrice = &maxPrice
+	}
+
+	// Everyone else only sees what is on sale
+	if role, _ := ctxutil.Role(c); role == "admin" {
+		if isActive, err := strconv.ParseBool(c.Query("is_active")); err == nil {
+			filter.IsActive = &isActive
+		}
+	} else {
+		active := true
+		filter.IsActive = &active
+	}
+
+	productsResponse, err := h.productUseCase.GetProducts(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get products", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved products list", zap.Int("count", len(productsResponse.Products)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Products retrieved successfully",
+		"data":    productsResponse,
+	})
+}
+
+// productID parses the :id path parameter, writing a 400 response when it is invalid
+func (h *ProductHandler) productID(c *gin.Context) (uint, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid product ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid product ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
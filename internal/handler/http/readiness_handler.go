@@ -0,0 +1,47 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"moon/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ReadinessHandler struct {
+	ready  func(ctx context.Context) error
+	logger *zap.Logger
+}
+
+// NewReadinessHandler creates a readiness handler reporting the result of ready
+func NewReadinessHandler(ready func(ctx context.Context) error) *ReadinessHandler {
+	return &ReadinessHandler{
+		ready:  ready,
+		logger: logger.GetLogger(),
+	}
+}
+
+// Readyz handles readiness probes
+// @Summary Readiness probe
+// @Description Report whether the database schema passed the startup self-test and the database answers
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /readyz [get]
+func (h *ReadinessHandler) Readyz(c *gin.Context) {
+	if err := h.ready(c.Request.Context()); err != nil {
+		h.logger.Warn("Readiness check failed", zap.Error(err))
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "unready",
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ready",
+	})
+}
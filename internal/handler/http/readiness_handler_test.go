@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadyz(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	tests := []struct {
+		name       string
+		readyErr   error
+		wantCode   int
+		wantStatus string
+	}{
+		{"ready", nil, http.StatusOK, "ready"},
+		{"failed self-test", errors.New("schema self-test failed: *post.Post: Error 1146"), http.StatusServiceUnavailable, "unready"},
+		{"database down", errors.New("connection refused"), http.StatusServiceUnavailable, "unready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewReadinessHandler(func(ctx context.Context) error { return tt.readyErr })
+			router := gin.New()
+			router.GET("/readyz", handler.Readyz)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			var body struct {
+				Status string `json:"status"`
+				Error  string `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, body.Status)
+			}
+			if tt.readyErr != nil && body.Error != tt.readyErr.Error() {
+				t.Errorf("expected the failure to be reported, got %q", body.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"moon/internal/ctxutil"
+	"moon/internal/domain/report"
+	"moon/internal/handler/response"
+	"moon/internal/usecase"
+	"moon/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ReportHandler struct {
+	reportUseCase usecase.ReportUseCase
+	logger        *zap.Logger
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reportUseCase usecase.ReportUseCase) *ReportHandler {
+	return &ReportHandler{
+		reportUseCase: reportUseCase,
+		logger:        logger.GetLogger(),
+	}
+}
+
+// ReportPost handles flagging a post as inappropriate
+// @Summary Report post
+// @Description Flag a post as inappropriate (authenticated users)
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body report.CreateReportRequest true "Report data"
+// @Success 201 {object} report.Report
+// @Success 200 {object} report.Report
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /posts/{id}/report [post]
+func (h *ReportHandler) ReportPost(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid post ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid post ID",
+		})
+		return
+	}
+
+	var req report.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	reportResponse, duplicate, err := h.reportUseCase.ReportPost(c.Request.Context(), uint(id), req, userID)
+	if err != nil {
+		h.logger.Error("Failed to report post", zap.Error(err), zap.Uint64("id", id), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	if duplicate {
+		h.logger.Info("Duplicate post report ignored", zap.Uint64("id", id), zap.Uint("user_id", userID))
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Post already reported",
+			"data":    reportResponse,
+		})
+		return
+	}
+
+	h.logger.Info("Post reported", zap.Uint64("id", id), zap.Uint("report_id", reportResponse.ID), zap.Uint("user_id", userID))
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Post reported successfully",
+		"data":    reportResponse,
+	})
+}
+
+// GetReports handles listing content reports (admin only)
+// @Summary Get reports
+// @Description Get content reports for triage with filtering and pagination (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param status query string false "Report status" Enums(open, reviewed, dismissed)
+// @Param target_type query string false "Reported content type" Enums(post)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} report.ReportsListResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/reports [get]
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	page, limit := paginationParams(c, "reports")
+
+	// Build filter
+	filter := report.ReportFilter{}
+
+	if status := c.Query("status"); status != "" {
+		filter.Status = &status
+	}
+
+	if targetType := c.Query("target_type"); targetType != "" {
+		filter.TargetType = &targetType
+	}
+
+	reportsResponse, err := h.reportUseCase.GetReports(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get reports", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved reports list", zap.Int("count", len(reportsResponse.Reports)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reports retrieved successfully",
+		"data":    reportsResponse,
+	})
+}
+
+// UpdateReportStatus handles moving a report through triage (admin only)
+// @Summary Update report status
+// @Description Mark a report as open, reviewed or dismissed (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Report ID"
+// @Param request body report.UpdateReportStatusRequest true "New status"
+// @Success 200 {object} report.Report
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/reports/{id}/status [patch]
+func (h *ReportHandler) UpdateReportStatus(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid report ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid report ID",
+		})
+		return
+	}
+
+	var req report.UpdateReportStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	reportResponse, err := h.reportUseCase.UpdateReportStatus(c.Request.Context(), uint(id), req, userID)
+	if err != nil {
+		h.logger.Error("Failed to update report status", zap.Error(err), zap.Uint64("id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Updated report status", zap.Uint64("id", id), zap.String("status", req.Status))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Report updated successfully",
+		"data":    reportResponse,
+	})
+}
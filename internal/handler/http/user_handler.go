@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"moon/internal/ctxutil"
 	"moon/internal/domain/user"
+	"moon/internal/handler/response"
 	"moon/internal/usecase"
 	"moon/pkg/logger"
 
@@ -40,15 +42,12 @@ func NewUserHandler(userUseCase usecase.UserUseCase) *UserHandler {
 // @Failure 500 {object} map[string]interface{}
 // @Router /admin/users [get]
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, limit := paginationParams(c, "users")
 
 	usersResponse, err := h.userUseCase.GetAllUsers(c.Request.Context(), page, limit)
 	if err != nil {
 		h.logger.Error("Failed to get users", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		response.FromError(c, err)
 		return
 	}
 
@@ -87,13 +86,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	userResponse, err := h.userUseCase.GetUserByID(c.Request.Context(), uint(id))
 	if err != nil {
 		h.logger.Error("Failed to get user", zap.Error(err), zap.Uint64("id", id))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			statusCode = http.StatusNotFound
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
-		})
+		response.FromError(c, err)
 		return
 	}
 
@@ -117,6 +110,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /admin/users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
@@ -143,13 +137,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	userResponse, err := h.userUseCase.UpdateUser(c.Request.Context(), uint(id), req)
 	if err != nil {
 		h.logger.Error("Failed to update user", zap.Error(err), zap.Uint64("id", id))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			statusCode = http.StatusNotFound
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
-		})
+		response.FromError(c, err)
 		return
 	}
 
@@ -186,7 +174,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	}
 
 	// Prevent admin from deleting themselves
-	currentUserID, _ := c.Get("user_id")
+	currentUserID, _ := ctxutil.UserID(c)
 	if currentUserID == uint(id) {
 		h.logger.Warn("Admin tried to delete themselves", zap.Uint64("id", id))
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -198,13 +186,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	err = h.userUseCase.DeleteUser(c.Request.Context(), uint(id))
 	if err != nil {
 		h.logger.Error("Failed to delete user", zap.Error(err), zap.Uint64("id", id))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			statusCode = http.StatusNotFound
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
-		})
+		response.FromError(c, err)
 		return
 	}
 
@@ -214,13 +196,248 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	})
 }
 
+// GetUserNames handles looking up display names for a batch of users (admin only)
+// @Summary Get user names
+// @Description Map up to 100 user IDs to their names; deleted or unknown users are omitted (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body user.NamesRequest true "User IDs"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/names [post]
+func (h *UserHandler) GetUserNames(c *gin.Context) {
+	var req user.NamesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	names, err := h.userUseCase.GetNames(c.Request.Context(), req.IDs)
+	if err != nil {
+		h.logger.Error("Failed to get user names", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved user names", zap.Int("requested", len(req.IDs)), zap.Int("found", len(names)))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User names retrieved successfully",
+		"data":    names,
+	})
+}
+
+// ForceLogout handles revoking all tokens of a user (admin only)
+// @Summary Force logout user
+// @Description Invalidate every token issued to a user (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/{id}/logout [post]
+func (h *UserHandler) ForceLogout(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid user ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	err = h.userUseCase.ForceLogout(c.Request.Context(), uint(id))
+	if err != nil {
+		h.logger.Error("Failed to force logout user", zap.Error(err), zap.Uint64("id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Forced logout of user", zap.Uint64("id", id))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User logged out from all sessions",
+	})
+}
+
+// GetPendingUsers handles listing registrations awaiting approval (admin only)
+// @Summary Get pending users
+// @Description Get users whose registration awaits admin approval, oldest first (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} user.UsersListResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/pending [get]
+func (h *UserHandler) GetPendingUsers(c *gin.Context) {
+	page, limit := paginationParams(c, "users")
+
+	usersResponse, err := h.userUseCase.GetPendingUsers(c.Request.Context(), page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get pending users", zap.Error(err))
+		response.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pending users retrieved successfully",
+		"data":    usersResponse,
+	})
+}
+
+// ApproveUser handles approving a pending registration (admin only)
+// @Summary Approve user
+// @Description Activate a user whose registration is pending approval (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} user.UserResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/{id}/approve [post]
+func (h *UserHandler) ApproveUser(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid user ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	userResponse, err := h.userUseCase.ApproveUser(c.Request.Context(), uint(id))
+	if err != nil {
+		h.logger.Error("Failed to approve user", zap.Error(err), zap.Uint64("id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	adminID, _ := ctxutil.UserID(c)
+	h.logger.Info("Approved user", zap.Uint64("id", id), zap.Uint("admin_id", adminID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User approved successfully",
+		"data":    userResponse,
+	})
+}
+
+// RejectUser handles rejecting a pending registration (admin only)
+// @Summary Reject user
+// @Description Delete a user whose registration is pending approval (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/{id}/reject [post]
+func (h *UserHandler) RejectUser(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		h.logger.Error("Invalid user ID", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	if err := h.userUseCase.RejectUser(c.Request.Context(), uint(id)); err != nil {
+		h.logger.Error("Failed to reject user", zap.Error(err), zap.Uint64("id", id))
+		response.FromError(c, err)
+		return
+	}
+
+	adminID, _ := ctxutil.UserID(c)
+	h.logger.Info("Rejected user", zap.Uint64("id", id), zap.Uint("admin_id", adminID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User rejected successfully",
+	})
+}
+
+// MergeUsers handles merging a duplicate account into a primary one (admin only)
+// @Summary Merge users
+// @Description Move the source user's posts, collaborations and reports to the target user, then delete the source (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body user.MergeUsersRequest true "Source and target user IDs"
+// @Success 200 {object} user.MergeUsersResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/merge [post]
+func (h *UserHandler) MergeUsers(c *gin.Context) {
+	var req user.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	mergeResponse, err := h.userUseCase.MergeUsers(c.Request.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to merge users", zap.Error(err), zap.Uint("source_id", req.SourceID), zap.Uint("target_id", req.TargetID))
+		response.FromError(c, err)
+		return
+	}
+
+	// There is no audit table yet; this log line is the audit record of the merge
+	adminID, _ := ctxutil.UserID(c)
+	h.logger.Info("Merged users",
+		zap.Uint("source_id", mergeResponse.SourceID),
+		zap.Uint("target_id", mergeResponse.TargetID),
+		zap.Uint("admin_id", adminID),
+		zap.String("request_id", ctxutil.RequestID(c)),
+		zap.Int64("posts_moved", mergeResponse.PostsMoved),
+		zap.Int64("collaborations_moved", mergeResponse.CollaborationsMoved),
+		zap.Int64("reports_moved", mergeResponse.ReportsMoved),
+	)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Users merged successfully",
+		"data":    mergeResponse,
+	})
+}
+
 // GetUsersByRole handles getting users by role (admin only)
 // @Summary Get users by role
 // @Description Get users filtered by role with pagination (admin only)
 // @Tags admin
 // @Accept json
 // @Produce json
-// @Param role path string true "User role" Enums(user, admin)
+// @Param role path string true "User role" Enums(user, editor, admin)
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Number of items per page" default(10)
 // @Success 200 {object} user.UsersListResponse
@@ -230,24 +447,21 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 // @Failure 500 {object} map[string]interface{}
 // @Router /admin/users/role/{role} [get]
 func (h *UserHandler) GetUsersByRole(c *gin.Context) {
-	role := c.Param("role")
-	if role != "user" && role != "admin" {
-		h.logger.Error("Invalid role", zap.String("role", role))
+	role, ok := user.NormalizeRole(c.Param("role"))
+	if !ok {
+		h.logger.Error("Invalid role", zap.String("role", c.Param("role")))
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid role. Must be 'user' or 'admin'",
+			"error": "Invalid role. Must be 'user', 'editor' or 'admin'",
 		})
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, limit := paginationParams(c, "users_by_role")
 
 	usersResponse, err := h.userUseCase.GetUsersByRole(c.Request.Context(), role, page, limit)
 	if err != nil {
 		h.logger.Error("Failed to get users by role", zap.Error(err), zap.String("role", role))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		response.FromError(c, err)
 		return
 	}
 
@@ -270,8 +484,8 @@ func (h *UserHandler) GetUsersByRole(c *gin.Context) {
 // @Failure 500 {object} map[string]interface{}
 // @Router /profile [get]
 func (h *UserHandler) GetProfile(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
 		h.logger.Error("User ID not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
@@ -279,22 +493,179 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
-	userResponse, err := h.userUseCase.GetUserByID(c.Request.Context(), userID.(uint))
+	userResponse, err := h.userUseCase.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		h.logger.Error("Failed to get user profile", zap.Error(err), zap.Any("user_id", userID))
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			statusCode = http.StatusNotFound
-		}
-		c.JSON(statusCode, gin.H{
-			"error": err.Error(),
-		})
+		h.logger.Error("Failed to get user profile", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
 		return
 	}
 
-	h.logger.Info("Retrieved user profile", zap.Any("user_id", userID))
+	h.logger.Info("Retrieved user profile", zap.Uint("user_id", userID))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Profile retrieved successfully",
 		"data":    userResponse,
 	})
 }
+
+// UpdateProfile handles updating the current user's profile
+// @Summary Update current user profile
+// @Description Update name, username, contact and location of the currently authenticated user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body user.UpdateProfileRequest true "Profile update data"
+// @Success 200 {object} user.UserResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile [put]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	var req user.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userResponse, err := h.userUseCase.UpdateProfile(c.Request.Context(), userID, req)
+	if err != nil {
+		h.logger.Error("Failed to update user profile", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Updated user profile", zap.Uint("user_id", userID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Profile updated successfully",
+		"data":    userResponse,
+	})
+}
+
+// GetNotificationPreferences handles getting the current user's notification preferences
+// @Summary Get notification preferences
+// @Description Get which optional emails the currently authenticated user receives
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} user.NotificationPreferences
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/notifications [get]
+func (h *UserHandler) GetNotificationPreferences(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	prefs, err := h.userUseCase.GetNotificationPreferences(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get notification preferences", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notification preferences retrieved successfully",
+		"data":    prefs,
+	})
+}
+
+// UpdateNotificationPreferences handles changing the current user's notification preferences
+// @Summary Update notification preferences
+// @Description Opt in or out of optional email categories; omitted categories are unchanged
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body user.UpdateNotificationPreferencesRequest true "Notification preferences"
+// @Success 200 {object} user.NotificationPreferences
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/notifications [put]
+func (h *UserHandler) UpdateNotificationPreferences(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	var req user.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	prefs, err := h.userUseCase.UpdateNotificationPreferences(c.Request.Context(), userID, req)
+	if err != nil {
+		h.logger.Error("Failed to update notification preferences", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Updated notification preferences", zap.Uint("user_id", userID))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notification preferences updated successfully",
+		"data":    prefs,
+	})
+}
+
+// GetMyPermissions handles getting the current user's permissions
+// @Summary Get current user permissions
+// @Description Get the effective permission set of the currently authenticated user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} user.PermissionsResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /profile/permissions [get]
+func (h *UserHandler) GetMyPermissions(c *gin.Context) {
+	userID, ok := ctxutil.UserID(c)
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	permissionsResponse, err := h.userUseCase.GetPermissions(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get user permissions", zap.Error(err), zap.Uint("user_id", userID))
+		response.FromError(c, err)
+		return
+	}
+
+	h.logger.Info("Retrieved user permissions", zap.Uint("user_id", userID))
+	c.Header("Cache-Control", "private, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Permissions retrieved successfully",
+		"data":    permissionsResponse,
+	})
+}
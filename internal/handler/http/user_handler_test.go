@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/internal/middleware"
+	"moon/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubApprovalUseCase records which approval actions reached the use case
+type stubApprovalUseCase struct {
+	usecase.UserUseCase
+	calls []string
+}
+
+func (s *stubApprovalUseCase) GetPendingUsers(ctx context.Context, page, limit int) (*user.UsersListResponse, error) {
+	s.calls = append(s.calls, "pending")
+	return &user.UsersListResponse{}, nil
+}
+
+func (s *stubApprovalUseCase) ApproveUser(ctx context.Context, id uint) (*user.UserResponse, error) {
+	s.calls = append(s.calls, "approve")
+	return &user.UserResponse{ID: id, IsActive: true}, nil
+}
+
+func (s *stubApprovalUseCase) RejectUser(ctx context.Context, id uint) error {
+	s.calls = append(s.calls, "reject")
+	return nil
+}
+
+// Only admins reach the approval endpoints, which are mounted like in the router
+func TestApprovalEndpointsRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/admin/users/pending"},
+		{http.MethodPost, "/admin/users/2/approve"},
+		{http.MethodPost, "/admin/users/2/reject"},
+	}
+
+	tests := []struct {
+		name     string
+		role     string
+		wantCode int
+	}{
+		{"admin", "admin", http.StatusOK},
+		{"user", "user", http.StatusForbidden},
+		{"editor", "editor", http.StatusForbidden},
+		{"no role", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubApprovalUseCase{}
+			handler := NewUserHandler(stub)
+			router := gin.New()
+			admin := router.Group("/admin", func(c *gin.Context) {
+				c.Set("user_id", uint(1))
+				if tt.role != "" {
+					c.Set("role", tt.role)
+				}
+			}, middleware.RoleMiddleware("admin"))
+			admin.GET("/users/pending", handler.GetPendingUsers)
+			admin.POST("/users/:id/approve", handler.ApproveUser)
+			admin.POST("/users/:id/reject", handler.RejectUser)
+
+			for _, r := range requests {
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(r.method, r.path, nil))
+				if w.Code != tt.wantCode {
+					t.Errorf("%s %s: expected %d, got %d: %s", r.method, r.path, tt.wantCode, w.Code, w.Body.String())
+				}
+			}
+
+			wantCalls := 0
+			if tt.wantCode == http.StatusOK {
+				wantCalls = len(requests)
+			}
+			if len(stub.calls) != wantCalls {
+				t.Errorf("expected %d use case calls, got %v", wantCalls, stub.calls)
+			}
+		})
+	}
+}
+
+// namesRecorder records the IDs of a user name lookup
+type namesRecorder struct {
+	usecase.UserUseCase
+	ids []uint
+}
+
+func (n *namesRecorder) GetNames(ctx context.Context, ids []uint) (map[uint]string, error) {
+	n.ids = ids
+	return map[uint]string{}, nil
+}
+
+func TestGetUserNamesCapsIDs(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	ids := func(n int) string {
+		parts := make([]string, n)
+		for i := range parts {
+			parts[i] = strconv.Itoa(i + 1)
+		}
+		return `{"ids":[` + strings.Join(parts, ",") + `]}`
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"one", ids(1), http.StatusOK},
+		{"at the cap", ids(100), http.StatusOK},
+		{"over the cap", ids(101), http.StatusBadRequest},
+		{"empty", `{"ids":[]}`, http.StatusBadRequest},
+		{"missing", `{}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &namesRecorder{}
+			router := gin.New()
+			router.POST("/admin/users/names", NewUserHandler(uc).GetUserNames)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/names", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if (uc.ids != nil) != (tt.wantCode == http.StatusOK) {
+				t.Errorf("expected the lookup to run only for valid requests, got %v", uc.ids)
+			}
+		})
+	}
+}
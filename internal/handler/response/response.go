@@ -0,0 +1,138 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+
+	"moon/internal/domain/organization"
+	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+	"moon/internal/domain/report"
+	"moon/internal/domain/user"
+	"moon/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mapping ties a domain error to the HTTP status and machine-readable code sent to clients
+type mapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// errorMappings is checked in order with errors.Is, so wrapped errors match too.
+// Errors missing from the table are reported as 500 internal_error.
+var errorMappings = []mapping{
+	// Not found
+	{post.ErrNotFound, http.StatusNotFound, "post_not_found"},
+	{post.ErrCollaboratorNotFound, http.StatusNotFound, "collaborator_not_found"},
+	{user.ErrNotFound, http.StatusNotFound, "user_not_found"},
+	{report.ErrNotFound, http.StatusNotFound, "report_not_found"},
+	{product.ErrNotFound, http.StatusNotFound, "product_not_found"},
+	{product.ErrCategoryNotFound, http.StatusNotFound, "category_not_found"},
+	{organization.ErrNotFound, http.StatusNotFound, "organization_not_found"},
+
+	// Authentication and authorization
+	{user.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+	{user.ErrDeactivated, http.StatusUnauthorized, "account_deactivated"},
+	{user.ErrTokenRevoked, http.StatusUnauthorized, "token_revoked"},
+	{user.ErrInvalidRefresh, http.StatusUnauthorized, "invalid_refresh_token"},
+	{user.ErrPendingApproval, http.StatusForbidden, "account_pending_approval"},
+	{post.ErrPermissionDenied, http.StatusForbidden, "permission_denied"},
+	{post.ErrPostLimitReached, http.StatusForbidden, "post_limit_reached"},
+	{user.ErrSessionLimit, http.StatusForbidden, "session_limit_reached"},
+
+	// Conflicts
+	{user.ErrEmailExists, http.StatusConflict, "email_already_exists"},
+	{user.ErrEmailInUse, http.StatusConflict, "email_in_use"},
+	{user.ErrUsernameInUse, http.StatusConflict, "username_in_use"},
+	{user.ErrNotPendingApproval, http.StatusConflict, "not_pending_approval"},
+	{user.ErrLastAdmin, http.StatusConflict, "last_admin"},
+	{post.ErrSlugInUse, http.StatusConflict, "slug_in_use"},
+	{product.ErrInsufficientStock, http.StatusConflict, "insufficient_stock"},
+
+	// Invalid input
+	{user.ErrIdentifierRequired, http.StatusBadRequest, "identifier_required"},
+	{user.ErrInvalidToken, http.StatusBadRequest, "invalid_token"},
+	{user.ErrSameEmail, http.StatusBadRequest, "same_email"},
+	{user.ErrWrongPassword, http.StatusBadRequest, "wrong_password"},
+	{user.ErrSamePassword, http.StatusBadRequest, "same_password"},
+	{user.ErrInvalidUsername, http.StatusBadRequest, "invalid_username"},
+	{user.ErrInvalidRole, http.StatusBadRequest, "invalid_role"},
+	{user.ErrMergeSameUser, http.StatusBadRequest, "merge_same_user"},
+	{post.ErrTitleRequired, http.StatusBadRequest, "title_required"},
+	{post.ErrContentRequired, http.StatusBadRequest, "content_required"},
+	{post.ErrInvalidSlug, http.StatusBadRequest, "invalid_slug"},
+	{post.ErrInvalidStatus, http.StatusBadRequest, "invalid_status"},
+	{post.ErrInvalidUnpublishStatus, http.StatusBadRequest, "invalid_unpublish_status"},
+	{post.ErrInvalidSort, http.StatusBadRequest, "invalid_sort"},
+	{post.ErrUnsupportedLanguage, http.StatusBadRequest, "unsupported_language"},
+	{post.ErrInvalidArchiveMonth, http.StatusBadRequest, "invalid_archive_month"},
+	{post.ErrInvalidAttentionRule, http.StatusBadRequest, "invalid_attention_rule"},
+	{post.ErrInvalidSearch, http.StatusBadRequest, "invalid_search"},
+	{post.ErrTooManyTags, http.StatusBadRequest, "too_many_tags"},
+	{post.ErrInvalidTag, http.StatusBadRequest, "invalid_tag"},
+	{post.ErrAuthorIsCollaborator, http.StatusBadRequest, "author_is_collaborator"},
+	{post.ErrNoMarkdownFiles, http.StatusBadRequest, "no_markdown_files"},
+	{post.ErrTooManyFiles, http.StatusBadRequest, "too_many_files"},
+	{post.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor"},
+	{product.ErrUnsupportedCurrency, http.StatusBadRequest, "unsupported_currency"},
+	{product.ErrInvalidPriceRange, http.StatusBadRequest, "invalid_price_range"},
+	{product.ErrInvalidQuantity, http.StatusBadRequest, "invalid_quantity"},
+
+	// Content that is well-formed but cannot be accepted
+	{post.ErrCategoryRequired, http.StatusUnprocessableEntity, "category_required"},
+	{post.ErrInvalidCategory, http.StatusUnprocessableEntity, "invalid_category"},
+	{post.ErrContentTooLarge, http.StatusUnprocessableEntity, "content_too_large"},
+	{post.ErrContentNotUTF8, http.StatusUnprocessableEntity, "content_not_utf8"},
+	{post.ErrBlockedWords, http.StatusUnprocessableEntity, "blocked_words"},
+	{post.ErrNotPublishable, http.StatusUnprocessableEntity, "not_publishable"},
+
+	// Query limits
+	{usecase.ErrResultTooLarge, http.StatusRequestEntityTooLarge, "result_too_large"},
+
+	// Throttling and availability
+	{report.ErrTooManyReports, http.StatusTooManyRequests, "too_many_reports"},
+	{report.ErrUnavailable, http.StatusServiceUnavailable, "reporting_unavailable"},
+}
+
+// Status returns the HTTP status and error code for err
+func Status(err error) (int, string) {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			return m.status, m.code
+		}
+	}
+	return http.StatusInternalServerError, "internal_error"
+}
+
+// FromError writes err as a JSON error response with the status mapped from its domain error
+func FromError(c *gin.Context, err error) {
+	status, code := Status(err)
+	body := ErrorBody(err)
+	body["code"] = code
+	c.JSON(status, body)
+}
+
+// ErrorBody builds the JSON body for a failed request. In debug mode it also lists
+// the chain of wrapped errors under "debug" so the underlying cause is visible.
+func ErrorBody(err error) gin.H {
+	body := gin.H{
+		"error": err.Error(),
+	}
+
+	if gin.Mode() != gin.DebugMode {
+		return body
+	}
+
+	var causes []string
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		causes = append(causes, cause.Error())
+	}
+	if len(causes) > 0 {
+		body["debug"] = causes
+	}
+
+	return body
+}
@@ -0,0 +1,119 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+	"moon/internal/domain/report"
+	"moon/internal/domain/user"
+	"moon/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFromError(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", post.ErrNotFound, http.StatusNotFound, "post_not_found"},
+		{"wrapped not found", fmt.Errorf("failed to fetch post: %w", post.ErrNotFound), http.StatusNotFound, "post_not_found"},
+		{"other domain's not found", product.ErrCategoryNotFound, http.StatusNotFound, "category_not_found"},
+		{"unauthorized", user.ErrTokenRevoked, http.StatusUnauthorized, "token_revoked"},
+		{"forbidden", post.ErrPermissionDenied, http.StatusForbidden, "permission_denied"},
+		{"wrapped forbidden", fmt.Errorf("update: %w", fmt.Errorf("check: %w", post.ErrPostLimitReached)), http.StatusForbidden, "post_limit_reached"},
+		{"conflict", user.ErrEmailExists, http.StatusConflict, "email_already_exists"},
+		{"bad request", post.ErrInvalidTag, http.StatusBadRequest, "invalid_tag"},
+		{"detailed bad request", fmt.Errorf("%w: %q contains %q", post.ErrInvalidTag, "a!", '!'), http.StatusBadRequest, "invalid_tag"},
+		{"unprocessable", post.ErrBlockedWords, http.StatusUnprocessableEntity, "blocked_words"},
+		{"too large", usecase.ErrResultTooLarge, http.StatusRequestEntityTooLarge, "result_too_large"},
+		{"throttled", report.ErrTooManyReports, http.StatusTooManyRequests, "too_many_reports"},
+		{"unavailable", report.ErrUnavailable, http.StatusServiceUnavailable, "reporting_unavailable"},
+		{"unknown", errors.New("boom"), http.StatusInternalServerError, "internal_error"},
+		{"wrapped unknown", fmt.Errorf("failed: %w", errors.New("boom")), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			FromError(c, tt.err)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid body %q: %v", w.Body.String(), err)
+			}
+			if body["code"] != tt.code {
+				t.Errorf("expected code %q, got %v", tt.code, body["code"])
+			}
+			if body["error"] != tt.err.Error() {
+				t.Errorf("expected error %q, got %v", tt.err.Error(), body["error"])
+			}
+		})
+	}
+}
+
+// Every sentinel in the table must reach its own entry, plain or wrapped, rather than be
+// caught by an earlier one
+func TestStatusCoversEverySentinel(t *testing.T) {
+	for _, m := range errorMappings {
+		for _, err := range []error{m.err, fmt.Errorf("wrapped: %w", m.err)} {
+			status, code := Status(err)
+			if status != m.status || code != m.code {
+				t.Errorf("%q: got %d %s, want %d %s", err, status, code, m.status, m.code)
+			}
+		}
+	}
+}
+
+func TestErrorBodyDebugCauses(t *testing.T) {
+	err := fmt.Errorf("failed to create post: %w", errors.New("connection refused"))
+
+	tests := []struct {
+		mode      string
+		wantDebug bool
+	}{
+		{gin.DebugMode, true},
+		{gin.ReleaseMode, false},
+		{gin.TestMode, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			gin.SetMode(tt.mode)
+			defer gin.SetMode(gin.ReleaseMode)
+
+			body := ErrorBody(err)
+			if body["error"] != err.Error() {
+				t.Errorf("expected the message %q, got %v", err.Error(), body["error"])
+			}
+			debug, ok := body["debug"].([]string)
+			if ok != tt.wantDebug {
+				t.Fatalf("expected debug causes %v, got %v", tt.wantDebug, body["debug"])
+			}
+			if ok && (len(debug) != 1 || debug[0] != "connection refused") {
+				t.Errorf("expected the wrapped cause, got %v", debug)
+			}
+		})
+	}
+
+	gin.SetMode(gin.DebugMode)
+	defer gin.SetMode(gin.ReleaseMode)
+	if _, ok := ErrorBody(errors.New("boom"))["debug"]; ok {
+		t.Error("expected no debug field for an error without causes")
+	}
+}
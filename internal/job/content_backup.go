@@ -0,0 +1,36 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/usecase"
+	"moon/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// NewContentBackupJob creates the job that exports content to backup storage
+func NewContentBackupJob(backupUseCase usecase.BackupUseCase, cfg config.BackupConfig) Job {
+	return Job{
+		Name:     "content-backup",
+		Interval: time.Duration(cfg.IntervalMinutes) * time.Minute,
+		Run: func(ctx context.Context) error {
+			result, err := backupUseCase.BackupContent(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Content backup completed",
+				zap.String("key", result.Key),
+				zap.Int64("size_bytes", result.Size),
+				zap.Int("posts", result.Posts),
+				zap.Int("users", result.Users),
+				zap.Strings("pruned", result.Pruned),
+				zap.Duration("elapsed", result.Elapsed),
+			)
+			return nil
+		},
+	}
+}
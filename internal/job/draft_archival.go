@@ -0,0 +1,44 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/usecase"
+	"moon/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// NewDraftArchivalJob creates the job that archives or deletes abandoned drafts
+func NewDraftArchivalJob(postUseCase usecase.PostUseCase, cfg config.DraftArchivalConfig) Job {
+	return Job{
+		Name:     "draft-archival",
+		Interval: time.Duration(cfg.IntervalMinutes) * time.Minute,
+		Run: func(ctx context.Context) error {
+			ids, affected, err := postUseCase.CleanupStaleDrafts(ctx)
+			if err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+
+			if cfg.DryRun {
+				logger.Info("Draft archival dry run",
+					zap.String("action", cfg.Action),
+					zap.Uints("post_ids", ids),
+				)
+				return nil
+			}
+
+			logger.Info("Cleaned up stale drafts",
+				zap.String("action", cfg.Action),
+				zap.Uints("post_ids", ids),
+				zap.Int64("affected", affected),
+			)
+			return nil
+		},
+	}
+}
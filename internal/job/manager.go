@@ -0,0 +1,71 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"moon/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Worker is a background component whose lifecycle is owned by the Manager
+type Worker interface {
+	// Start launches the worker's goroutines; they must exit once ctx is cancelled
+	Start(ctx context.Context)
+	// Stop blocks until the worker has drained its pending work or ctx is done
+	Stop(ctx context.Context) error
+}
+
+type namedWorker struct {
+	name   string
+	worker Worker
+}
+
+// Manager starts background workers together and drains them on shutdown
+type Manager struct {
+	workers []namedWorker
+	cancel  context.CancelFunc
+}
+
+// NewManager creates a new worker manager
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Add registers a worker; workers start in registration order and stop in reverse
+func (m *Manager) Add(name string, worker Worker) {
+	m.workers = append(m.workers, namedWorker{name: name, worker: worker})
+}
+
+// Start launches every registered worker. They run until Stop is called, even if ctx
+// is cancelled earlier.
+func (m *Manager) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	for _, w := range m.workers {
+		w.worker.Start(ctx)
+		logger.Info("Worker started", zap.String("worker", w.name))
+	}
+}
+
+// Stop signals every worker to finish and waits for them to drain until ctx is done.
+// Workers that do not finish in time are reported in the returned error.
+func (m *Manager) Stop(ctx context.Context) error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+
+	var errs []error
+	for i := len(m.workers) - 1; i >= 0; i-- {
+		w := m.workers[i]
+		if err := w.worker.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", w.name, err))
+			continue
+		}
+		logger.Info("Worker stopped", zap.String("worker", w.name))
+	}
+
+	return errors.Join(errs...)
+}
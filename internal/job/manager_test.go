@@ -0,0 +1,88 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"moon/pkg/cache/cachetest"
+)
+
+// A scheduled run in flight at shutdown finishes with a live context before Stop returns
+func TestManagerDrainsInFlightRuns(t *testing.T) {
+	_, store := cachetest.NewServer(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	runErr := make(chan error, 1)
+	scheduler := NewScheduler(store)
+	scheduler.Register(Job{
+		Name:     "slow",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			select {
+			case <-started:
+				return nil // only the first run is observed
+			default:
+			}
+			close(started)
+			<-release
+			runErr <- ctx.Err()
+			return nil
+		},
+	})
+
+	workers := NewManager()
+	workers.Add("scheduler", scheduler)
+	workers.Start(context.Background())
+	<-started
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- workers.Stop(context.Background()) }()
+
+	select {
+	case err := <-stopped:
+		t.Fatalf("Stop returned with a run in flight: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-stopped; err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if err := <-runErr; err != nil {
+		t.Errorf("expected the in-flight run to keep a live context, got %v", err)
+	}
+}
+
+func TestManagerStopDeadline(t *testing.T) {
+	_, store := cachetest.NewServer(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	var once sync.Once
+	scheduler := NewScheduler(store)
+	scheduler.Register(Job{
+		Name:     "stuck",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			once.Do(func() { close(started) })
+			<-release
+			return nil
+		},
+	})
+
+	workers := NewManager()
+	workers.Add("scheduler", scheduler)
+	workers.Start(context.Background())
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := workers.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the stuck worker to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/usecase"
+	"moon/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// NewScheduledPublishJob creates the job that publishes drafts whose publish time has passed
+func NewScheduledPublishJob(postUseCase usecase.PostUseCase, cfg config.ScheduledPublishingConfig) Job {
+	return Job{
+		Name:     "scheduled-publish",
+		Interval: time.Duration(cfg.IntervalMinutes) * time.Minute,
+		Run: func(ctx context.Context) error {
+			result, err := postUseCase.PublishDuePosts(ctx)
+			if result != nil && len(result.Published) > 0 {
+				logger.Info("Published scheduled posts", zap.Uints("post_ids", result.Published))
+			}
+			if result != nil && len(result.Skipped) > 0 {
+				logger.Warn("Scheduled posts not publishable", zap.Uints("post_ids", result.Skipped))
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"moon/pkg/cache"
+	"moon/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Job is a task run periodically by the scheduler
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs on fixed intervals. Each run is guarded by a distributed lock so
+// that only one instance executes a job at a time; runs are skipped while Redis is down.
+type Scheduler struct {
+	cache *cache.Cache
+	jobs  []Job
+	wg    sync.WaitGroup
+}
+
+// NewScheduler creates a new scheduler
+func NewScheduler(cache *cache.Cache) *Scheduler {
+	return &Scheduler{
+		cache: cache,
+	}
+}
+
+// Register adds a job to the scheduler
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs every registered job in its own goroutine until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Stop waits for the job loops to exit after the start context is cancelled, letting
+// in-flight runs finish, or until ctx is done
+func (s *Scheduler) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A run that has started is allowed to finish during shutdown; Stop bounds the wait
+			s.runOnce(context.WithoutCancel(ctx), job)
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	lockKey := "job:lock:" + job.Name
+
+	token, ok, err := s.cache.TryLock(ctx, lockKey, job.Interval)
+	if err != nil {
+		logger.Warn("Skipping job, lock unavailable", zap.String("job", job.Name), zap.Error(err))
+		return
+	}
+	if !ok {
+		// Another instance is running this job
+		return
+	}
+	defer s.cache.Unlock(context.Background(), lockKey, token)
+
+	if err := job.Run(ctx); err != nil {
+		logger.Error("Job failed", zap.String("job", job.Name), zap.Error(err))
+	}
+}
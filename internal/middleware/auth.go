@@ -1,50 +1,118 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/internal/usecase"
 	"moon/pkg/jwt"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ClaimsKey is the context key under which the parsed token claims are stored
+const ClaimsKey = "claims"
+
+// GetClaims returns the token claims stored by AuthMiddleware
+func GetClaims(c *gin.Context) (*jwt.Claims, bool) {
+	value, exists := c.Get(ClaimsKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*jwt.Claims)
+	return claims, ok
+}
+
 // AuthMiddleware validates JWT token and sets user info in context
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(authUseCase usecase.AuthUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		claims, status, message := authenticate(c, authUseCase)
+		if claims == nil {
+			c.JSON(status, gin.H{"error": message})
 			c.Abort()
 			return
 		}
 
-		// Check if the header starts with "Bearer "
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
-			return
+		setUserInfo(c, claims)
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware sets user info in context when the request carries a valid token,
+// and otherwise lets it through anonymously
+func OptionalAuthMiddleware(authUseCase usecase.AuthUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			if claims, _, _ := authenticate(c, authUseCase); claims != nil {
+				setUserInfo(c, claims)
+			}
 		}
 
-		// Extract the token
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		c.Next()
+	}
+}
+
+// authenticate parses and validates the bearer token of the request. When the token is
+// missing or rejected it returns nil claims with the status and reason to answer with.
+func authenticate(c *gin.Context, authUseCase usecase.AuthUseCase) (*jwt.Claims, int, string) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return nil, http.StatusUnauthorized, "Authorization header is required"
+	}
 
-		// Validate the token
-		cfg := config.GetConfig()
-		claims, err := jwt.ValidateToken(tokenString, cfg.JWT.Secret)
+	// Check if the header starts with "Bearer "
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, http.StatusUnauthorized, "Invalid authorization header format"
+	}
+
+	// Extract the token
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	// Parse the token
+	cfg := config.GetConfig()
+	claims, err := jwt.ParseToken(tokenString, cfg.JWT.Secret)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, http.StatusUnauthorized, "Token has expired"
+		}
+		return nil, http.StatusUnauthorized, "Invalid token"
+	}
+
+	// Reject tokens issued before the user's token version was bumped
+	if err := authUseCase.ValidateTokenVersion(c.Request.Context(), claims.UserID, claims.TokenVersion); err != nil {
+		if errors.Is(err, user.ErrDeactivated) {
+			return nil, http.StatusUnauthorized, "User account is deactivated"
+		}
+		return nil, http.StatusUnauthorized, "Token has been revoked"
+	}
+
+	// Reject tokens that were logged out. Without the blacklist the token can't be checked,
+	// which is the server's failure rather than the token's.
+	if claims.ID != "" {
+		blacklisted, err := authUseCase.IsTokenBlacklisted(c.Request.Context(), claims.ID)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
-			return
+			return nil, http.StatusServiceUnavailable, "Service temporarily unavailable"
+		}
+		if blacklisted {
+			return nil, http.StatusUnauthorized, "Token has been revoked"
 		}
+	}
 
-		// Set user info in context
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
+	return claims, 0, ""
+}
 
-		c.Next()
+// setUserInfo stores the claims in context, keeping the individual keys for compatibility
+func setUserInfo(c *gin.Context, claims *jwt.Claims) {
+	c.Set(ClaimsKey, claims)
+	c.Set("user_id", claims.UserID)
+	c.Set("email", claims.Email)
+	c.Set("role", claims.Role)
+	c.Set("token_id", claims.ID)
+	if claims.ExpiresAt != nil {
+		c.Set("token_expires_at", claims.ExpiresAt.Time)
 	}
 }
 
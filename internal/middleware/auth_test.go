@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/internal/usecase"
+	"moon/pkg/cache"
+	"moon/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubAuthUseCase accepts every token version except revokedVersion, and treats tokens of
+// deactivatedVersion as belonging to a deactivated user. With loggedOut set every token is
+// blacklisted, and blacklistErr fails the blacklist check.
+type stubAuthUseCase struct {
+	usecase.AuthUseCase
+	revokedVersion     int
+	deactivatedVersion int
+	loggedOut          bool
+	blacklistErr       error
+}
+
+func (s *stubAuthUseCase) ValidateTokenVersion(ctx context.Context, userID uint, tokenVersion int) error {
+	switch tokenVersion {
+	case s.revokedVersion:
+		return user.ErrTokenRevoked
+	case s.deactivatedVersion:
+		return user.ErrDeactivated
+	}
+	return nil
+}
+
+func (s *stubAuthUseCase) IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	return s.loggedOut, s.blacklistErr
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	cfg := config.GetConfig()
+	saved := cfg.JWT
+	t.Cleanup(func() { cfg.JWT = saved })
+	cfg.JWT.Secret = testJWTSecret
+
+	token := func(version, expiresIn int, secret string) string {
+		t.Helper()
+		signed, err := jwt.GenerateToken(7, "a@example.com", "editor", version, 0, secret, expiresIn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + signed
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantError     string
+	}{
+		{"valid", token(1, 1, testJWTSecret), http.StatusOK, ""},
+		{"missing", "", http.StatusUnauthorized, "Authorization header is required"},
+		{"not a bearer token", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, "Invalid authorization header format"},
+		{"expired", token(1, -1, testJWTSecret), http.StatusUnauthorized, "Token has expired"},
+		{"invalid", token(1, 1, "other-secret"), http.StatusUnauthorized, "Invalid token"},
+		{"revoked", token(2, 1, testJWTSecret), http.StatusUnauthorized, "Token has been revoked"},
+		{"deactivated", token(3, 1, testJWTSecret), http.StatusUnauthorized, "User account is deactivated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims *jwt.Claims
+			var userID uint
+			var role string
+
+			router := gin.New()
+			router.GET("/me", AuthMiddleware(&stubAuthUseCase{revokedVersion: 2, deactivatedVersion: 3}), func(c *gin.Context) {
+				claims, _ = GetClaims(c)
+				userID = c.GetUint("user_id")
+				role = c.GetString("role")
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantError != "" {
+				var body map[string]string
+				json.Unmarshal(w.Body.Bytes(), &body)
+				if body["error"] != tt.wantError {
+					t.Errorf("expected error %q, got %q", tt.wantError, body["error"])
+				}
+				return
+			}
+
+			if claims == nil || claims.UserID != 7 || claims.Role != "editor" {
+				t.Errorf("expected the typed claims in context, got %+v", claims)
+			}
+			if userID != 7 || role != "editor" {
+				t.Errorf("expected user_id and role kept for compatibility, got %d %q", userID, role)
+			}
+		})
+	}
+}
+
+// An unreadable blacklist is reported as an outage, not as every token being logged out
+func TestAuthMiddlewareBlacklist(t *testing.T) {
+	cfg := config.GetConfig()
+	saved := cfg.JWT
+	t.Cleanup(func() { cfg.JWT = saved })
+	cfg.JWT.Secret = testJWTSecret
+
+	tests := []struct {
+		name       string
+		stub       *stubAuthUseCase
+		wantStatus int
+		wantError  string
+	}{
+		{"logged out", &stubAuthUseCase{revokedVersion: -1, deactivatedVersion: -1, loggedOut: true}, http.StatusUnauthorized, "Token has been revoked"},
+		{"blacklist unavailable", &stubAuthUseCase{revokedVersion: -1, deactivatedVersion: -1, blacklistErr: cache.ErrUnavailable}, http.StatusServiceUnavailable, "Service temporarily unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/me", AuthMiddleware(tt.stub), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", bearer(t, 7, "user", testJWTSecret))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var body map[string]string
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if w.Code != tt.wantStatus || body["error"] != tt.wantError {
+				t.Errorf("expected %d %q, got %d %q", tt.wantStatus, tt.wantError, w.Code, body["error"])
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"moon/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConcurrencyLimit caps how many requests a single client IP may have in flight at once,
+// answering the excess with 429. Unlike RateLimit the counters live in process memory, so
+// the cap applies per instance. Exempt IPs and CIDRs are never limited. The client IP only
+// honours X-Forwarded-For from the engine's trusted proxies, so a client can't claim a fresh
+// set of slots by sending the header itself.
+func ConcurrencyLimit() gin.HandlerFunc {
+	cfg := config.GetConfig().ConcurrencyLimit
+	exempt := parseTrustedProxies(cfg.ExemptIPs)
+	limiter := newConcurrencyLimiter(cfg.PerIP)
+
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		if ipInNetworks(ip, exempt) {
+			c.Next()
+			return
+		}
+
+		if !limiter.acquire(ip) {
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": "Too many concurrent requests",
+			})
+			return
+		}
+		// Deferred so the slot is freed even when a handler panics
+		defer limiter.release(ip)
+
+		c.Next()
+	}
+}
+
+// concurrencyLimiter is a counting semaphore per key; keys are dropped once idle so the
+// map only holds clients with requests in flight
+type concurrencyLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	inFlight map[string]int
+}
+
+func newConcurrencyLimiter(limit int) *concurrencyLimiter {
+	return &concurrencyLimiter{
+		limit:    limit,
+		inFlight: make(map[string]int),
+	}
+}
+
+// acquire takes a slot for key, reporting false when all of its slots are in use
+func (l *concurrencyLimiter) acquire(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[key] >= l.limit {
+		return false
+	}
+	l.inFlight[key]++
+	return true
+}
+
+func (l *concurrencyLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[key] <= 1 {
+		delete(l.inFlight, key)
+		return
+	}
+	l.inFlight[key]--
+}
+
+// ipInNetworks reports whether ip falls inside any of the networks
+func ipInNetworks(ip string, networks []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"moon/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setConcurrencyLimit(t *testing.T, perIP int, exemptIPs ...string) {
+	t.Helper()
+	cfg := config.GetConfig()
+	saved := cfg.ConcurrencyLimit
+	t.Cleanup(func() { cfg.ConcurrencyLimit = saved })
+	cfg.ConcurrencyLimit = config.ConcurrencyLimitConfig{Enabled: true, PerIP: perIP, ExemptIPs: exemptIPs}
+}
+
+// concurrencyRouter serves /slow, which holds its slot until release is closed, and
+// /panic, which fails after taking one
+func concurrencyRouter(started chan<- struct{}, release <-chan struct{}) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ interface{}) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.Use(ConcurrencyLimit())
+	router.GET("/slow", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("handler failed")
+	})
+	return router
+}
+
+func serveFrom(router *gin.Engine, path, ip string) int {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	setConcurrencyLimit(t, 2, "10.0.0.0/8")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := concurrencyRouter(started, release)
+
+	// Fill both slots of one client with requests held in flight
+	var wg sync.WaitGroup
+	codes := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- serveFrom(router, "/slow", "192.0.2.1")
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		<-started
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the third concurrent request to get 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+
+	// Other clients and exempt networks have slots of their own
+	for _, ip := range []string{"192.0.2.2", "10.1.2.3", "10.1.2.3", "10.1.2.3"} {
+		go serveFrom(router, "/slow", ip)
+		<-started
+	}
+
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("expected requests within the cap to succeed, got %d", code)
+		}
+	}
+
+	// Slots are returned once requests finish
+	go func() { <-started }()
+	if code := serveFrom(router, "/slow", "192.0.2.1"); code != http.StatusOK {
+		t.Errorf("expected a request after the others finished to succeed, got %d", code)
+	}
+}
+
+func TestConcurrencyLimitReleasesOnPanic(t *testing.T) {
+	setConcurrencyLimit(t, 1)
+	router := concurrencyRouter(make(chan struct{}), make(chan struct{}))
+
+	for i := 0; i < 3; i++ {
+		if code := serveFrom(router, "/panic", "192.0.2.1"); code != http.StatusInternalServerError {
+			t.Fatalf("request %d: expected the panic to be recovered with 500, got %d", i+1, code)
+		}
+	}
+}
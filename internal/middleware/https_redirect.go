@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"moon/internal/config"
+	"moon/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// HTTPSRedirect redirects requests that reached the app over plain HTTP to the same path on
+// HTTPS (see isPlainHTTP). The target uses the configured https.host rather than the
+// client-supplied Host header, so the redirect can't be pointed at another site. GET and
+// HEAD get a 301, other methods a 308 so the method and body are kept.
+func HTTPSRedirect() gin.HandlerFunc {
+	cfg := config.GetConfig().HTTPS
+	proxies := parseTrustedProxies(cfg.TrustedProxies)
+
+	return func(c *gin.Context) {
+		if slices.Contains(cfg.ExemptPaths, c.Request.URL.Path) || !isPlainHTTP(c.Request, proxies) {
+			c.Next()
+			return
+		}
+
+		status := http.StatusMovedPermanently
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			status = http.StatusPermanentRedirect
+		}
+		c.Redirect(status, "https://"+cfg.Host+c.Request.URL.RequestURI())
+		c.Abort()
+	}
+}
+
+// isPlainHTTP reports whether the client's request was sent without TLS. A peer other than
+// a trusted proxy reached the app directly, so its plain connection counts whatever
+// X-Forwarded-Proto it sends; a client can't skip the redirect by claiming https. From a
+// trusted proxy the header is believed, and requests without it are left alone so a proxy
+// that doesn't report the scheme can't cause a redirect loop.
+func isPlainHTTP(r *http.Request, proxies []*net.IPNet) bool {
+	if r.TLS != nil {
+		return false
+	}
+	if !isTrustedPeer(r.RemoteAddr, proxies) {
+		return true
+	}
+
+	proto := r.Header.Get("X-Forwarded-Proto")
+	if proto == "" {
+		return false
+	}
+
+	// A chain of proxies appends its schemes; the first is the client's
+	first, _, _ := strings.Cut(proto, ",")
+	return strings.EqualFold(strings.TrimSpace(first), "http")
+}
+
+// isTrustedPeer reports whether the connection comes from a trusted proxy; with no proxies
+// configured every peer is trusted
+func isTrustedPeer(remoteAddr string, proxies []*net.IPNet) bool {
+	if len(proxies) == 0 {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies turns IPs and CIDRs into networks; single IPs become /32 or /128
+func parseTrustedProxies(values []string) []*net.IPNet {
+	var proxies []*net.IPNet
+	for _, value := range values {
+		if _, network, err := net.ParseCIDR(value); err == nil {
+			proxies = append(proxies, network)
+			continue
+		}
+		ip := net.ParseIP(value)
+		if ip == nil {
+			logger.Warn("Ignoring invalid trusted proxy", zap.String("proxy", value))
+			continue
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 32
+		}
+		proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return proxies
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"moon/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHTTPSRedirect(t *testing.T) {
+	cfg := config.GetConfig()
+	saved := cfg.HTTPS
+	t.Cleanup(func() { cfg.HTTPS = saved })
+	cfg.HTTPS = config.HTTPSConfig{
+		Redirect:       true,
+		Host:           "example.com",
+		TrustedProxies: []string{"10.0.0.1"},
+		ExemptPaths:    []string{"/ping"},
+	}
+
+	router := gin.New()
+	router.Use(HTTPSRedirect())
+	router.Any("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	const proxy, client = "10.0.0.1:1234", "203.0.113.7:1234"
+
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		remoteAddr   string
+		proto        string
+		tls          bool
+		wantStatus   int
+		wantLocation string
+	}{
+		{"plain through the proxy", http.MethodGet, "/posts?page=2", proxy, "http", false, http.StatusMovedPermanently, "https://example.com/posts?page=2"},
+		{"plain post through the proxy", http.MethodPost, "/posts", proxy, "http", false, http.StatusPermanentRedirect, "https://example.com/posts"},
+		{"https through the proxy", http.MethodGet, "/posts", proxy, "https", false, http.StatusOK, ""},
+		{"first scheme of a proxy chain", http.MethodGet, "/posts", proxy, "https, http", false, http.StatusOK, ""},
+		{"proxy without the header", http.MethodGet, "/posts", proxy, "", false, http.StatusOK, ""},
+		{"direct client claiming https", http.MethodGet, "/posts", client, "https", false, http.StatusMovedPermanently, "https://example.com/posts"},
+		{"direct client without the header", http.MethodGet, "/posts", client, "", false, http.StatusMovedPermanently, "https://example.com/posts"},
+		{"direct TLS connection", http.MethodGet, "/posts", client, "", true, http.StatusOK, ""},
+		{"exempt path", http.MethodGet, "/ping", client, "http", false, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.RemoteAddr = tt.remoteAddr
+			// A spoofed Host must never end up in the redirect
+			req.Host = "evil.example"
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != tt.wantLocation {
+				t.Errorf("expected location %q, got %q", tt.wantLocation, location)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"bytes"
+	"strings"
+
+	"moon/internal/config"
+	"moon/pkg/jsoncase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSONCase rewrites the keys of JSON responses to camelCase when app.json_case is "camel".
+// Responses are produced in snake_case by the handlers, so with the default setting this
+// is a no-op. Request bodies are not affected.
+func JSONCase() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if config.GetConfig().App.JSONCase != "camel" {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		body := writer.body.Bytes()
+		if len(body) == 0 {
+			writer.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		if strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json") {
+			if converted, err := jsoncase.ToCamel(body); err == nil {
+				body = converted
+			}
+		}
+		writer.ResponseWriter.Write(body)
+	}
+}
+
+// bufferedWriter holds the response body back so it can be rewritten once the handler is done
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
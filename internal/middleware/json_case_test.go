@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setJSONCase(t *testing.T, jsonCase string) {
+	t.Helper()
+	cfg := config.GetConfig()
+	saved := cfg.App.JSONCase
+	t.Cleanup(func() { cfg.App.JSONCase = saved })
+	cfg.App.JSONCase = jsonCase
+}
+
+func TestJSONCase(t *testing.T) {
+	sample := post.PostsListResponse{
+		Posts: []post.PostResponse{{
+			ID:         1,
+			Title:      "snake_case stays in values",
+			AuthorName: "A",
+			ViewCount:  3,
+			IsPublic:   true,
+			Tags:       []string{},
+			CreatedAt:  time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt:  time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		Total:      1,
+		Page:       1,
+		Limit:      10,
+		TotalPages: 1,
+	}
+
+	tests := []struct {
+		jsonCase string
+		want     []string
+		notWant  []string
+	}{
+		{"snake", []string{`"total_pages":1`, `"author_name":"A"`, `"view_count":3`, `"is_public":true`}, []string{"totalPages", "authorName"}},
+		{"camel", []string{`"totalPages":1`, `"authorName":"A"`, `"viewCount":3`, `"isPublic":true`, `"snake_case stays in values"`}, []string{"total_pages", "author_name", "view_count"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.jsonCase, func(t *testing.T) {
+			setJSONCase(t, tt.jsonCase)
+
+			router := gin.New()
+			router.Use(JSONCase())
+			router.GET("/posts", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"data": sample})
+			})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", w.Code)
+			}
+			body := w.Body.String()
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("expected %s in %s", want, body)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("expected no %s in %s", notWant, body)
+				}
+			}
+		})
+	}
+}
+
+// Only JSON is rewritten, and bodiless responses still go out with their status
+func TestJSONCaseLeavesOtherResponses(t *testing.T) {
+	setJSONCase(t, "camel")
+
+	router := gin.New()
+	router.Use(JSONCase())
+	router.GET("/text", func(c *gin.Context) {
+		c.String(http.StatusOK, `{"total_pages":1}`)
+	})
+	router.GET("/empty", func(c *gin.Context) {
+		c.Status(http.StatusNotModified)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/text", nil))
+	if got := w.Body.String(); got != `{"total_pages":1}` {
+		t.Errorf("expected a text body to be left alone, got %s", got)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/empty", nil))
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("expected an empty 304, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"os"
+	"testing"
+
+	"moon/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestMain loads the default configuration, which the middleware read per request;
+// tests change the sections they exercise and restore them when done
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.ReleaseMode)
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/usecase"
+	"moon/pkg/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit caps the number of requests per fixed window. Requests with a valid bearer
+// token are counted per user against the limit of their role; other requests are counted
+// per client IP against the anonymous limit. It runs ahead of the auth middleware, so the
+// token is validated here as well, or a logged out or demoted token would keep the budget
+// of the role it was issued for.
+func RateLimit(store *cache.Cache, authUseCase usecase.AuthUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.GetConfig()
+		if !cfg.RateLimit.Enabled {
+			c.Next()
+			return
+		}
+
+		bucket := rateLimitBucketFor(c, cfg, authUseCase)
+		key, limit := bucket.key, bucket.limit
+		if limit == 0 {
+			c.Next()
+			return
+		}
+
+		window := time.Duration(cfg.RateLimit.WindowSeconds) * time.Second
+		ctx := c.Request.Context()
+
+		count, err := store.IncrWithExpiry(ctx, key, window)
+		if err != nil {
+			if cfg.Redis.FailOpen.RateLimit {
+				c.Next()
+				return
+			}
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Service temporarily unavailable"})
+			c.Abort()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(max(int64(limit)-count, 0), 10))
+
+		if count > int64(limit) {
+			retryAfter := window
+			if ttl, err := store.TTL(ctx, key); err == nil && ttl > 0 {
+				retryAfter = ttl
+			}
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitBucket is the counter a request is charged to
+type rateLimitBucket struct {
+	key   string
+	scope string // "user" or "ip"
+	role  string
+	limit int // 0 means unlimited
+}
+
+// rateLimitBucketFor returns the bucket the request is counted in. Only a token that passes
+// the same checks as AuthMiddleware gets a user bucket; anything else is counted per IP.
+func rateLimitBucketFor(c *gin.Context, cfg *config.Config, authUseCase usecase.AuthUseCase) rateLimitBucket {
+	if strings.HasPrefix(c.GetHeader("Authorization"), "Bearer ") {
+		if claims, _, _ := authenticate(c, authUseCase); claims != nil {
+			limit, ok := cfg.RateLimit.Roles[claims.Role]
+			if !ok {
+				limit = cfg.RateLimit.Authenticated
+			}
+			return rateLimitBucket{
+				key:   fmt.Sprintf("ratelimit:user:%d", claims.UserID),
+				scope: "user",
+				role:  claims.Role,
+				limit: limit,
+			}
+		}
+	}
+	return rateLimitBucket{
+		key:   fmt.Sprintf("ratelimit:ip:%s", c.ClientIP()),
+		scope: "ip",
+		limit: cfg.RateLimit.Anonymous,
+	}
+}
+
+// RateLimitStatus reports the caller's current rate limit budget without counting the
+// request against it, so it must be registered outside the routes RateLimit applies to
+func RateLimitStatus(store *cache.Cache, authUseCase usecase.AuthUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.GetConfig()
+		if !cfg.RateLimit.Enabled {
+			c.JSON(http.StatusOK, gin.H{
+				"data": gin.H{"enabled": false},
+			})
+			return
+		}
+
+		bucket := rateLimitBucketFor(c, cfg, authUseCase)
+		status := gin.H{
+			"enabled":        true,
+			"scope":          bucket.scope,
+			"limit":          bucket.limit,
+			"window_seconds": cfg.RateLimit.WindowSeconds,
+		}
+		if bucket.role != "" {
+			status["role"] = bucket.role
+		}
+		if bucket.limit == 0 {
+			status["unlimited"] = true
+			c.JSON(http.StatusOK, gin.H{"data": status})
+			return
+		}
+
+		ctx := c.Request.Context()
+		window := time.Duration(cfg.RateLimit.WindowSeconds) * time.Second
+
+		var used int64
+		value, err := store.Get(ctx, bucket.key)
+		if err == nil {
+			used, _ = strconv.ParseInt(value, 10, 64)
+		} else if !errors.Is(err, cache.ErrMiss) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Service temporarily unavailable"})
+			return
+		}
+
+		// An unused bucket starts a full window with the next request
+		resetIn := window
+		if used > 0 {
+			if ttl, err := store.TTL(ctx, bucket.key); err == nil && ttl > 0 {
+				resetIn = ttl
+			}
+		}
+
+		status["remaining"] = max(int64(bucket.limit)-used, 0)
+		status["reset_at"] = time.Now().Add(resetIn).UTC().Truncate(time.Second)
+		c.JSON(http.StatusOK, gin.H{"data": status})
+	}
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"moon/internal/config"
+	"moon/pkg/cache"
+	"moon/pkg/cache/cachetest"
+	"moon/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testJWTSecret = "test-secret"
+
+// setRateLimit replaces the rate limit settings for the test: anonymous requests get 2 per
+// window, users 5, editors 10 and admins are unlimited
+func setRateLimit(t *testing.T) *config.Config {
+	t.Helper()
+	cfg := config.GetConfig()
+	saved, savedJWT, savedFailOpen := cfg.RateLimit, cfg.JWT, cfg.Redis.FailOpen
+	t.Cleanup(func() {
+		cfg.RateLimit, cfg.JWT, cfg.Redis.FailOpen = saved, savedJWT, savedFailOpen
+	})
+
+	cfg.JWT.Secret = testJWTSecret
+	cfg.RateLimit = config.RateLimitConfig{
+		Enabled:       true,
+		WindowSeconds: 60,
+		Anonymous:     2,
+		Authenticated: 5,
+		Roles:         map[string]int{"editor": 10, "admin": 0},
+	}
+	return cfg
+}
+
+func bearer(t *testing.T, userID uint, role, secret string) string {
+	t.Helper()
+	return bearerVersion(t, userID, role, secret, 0)
+}
+
+func bearerVersion(t *testing.T, userID uint, role, secret string, tokenVersion int) string {
+	t.Helper()
+	token, err := jwt.GenerateToken(userID, "a@example.com", role, tokenVersion, 0, secret, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+func TestRateLimitBucketFor(t *testing.T) {
+	cfg := setRateLimit(t)
+
+	anonymous := rateLimitBucket{key: "ratelimit:ip:192.0.2.1", scope: "ip", limit: 2}
+
+	tests := []struct {
+		name          string
+		authorization string
+		loggedOut     bool
+		want          rateLimitBucket
+	}{
+		{"anonymous", "", false, anonymous},
+		{"user", bearer(t, 7, "user", testJWTSecret), false, rateLimitBucket{key: "ratelimit:user:7", scope: "user", role: "user", limit: 5}},
+		{"role override", bearer(t, 8, "editor", testJWTSecret), false, rateLimitBucket{key: "ratelimit:user:8", scope: "user", role: "editor", limit: 10}},
+		{"unlimited role", bearer(t, 9, "admin", testJWTSecret), false, rateLimitBucket{key: "ratelimit:user:9", scope: "user", role: "admin", limit: 0}},
+		{"invalid token counted as anonymous", bearer(t, 7, "admin", "other-secret"), false, anonymous},
+		{"not a bearer token", "Basic dXNlcjpwYXNz", false, anonymous},
+		// A demoted admin's old token was revoked by the version bump
+		{"revoked admin token counted as anonymous", bearerVersion(t, 9, "admin", testJWTSecret, 2), false, anonymous},
+		{"logged out admin token counted as anonymous", bearer(t, 9, "admin", testJWTSecret), true, anonymous},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+			c.Request.RemoteAddr = "192.0.2.1:1234"
+			if tt.authorization != "" {
+				c.Request.Header.Set("Authorization", tt.authorization)
+			}
+
+			authUseCase := &stubAuthUseCase{revokedVersion: 2, deactivatedVersion: 3, loggedOut: tt.loggedOut}
+			if got := rateLimitBucketFor(c, cfg, authUseCase); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newRateLimitedRouter(store *cache.Cache) *gin.Engine {
+	router := gin.New()
+	authUseCase := &stubAuthUseCase{revokedVersion: 2, deactivatedVersion: 3}
+	router.GET("/api/v1/rate-limit/status", RateLimitStatus(store, authUseCase))
+	router.GET("/api/v1/posts", RateLimit(store, authUseCase), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func serveRateLimited(router *gin.Engine, path, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	setRateLimit(t)
+	server, store := cachetest.NewServer(t)
+	router := newRateLimitedRouter(store)
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		w := serveRateLimited(router, "/api/v1/posts", "")
+		if w.Code != want {
+			t.Fatalf("anonymous request %d: expected %d, got %d", i+1, want, w.Code)
+		}
+	}
+	if got := server.Get("ratelimit:ip:192.0.2.1"); got != "3" {
+		t.Errorf("expected 3 requests counted, got %q", got)
+	}
+
+	// Users have their own, larger budget
+	user := bearer(t, 7, "user", testJWTSecret)
+	if w := serveRateLimited(router, "/api/v1/posts", user); w.Code != http.StatusOK {
+		t.Errorf("user request: expected 200, got %d", w.Code)
+	} else if remaining := w.Header().Get("X-RateLimit-Remaining"); remaining != "4" {
+		t.Errorf("expected 4 requests remaining, got %q", remaining)
+	}
+
+	// Unlimited roles are never counted
+	admin := bearer(t, 9, "admin", testJWTSecret)
+	for i := 0; i < 5; i++ {
+		if w := serveRateLimited(router, "/api/v1/posts", admin); w.Code != http.StatusOK {
+			t.Fatalf("admin request %d: expected 200, got %d", i+1, w.Code)
+		}
+	}
+	if got := server.Get("ratelimit:user:9"); got != "" {
+		t.Errorf("expected no counter for an unlimited role, got %q", got)
+	}
+}
+
+// A counter left without a timeout, e.g. by a request that failed after counting, gets one
+// with the next request instead of locking the client out for good
+func TestRateLimitCounterExpires(t *testing.T) {
+	cfg := setRateLimit(t)
+	server, store := cachetest.NewServer(t)
+	router := newRateLimitedRouter(store)
+
+	if err := store.Set(context.Background(), "ratelimit:ip:192.0.2.1", 5, 0); err != nil {
+		t.Fatal(err)
+	}
+	serveRateLimited(router, "/api/v1/posts", "")
+
+	if ttl := server.TTL("ratelimit:ip:192.0.2.1"); ttl != cfg.RateLimit.WindowSeconds {
+		t.Errorf("expected the counter to expire with the window, got a TTL of %d", ttl)
+	}
+
+	// A fresh counter gets the window from its first request
+	serveRateLimited(router, "/api/v1/posts", bearer(t, 7, "user", testJWTSecret))
+	if ttl := server.TTL("ratelimit:user:7"); ttl != cfg.RateLimit.WindowSeconds {
+		t.Errorf("expected a new counter to expire with the window, got a TTL of %d", ttl)
+	}
+}
+
+func TestRateLimitRedisUnavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		failOpen bool
+		want     int
+	}{
+		{"fail open", true, http.StatusOK},
+		{"fail closed", false, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setRateLimit(t)
+			cfg.Redis.FailOpen.RateLimit = tt.failOpen
+			// A cache without Redis is always unavailable
+			router := newRateLimitedRouter(cache.New(nil, cache.Options{}))
+
+			if w := serveRateLimited(router, "/api/v1/posts", ""); w.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
+
+func TestRateLimitStatus(t *testing.T) {
+	setRateLimit(t)
+	server, store := cachetest.NewServer(t)
+	router := newRateLimitedRouter(store)
+	user := bearer(t, 7, "user", testJWTSecret)
+
+	status := func(authorization string) map[string]interface{} {
+		t.Helper()
+		w := serveRateLimited(router, "/api/v1/rate-limit/status", authorization)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		return body.Data
+	}
+
+	if got := status(user)["remaining"]; got != float64(5) {
+		t.Errorf("expected a full budget before any request, got %v", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		serveRateLimited(router, "/api/v1/posts", user)
+	}
+
+	// Checking the status repeatedly reflects the two requests and never consumes more
+	for i := 0; i < 3; i++ {
+		data := status(user)
+		if data["remaining"] != float64(3) || data["limit"] != float64(5) {
+			t.Errorf("check %d: expected 3 of 5 remaining, got %v of %v", i+1, data["remaining"], data["limit"])
+		}
+		if data["scope"] != "user" || data["role"] != "user" {
+			t.Errorf("expected the user bucket with its role, got %v", data)
+		}
+	}
+	if got := server.Get("ratelimit:user:7"); got != "2" {
+		t.Errorf("expected the counter left at 2, got %q", got)
+	}
+
+	if data := status(""); data["scope"] != "ip" || data["remaining"] != float64(2) {
+		t.Errorf("expected the untouched anonymous bucket, got %v", data)
+	}
+	if data := status(bearer(t, 9, "admin", testJWTSecret)); data["unlimited"] != true {
+		t.Errorf("expected an unlimited role to be reported as such, got %v", data)
+	}
+}
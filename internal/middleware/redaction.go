@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"slices"
+	"strings"
+
+	"moon/internal/config"
+	"moon/internal/ctxutil"
+	"moon/pkg/jsonredact"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Redaction removes the response fields configured under redaction.rules that the
+// requester's role may not see. It reads the role after the handler has run, so it can be
+// registered globally ahead of the auth middleware; anonymous requests have no role and
+// see only unrestricted fields. It must run inside JSONCase, as rules name snake_case keys.
+func Redaction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.GetConfig().Redaction
+		if !cfg.Enabled || len(cfg.Rules) == 0 {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		body := writer.body.Bytes()
+		if len(body) == 0 {
+			writer.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		if strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json") {
+			role, _ := ctxutil.Role(c)
+			if fields := redactedFields(cfg.Rules, c.Request.URL.Path, role); len(fields) > 0 {
+				if redacted, err := jsonredact.Remove(body, fields); err == nil {
+					body = redacted
+				}
+			}
+		}
+		writer.ResponseWriter.Write(body)
+	}
+}
+
+// redactedFields returns the fields hidden from role on path
+func redactedFields(rules []config.RedactionRule, path, role string) map[string]bool {
+	fields := make(map[string]bool)
+	for _, rule := range rules {
+		if role != "" && slices.Contains(rule.Roles, role) {
+			continue
+		}
+		if len(rule.Paths) > 0 && !slices.ContainsFunc(rule.Paths, func(prefix string) bool {
+			return strings.HasPrefix(path, prefix)
+		}) {
+			continue
+		}
+		fields[rule.Field] = true
+	}
+	return fields
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"moon/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setRedactionRules(t *testing.T, rules ...config.RedactionRule) {
+	t.Helper()
+	cfg := config.GetConfig()
+	saved := cfg.Redaction
+	t.Cleanup(func() { cfg.Redaction = saved })
+	cfg.Redaction = config.RedactionConfig{Enabled: true, Rules: rules}
+}
+
+// serveRedacted runs a request through Redaction in front of a handler writing body as JSON,
+// with role set as the auth middleware would; an empty role is an anonymous request
+func serveRedacted(path, role, body string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(Redaction())
+	router.GET("/*path", func(c *gin.Context) {
+		if role != "" {
+			c.Set("role", role)
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(body))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestRedaction(t *testing.T) {
+	setRedactionRules(t,
+		config.RedactionRule{Field: "view_count", Roles: []string{"admin", "editor"}, Paths: []string{"/api/v1/posts"}},
+		config.RedactionRule{Field: "email", Roles: []string{"admin"}},
+	)
+
+	const post = `{"id":1,"view_count":7,"author":{"email":"a@example.com","name":"A"}}`
+	tests := []struct {
+		name string
+		path string
+		role string
+		body string
+		want string
+	}{
+		{
+			name: "anonymous sees no restricted field",
+			path: "/api/v1/posts/1",
+			body: post,
+			want: `{"author":{"name":"A"},"id":1}`,
+		},
+		{
+			name: "allowed role sees everything",
+			path: "/api/v1/posts/1",
+			role: "admin",
+			body: post,
+			want: post,
+		},
+		{
+			name: "role allowed by one rule only",
+			path: "/api/v1/posts/1",
+			role: "editor",
+			body: post,
+			want: `{"author":{"name":"A"},"id":1,"view_count":7}`,
+		},
+		{
+			name: "disallowed role",
+			path: "/api/v1/posts/1",
+			role: "user",
+			body: post,
+			want: `{"author":{"name":"A"},"id":1}`,
+		},
+		{
+			name: "path outside the rule's prefixes",
+			path: "/api/v1/users/1",
+			role: "user",
+			body: post,
+			want: `{"author":{"name":"A"},"id":1,"view_count":7}`,
+		},
+		{
+			name: "path prefix matches nested paths",
+			path: "/api/v1/posts/1/collaborators",
+			role: "user",
+			body: `{"view_count":7}`,
+			want: `{}`,
+		},
+		{
+			name: "nested arrays",
+			path: "/api/v1/posts",
+			role: "user",
+			body: `{"posts":[{"id":1,"view_count":7},{"id":2,"tags":[[{"view_count":1,"name":"go"}]]}]}`,
+			want: `{"posts":[{"id":1},{"id":2,"tags":[[{"name":"go"}]]}]}`,
+		},
+		{
+			name: "numbers kept exactly",
+			path: "/api/v1/posts",
+			role: "user",
+			body: `{"id":12345678901234567890,"price":1.10,"view_count":7}`,
+			want: `{"id":12345678901234567890,"price":1.10}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveRedacted(tt.path, tt.role, tt.body)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected status 200, got %d", w.Code)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("got body %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// A body that claims to be JSON but can't be parsed is passed through untouched rather
+// than failing the request; redaction fails open
+func TestRedactionFailsOpenOnInvalidJSON(t *testing.T) {
+	setRedactionRules(t, config.RedactionRule{Field: "email", Roles: []string{"admin"}})
+
+	const body = `{"email":"a@example.com",`
+	w := serveRedacted("/api/v1/users/1", "user", body)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Body.String(); got != body {
+		t.Errorf("expected the body unchanged, got %s", got)
+	}
+}
+
+func TestRedactionDisabled(t *testing.T) {
+	setRedactionRules(t, config.RedactionRule{Field: "email"})
+	config.GetConfig().Redaction.Enabled = false
+
+	const body = `{"email":"a@example.com"}`
+	if got := serveRedacted("/api/v1/users/1", "", body).Body.String(); got != body {
+		t.Errorf("expected the body unchanged, got %s", got)
+	}
+}
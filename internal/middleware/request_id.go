@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"moon/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID assigns every request an ID, stored under "request_id" and echoed in the first
+// configured header. The ID is taken from the first configured incoming header that carries
+// a usable value, then from the trace ID of a W3C traceparent header when enabled, and is
+// generated otherwise.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.GetConfig().RequestID
+
+		id := ""
+		for _, header := range cfg.Headers {
+			if value := strings.TrimSpace(c.GetHeader(header)); isValidRequestID(value) {
+				id = value
+				break
+			}
+		}
+		if id == "" && cfg.TraceParent {
+			id = traceIDFromParent(c.GetHeader("traceparent"))
+		}
+		if id == "" {
+			id = newRequestID()
+		}
+
+		c.Set("request_id", id)
+		c.Header(cfg.Headers[0], id)
+		c.Next()
+	}
+}
+
+// isValidRequestID accepts short printable ASCII values so client-supplied IDs can't be used
+// to inject into headers or logs
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// traceIDFromParent extracts the trace ID from a traceparent header
+// (version-traceid-parentid-flags), returning "" when it is malformed or all zeros
+func traceIDFromParent(header string) string {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return ""
+	}
+
+	traceID := strings.ToLower(parts[1])
+	if len(traceID) != 32 || strings.Trim(traceID, "0") == "" {
+		return ""
+	}
+	if _, err := hex.DecodeString(traceID); err != nil {
+		return ""
+	}
+	return traceID
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"moon/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name        string
+		headers     []string
+		traceParent bool
+		incoming    map[string]string
+		wantHeader  string
+		wantID      string // "" expects a generated ID
+	}{
+		{"incoming default header", []string{"X-Request-ID"}, true, map[string]string{"X-Request-ID": "abc-123"}, "X-Request-ID", "abc-123"},
+		{"generated when absent", []string{"X-Request-ID"}, true, nil, "X-Request-ID", ""},
+		{"configured header name", []string{"X-Correlation-ID"}, false, map[string]string{"X-Correlation-ID": "corr-1", "X-Request-ID": "ignored"}, "X-Correlation-ID", "corr-1"},
+		{"first usable configured header", []string{"X-Correlation-ID", "X-Request-ID"}, false, map[string]string{"X-Correlation-ID": "has space", "X-Request-ID": "req-2"}, "X-Correlation-ID", "req-2"},
+		{"traceparent", []string{"X-Request-ID"}, true, map[string]string{"traceparent": traceparent}, "X-Request-ID", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"traceparent disabled", []string{"X-Request-ID"}, false, map[string]string{"traceparent": traceparent}, "X-Request-ID", ""},
+		{"header before traceparent", []string{"X-Request-ID"}, true, map[string]string{"X-Request-ID": "abc-123", "traceparent": traceparent}, "X-Request-ID", "abc-123"},
+		{"all-zero trace ID", []string{"X-Request-ID"}, true, map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"}, "X-Request-ID", ""},
+		{"too long", []string{"X-Request-ID"}, false, map[string]string{"X-Request-ID": strings.Repeat("a", maxRequestIDLength+1)}, "X-Request-ID", ""},
+	}
+
+	cfg := config.GetConfig()
+	saved := cfg.RequestID
+	t.Cleanup(func() { cfg.RequestID = saved })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.RequestID = config.RequestIDConfig{Headers: tt.headers, TraceParent: tt.traceParent}
+
+			var stored string
+			router := gin.New()
+			router.Use(RequestID())
+			router.GET("/", func(c *gin.Context) {
+				stored = c.GetString("request_id")
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.incoming {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			echoed := w.Header().Get(tt.wantHeader)
+			if echoed != stored {
+				t.Errorf("expected %s to echo the request ID %q, got %q", tt.wantHeader, stored, echoed)
+			}
+			if tt.wantID != "" {
+				if stored != tt.wantID {
+					t.Errorf("expected ID %q, got %q", tt.wantID, stored)
+				}
+				return
+			}
+			// A generated ID is 16 random bytes in hex
+			if len(stored) != 32 || stored == traceIDFromParent(traceparent) {
+				t.Errorf("expected a generated ID, got %q", stored)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"moon/internal/config"
+	"moon/internal/domain/organization"
+	"moon/internal/tenant"
+	"moon/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantScope resolves the organization of the request and scopes the request context to it,
+// so every repository query made with that context is filtered to the organization.
+// The organization comes from the configured header, the subdomain of the base domain, or
+// the org_id claim of the bearer token, in that order. A token issued for another
+// organization is rejected.
+func TenantScope(orgRepo organization.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.GetConfig()
+		if !cfg.Tenant.Enabled {
+			c.Next()
+			return
+		}
+
+		var orgID uint
+		if slug := orgSlugFromRequest(c, cfg.Tenant); slug != "" {
+			org, err := orgRepo.GetBySlug(c.Request.Context(), slug)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+				c.Abort()
+				return
+			}
+			orgID = org.ID
+		}
+
+		if claimOrgID := orgIDFromToken(c, cfg.JWT.Secret); claimOrgID != 0 {
+			if orgID != 0 && orgID != claimOrgID {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Token does not belong to this organization"})
+				c.Abort()
+				return
+			}
+			orgID = claimOrgID
+		}
+
+		if orgID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Organization is required"})
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.WithOrgID(c.Request.Context(), orgID))
+		c.Next()
+	}
+}
+
+// orgSlugFromRequest reads the organization slug from the header or the subdomain
+func orgSlugFromRequest(c *gin.Context, cfg config.TenantConfig) string {
+	if slug := strings.TrimSpace(c.GetHeader(cfg.Header)); slug != "" {
+		return slug
+	}
+
+	if cfg.BaseDomain == "" {
+		return ""
+	}
+
+	host := c.Request.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	subdomain, found := strings.CutSuffix(host, "."+cfg.BaseDomain)
+	if !found || subdomain == "" || strings.Contains(subdomain, ".") {
+		return ""
+	}
+	return subdomain
+}
+
+// orgIDFromToken returns the org_id claim of a valid bearer token, or 0
+func orgIDFromToken(c *gin.Context, secret string) uint {
+	tokenString, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found {
+		return 0
+	}
+
+	claims, err := jwt.ParseToken(tokenString, secret)
+	if err != nil {
+		return 0
+	}
+	return claims.OrgID
+}
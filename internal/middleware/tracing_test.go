@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/posts/:id", func(c *gin.Context) {
+		// Spans started from the request context are children of the server span
+		_, span := otel.Tracer("test").Start(c.Request.Context(), "usecase")
+		span.End()
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name        string
+		target      string
+		traceparent string
+		wantName    string
+		wantStatus  int
+		wantError   bool
+	}{
+		{"new trace", "/posts/7", "", "GET /posts/:id", http.StatusOK, false},
+		{"continued trace", "/posts/7", "00-" + traceID + "-00f067aa0ba902b7-01", "GET /posts/:id", http.StatusOK, false},
+		{"server error", "/fail", "", "GET /fail", http.StatusInternalServerError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder.Reset()
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			var server sdktrace.ReadOnlySpan
+			for _, span := range spans {
+				if span.SpanKind() == trace.SpanKindServer {
+					server = span
+				}
+			}
+			if server == nil {
+				t.Fatalf("expected a server span, got %d spans", len(spans))
+			}
+
+			if server.Name() != tt.wantName {
+				t.Errorf("expected span %q, got %q", tt.wantName, server.Name())
+			}
+			wantAttr := attribute.Int("http.response.status_code", tt.wantStatus)
+			found := false
+			for _, attr := range server.Attributes() {
+				found = found || attr == wantAttr
+			}
+			if !found {
+				t.Errorf("expected attribute %v, got %v", wantAttr, server.Attributes())
+			}
+			if isError := server.Status().Code == codes.Error; isError != tt.wantError {
+				t.Errorf("expected error status %v, got %v", tt.wantError, server.Status())
+			}
+
+			if tt.traceparent != "" {
+				if got := server.SpanContext().TraceID().String(); got != traceID {
+					t.Errorf("expected the incoming trace %s to continue, got %s", traceID, got)
+				}
+				if !server.Parent().IsRemote() {
+					t.Error("expected the server span to have the remote caller as parent")
+				}
+			}
+
+			for _, span := range spans {
+				if span.Name() == "usecase" && span.Parent().SpanID() != server.SpanContext().SpanID() {
+					t.Errorf("expected the handler span to be a child of the server span")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"moon/internal/domain/product"
+	"moon/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new category repository
+func NewCategoryRepository(db *gorm.DB) product.CategoryRepository {
+	return &categoryRepository{
+		db: db,
+	}
+}
+
+func (r *categoryRepository) GetByID(ctx context.Context, id uint) (*product.Category, error) {
+	var c product.Category
+	err := r.db.WithContext(ctx).First(&c, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, product.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *categoryRepository) GetBySlug(ctx context.Context, slug string) (*product.Category, error) {
+	var c product.Category
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&c).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, product.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetWithPostCounts returns active categories with the number of published public posts in each
+func (r *categoryRepository) GetWithPostCounts(ctx context.Context, nonEmpty bool) ([]*product.CategoryWithPostCount, error) {
+	var categories []*product.CategoryWithPostCount
+	query := r.db.WithContext(ctx).
+		Table("categories").
+		Select("categories.id, categories.name, categories.slug, categories.description, COUNT(posts.id) AS post_count").
+		Joins("LEFT JOIN posts ON posts.category_id = categories.id AND posts.status = ? AND posts.is_public = ? AND posts.deleted_at IS NULL", "published", true).
+		Where("categories.deleted_at IS NULL AND categories.is_active = ?", true).
+		Group("categories.id, categories.name, categories.slug, categories.description")
+
+	// Raw table queries are not covered by the tenant callbacks
+	if orgID, ok := tenant.OrgID(ctx); ok {
+		query = query.Where("categories.org_id = ?", orgID)
+	}
+
+	if nonEmpty {
+		query = query.Having("COUNT(posts.id) > 0")
+	}
+
+	err := query.Order("categories.name ASC").Scan(&categories).Error
+	return categories, err
+}
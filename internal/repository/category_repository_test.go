@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"moon/internal/tenant"
+)
+
+func TestGetWithPostCounts(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewCategoryRepository(db)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		nonEmpty bool
+		want     []string
+		notWant  []string
+	}{
+		{
+			name: "all categories",
+			ctx:  context.Background(),
+			want: []string{
+				"LEFT JOIN posts ON posts.category_id = categories.id AND posts.status = ? AND posts.is_public = ? AND posts.deleted_at IS NULL",
+				"categories.deleted_at IS NULL AND categories.is_active = ?",
+				"ORDER BY categories.name ASC",
+			},
+			notWant: []string{"HAVING", "categories.org_id"},
+		},
+		{
+			name:     "non-empty only",
+			ctx:      context.Background(),
+			nonEmpty: true,
+			want:     []string{"HAVING COUNT(posts.id) > 0"},
+		},
+		{
+			name: "scoped to the organization",
+			ctx:  tenant.WithOrgID(context.Background(), 42),
+			want: []string{"categories.org_id = ?"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Scan reports dry runs as unsupported once the statement is built, so only the SQL is checked
+			repo.GetWithPostCounts(tt.ctx, tt.nonEmpty)
+
+			stmt := lastSQL()
+			for _, want := range tt.want {
+				if !strings.Contains(stmt, want) {
+					t.Errorf("expected %q in %s", want, stmt)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(stmt, notWant) {
+					t.Errorf("unexpected %q in %s", notWant, stmt)
+				}
+			}
+		})
+	}
+}
+
+func TestCategoryGetBySlug(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewCategoryRepository(db)
+
+	// A dry run finds no row, so only the statement is checked
+	repo.GetBySlug(context.Background(), "go-lang")
+
+	stmt := lastSQL()
+	if !strings.Contains(stmt, "WHERE slug = ? AND `categories`.`deleted_at` IS NULL") {
+		t.Errorf("expected a lookup of a live category by slug: %s", stmt)
+	}
+}
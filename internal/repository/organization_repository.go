@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"moon/internal/domain/organization"
+
+	"gorm.io/gorm"
+)
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(db *gorm.DB) organization.Repository {
+	return &organizationRepository{
+		db: db,
+	}
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id uint) (*organization.Organization, error) {
+	var org organization.Organization
+	err := r.db.WithContext(ctx).First(&org, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, organization.ErrNotFound
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *organizationRepository) GetBySlug(ctx context.Context, slug string) (*organization.Organization, error) {
+	var org organization.Organization
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&org).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, organization.ErrNotFound
+		}
+		return nil, err
+	}
+	return &org, nil
+}
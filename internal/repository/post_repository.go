@@ -2,12 +2,16 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"slices"
 	"strings"
+	"time"
 
 	"moon/internal/domain/post"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type postRepository struct {
@@ -30,7 +34,7 @@ func (r *postRepository) GetByID(ctx context.Context, id uint) (*post.Post, erro
 	err := r.db.WithContext(ctx).First(&p, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("post not found")
+			return nil, post.ErrNotFound
 		}
 		return nil, err
 	}
@@ -42,7 +46,44 @@ func (r *postRepository) GetBySlug(ctx context.Context, slug string) (*post.Post
 	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&p).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("post not found")
+			return nil, post.ErrNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetBySlugs returns the posts with the given slugs in no particular order; unknown slugs
+// are skipped
+func (r *postRepository) GetBySlugs(ctx context.Context, slugs []string) ([]*post.Post, error) {
+	var posts []*post.Post
+	err := r.db.WithContext(ctx).Where("slug IN ?", slugs).Find(&posts).Error
+	return posts, err
+}
+
+// GetAfterID returns up to limit posts with an ID greater than afterID in ID order, for
+// walking the whole table in batches
+func (r *postRepository) GetAfterID(ctx context.Context, afterID uint, limit int) ([]*post.Post, error) {
+	var posts []*post.Post
+	err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// GetBySlugForUpdate loads a post by slug and locks its row until the transaction ends;
+// it is only meaningful on a transaction-bound repository
+func (r *postRepository) GetBySlugForUpdate(ctx context.Context, slug string) (*post.Post, error) {
+	var p post.Post
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("slug = ?", slug).
+		First(&p).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, post.ErrNotFound
 		}
 		return nil, err
 	}
@@ -62,7 +103,7 @@ func (r *postRepository) GetAll(ctx context.Context, filter post.PostFilter, lim
 	query := r.db.WithContext(ctx).Model(&post.Post{})
 
 	// Apply filters
-	query = r.applyFilters(query, filter)
+	query = r.applyFilters(ctx, query, filter)
 
 	err := query.
 		Limit(limit).
@@ -78,7 +119,7 @@ func (r *postRepository) GetTotalCount(ctx context.Context, filter post.PostFilt
 	query := r.db.WithContext(ctx).Model(&post.Post{})
 
 	// Apply filters
-	query = r.applyFilters(query, filter)
+	query = r.applyFilters(ctx, query, filter)
 
 	err := query.Count(&count).Error
 	return count, err
@@ -95,6 +136,24 @@ func (r *postRepository) GetByAuthor(ctx context.Context, authorID uint, limit,
 	return posts, err
 }
 
+func (r *postRepository) CountByAuthor(ctx context.Context, authorID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Where("author_id = ?", authorID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *postRepository) CountPublishedByAuthor(ctx context.Context, authorID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Where("author_id = ? AND status = ?", authorID, "published").
+		Count(&count).Error
+	return count, err
+}
+
 func (r *postRepository) GetByCategory(ctx context.Context, categoryID uint, limit, offset int) ([]*post.Post, error) {
 	var posts []*post.Post
 	err := r.db.WithContext(ctx).
@@ -106,17 +165,129 @@ func (r *postRepository) GetByCategory(ctx context.Context, categoryID uint, lim
 	return posts, err
 }
 
-func (r *postRepository) GetPublished(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+// GetByTitleLength returns the most recent posts readable by viewer whose title length, in
+// characters, lies in the given range. It narrows the candidates for duplicate title
+// detection; a nil viewer reads every post.
+func (r *postRepository) GetByTitleLength(ctx context.Context, minLength, maxLength int, viewer *post.Viewer, limit int) ([]*post.Post, error) {
+	var posts []*post.Post
+	query := r.db.WithContext(ctx).
+		Select("id", "title", "slug", "status").
+		Where("CHAR_LENGTH(title) BETWEEN ? AND ?", minLength, maxLength)
+	if viewer != nil {
+		query = query.Where(r.readableBy(ctx, viewer))
+	}
+	err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// publishedOrder returns the ORDER BY clause for a published posts sort. The hot score decays
+// views by the post's age in hours, so fresh posts with some traction rank first.
+func publishedOrder(sort string) string {
+	switch sort {
+	case post.SortViewCount:
+		return "view_count DESC, published_at DESC"
+	case post.SortHot:
+		return "(view_count + 1) / POW(TIMESTAMPDIFF(HOUR, published_at, NOW()) + 2, 1.5) DESC, published_at DESC"
+	default:
+		return "published_at DESC"
+	}
+}
+
+func (r *postRepository) GetPublished(ctx context.Context, sort, language string, limit, offset int) ([]*post.Post, error) {
 	var posts []*post.Post
 	err := r.db.WithContext(ctx).
 		Where("status = ? AND is_public = ?", "published", true).
+		Scopes(languageScope(language)).
 		Limit(limit).
 		Offset(offset).
+		Order(publishedOrder(sort)).
+		Find(&posts).Error
+	return posts, err
+}
+
+// GetPublishedAfter returns published public posts newest first, starting after the post
+// cursorID, or from the newest when cursorID is 0. The keyset on (published_at, id) keeps
+// pages stable while new posts are published.
+func (r *postRepository) GetPublishedAfter(ctx context.Context, cursorID uint, language string, limit int) ([]*post.Post, error) {
+	query := r.db.WithContext(ctx).
+		Where("status = ? AND is_public = ?", "published", true).
+		Scopes(languageScope(language))
+
+	if cursorID > 0 {
+		cursorPublishedAt := r.db.WithContext(ctx).
+			Unscoped().
+			Model(&post.Post{}).
+			Select("published_at").
+			Where("id = ?", cursorID)
+		query = query.Where("(published_at < (?) OR (published_at = (?) AND id < ?))",
+			cursorPublishedAt, cursorPublishedAt, cursorID)
+	}
+
+	var posts []*post.Post
+	err := query.
+		Order("published_at DESC, id DESC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// GetArchiveCounts counts published public posts per month of published_at, newest first
+func (r *postRepository) GetArchiveCounts(ctx context.Context) ([]*post.ArchiveMonth, error) {
+	var months []*post.ArchiveMonth
+	err := r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Select("YEAR(published_at) AS year, MONTH(published_at) AS month, COUNT(*) AS count").
+		Where("status = ? AND is_public = ? AND published_at IS NOT NULL", "published", true).
+		Group("year, month").
+		Order("year DESC, month DESC").
+		Scan(&months).Error
+	return months, err
+}
+
+// GetPublishedInMonth returns the published public posts of one month, newest first. The
+// month is matched the same way GetArchiveCounts groups, so the two always agree.
+func (r *postRepository) GetPublishedInMonth(ctx context.Context, year, month, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND is_public = ?", "published", true).
+		Where("YEAR(published_at) = ? AND MONTH(published_at) = ?", year, month).
 		Order("published_at DESC").
+		Limit(limit).
+		Offset(offset).
 		Find(&posts).Error
 	return posts, err
 }
 
+func (r *postRepository) CountPublishedInMonth(ctx context.Context, year, month int) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Where("status = ? AND is_public = ?", "published", true).
+		Where("YEAR(published_at) = ? AND MONTH(published_at) = ?", year, month).
+		Count(&count).Error
+	return count, err
+}
+
+// GetPublishedLastModified returns when any post last changed, was created or was deleted,
+// or nil when there are no posts. Any such change can move posts between pages of the
+// published listing, so it is taken over every post, including unpublished and deleted ones.
+func (r *postRepository) GetPublishedLastModified(ctx context.Context) (*time.Time, error) {
+	var lastModified sql.NullTime
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&post.Post{}).
+		Select("MAX(GREATEST(updated_at, COALESCE(deleted_at, updated_at)))").
+		Row().
+		Scan(&lastModified)
+	if err != nil || !lastModified.Valid {
+		return nil, err
+	}
+	return &lastModified.Time, nil
+}
+
 func (r *postRepository) IncrementViewCount(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).
 		Model(&post.Post{}).
@@ -124,8 +295,562 @@ func (r *postRepository) IncrementViewCount(ctx context.Context, id uint) error
 		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
 }
 
+// ResetViewCounts sets the view count of the given posts to zero in a single transaction
+func (r *postRepository) ResetViewCounts(ctx context.Context, ids []uint) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&post.Post{}).
+			Where("id IN ?", ids).
+			UpdateColumn("view_count", 0)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+		return nil
+	})
+	return affected, err
+}
+
+// Touch bumps updated_at without changing anything else
+func (r *postRepository) Touch(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Where("id = ?", id).
+		UpdateColumn("updated_at", time.Now()).Error
+}
+
+func (r *postRepository) GetDeleted(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Limit(limit).
+		Offset(offset).
+		Order("deleted_at DESC").
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *postRepository) GetDeletedCount(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&post.Post{}).
+		Where("deleted_at IS NOT NULL").
+		Count(&count).Error
+	return count, err
+}
+
+func (r *postRepository) GetDeletedByAuthor(ctx context.Context, authorID uint, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("deleted_at IS NOT NULL AND author_id = ?", authorID).
+		Limit(limit).
+		Offset(offset).
+		Order("deleted_at DESC").
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *postRepository) GetDeletedByAuthorCount(ctx context.Context, authorID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&post.Post{}).
+		Where("deleted_at IS NOT NULL AND author_id = ?", authorID).
+		Count(&count).Error
+	return count, err
+}
+
+// GetDeletedByID returns a soft-deleted post; live posts are reported as not found
+func (r *postRepository) GetDeletedByID(ctx context.Context, id uint) (*post.Post, error) {
+	var p post.Post
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&p, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, post.ErrNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Restore clears deleted_at on a soft-deleted post, saving the slug it is restored under
+func (r *postRepository) Restore(ctx context.Context, id uint, slug string) error {
+	return r.db.WithContext(ctx).
+		Unscoped().
+		Model(&post.Post{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "slug": slug}).Error
+}
+
+func (r *postRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *postRepository) GetScheduled(ctx context.Context, filter post.ScheduledFilter, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	err := r.scheduledQuery(ctx, filter).
+		Limit(limit).
+		Offset(offset).
+		Order("publish_at ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *postRepository) GetScheduledCount(ctx context.Context, filter post.ScheduledFilter) (int64, error) {
+	var count int64
+	err := r.scheduledQuery(ctx, filter).Count(&count).Error
+	return count, err
+}
+
+// scheduledQuery selects drafts whose publish time is still in the future
+func (r *postRepository) scheduledQuery(ctx context.Context, filter post.ScheduledFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Where("status = ? AND publish_at > ?", "draft", time.Now())
+
+	if filter.From != nil {
+		query = query.Where("publish_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		query = query.Where("publish_at <= ?", *filter.To)
+	}
+
+	return query
+}
+
+func (r *postRepository) GetNeedingAttention(ctx context.Context, filter post.AttentionFilter, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	err := r.attentionQuery(ctx, filter).
+		Limit(limit).
+		Offset(offset).
+		Order("updated_at ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *postRepository) GetNeedingAttentionCount(ctx context.Context, filter post.AttentionFilter) (int64, error) {
+	var count int64
+	err := r.attentionQuery(ctx, filter).Count(&count).Error
+	return count, err
+}
+
+// attentionQuery selects the posts matching an attention rule
+func (r *postRepository) attentionQuery(ctx context.Context, filter post.AttentionFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&post.Post{})
+
+	switch filter.Rule {
+	case post.AttentionStaleDrafts:
+		query = query.Where("status = ? AND updated_at < ?", "draft", filter.Before).
+			Where("publish_at IS NULL OR publish_at <= ?", time.Now())
+	case post.AttentionNoViews:
+		query = query.Where("status = ? AND view_count = 0 AND published_at < ?", "published", filter.Before)
+	case post.AttentionIncomplete:
+		query = query.Where("status <> ?", "archived").
+			Where("summary IS NULL OR summary = '' OR featured_img IS NULL OR featured_img = ''")
+	case post.AttentionFlagged:
+		query = query.Where("flagged = ?", true)
+	}
+
+	return query
+}
+
+// ArchiveStaleDrafts archives the given posts that are still drafts last updated before the cutoff
+func (r *postRepository) ArchiveStaleDrafts(ctx context.Context, ids []uint, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Where("id IN ? AND status = ? AND updated_at < ?", ids, "draft", before).
+		Update("status", "archived")
+	return result.RowsAffected, result.Error
+}
+
+// GetDuePosts returns drafts whose scheduled publish time has passed, oldest first
+func (r *postRepository) GetDuePosts(ctx context.Context, now time.Time, limit int) ([]*post.Post, error) {
+	var posts []*post.Post
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND publish_at <= ?", "draft", now).
+		Order("publish_at ASC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// PublishScheduled publishes a due draft, reporting false when it is no longer a due draft.
+// The status check in the update makes publishing idempotent, so a post picked up twice,
+// e.g. by a run interrupted by a restart, is only published once.
+func (r *postRepository) PublishScheduled(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Where("id = ? AND status = ? AND publish_at <= ?", id, "draft", now).
+		Updates(map[string]interface{}{
+			"status":       "published",
+			"published_at": now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Unschedule clears the publish time of a due draft. A draft rescheduled to a later time in
+// the meantime keeps its new schedule.
+func (r *postRepository) Unschedule(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Where("id = ? AND status = ? AND publish_at <= ?", id, "draft", now).
+		Update("publish_at", nil).Error
+}
+
+// DeleteStaleDrafts soft-deletes the given posts that are still drafts last updated before the cutoff
+func (r *postRepository) DeleteStaleDrafts(ctx context.Context, ids []uint, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("id IN ? AND status = ? AND updated_at < ?", ids, "draft", before).
+		Delete(&post.Post{})
+	return result.RowsAffected, result.Error
+}
+
+// SaveCollaborator adds a collaborator, or updates the permission of an existing one
+func (r *postRepository) SaveCollaborator(ctx context.Context, collaborator *post.Collaborator) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"permission"})}).
+		Create(collaborator).Error
+}
+
+func (r *postRepository) DeleteCollaborator(ctx context.Context, postID, userID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("post_id = ? AND user_id = ?", postID, userID).
+		Delete(&post.Collaborator{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return post.ErrCollaboratorNotFound
+	}
+	return nil
+}
+
+func (r *postRepository) GetCollaborator(ctx context.Context, postID, userID uint) (*post.Collaborator, error) {
+	var collaborator post.Collaborator
+	err := r.db.WithContext(ctx).
+		Where("post_id = ? AND user_id = ?", postID, userID).
+		First(&collaborator).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, post.ErrCollaboratorNotFound
+		}
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+func (r *postRepository) GetCollaborators(ctx context.Context, postID uint) ([]*post.Collaborator, error) {
+	var collaborators []*post.Collaborator
+	err := r.db.WithContext(ctx).
+		Where("post_id = ?", postID).
+		Order("created_at ASC").
+		Find(&collaborators).Error
+	return collaborators, err
+}
+
+// GetSharedWith returns the posts the user collaborates on, most recently updated first
+func (r *postRepository) GetSharedWith(ctx context.Context, userID uint, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	err := r.db.WithContext(ctx).
+		Joins("JOIN post_collaborators ON post_collaborators.post_id = posts.id").
+		Where("post_collaborators.user_id = ?", userID).
+		Order("posts.updated_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *postRepository) CountSharedWith(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&post.Post{}).
+		Joins("JOIN post_collaborators ON post_collaborators.post_id = posts.id").
+		Where("post_collaborators.user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// ReassignAuthor moves every post of fromUserID, trashed ones included, to toUserID along
+// with the drafts fromUserID scheduled. It returns the number of posts whose author changed.
+func (r *postRepository) ReassignAuthor(ctx context.Context, fromUserID, toUserID uint) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&post.Post{}).
+		Where("author_id = ?", fromUserID).
+		Update("author_id", toUserID)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&post.Post{}).
+		Where("scheduled_by = ?", fromUserID).
+		UpdateColumn("scheduled_by", toUserID).Error
+	return result.RowsAffected, err
+}
+
+// ReassignCollaborations moves fromUserID's collaborations to toUserID. Collaborations on
+// posts toUserID already collaborates on or authors are dropped, keeping toUserID's own
+// access. It returns the number of collaborations moved.
+func (r *postRepository) ReassignCollaborations(ctx context.Context, fromUserID, toUserID uint) (int64, error) {
+	// MySQL can't delete from a table using a subquery on the same table, so load these first
+	var sharedPostIDs []uint
+	err := r.db.WithContext(ctx).
+		Model(&post.Collaborator{}).
+		Where("user_id = ?", toUserID).
+		Pluck("post_id", &sharedPostIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	authored := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&post.Post{}).
+		Select("id").
+		Where("author_id = ?", toUserID)
+	conflicts := r.db.WithContext(ctx).Where("user_id = ?", fromUserID)
+	if len(sharedPostIDs) > 0 {
+		conflicts = conflicts.Where(r.db.Where("post_id IN (?)", authored).Or("post_id IN ?", sharedPostIDs))
+	} else {
+		conflicts = conflicts.Where("post_id IN (?)", authored)
+	}
+	if err := conflicts.Delete(&post.Collaborator{}).Error; err != nil {
+		return 0, err
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&post.Collaborator{}).
+		Where("user_id = ?", fromUserID).
+		UpdateColumn("user_id", toUserID)
+	return result.RowsAffected, result.Error
+}
+
+// UpsertTags returns the tags with the given names in the same order, creating those that
+// don't exist yet. Names are matched by slug and names without one are skipped.
+func (r *postRepository) UpsertTags(ctx context.Context, names []string) ([]post.Tag, error) {
+	tags := make([]post.Tag, 0, len(names))
+	slugs := make([]string, 0, len(names))
+	for _, name := range names {
+		tagSlug := post.TagSlug(name)
+		if tagSlug == "" || slices.Contains(slugs, tagSlug) {
+			continue
+		}
+		tags = append(tags, post.Tag{Name: name, Slug: tagSlug})
+		slugs = append(slugs, tagSlug)
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	bySlug, err := r.tagsBySlug(ctx, slugs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only insert the missing tags: the (org_id, slug) unique index can't catch duplicates
+	// of tags without an organization, as NULLs never conflict
+	missing := slices.DeleteFunc(tags, func(tag post.Tag) bool {
+		_, ok := bySlug[tag.Slug]
+		return ok
+	})
+	if len(missing) > 0 {
+		if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error; err != nil {
+			return nil, err
+		}
+		// Tags created concurrently got no ID from the insert, so read them all back
+		if bySlug, err = r.tagsBySlug(ctx, slugs); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]post.Tag, 0, len(slugs))
+	for _, tagSlug := range slugs {
+		if tag, ok := bySlug[tagSlug]; ok {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// tagsBySlug returns the existing tags among slugs keyed by slug
+func (r *postRepository) tagsBySlug(ctx context.Context, slugs []string) (map[string]post.Tag, error) {
+	var stored []post.Tag
+	if err := r.db.WithContext(ctx).Where("slug IN ?", slugs).Order("id ASC").Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]post.Tag, len(stored))
+	for _, tag := range stored {
+		if _, ok := bySlug[tag.Slug]; !ok {
+			bySlug[tag.Slug] = tag
+		}
+	}
+	return bySlug, nil
+}
+
+// SetPostTags replaces the tags of a post
+func (r *postRepository) SetPostTags(ctx context.Context, postID uint, tagIDs []uint) error {
+	if err := r.db.WithContext(ctx).Where("post_id = ?", postID).Delete(&post.PostTag{}).Error; err != nil {
+		return err
+	}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	links := make([]post.PostTag, len(tagIDs))
+	for i, tagID := range tagIDs {
+		links[i] = post.PostTag{PostID: postID, TagID: tagID}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+}
+
+// GetTagNames returns the names of the tags of each of the given posts in alphabetical
+// order, keyed by post ID. Posts without tags are left out.
+func (r *postRepository) GetTagNames(ctx context.Context, postIDs []uint) (map[uint][]string, error) {
+	names := make(map[uint][]string)
+	if len(postIDs) == 0 {
+		return names, nil
+	}
+
+	var rows []struct {
+		PostID uint
+		Name   string
+	}
+	err := r.db.WithContext(ctx).
+		Model(&post.Tag{}).
+		Select("post_tags.post_id, tags.name").
+		Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Where("post_tags.post_id IN ?", postIDs).
+		Order("tags.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		names[row.PostID] = append(names[row.PostID], row.Name)
+	}
+	return names, nil
+}
+
+// GetTagUsage returns the existing tags among slugs with how many posts use each
+func (r *postRepository) GetTagUsage(ctx context.Context, slugs []string) ([]post.TagUsage, error) {
+	var usage []post.TagUsage
+	if len(slugs) == 0 {
+		return usage, nil
+	}
+	err := r.db.WithContext(ctx).
+		Model(&post.Tag{}).
+		Select("tags.name, tags.slug, COUNT(posts.id) AS posts").
+		Joins("LEFT JOIN post_tags ON post_tags.tag_id = tags.id").
+		// Tags are scoped to the organization by the tenant callbacks; posts are joined on
+		// the tag's organization so a stray link from another one isn't counted
+		Joins("LEFT JOIN posts ON posts.id = post_tags.post_id AND posts.org_id <=> tags.org_id AND posts.deleted_at IS NULL").
+		Where("tags.slug IN ?", slugs).
+		Group("tags.id, tags.name, tags.slug").
+		Scan(&usage).Error
+	return usage, err
+}
+
+// GetAuthorTags returns the tags of an author's published public posts with how many of
+// them use each, most used first. A limit of 0 returns every tag.
+func (r *postRepository) GetAuthorTags(ctx context.Context, authorID uint, limit int) ([]post.TagUsage, error) {
+	query := r.db.WithContext(ctx).
+		Model(&post.Tag{}).
+		Select("tags.name, tags.slug, COUNT(posts.id) AS posts").
+		Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("JOIN posts ON posts.id = post_tags.post_id AND posts.org_id <=> tags.org_id AND posts.deleted_at IS NULL").
+		Where("posts.author_id = ? AND posts.status = ? AND posts.is_public = ?", authorID, "published", true).
+		Group("tags.id, tags.name, tags.slug").
+		Order("posts DESC, tags.name ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	usage := []post.TagUsage{}
+	err := query.Scan(&usage).Error
+	return usage, err
+}
+
+// GetChanges returns up to limit posts changed after filter.Since in change order, for the
+// incremental sync feed. With IncludeDeleted, soft-deleted posts are returned as of their
+// deletion time so clients can drop them.
+func (r *postRepository) GetChanges(ctx context.Context, filter post.ChangesFilter, limit int) ([]*post.Post, error) {
+	changedAt := "updated_at"
+	query := r.db.WithContext(ctx)
+	if filter.IncludeDeleted {
+		changedAt = "COALESCE(deleted_at, updated_at)"
+		query = query.Unscoped()
+	}
+
+	query = query.Where(changedAt+" > ?", filter.Since)
+	if filter.After != nil {
+		query = query.Where("("+changedAt+" > ? OR ("+changedAt+" = ? AND id > ?))",
+			filter.After.ChangedAt, filter.After.ChangedAt, filter.After.ID)
+	}
+
+	var posts []*post.Post
+	err := query.
+		Order(changedAt + " ASC, id ASC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// languageScope restricts a query to posts in language; an empty language matches all
+func languageScope(language string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if language == "" {
+			return db
+		}
+		return db.Where("language = ?", language)
+	}
+}
+
+// readableBy returns the condition matching the posts viewer may read: published public
+// posts, published private ones when the viewer may read those, and the viewer's own and
+// shared posts
+func (r *postRepository) readableBy(ctx context.Context, viewer *post.Viewer) *gorm.DB {
+	readable := r.db.Where("status = ? AND is_public = ?", "published", true)
+	if viewer.PrivatePublished {
+		readable = r.db.Where("status = ?", "published")
+	}
+	if viewer.UserID != 0 {
+		shared := r.db.WithContext(ctx).
+			Model(&post.Collaborator{}).
+			Select("post_id").
+			Where("user_id = ?", viewer.UserID)
+		readable = readable.Or("author_id = ?", viewer.UserID).Or("posts.id IN (?)", shared)
+	}
+	return readable
+}
+
 // Helper function to apply filters
-func (r *postRepository) applyFilters(query *gorm.DB, filter post.PostFilter) *gorm.DB {
+func (r *postRepository) applyFilters(ctx context.Context, query *gorm.DB, filter post.PostFilter) *gorm.DB {
 	if filter.Status != nil {
 		query = query.Where("status = ?", *filter.Status)
 	}
@@ -142,9 +867,38 @@ func (r *postRepository) applyFilters(query *gorm.DB, filter post.PostFilter) *g
 		query = query.Where("is_public = ?", *filter.IsPublic)
 	}
 
-	if filter.Search != nil && *filter.Search != "" {
-		searchTerm := "%" + strings.ToLower(*filter.Search) + "%"
-		query = query.Where("LOWER(title) LIKE ? OR LOWER(content) LIKE ?", searchTerm, searchTerm)
+	if filter.Tag != nil {
+		// Selected from tags so the tenant callbacks scope the slug to the organization
+		tagged := r.db.WithContext(ctx).
+			Model(&post.Tag{}).
+			Select("post_tags.post_id").
+			Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+			Where("tags.slug = ?", *filter.Tag)
+		query = query.Where("posts.id IN (?)", tagged)
+	}
+
+	if filter.Language != nil {
+		query = query.Where("language = ?", *filter.Language)
+	}
+
+	if filter.VisibleTo != nil {
+		query = query.Where(r.readableBy(ctx, filter.VisibleTo))
+	}
+
+	terms := filter.SearchTerms
+	if len(terms) == 0 && filter.Search != nil && *filter.Search != "" {
+		terms = []string{*filter.Search}
+	}
+	for _, term := range terms {
+		searchTerm := "%" + strings.ToLower(term) + "%"
+		switch filter.SearchFields {
+		case post.SearchFieldsTitle:
+			query = query.Where("LOWER(title) LIKE ?", searchTerm)
+		case post.SearchFieldsContent:
+			query = query.Where("LOWER(content) LIKE ?", searchTerm)
+		default:
+			query = query.Where("LOWER(title) LIKE ? OR LOWER(content) LIKE ?", searchTerm, searchTerm)
+		}
 	}
 
 	return query
@@ -0,0 +1,589 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"moon/internal/domain/post"
+	"moon/internal/tenant"
+)
+
+func TestGetTagUsageScopedToOrganization(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	ctx := tenant.WithOrgID(context.Background(), 42)
+	// Scan reports dry runs as unsupported once the statement is built, so only the SQL is checked
+	repo.GetTagUsage(ctx, []string{"go"})
+
+	stmt := lastSQL()
+	if !strings.Contains(stmt, "`tags`.`org_id` = ?") {
+		t.Errorf("expected tags scoped to the organization: %s", stmt)
+	}
+	if !strings.Contains(stmt, "posts.org_id <=> tags.org_id") {
+		t.Errorf("expected posts joined on the tag's organization: %s", stmt)
+	}
+}
+
+// Only the author's published public posts count towards their tags
+func TestGetAuthorTagsCountsPublishedPosts(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	ctx := tenant.WithOrgID(context.Background(), 42)
+	repo.GetAuthorTags(ctx, 7, 5)
+
+	stmt := lastSQL()
+	for _, want := range []string{
+		"COUNT(posts.id) AS posts",
+		"posts.org_id <=> tags.org_id AND posts.deleted_at IS NULL",
+		"posts.author_id = ? AND posts.status = ? AND posts.is_public = ?",
+		"`tags`.`org_id` = ?",
+		"GROUP BY tags.id, tags.name, tags.slug",
+		"ORDER BY posts DESC, tags.name ASC LIMIT ?",
+	} {
+		if !strings.Contains(stmt, want) {
+			t.Errorf("expected %q in: %s", want, stmt)
+		}
+	}
+
+	repo.GetAuthorTags(ctx, 7, 0)
+	if stmt := lastSQL(); strings.Contains(stmt, "LIMIT") {
+		t.Errorf("expected every tag without a limit: %s", stmt)
+	}
+}
+
+func TestTagFilterScopedToOrganization(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	ctx := tenant.WithOrgID(context.Background(), 42)
+	tag := "go"
+	if _, err := repo.GetAll(ctx, post.PostFilter{Tag: &tag}, 10, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stmt := lastSQL()
+	if !strings.Contains(stmt, "posts.id IN (SELECT post_tags.post_id FROM `tags`") {
+		t.Errorf("expected posts filtered by a tag subquery: %s", stmt)
+	}
+	if !strings.Contains(stmt, "`tags`.`org_id` = ?") {
+		t.Errorf("expected the tag subquery scoped to the organization: %s", stmt)
+	}
+}
+
+func TestListingVisibleToViewer(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	const own = " OR author_id = ? OR posts.id IN (SELECT `post_id` FROM `post_collaborators` WHERE user_id = ?)"
+	tests := []struct {
+		name   string
+		viewer *post.Viewer
+		want   string
+	}{
+		{"anonymous", &post.Viewer{}, "WHERE (status = ? AND is_public = ?) AND"},
+		{"signed in", &post.Viewer{UserID: 3}, "WHERE ((status = ? AND is_public = ?)" + own + ") AND"},
+		{"private published readable", &post.Viewer{UserID: 3, PrivatePublished: true}, "WHERE (status = ?" + own + ") AND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := post.PostFilter{VisibleTo: tt.viewer}
+			if _, err := repo.GetAll(context.Background(), filter, 10, 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stmt := lastSQL(); !strings.Contains(stmt, tt.want) {
+				t.Errorf("expected %q in %s", tt.want, stmt)
+			}
+		})
+	}
+
+	// Without a viewer, as for admins, nothing is hidden
+	if _, err := repo.GetAll(context.Background(), post.PostFilter{}, 10, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stmt := lastSQL(); strings.Contains(stmt, "status") {
+		t.Errorf("expected no visibility condition: %s", stmt)
+	}
+}
+
+func TestGetPublishedFiltersByLanguage(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	tests := []struct {
+		name     string
+		language string
+		want     bool
+	}{
+		{"language", "en", true},
+		{"all languages", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := repo.GetPublished(context.Background(), "published_at", tt.language, 10, 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Contains(lastSQL(), "language = ?"); got != tt.want {
+				t.Errorf("expected language filter %v: %s", tt.want, lastSQL())
+			}
+		})
+	}
+}
+
+func TestGetChanges(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  post.ChangesFilter
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "since",
+			filter:  post.ChangesFilter{Since: since},
+			want:    []string{"updated_at > ?", "`posts`.`deleted_at` IS NULL", "ORDER BY updated_at ASC, id ASC"},
+			notWant: []string{"COALESCE"},
+		},
+		{
+			name:    "with tombstones",
+			filter:  post.ChangesFilter{Since: since, IncludeDeleted: true},
+			want:    []string{"COALESCE(deleted_at, updated_at) > ?", "ORDER BY COALESCE(deleted_at, updated_at) ASC, id ASC"},
+			notWant: []string{"`deleted_at` IS NULL"},
+		},
+		{
+			name:   "after a cursor",
+			filter: post.ChangesFilter{Since: since, After: &post.ChangesCursor{ChangedAt: since.Add(time.Hour), ID: 7}},
+			want:   []string{"(updated_at > ? OR (updated_at = ? AND id > ?))"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := repo.GetChanges(context.Background(), tt.filter, 10); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			stmt := lastSQL()
+			for _, want := range tt.want {
+				if !strings.Contains(stmt, want) {
+					t.Errorf("expected %q in: %s", want, stmt)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(stmt, notWant) {
+					t.Errorf("unexpected %q in: %s", notWant, stmt)
+				}
+			}
+		})
+	}
+}
+
+func TestGetBySlugsIsOneQuery(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	if _, err := repo.GetBySlugs(context.Background(), []string{"a", "b", "c"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stmt := lastSQL(); !strings.Contains(stmt, "slug IN (?,?,?)") {
+		t.Errorf("expected a single IN query over the slugs: %s", stmt)
+	}
+}
+
+func TestPublishScheduledOnlyPublishesDueDrafts(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	if _, err := repo.PublishScheduled(context.Background(), 1, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stmt := lastSQL(); !strings.Contains(stmt, "id = ? AND status = ? AND publish_at <= ?") {
+		t.Errorf("expected the update to be guarded by status and publish time: %s", stmt)
+	}
+}
+
+func TestUnscheduleOnlyClearsDueDrafts(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	if err := repo.Unschedule(context.Background(), 1, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stmt := lastSQL()
+	if !strings.Contains(stmt, "`publish_at`=?") || !strings.Contains(stmt, "id = ? AND status = ? AND publish_at <= ?") {
+		t.Errorf("expected the publish time cleared only for a due draft: %s", stmt)
+	}
+}
+
+func TestGetDeletedListsOnlyTrashedPosts(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	if _, err := repo.GetDeleted(context.Background(), 10, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stmt := lastSQL()
+	if !strings.Contains(stmt, "deleted_at IS NOT NULL") || !strings.Contains(stmt, "ORDER BY deleted_at DESC") {
+		t.Errorf("expected trashed posts, most recently deleted first: %s", stmt)
+	}
+	if strings.Contains(stmt, "`posts`.`deleted_at` IS NULL") {
+		t.Errorf("expected the soft delete scope to be lifted: %s", stmt)
+	}
+}
+
+func TestGetScheduled(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		filter  post.ScheduledFilter
+		want    []string
+		notWant []string
+	}{
+		{"upcoming", post.ScheduledFilter{}, []string{"status = ? AND publish_at > ?", "ORDER BY publish_at ASC"}, []string{"publish_at >= ?", "publish_at <= ?"}},
+		{"from", post.ScheduledFilter{From: &from}, []string{"publish_at >= ?"}, []string{"publish_at <= ?"}},
+		{"window", post.ScheduledFilter{From: &from, To: &to}, []string{"publish_at >= ?", "publish_at <= ?"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := repo.GetScheduled(context.Background(), tt.filter, 10, 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			stmt := lastSQL()
+			for _, want := range tt.want {
+				if !strings.Contains(stmt, want) {
+					t.Errorf("expected %q in %s", want, stmt)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(stmt, notWant) {
+					t.Errorf("unexpected %q in %s", notWant, stmt)
+				}
+			}
+		})
+	}
+}
+
+func TestResetViewCounts(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewPostRepository(db)
+
+	if _, err := repo.ResetViewCounts(context.Background(), []uint{1, 2, 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recorder.committed) != 1 {
+		t.Fatalf("expected one committed statement, got %q", recorder.committed)
+	}
+	stmt := recorder.committed[0]
+	if !strings.HasPrefix(stmt, "UPDATE `posts` SET `view_count`=") || !strings.Contains(stmt, "id IN (?,?,?)") {
+		t.Errorf("expected the view counts of the given posts to be reset: %s", stmt)
+	}
+	if strings.Contains(stmt, "updated_at") {
+		t.Errorf("resetting views must not touch updated_at: %s", stmt)
+	}
+}
+
+func TestGetNeedingAttention(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+	before := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule string
+		want []string
+	}{
+		{post.AttentionStaleDrafts, []string{"status = ? AND updated_at < ?", "(publish_at IS NULL OR publish_at <= ?)"}},
+		{post.AttentionNoViews, []string{"status = ? AND view_count = 0 AND published_at < ?"}},
+		{post.AttentionIncomplete, []string{"status <> ?", "(summary IS NULL OR summary = '' OR featured_img IS NULL OR featured_img = '')"}},
+		{post.AttentionFlagged, []string{"flagged = ?"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			if _, err := repo.GetNeedingAttention(context.Background(), post.AttentionFilter{Rule: tt.rule, Before: before}, 10, 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			stmt := lastSQL()
+			for _, want := range append(tt.want, "`posts`.`deleted_at` IS NULL", "ORDER BY updated_at ASC") {
+				if !strings.Contains(stmt, want) {
+					t.Errorf("expected %q in %s", want, stmt)
+				}
+			}
+		})
+	}
+}
+
+func TestStaleDraftCleanupOnlyTouchesStaleDrafts(t *testing.T) {
+	before := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		run    func(repo post.Repository) (int64, error)
+		prefix string
+	}{
+		{"archive", func(repo post.Repository) (int64, error) {
+			return repo.ArchiveStaleDrafts(context.Background(), []uint{1, 2}, before)
+		}, "UPDATE `posts` SET `status`=?"},
+		{"delete", func(repo post.Repository) (int64, error) {
+			return repo.DeleteStaleDrafts(context.Background(), []uint{1, 2}, before)
+		}, "UPDATE `posts` SET `deleted_at`=?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, recorder := newRecordingDB(t)
+			if _, err := tt.run(NewPostRepository(db)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(recorder.committed) != 1 {
+				t.Fatalf("expected one committed statement, got %q", recorder.committed)
+			}
+			stmt := recorder.committed[0]
+			if !strings.HasPrefix(stmt, tt.prefix) {
+				t.Errorf("expected %q, got %s", tt.prefix, stmt)
+			}
+			// The guard re-checks each post, which may have been edited since it was matched
+			if !strings.Contains(stmt, "id IN (?,?) AND status = ? AND updated_at < ?") {
+				t.Errorf("expected only stale drafts to be affected: %s", stmt)
+			}
+		})
+	}
+}
+
+func TestCountByAuthor(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	tests := []struct {
+		name  string
+		count func(ctx context.Context, authorID uint) (int64, error)
+		want  string
+	}{
+		{"all", repo.CountByAuthor, "WHERE author_id = ? AND `posts`.`deleted_at` IS NULL"},
+		{"published", repo.CountPublishedByAuthor, "WHERE (author_id = ? AND status = ?) AND `posts`.`deleted_at` IS NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.count(context.Background(), 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			stmt := lastSQL()
+			if !strings.HasPrefix(stmt, "SELECT count(*) FROM `posts`") || !strings.Contains(stmt, tt.want) {
+				t.Errorf("expected a count with %q, got %s", tt.want, stmt)
+			}
+		})
+	}
+}
+
+func TestTouchOnlyUpdatesUpdatedAt(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewPostRepository(db)
+
+	if err := repo.Touch(context.Background(), 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recorder.committed) != 1 {
+		t.Fatalf("expected one committed statement, got %q", recorder.committed)
+	}
+	if stmt := recorder.committed[0]; !strings.HasPrefix(stmt, "UPDATE `posts` SET `updated_at`=? WHERE id = ?") {
+		t.Errorf("expected only updated_at to be set: %s", stmt)
+	}
+}
+
+func TestSearchFields(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+	search := "Go"
+
+	tests := []struct {
+		fields  string
+		want    string
+		notWant string
+	}{
+		{post.SearchFieldsTitle, "WHERE LOWER(title) LIKE ?", "content"},
+		{post.SearchFieldsContent, "WHERE LOWER(content) LIKE ?", "title"},
+		{post.SearchFieldsAll, "WHERE (LOWER(title) LIKE ? OR LOWER(content) LIKE ?)", ""},
+		{"", "WHERE (LOWER(title) LIKE ? OR LOWER(content) LIKE ?)", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fields, func(t *testing.T) {
+			filter := post.PostFilter{Search: &search, SearchFields: tt.fields}
+			if _, err := repo.GetTotalCount(context.Background(), filter); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			stmt := lastSQL()
+			if !strings.Contains(stmt, tt.want) {
+				t.Errorf("expected %q in %s", tt.want, stmt)
+			}
+			if tt.notWant != "" && strings.Contains(stmt, tt.notWant) {
+				t.Errorf("expected %s not to be searched: %s", tt.notWant, stmt)
+			}
+		})
+	}
+}
+
+// Every search term must match, each in either field
+func TestSearchTermsAreAllRequired(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	filter := post.PostFilter{SearchTerms: []string{"bánh", "phở"}}
+	if _, err := repo.GetTotalCount(context.Background(), filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	match := "(LOWER(title) LIKE ? OR LOWER(content) LIKE ?)"
+	if stmt := lastSQL(); !strings.Contains(stmt, "WHERE "+match+" AND "+match) {
+		t.Errorf("expected one condition per term: %s", stmt)
+	}
+}
+
+func TestGetDeletedByAuthorIsScopedToTheAuthor(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	if _, err := repo.GetDeletedByAuthor(context.Background(), 1, 10, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stmt := lastSQL()
+	if !strings.Contains(stmt, "deleted_at IS NOT NULL AND author_id = ?") {
+		t.Errorf("expected only the author's trashed posts: %s", stmt)
+	}
+	if strings.Contains(stmt, "`posts`.`deleted_at` IS NULL") {
+		t.Errorf("expected the soft delete scope to be lifted: %s", stmt)
+	}
+}
+
+func TestRestoreOnlyTouchesTrashedPosts(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewPostRepository(db)
+
+	if err := repo.Restore(context.Background(), 7, "restored"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recorder.committed) != 1 {
+		t.Fatalf("expected one committed statement, got %q", recorder.committed)
+	}
+	stmt := recorder.committed[0]
+	if !strings.HasPrefix(stmt, "UPDATE `posts` SET `deleted_at`=?,`slug`=?") || !strings.Contains(stmt, "WHERE id = ? AND deleted_at IS NOT NULL") {
+		t.Errorf("expected a trashed post to be restored under its slug: %s", stmt)
+	}
+}
+
+func TestGetPublishedOrder(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	tests := []struct {
+		sort string
+		want string
+	}{
+		{post.SortPublishedAt, "ORDER BY published_at DESC"},
+		{post.SortViewCount, "ORDER BY view_count DESC, published_at DESC"},
+		{post.SortHot, "ORDER BY (view_count + 1) / POW(TIMESTAMPDIFF(HOUR, published_at, NOW()) + 2, 1.5) DESC, published_at DESC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			if _, err := repo.GetPublished(context.Background(), tt.sort, "", 10, 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stmt := lastSQL(); !strings.Contains(stmt, tt.want) {
+				t.Errorf("expected %q in %s", tt.want, stmt)
+			}
+		})
+	}
+}
+
+func TestArchiveQueries(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+	ctx := context.Background()
+
+	// Scanning into a struct isn't supported in dry run, but the statement is still built
+	repo.GetArchiveCounts(ctx)
+	counts := lastSQL()
+	for _, want := range []string{
+		"SELECT YEAR(published_at) AS year, MONTH(published_at) AS month, COUNT(*) AS count FROM `posts`",
+		"status = ? AND is_public = ? AND published_at IS NOT NULL",
+		"GROUP BY year, month ORDER BY year DESC, month DESC",
+	} {
+		if !strings.Contains(counts, want) {
+			t.Errorf("expected %q in %s", want, counts)
+		}
+	}
+
+	// The drill-down matches months the same way the counts group them
+	month := "WHERE (status = ? AND is_public = ?) AND (YEAR(published_at) = ? AND MONTH(published_at) = ?)"
+	if _, err := repo.GetPublishedInMonth(ctx, 2024, 3, 10, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stmt := lastSQL(); !strings.Contains(stmt, month) || !strings.Contains(stmt, "ORDER BY published_at DESC") {
+		t.Errorf("expected the month's posts newest first: %s", stmt)
+	}
+	if _, err := repo.CountPublishedInMonth(ctx, 2024, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stmt := lastSQL(); !strings.Contains(stmt, month) {
+		t.Errorf("expected the month's posts to be counted: %s", stmt)
+	}
+}
+
+// A merge moves trashed posts too, so they restore under the remaining account
+func TestReassignAuthorIncludesTrashedPosts(t *testing.T) {
+	db, recorder := newRecordingDB(t)
+	repo := NewPostRepository(db)
+
+	moved, err := repo.ReassignAuthor(context.Background(), 10, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if moved != 1 {
+		t.Errorf("expected the authored rows to be counted, got %d", moved)
+	}
+
+	if len(recorder.committed) != 2 {
+		t.Fatalf("expected two committed statements, got %q", recorder.committed)
+	}
+	wants := []string{"SET `author_id`=?", "SET `scheduled_by`=?"}
+	for i, stmt := range recorder.committed {
+		if !strings.Contains(stmt, wants[i]) {
+			t.Errorf("expected %q in %s", wants[i], stmt)
+		}
+		if strings.Contains(stmt, "deleted_at") {
+			t.Errorf("expected the soft delete scope to be lifted: %s", stmt)
+		}
+	}
+}
+
+func TestGetByTitleLengthReadableByViewer(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewPostRepository(db)
+
+	if _, err := repo.GetByTitleLength(context.Background(), 5, 15, &post.Viewer{UserID: 3}, 200); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "AND ((status = ? AND is_public = ?) OR author_id = ? OR posts.id IN (SELECT `post_id` FROM `post_collaborators` WHERE user_id = ?))"
+	if stmt := lastSQL(); !strings.Contains(stmt, want) {
+		t.Errorf("expected the candidates limited to readable posts: %s", stmt)
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"moon/internal/domain/product"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type productRepository struct {
+	db *gorm.DB
+}
+
+// NewProductRepository creates a new product repository
+func NewProductRepository(db *gorm.DB) product.Repository {
+	return &productRepository{
+		db: db,
+	}
+}
+
+func (r *productRepository) Create(ctx context.Context, p *product.Product) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(p).Error
+}
+
+func (r *productRepository) GetByID(ctx context.Context, id uint) (*product.Product, error) {
+	var p product.Product
+	err := r.db.WithContext(ctx).Preload("Category").First(&p, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, product.ErrNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Update saves the product's own columns; the preloaded category is never written back
+func (r *productRepository) Update(ctx context.Context, p *product.Product) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(p).Error
+}
+
+func (r *productRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&product.Product{}, id).Error
+}
+
+// DecrementStock atomically takes qty off an active product's stock. The stock check is part of
+// the UPDATE, so concurrent callers can never oversell; false means nothing was decremented.
+func (r *productRepository) DecrementStock(ctx context.Context, id uint, qty int) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&product.Product{}).
+		Where("id = ? AND is_active = ? AND stock >= ?", id, true, qty).
+		UpdateColumn("stock", gorm.Expr("stock - ?", qty))
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *productRepository) GetAll(ctx context.Context, filter product.ProductFilter, limit, offset int) ([]*product.Product, error) {
+	var products []*product.Product
+	query := r.db.WithContext(ctx).Model(&product.Product{}).Preload("Category")
+
+	// Apply filters
+	query = r.applyFilters(query, filter)
+
+	err := query.
+		Limit(limit).
+		Offset(offset).
+		Order("created_at DESC").
+		Find(&products).Error
+
+	return products, err
+}
+
+func (r *productRepository) GetTotalCount(ctx context.Context, filter product.ProductFilter) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&product.Product{})
+
+	// Apply filters
+	query = r.applyFilters(query, filter)
+
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// Helper function to apply filters
+func (r *productRepository) applyFilters(query *gorm.DB, filter product.ProductFilter) *gorm.DB {
+	if filter.CategoryID != nil {
+		query = query.Where("category_id = ?", *filter.CategoryID)
+	}
+
+	if filter.Currency != nil {
+		query = query.Where("currency = ?", *filter.Currency)
+	}
+
+	if filter.MinPrice != nil {
+		query = query.Where("price >= ?", *filter.MinPrice)
+	}
+
+	if filter.MaxPrice != nil {
+		query = query.Where("price <= ?", *filter.MaxPrice)
+	}
+
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+
+	return query
+}
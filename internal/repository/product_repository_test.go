@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDecrementStockIsGuarded(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewProductRepository(db)
+
+	if _, err := repo.DecrementStock(context.Background(), 1, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stmt := lastSQL()
+	if !strings.Contains(stmt, "`stock`=stock - ?") {
+		t.Errorf("expected an in-place decrement: %s", stmt)
+	}
+	if !strings.Contains(stmt, "stock >= ?") {
+		t.Errorf("expected the update guarded by the stock left: %s", stmt)
+	}
+}
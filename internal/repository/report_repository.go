@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"moon/internal/domain/report"
+
+	"gorm.io/gorm"
+)
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *gorm.DB) report.Repository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+func (r *reportRepository) Create(ctx context.Context, rp *report.Report) error {
+	return r.db.WithContext(ctx).Create(rp).Error
+}
+
+func (r *reportRepository) GetByID(ctx context.Context, id uint) (*report.Report, error) {
+	var rp report.Report
+	err := r.db.WithContext(ctx).First(&rp, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, report.ErrNotFound
+		}
+		return nil, err
+	}
+	return &rp, nil
+}
+
+func (r *reportRepository) GetByReporterAndTarget(ctx context.Context, reporterID uint, targetType string, targetID uint) (*report.Report, error) {
+	var rp report.Report
+	err := r.db.WithContext(ctx).
+		Where("reporter_id = ? AND target_type = ? AND target_id = ?", reporterID, targetType, targetID).
+		First(&rp).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, report.ErrNotFound
+		}
+		return nil, err
+	}
+	return &rp, nil
+}
+
+func (r *reportRepository) Update(ctx context.Context, rp *report.Report) error {
+	return r.db.WithContext(ctx).Save(rp).Error
+}
+
+func (r *reportRepository) GetAll(ctx context.Context, filter report.ReportFilter, limit, offset int) ([]*report.Report, error) {
+	var reports []*report.Report
+	query := r.db.WithContext(ctx).Model(&report.Report{})
+
+	// Apply filters
+	query = r.applyFilters(query, filter)
+
+	err := query.
+		Limit(limit).
+		Offset(offset).
+		Order("created_at DESC").
+		Find(&reports).Error
+
+	return reports, err
+}
+
+func (r *reportRepository) GetTotalCount(ctx context.Context, filter report.ReportFilter) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&report.Report{})
+
+	// Apply filters
+	query = r.applyFilters(query, filter)
+
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// ReassignReporter moves fromUserID's reports to toUserID. Reports of targets toUserID has
+// already reported are dropped, since a user reports each target at most once. It returns
+// the number of reports moved.
+func (r *reportRepository) ReassignReporter(ctx context.Context, fromUserID, toUserID uint) (int64, error) {
+	var reported []*report.Report
+	err := r.db.WithContext(ctx).
+		Select("target_type", "target_id").
+		Where("reporter_id = ?", toUserID).
+		Find(&reported).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rp := range reported {
+		err := r.db.WithContext(ctx).
+			Where("reporter_id = ? AND target_type = ? AND target_id = ?", fromUserID, rp.TargetType, rp.TargetID).
+			Delete(&report.Report{}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&report.Report{}).
+		Where("reporter_id = ?", fromUserID).
+		UpdateColumn("reporter_id", toUserID)
+	return result.RowsAffected, result.Error
+}
+
+// Helper function to apply filters
+func (r *reportRepository) applyFilters(query *gorm.DB, filter report.ReportFilter) *gorm.DB {
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if filter.TargetType != nil {
+		query = query.Where("target_type = ?", *filter.TargetType)
+	}
+
+	return query
+}
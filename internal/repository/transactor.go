@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"moon/internal/domain/transaction"
+
+	"gorm.io/gorm"
+)
+
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a transactor backed by GORM transactions
+func NewTransactor(db *gorm.DB) transaction.Transactor {
+	return &gormTransactor{
+		db: db,
+	}
+}
+
+func (t *gormTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context, repos transaction.Repositories) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(ctx, transaction.Repositories{
+			Users:      NewUserRepository(tx),
+			Posts:      NewPostRepository(tx),
+			Reports:    NewReportRepository(tx),
+			Categories: NewCategoryRepository(tx),
+		})
+	})
+}
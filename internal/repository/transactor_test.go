@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/transaction"
+	"moon/internal/domain/user"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingDriver is a database/sql driver that accepts every statement. Statements run in
+// a transaction are only kept once it commits, so the committed list is what a real
+// database would have persisted.
+type recordingDriver struct {
+	mu         sync.Mutex
+	committed  []string
+	rolledBack int
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+type recordingConn struct {
+	driver  *recordingDriver
+	pending []string // statements of the open transaction
+	inTx    bool
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.inTx = true
+	return c, nil
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.inTx {
+		c.pending = append(c.pending, query)
+	} else {
+		c.driver.mu.Lock()
+		c.driver.committed = append(c.driver.committed, query)
+		c.driver.mu.Unlock()
+	}
+	return recordingResult{}, nil
+}
+
+// recordingResult reports every statement as affecting one row with ID 1
+type recordingResult struct{}
+
+func (recordingResult) LastInsertId() (int64, error) { return 1, nil }
+
+func (recordingResult) RowsAffected() (int64, error) { return 1, nil }
+
+func (c *recordingConn) Commit() error {
+	c.driver.mu.Lock()
+	c.driver.committed = append(c.driver.committed, c.pending...)
+	c.driver.mu.Unlock()
+	c.pending, c.inTx = nil, false
+	return nil
+}
+
+func (c *recordingConn) Rollback() error {
+	c.driver.mu.Lock()
+	c.driver.rolledBack++
+	c.driver.mu.Unlock()
+	c.pending, c.inTx = nil, false
+	return nil
+}
+
+// newRecordingDB returns a database running statements through a fresh recording driver
+func newRecordingDB(t *testing.T) (*gorm.DB, *recordingDriver) {
+	t.Helper()
+
+	recorder := &recordingDriver{}
+	sqlDB := sql.OpenDB(recordingConnector{recorder})
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, recorder
+}
+
+type recordingConnector struct {
+	driver *recordingDriver
+}
+
+func (c recordingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c recordingConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func TestWithinTransaction(t *testing.T) {
+	failure := errors.New("tagging failed")
+
+	tests := []struct {
+		name          string
+		err           error
+		wantCommitted int
+		wantRollback  int
+	}{
+		{"commit", nil, 2, 0},
+		{"failure partway", failure, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, recorder := newRecordingDB(t)
+			transactor := NewTransactor(db)
+
+			err := transactor.WithinTransaction(context.Background(), func(ctx context.Context, repos transaction.Repositories) error {
+				if err := repos.Users.Create(ctx, &user.User{Email: "a@example.com", Name: "A"}); err != nil {
+					return err
+				}
+				if err := repos.Posts.Create(ctx, &post.Post{Title: "A", Slug: "a", AuthorID: 1}); err != nil {
+					return err
+				}
+				return tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if len(recorder.committed) != tt.wantCommitted {
+				t.Errorf("expected %d statements persisted, got %q", tt.wantCommitted, recorder.committed)
+			}
+			for _, stmt := range recorder.committed {
+				if !strings.HasPrefix(stmt, "INSERT") {
+					t.Errorf("unexpected statement %q", stmt)
+				}
+			}
+			if recorder.rolledBack != tt.wantRollback {
+				t.Errorf("expected %d rollbacks, got %d", tt.wantRollback, recorder.rolledBack)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+	"moon/internal/domain/user"
+
+	"gorm.io/gorm/schema"
+)
+
+// The existence checks run in the request's organization, so the unique keys they guard
+// must be per organization too, or a value taken in another one fails on insert
+func TestUniqueKeysScopedToOrganization(t *testing.T) {
+	tests := []struct {
+		model  interface{}
+		index  string
+		column string
+	}{
+		{&user.User{}, "idx_users_org_email", "email"},
+		{&user.User{}, "idx_users_org_username", "username"},
+		{&post.Post{}, "idx_posts_org_slug", "slug"},
+		{&post.Tag{}, "idx_tags_org_slug", "slug"},
+		{&product.Category{}, "idx_categories_org_slug", "slug"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.index, func(t *testing.T) {
+			s, err := schema.Parse(tt.model, &sync.Map{}, schema.NamingStrategy{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			index := s.LookIndex(tt.index)
+			if index == nil || index.Class != "UNIQUE" {
+				t.Fatalf("expected a unique index %s, got %+v", tt.index, index)
+			}
+			var columns []string
+			for _, option := range index.Fields {
+				columns = append(columns, option.DBName)
+			}
+			if want := []string{"org_id", tt.column}; !slices.Equal(columns, want) {
+				t.Errorf("expected columns %v, got %v", want, columns)
+			}
+
+			// No unique index on the column alone is left behind
+			for _, other := range s.ParseIndexes() {
+				if other.Class == "UNIQUE" && len(other.Fields) == 1 && other.Fields[0].DBName == tt.column {
+					t.Errorf("expected no global unique index on %s, found %s", tt.column, other.Name)
+				}
+			}
+		})
+	}
+}
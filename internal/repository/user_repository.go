@@ -3,10 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"moon/internal/domain/user"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type userRepository struct {
@@ -21,7 +23,15 @@ func NewUserRepository(db *gorm.DB) user.Repository {
 }
 
 func (r *userRepository) Create(ctx context.Context, u *user.User) error {
-	return r.db.WithContext(ctx).Create(u).Error
+	if err := r.db.WithContext(ctx).Create(u).Error; err != nil {
+		return err
+	}
+	// GORM leaves zero values of fields with a default out of the insert, so an inactive
+	// user would be stored as active
+	if !u.IsActive {
+		return r.db.WithContext(ctx).Model(u).Update("is_active", false).Error
+	}
+	return nil
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id uint) (*user.User, error) {
@@ -29,7 +39,23 @@ func (r *userRepository) GetByID(ctx context.Context, id uint) (*user.User, erro
 	err := r.db.WithContext(ctx).First(&u, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetByIDForUpdate loads a user and locks its row until the transaction ends; it is only
+// meaningful on a transaction-bound repository
+func (r *userRepository) GetByIDForUpdate(ctx context.Context, id uint) (*user.User, error) {
+	var u user.User
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&u, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, user.ErrNotFound
 		}
 		return nil, err
 	}
@@ -41,15 +67,84 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 	err := r.db.WithContext(ctx).Where("email = ?", email).First(&u).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetByIDs loads the given users in one query; soft-deleted and unknown IDs are skipped
+func (r *userRepository) GetByIDs(ctx context.Context, ids []uint) ([]*user.User, error) {
+	var users []*user.User
+	err := r.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&users).Error
+	return users, err
+}
+
+// GetAfterID returns up to limit users with an ID greater than afterID in ID order, for
+// walking the whole table in batches
+func (r *userRepository) GetAfterID(ctx context.Context, afterID uint, limit int) ([]*user.User, error) {
+	var users []*user.User
+	err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// GetNotificationPreferences returns the user's stored preferences, or the defaults when
+// they never changed them
+func (r *userRepository) GetNotificationPreferences(ctx context.Context, userID uint) (*user.NotificationPreferences, error) {
+	var prefs user.NotificationPreferences
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&prefs).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user.DefaultNotificationPreferences(userID), nil
+		}
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// SaveNotificationPreferences inserts or replaces the user's preferences
+func (r *userRepository) SaveNotificationPreferences(ctx context.Context, prefs *user.NotificationPreferences) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(prefs).Error
+}
+
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	var u user.User
+	err := r.db.WithContext(ctx).Where("username = ?", username).First(&u).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *userRepository) GetByEmailChangeToken(ctx context.Context, tokenHash string) (*user.User, error) {
+	var u user.User
+	err := r.db.WithContext(ctx).Where("email_change_token_hash = ?", tokenHash).First(&u).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, user.ErrNotFound
 		}
 		return nil, err
 	}
 	return &u, nil
 }
 
+// Update writes every column of u except token_version, which only IncrementTokenVersion
+// changes: writing back a version read before a concurrent revocation would make the
+// revoked tokens valid again
 func (r *userRepository) Update(ctx context.Context, u *user.User) error {
-	return r.db.WithContext(ctx).Save(u).Error
+	return r.db.WithContext(ctx).Model(u).Select("*").Omit("token_version").Updates(u).Error
 }
 
 func (r *userRepository) Delete(ctx context.Context, id uint) error {
@@ -66,6 +161,27 @@ func (r *userRepository) GetAll(ctx context.Context, limit, offset int) ([]*user
 	return users, err
 }
 
+// GetPendingApproval returns users awaiting approval, oldest registration first
+func (r *userRepository) GetPendingApproval(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	var users []*user.User
+	err := r.db.WithContext(ctx).
+		Where("pending_approval = ?", true).
+		Limit(limit).
+		Offset(offset).
+		Order("created_at ASC").
+		Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) CountPendingApproval(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&user.User{}).
+		Where("pending_approval = ?", true).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *userRepository) GetTotalCount(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&user.User{}).Count(&count).Error
@@ -82,3 +198,87 @@ func (r *userRepository) GetByRole(ctx context.Context, role string, limit, offs
 		Find(&users).Error
 	return users, err
 }
+
+func (r *userRepository) GetCountByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&user.User{}).
+		Where("role = ?", role).
+		Count(&count).Error
+	return count, err
+}
+
+// IncrementTokenVersion bumps the version in a single statement, so concurrent bumps and
+// updates can't lose an increment
+func (r *userRepository) IncrementTokenVersion(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&user.User{}).
+		Where("id = ?", id).
+		UpdateColumn("token_version", gorm.Expr("token_version + ?", 1)).Error
+}
+
+func (r *userRepository) CountByActive(ctx context.Context) (map[bool]int64, error) {
+	var rows []struct {
+		IsActive bool
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&user.User{}).
+		Select("is_active, COUNT(*) AS count").
+		Group("is_active").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[bool]int64, len(rows))
+	for _, row := range rows {
+		counts[row.IsActive] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *userRepository) CreateRefreshToken(ctx context.Context, token *user.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *userRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*user.RefreshToken, error) {
+	var token user.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, user.ErrInvalidRefresh
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken marks a refresh token revoked. It reports false when the token was
+// already revoked, so two concurrent refreshes with the same token cannot both succeed.
+func (r *userRepository) RevokeRefreshToken(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&user.RefreshToken{}).
+		Where("id = ? AND revoked = ?", id, false).
+		Update("revoked", true)
+	return result.RowsAffected == 1, result.Error
+}
+
+// GetActiveRefreshTokens returns the refresh tokens of a user that can still be used,
+// oldest first: not revoked, not expired and issued for the current token version
+func (r *userRepository) GetActiveRefreshTokens(ctx context.Context, userID uint, tokenVersion int, now time.Time) ([]*user.RefreshToken, error) {
+	var tokens []*user.RefreshToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked = ? AND token_version = ? AND expires_at > ?", userID, false, tokenVersion, now).
+		Order("created_at ASC, id ASC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// RevokeRefreshTokens revokes every outstanding refresh token of a user
+func (r *userRepository) RevokeRefreshTokens(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).
+		Model(&user.RefreshToken{}).
+		Where("user_id = ? AND revoked = ?", userID, false).
+		Update("revoked", true).Error
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"moon/internal/domain/user"
+	"moon/internal/tenant"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newDryRunDB returns a database with the tenant callbacks that builds statements without
+// running them, and a function returning the SQL of the last query or update
+func newDryRunDB(t *testing.T) (*gorm.DB, func() string) {
+	t.Helper()
+
+	sqlDB, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:3306)/moon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tenant.RegisterCallbacks(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var lastSQL string
+	capture := func(tx *gorm.DB) {
+		lastSQL = tx.Statement.SQL.String()
+	}
+	db.Callback().Query().After("gorm:query").Register("test:capture", capture)
+	db.Callback().Row().After("gorm:row").Register("test:capture", capture)
+	db.Callback().Update().After("gorm:update").Register("test:capture", capture)
+	return db, func() string { return lastSQL }
+}
+
+func TestUserUpdateKeepsTokenVersion(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewUserRepository(db)
+
+	u := &user.User{ID: 7, Email: "a@example.com", Name: "A", TokenVersion: 1}
+	if err := repo.Update(context.Background(), u); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stmt := lastSQL()
+	if !strings.HasPrefix(stmt, "UPDATE") {
+		t.Fatalf("expected an UPDATE, got %q", stmt)
+	}
+	if strings.Contains(stmt, "token_version") {
+		t.Errorf("Update must not write token_version: %s", stmt)
+	}
+	if !strings.Contains(stmt, "`email`=") {
+		t.Errorf("Update should write the other columns: %s", stmt)
+	}
+}
+
+func TestIncrementTokenVersionIsAtomic(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewUserRepository(db)
+
+	if err := repo.IncrementTokenVersion(context.Background(), 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stmt := lastSQL(); !strings.Contains(stmt, "`token_version`=token_version + ?") {
+		t.Errorf("expected an in-place increment, got %s", stmt)
+	}
+}
+
+func TestGetByIDForUpdateLocksRow(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewUserRepository(db)
+
+	if _, err := repo.GetByIDForUpdate(context.Background(), 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stmt := lastSQL(); !strings.HasSuffix(stmt, "FOR UPDATE") {
+		t.Errorf("expected the user row locked, got %s", stmt)
+	}
+}
+
+func TestGetByIDsLooksUpLiveUsersInOneQuery(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewUserRepository(db)
+
+	if _, err := repo.GetByIDs(context.Background(), []uint{1, 2, 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stmt := lastSQL()
+	if !strings.Contains(stmt, "id IN (?,?,?)") || !strings.Contains(stmt, "`users`.`deleted_at` IS NULL") {
+		t.Errorf("expected one lookup of the live users: %s", stmt)
+	}
+}
+
+func TestGetActiveRefreshTokensOldestFirst(t *testing.T) {
+	db, lastSQL := newDryRunDB(t)
+	repo := NewUserRepository(db)
+
+	if _, err := repo.GetActiveRefreshTokens(context.Background(), 7, 2, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stmt := lastSQL()
+	if !strings.Contains(stmt, "user_id = ? AND revoked = ? AND token_version = ? AND expires_at > ?") {
+		t.Errorf("expected only usable tokens of the user: %s", stmt)
+	}
+	if !strings.HasSuffix(stmt, "ORDER BY created_at ASC, id ASC") {
+		t.Errorf("expected the oldest sessions first: %s", stmt)
+	}
+}
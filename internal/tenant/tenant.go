@@ -0,0 +1,79 @@
+package tenant
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type contextKey struct{}
+
+// WithOrgID returns a copy of ctx scoped to the given organization
+func WithOrgID(ctx context.Context, orgID uint) context.Context {
+	return context.WithValue(ctx, contextKey{}, orgID)
+}
+
+// OrgID returns the organization ctx is scoped to
+func OrgID(ctx context.Context) (uint, bool) {
+	orgID, ok := ctx.Value(contextKey{}).(uint)
+	return orgID, ok
+}
+
+// Scope restricts a query on the current table to the given organization
+func Scope(orgID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "org_id"}, Value: orgID},
+		}})
+		return db
+	}
+}
+
+// RegisterCallbacks makes every statement run with an organization-scoped context
+// tenant-aware: reads, updates and deletes of models with an OrgID field are filtered
+// to that organization, and created records are assigned to it.
+func RegisterCallbacks(db *gorm.DB) error {
+	callback := db.Callback()
+
+	if err := callback.Create().Before("gorm:create").Register("tenant:assign", assignOrg); err != nil {
+		return err
+	}
+	if err := callback.Query().Before("gorm:query").Register("tenant:scope", scopeToOrg); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("tenant:scope", scopeToOrg); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("tenant:scope", scopeToOrg); err != nil {
+		return err
+	}
+	return callback.Row().Before("gorm:row").Register("tenant:scope", scopeToOrg)
+}
+
+func scopeToOrg(db *gorm.DB) {
+	orgID, ok := orgIDFor(db)
+	if !ok {
+		return
+	}
+	Scope(orgID)(db)
+}
+
+func assignOrg(db *gorm.DB) {
+	orgID, ok := orgIDFor(db)
+	if !ok {
+		return
+	}
+	db.Statement.SetColumn("OrgID", &orgID, true)
+}
+
+// orgIDFor returns the organization of the statement's context when its model is tenant-owned
+func orgIDFor(db *gorm.DB) (uint, bool) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Context == nil {
+		return 0, false
+	}
+	if db.Statement.Schema.LookUpField("OrgID") == nil {
+		return 0, false
+	}
+	return OrgID(db.Statement.Context)
+}
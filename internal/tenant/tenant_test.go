@@ -0,0 +1,126 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"moon/internal/domain/post"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// note is owned by an organization; country is shared by all of them
+type note struct {
+	ID    uint
+	OrgID uint
+	Title string
+}
+
+type country struct {
+	ID   uint
+	Name string
+}
+
+// newDryRunDB returns a database with the tenant callbacks that builds statements without
+// running them
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	sqlDB, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:3306)/moon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterCallbacks(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestTenantIsolation(t *testing.T) {
+	db := newDryRunDB(t)
+	orgCtx := WithOrgID(context.Background(), 42)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		statement func(tx *gorm.DB) *gorm.DB
+		want      string // org_id condition or insert expected; empty when org_id must not appear
+	}{
+		{"find", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			var notes []note
+			return tx.Where("title = ?", "a").Find(&notes)
+		}, "`org_id` = 42"},
+		{"first by ID", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			var n note
+			return tx.First(&n, 7)
+		}, "`org_id` = 42"},
+		{"count", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			var count int64
+			return tx.Model(&note{}).Count(&count)
+		}, "`org_id` = 42"},
+		{"row", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			tx = tx.Model(&note{}).Select("MAX(id)")
+			tx.Row()
+			return tx
+		}, "`org_id` = 42"},
+		{"update", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			return tx.Model(&note{}).Where("id = ?", 7).Update("title", "b")
+		}, "`org_id` = 42"},
+		{"delete", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("id = ?", 7).Delete(&note{})
+		}, "`org_id` = 42"},
+		{"create", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			return tx.Create(&note{Title: "a"})
+		}, "(`org_id`,`title`) VALUES (42,"},
+		{"find tags", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			var tags []post.Tag
+			return tx.Where("slug IN ?", []string{"go"}).Find(&tags)
+		}, "`tags`.`org_id` = 42"},
+		{"create tag", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			return tx.Create(&post.Tag{Name: "Go", Slug: "go"})
+		}, "(`org_id`,`name`,`slug`,`created_at`) VALUES (42,"},
+		{"pluck tag names", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			var names []string
+			return tx.Model(&post.Tag{}).
+				Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+				Pluck("tags.name", &names)
+		}, "`tags`.`org_id` = 42"},
+		{"model without organization", orgCtx, func(tx *gorm.DB) *gorm.DB {
+			var countries []country
+			return tx.Find(&countries)
+		}, ""},
+		{"context without organization", context.Background(), func(tx *gorm.DB) *gorm.DB {
+			var notes []note
+			return tx.Find(&notes)
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tt.statement(tx.WithContext(tt.ctx))
+			})
+
+			if tt.want == "" {
+				if strings.Contains(stmt, "org_id") {
+					t.Errorf("expected no organization scope: %s", stmt)
+				}
+				return
+			}
+			if !strings.Contains(stmt, tt.want) {
+				t.Errorf("expected %q in: %s", tt.want, stmt)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+)
+
+// newApprovalTestUseCases returns auth and user use cases sharing one repository, with
+// approval of new registrations required
+func newApprovalTestUseCases(t *testing.T) (AuthUseCase, UserUseCase, *fakeUserRepo) {
+	t.Helper()
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.JWT.Secret = "test-secret"
+	cfg.JWT.ExpiresIn = 1
+	cfg.JWT.RefreshExpiresIn = 24
+	cfg.Account.RequireApproval = true
+
+	repo := &fakeUserRepo{users: map[uint]*user.User{}}
+	store := cache.New(nil, cache.Options{})
+	return NewAuthUseCase(repo, store, nil, cfg), NewUserUseCase(repo, nil, store), repo
+}
+
+func TestApprovalWorkflow(t *testing.T) {
+	ctx := context.Background()
+	auth, users, repo := newApprovalTestUseCases(t)
+
+	registered, err := auth.Register(ctx, user.CreateUserRequest{Email: "a@example.com", Password: "secret123", Name: "A"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if registered.IsActive || !registered.PendingApproval {
+		t.Fatalf("expected an inactive registration pending approval, got %+v", registered)
+	}
+
+	login := user.LoginRequest{Email: "a@example.com", Password: "secret123"}
+	if _, err := auth.Login(ctx, login); !errors.Is(err, user.ErrPendingApproval) {
+		t.Fatalf("expected login to report the pending approval, got %v", err)
+	}
+	// The approval status is only revealed to someone who knows the password
+	if _, err := auth.Login(ctx, user.LoginRequest{Email: "a@example.com", Password: "wrong"}); !errors.Is(err, user.ErrInvalidCredentials) {
+		t.Errorf("expected invalid credentials for a wrong password, got %v", err)
+	}
+
+	pending, err := users.GetPendingUsers(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("pending users: %v", err)
+	}
+	if pending.Total != 1 || len(pending.Users) != 1 || pending.Users[0].ID != registered.ID {
+		t.Fatalf("expected the registration to be listed as pending, got %+v", pending)
+	}
+
+	approved, err := users.ApproveUser(ctx, registered.ID)
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if !approved.IsActive {
+		t.Errorf("expected the approved user to be active, got %+v", approved)
+	}
+	if stored := repo.users[registered.ID]; !stored.IsActive || stored.PendingApproval {
+		t.Errorf("expected the stored user to be active and no longer pending, got %+v", stored)
+	}
+
+	if _, err := auth.Login(ctx, login); err != nil {
+		t.Errorf("expected login to succeed after approval, got %v", err)
+	}
+	if pending, err := users.GetPendingUsers(ctx, 1, 10); err != nil || pending.Total != 0 {
+		t.Errorf("expected no pending users after approval, got %+v, %v", pending, err)
+	}
+
+	// Approval is one-off
+	if _, err := users.ApproveUser(ctx, registered.ID); !errors.Is(err, user.ErrNotPendingApproval) {
+		t.Errorf("expected approving twice to fail with %v, got %v", user.ErrNotPendingApproval, err)
+	}
+}
+
+func TestRejectUser(t *testing.T) {
+	ctx := context.Background()
+	auth, users, repo := newApprovalTestUseCases(t)
+
+	registered, err := auth.Register(ctx, user.CreateUserRequest{Email: "a@example.com", Password: "secret123", Name: "A"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if err := users.RejectUser(ctx, registered.ID); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if _, ok := repo.users[registered.ID]; ok {
+		t.Error("expected the rejected registration to be deleted")
+	}
+	if _, err := auth.Login(ctx, user.LoginRequest{Email: "a@example.com", Password: "secret123"}); !errors.Is(err, user.ErrInvalidCredentials) {
+		t.Errorf("expected the rejected user to be unable to log in, got %v", err)
+	}
+	// The address is free to register again
+	if _, err := auth.Register(ctx, user.CreateUserRequest{Email: "a@example.com", Password: "secret123", Name: "A"}); err != nil {
+		t.Errorf("expected the address to be reusable after rejection, got %v", err)
+	}
+}
+
+func TestApprovalOfUnknownOrActiveUsers(t *testing.T) {
+	ctx := context.Background()
+	_, users, repo := newApprovalTestUseCases(t)
+	repo.users[1] = &user.User{ID: 1, Email: "active@example.com", IsActive: true}
+
+	tests := []struct {
+		name    string
+		id      uint
+		wantErr error
+	}{
+		{"active user", 1, user.ErrNotPendingApproval},
+		{"unknown user", 99, user.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := users.ApproveUser(ctx, tt.id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("approve: expected %v, got %v", tt.wantErr, err)
+			}
+			if err := users.RejectUser(ctx, tt.id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("reject: expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, ok := repo.users[1]; !ok {
+		t.Error("expected an active user to survive a rejection attempt")
+	}
+}
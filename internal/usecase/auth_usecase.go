@@ -3,27 +3,44 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"moon/internal/config"
 	"moon/internal/domain/user"
+	"moon/pkg/cache"
 	"moon/pkg/hash"
 	"moon/pkg/jwt"
+	"moon/pkg/mailer"
 )
 
 type AuthUseCase interface {
 	Register(ctx context.Context, req user.CreateUserRequest) (*user.UserResponse, error)
 	Login(ctx context.Context, req user.LoginRequest) (*user.LoginResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*user.LoginResponse, error)
+	ValidateTokenVersion(ctx context.Context, userID uint, tokenVersion int) error
+	Logout(ctx context.Context, userID uint, tokenID string, expiresAt time.Time, refreshToken string) error
+	IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error)
+	RequestEmailChange(ctx context.Context, userID uint, req user.ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+	ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error
 }
 
 type authUseCase struct {
 	userRepo user.Repository
+	cache    *cache.Cache
+	mailer   mailer.Mailer
 	cfg      *config.Config
 }
 
 // NewAuthUseCase creates a new auth use case
-func NewAuthUseCase(userRepo user.Repository, cfg *config.Config) AuthUseCase {
+func NewAuthUseCase(userRepo user.Repository, cache *cache.Cache, mailer mailer.Mailer, cfg *config.Config) AuthUseCase {
 	return &authUseCase{
 		userRepo: userRepo,
+		cache:    cache,
+		mailer:   mailer,
 		cfg:      cfg,
 	}
 }
@@ -32,13 +49,13 @@ func (uc *authUseCase) Register(ctx context.Context, req user.CreateUserRequest)
 	// Check if user already exists
 	existingUser, _ := uc.userRepo.GetByEmail(ctx, req.Email)
 	if existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, user.ErrEmailExists
 	}
 
 	// Hash password
 	hashedPassword, err := hash.HashPassword(req.Password)
 	if err != nil {
-		return nil, errors.New("failed to hash password")
+		return nil, wrapError("failed to hash password", err)
 	}
 
 	// Create user
@@ -54,73 +71,254 @@ func (uc *authUseCase) Register(ctx context.Context, req user.CreateUserRequest)
 		IsActive: true,
 	}
 
-	if err := uc.userRepo.Create(ctx, newUser); err != nil {
-		return nil, errors.New("failed to create user")
+	// Moderated sites keep new accounts inactive until an admin approves them
+	if uc.cfg.Account.RequireApproval {
+		newUser.IsActive = false
+		newUser.PendingApproval = true
 	}
 
-	// Return user response
-	response := &user.UserResponse{
-		ID:        newUser.ID,
-		Email:     newUser.Email,
-		Name:      newUser.Name,
-		Phone:     getStringValue(newUser.Phone),
-		Address:   getStringValue(newUser.Address),
-		Lat:       getFloat64Value(newUser.Lat),
-		Lng:       getFloat64Value(newUser.Lng),
-		Role:      newUser.Role,
-		IsActive:  newUser.IsActive,
-		CreatedAt: newUser.CreatedAt,
-		UpdatedAt: newUser.UpdatedAt,
+	if err := uc.userRepo.Create(ctx, newUser); err != nil {
+		return nil, wrapError("failed to create user", err)
 	}
 
-	return response, nil
+	response := toUserResponse(newUser)
+	return &response, nil
 }
 
 func (uc *authUseCase) Login(ctx context.Context, req user.LoginRequest) (*user.LoginResponse, error) {
-	// Get user by email
-	u, err := uc.userRepo.GetByEmail(ctx, req.Email)
-	if err != nil {
-		return nil, errors.New("invalid email or password")
+	identifier := strings.TrimSpace(req.Identifier)
+	if identifier == "" {
+		identifier = strings.TrimSpace(req.Email)
+	}
+	if identifier == "" {
+		return nil, user.ErrIdentifierRequired
 	}
 
-	// Check if user is active
-	if !u.IsActive {
-		return nil, errors.New("user account is deactivated")
+	// Get user by email, or by username when the identifier is not an address
+	var u *user.User
+	var err error
+	if strings.Contains(identifier, "@") || !uc.cfg.Account.UsernameLogin {
+		u, err = uc.userRepo.GetByEmail(ctx, identifier)
+	} else {
+		u, err = uc.userRepo.GetByUsername(ctx, identifier)
+	}
+	if err != nil {
+		return nil, user.ErrInvalidCredentials
 	}
 
 	// Verify password
 	if !hash.CheckPasswordHash(req.Password, u.Password) {
-		return nil, errors.New("invalid email or password")
+		return nil, user.ErrInvalidCredentials
+	}
+
+	// Check if user is active; approval status is only revealed to the account owner
+	if u.PendingApproval {
+		return nil, user.ErrPendingApproval
+	}
+	if !u.IsActive {
+		return nil, user.ErrDeactivated
+	}
+
+	evicted, err := uc.limitSessions(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := uc.issueTokens(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	response.EvictedSessions = evicted
+	return response, nil
+}
+
+// limitSessions makes room for a new session under account.max_sessions. In evict_oldest
+// mode it revokes the user's oldest refresh tokens and returns how many; their access
+// tokens stay valid until they expire but can no longer be renewed. In reject mode a user
+// at the cap gets ErrSessionLimit. Refreshing replaces a token, so only logins are capped.
+func (uc *authUseCase) limitSessions(ctx context.Context, u *user.User) (int, error) {
+	limit := uc.cfg.Account.MaxSessions
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	active, err := uc.userRepo.GetActiveRefreshTokens(ctx, u.ID, u.TokenVersion, time.Now())
+	if err != nil {
+		return 0, wrapError("failed to fetch sessions", err)
+	}
+	excess := len(active) - limit + 1
+	if excess <= 0 {
+		return 0, nil
+	}
+	if uc.cfg.Account.SessionLimitMode == "reject" {
+		return 0, user.ErrSessionLimit
+	}
+
+	evicted := 0
+	for _, token := range active[:excess] {
+		revoked, err := uc.userRepo.RevokeRefreshToken(ctx, token.ID)
+		if err != nil {
+			return 0, wrapError("failed to revoke refresh token", err)
+		}
+		if revoked {
+			evicted++
+		}
+	}
+	return evicted, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access token and a new refresh token.
+// The presented token is revoked, so it can only be used once.
+func (uc *authUseCase) RefreshToken(ctx context.Context, refreshToken string) (*user.LoginResponse, error) {
+	stored, err := uc.userRepo.GetRefreshToken(ctx, jwt.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidRefresh) {
+			return nil, err
+		}
+		return nil, wrapError("failed to fetch refresh token", err)
+	}
+
+	if stored.Revoked {
+		// A revoked token being presented again means it leaked; end every session it may
+		// have spawned
+		if err := uc.userRepo.RevokeRefreshTokens(ctx, stored.UserID); err != nil {
+			return nil, wrapError("failed to revoke refresh tokens", err)
+		}
+		return nil, user.ErrInvalidRefresh
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, user.ErrInvalidRefresh
+	}
+
+	revoked, err := uc.userRepo.RevokeRefreshToken(ctx, stored.ID)
+	if err != nil {
+		return nil, wrapError("failed to revoke refresh token", err)
+	}
+	if !revoked {
+		// Another request rotated this token first
+		return nil, user.ErrInvalidRefresh
 	}
 
+	u, err := uc.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, user.ErrInvalidRefresh
+	}
+	if u.TokenVersion != stored.TokenVersion {
+		return nil, user.ErrInvalidRefresh
+	}
+	if u.PendingApproval {
+		return nil, user.ErrPendingApproval
+	}
+	if !u.IsActive {
+		return nil, user.ErrDeactivated
+	}
+
+	return uc.issueTokens(ctx, u)
+}
+
+// issueTokens creates an access token and a refresh token for u
+func (uc *authUseCase) issueTokens(ctx context.Context, u *user.User) (*user.LoginResponse, error) {
 	// Generate JWT token
-	token, err := jwt.GenerateToken(u.ID, u.Email, u.Role, uc.cfg.JWT.Secret, uc.cfg.JWT.ExpiresIn)
+	var orgID uint
+	if u.OrgID != nil {
+		orgID = *u.OrgID
+	}
+
+	token, err := jwt.GenerateToken(u.ID, u.Email, u.Role, u.TokenVersion, orgID, uc.cfg.JWT.Secret, uc.cfg.JWT.ExpiresIn)
 	if err != nil {
-		return nil, errors.New("failed to generate token")
+		return nil, wrapError("failed to generate token", err)
 	}
 
-	// Prepare user response
-	userResponse := user.UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		Phone:     getStringValue(u.Phone),
-		Address:   getStringValue(u.Address),
-		Lat:       getFloat64Value(u.Lat),
-		Lng:       getFloat64Value(u.Lng),
-		Role:      u.Role,
-		IsActive:  u.IsActive,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+	refreshToken, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		return nil, wrapError("failed to generate refresh token", err)
+	}
+	err = uc.userRepo.CreateRefreshToken(ctx, &user.RefreshToken{
+		UserID:       u.ID,
+		TokenHash:    jwt.HashRefreshToken(refreshToken),
+		TokenVersion: u.TokenVersion,
+		ExpiresAt:    time.Now().Add(time.Duration(uc.cfg.JWT.RefreshExpiresIn) * time.Hour),
+	})
+	if err != nil {
+		return nil, wrapError("failed to store refresh token", err)
 	}
 
+	// Prepare user response
+	userResponse := toUserResponse(u)
+
 	// Return login response
 	return &user.LoginResponse{
-		Token: token,
-		User:  userResponse,
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         userResponse,
 	}, nil
 }
 
+// Logout revokes the session's refresh token and blacklists its access token until it
+// expires, so the client can neither keep using nor renew the session
+func (uc *authUseCase) Logout(ctx context.Context, userID uint, tokenID string, expiresAt time.Time, refreshToken string) error {
+	if tokenID == "" {
+		// Tokens issued before token IDs existed cannot be revoked individually
+		return user.ErrInvalidToken
+	}
+
+	stored, err := uc.userRepo.GetRefreshToken(ctx, jwt.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidRefresh) {
+			return err
+		}
+		return wrapError("failed to fetch refresh token", err)
+	}
+	if stored.UserID != userID {
+		return user.ErrInvalidRefresh
+	}
+	// A token already revoked, e.g. by an earlier logout, needs nothing more
+	if _, err := uc.userRepo.RevokeRefreshToken(ctx, stored.ID); err != nil {
+		return wrapError("failed to revoke refresh token", err)
+	}
+
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := uc.cache.Set(ctx, tokenBlacklistKey(tokenID), 1, ttl); err != nil {
+		return wrapError("failed to revoke token", err)
+	}
+	return nil
+}
+
+// IsTokenBlacklisted reports whether the token was logged out. While Redis is unavailable
+// it returns cache.ErrUnavailable, since no token can be told apart from a logged out one,
+// unless redis.fail_open.token_blacklist lets logged out tokens work until Redis is back.
+func (uc *authUseCase) IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	_, err := uc.cache.Get(ctx, tokenBlacklistKey(tokenID))
+	if errors.Is(err, cache.ErrUnavailable) {
+		if uc.cfg.Redis.FailOpen.TokenBlacklist {
+			return false, nil
+		}
+		return false, wrapError("failed to check token blacklist", err)
+	}
+	return err == nil, nil
+}
+
+func (uc *authUseCase) ValidateTokenVersion(ctx context.Context, userID uint, tokenVersion int) error {
+	state, err := getTokenState(ctx, uc.userRepo, uc.cache, userID)
+	if err != nil {
+		return user.ErrNotFound
+	}
+
+	if state.Version != tokenVersion {
+		return user.ErrTokenRevoked
+	}
+
+	if uc.cfg.JWT.CheckUserActive && !state.Active {
+		return user.ErrDeactivated
+	}
+
+	return nil
+}
+
 // Helper functions to handle pointer values
 func getStringValue(ptr *string) string {
 	if ptr == nil {
@@ -135,3 +333,108 @@ func getFloat64Value(ptr *float64) float64 {
 	}
 	return *ptr
 }
+
+// RequestEmailChange stores the new address as pending and mails a confirmation link to it.
+// The current email stays in use until the link is followed.
+func (uc *authUseCase) RequestEmailChange(ctx context.Context, userID uint, req user.ChangeEmailRequest) error {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	newEmail := strings.TrimSpace(req.Email)
+	if strings.EqualFold(newEmail, u.Email) {
+		return user.ErrSameEmail
+	}
+
+	existingUser, _ := uc.userRepo.GetByEmail(ctx, newEmail)
+	if existingUser != nil {
+		return user.ErrEmailInUse
+	}
+
+	token, err := hash.GenerateToken()
+	if err != nil {
+		return wrapError("failed to generate confirmation token", err)
+	}
+
+	tokenHash := hash.HashToken(token)
+	expiresAt := time.Now().Add(time.Duration(uc.cfg.Account.EmailChangeTTLMinutes) * time.Minute)
+	u.PendingEmail = &newEmail
+	u.EmailChangeTokenHash = &tokenHash
+	u.EmailChangeExpiresAt = &expiresAt
+
+	if err := uc.userRepo.Update(ctx, u); err != nil {
+		return wrapError("failed to save email change", err)
+	}
+
+	link := uc.cfg.Account.ConfirmEmailURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hi %s,\n\nConfirm your new email address by opening this link:\n%s\n\nThe link expires in %d minutes. If you did not request this change, ignore this email.",
+		u.Name, link, uc.cfg.Account.EmailChangeTTLMinutes)
+
+	if err := uc.mailer.Send(newEmail, "Confirm your new email address", body); err != nil {
+		return wrapError("failed to send confirmation email", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange applies a pending email change and signs the user out everywhere
+func (uc *authUseCase) ConfirmEmailChange(ctx context.Context, token string) error {
+	u, err := uc.userRepo.GetByEmailChangeToken(ctx, hash.HashToken(token))
+	if err != nil || u.PendingEmail == nil || u.EmailChangeExpiresAt == nil || time.Now().After(*u.EmailChangeExpiresAt) {
+		return user.ErrInvalidToken
+	}
+
+	// The address may have been taken since the change was requested
+	existingUser, _ := uc.userRepo.GetByEmail(ctx, *u.PendingEmail)
+	if existingUser != nil {
+		return user.ErrEmailInUse
+	}
+
+	u.Email = *u.PendingEmail
+	u.PendingEmail = nil
+	u.EmailChangeTokenHash = nil
+	u.EmailChangeExpiresAt = nil
+
+	if err := uc.userRepo.Update(ctx, u); err != nil {
+		return wrapError("failed to update email", err)
+	}
+
+	if err := revokeTokens(ctx, uc.userRepo, uc.cache, u.ID); err != nil {
+		return wrapError("failed to revoke user tokens", err)
+	}
+
+	return nil
+}
+
+// ChangePassword replaces the user's password after verifying the current one, and signs
+// the user out of all sessions
+func (uc *authUseCase) ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return user.ErrNotFound
+	}
+
+	if !hash.CheckPasswordHash(oldPassword, u.Password) {
+		return user.ErrWrongPassword
+	}
+	if newPassword == oldPassword {
+		return user.ErrSamePassword
+	}
+
+	hashedPassword, err := hash.HashPassword(newPassword)
+	if err != nil {
+		return wrapError("failed to hash password", err)
+	}
+	u.Password = hashedPassword
+
+	if err := uc.userRepo.Update(ctx, u); err != nil {
+		return wrapError("failed to update password", err)
+	}
+
+	if err := revokeTokens(ctx, uc.userRepo, uc.cache, u.ID); err != nil {
+		return wrapError("failed to revoke user tokens", err)
+	}
+
+	return nil
+}
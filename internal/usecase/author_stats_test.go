@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+
+	"gorm.io/gorm"
+)
+
+func TestAuthorPostCounts(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.GetConfig()
+
+	repo := &fakePostRepo{posts: []*post.Post{
+		{ID: 1, AuthorID: authorID, Status: "published"},
+		{ID: 2, AuthorID: authorID, Status: "draft"},
+		{ID: 3, AuthorID: authorID, Status: "archived"},
+		{ID: 4, AuthorID: authorID, Status: "published"},
+		{ID: 5, AuthorID: authorID, Status: "published", DeletedAt: gorm.DeletedAt{Valid: true}},
+		{ID: 6, AuthorID: otherUserID, Status: "published"},
+	}}
+	uc := NewPostUseCase(repo, &fakeUserRepo{users: map[uint]*user.User{}}, nil, nil, &cfg)
+	ctx := context.Background()
+
+	stats, err := uc.GetAuthorStats(ctx, authorID)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.TotalPosts != 4 || stats.PublishedPosts != 2 {
+		t.Errorf("expected 4 posts of which 2 published, got %+v", stats)
+	}
+
+	mine, err := uc.GetMyPosts(ctx, authorID, 1, 3)
+	if err != nil {
+		t.Fatalf("my posts: %v", err)
+	}
+	if mine.Total != stats.TotalPosts || len(mine.Posts) != 3 || mine.TotalPages != 2 {
+		t.Errorf("expected the first 3 of %d posts over 2 pages, got %d of %d over %d pages", stats.TotalPosts, len(mine.Posts), mine.Total, mine.TotalPages)
+	}
+
+	if stats, err := uc.GetAuthorStats(ctx, 99); err != nil || stats.TotalPosts != 0 || stats.PublishedPosts != 0 {
+		t.Errorf("expected no posts for an unknown author, got %+v, %v", stats, err)
+	}
+}
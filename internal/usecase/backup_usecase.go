@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+	"moon/pkg/storage"
+)
+
+const (
+	backupPrefix    = "content/"
+	backupBatchSize = 500
+)
+
+// BackupResult describes a completed backup
+type BackupResult struct {
+	Key     string
+	Size    int64
+	Pruned  []string
+	Posts   int
+	Users   int
+	Elapsed time.Duration
+}
+
+type BackupUseCase interface {
+	BackupContent(ctx context.Context) (*BackupResult, error)
+}
+
+type backupUseCase struct {
+	postRepo post.Repository
+	userRepo user.Repository
+	storage  storage.Storage
+	cfg      *config.Config
+}
+
+// NewBackupUseCase creates a new backup use case
+func NewBackupUseCase(postRepo post.Repository, userRepo user.Repository, storage storage.Storage, cfg *config.Config) BackupUseCase {
+	return &backupUseCase{
+		postRepo: postRepo,
+		userRepo: userRepo,
+		storage:  storage,
+		cfg:      cfg,
+	}
+}
+
+// BackupContent streams every post, and users when configured, as one JSON document to
+// storage, then deletes backups beyond the retention count
+func (uc *backupUseCase) BackupContent(ctx context.Context) (*BackupResult, error) {
+	started := time.Now()
+	result := &BackupResult{
+		Key: fmt.Sprintf("%s%s.json", backupPrefix, started.UTC().Format("20060102T150405Z")),
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(uc.export(ctx, writer, started, result))
+	}()
+
+	size, err := uc.storage.Put(ctx, result.Key, reader)
+	reader.Close()
+	if err != nil {
+		return nil, wrapError("failed to write backup", err)
+	}
+	result.Size = size
+
+	pruned, err := uc.prune(ctx)
+	if err != nil {
+		return nil, wrapError("failed to prune backups", err)
+	}
+	result.Pruned = pruned
+	result.Elapsed = time.Since(started)
+
+	return result, nil
+}
+
+// export writes {"created_at": ..., "posts": [...], "users": [...]} reading the tables in
+// batches so the whole data set is never held in memory
+func (uc *backupUseCase) export(ctx context.Context, w io.Writer, createdAt time.Time, result *BackupResult) error {
+	header, err := json.Marshal(createdAt.UTC())
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, `{"created_at":%s,"posts":`, header); err != nil {
+		return err
+	}
+
+	result.Posts, err = writeBatches(ctx, w, func(afterID uint) ([]*post.Post, error) {
+		return uc.postRepo.GetAfterID(ctx, afterID, backupBatchSize)
+	}, func(p *post.Post) uint { return p.ID })
+	if err != nil {
+		return err
+	}
+
+	if uc.cfg.Backup.IncludeUsers {
+		if _, err := io.WriteString(w, `,"users":`); err != nil {
+			return err
+		}
+		result.Users, err = writeBatches(ctx, w, func(afterID uint) ([]*user.User, error) {
+			return uc.userRepo.GetAfterID(ctx, afterID, backupBatchSize)
+		}, func(u *user.User) uint { return u.ID })
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "}")
+	return err
+}
+
+// writeBatches writes a JSON array of every record returned by fetch, paging by ID
+func writeBatches[T any](ctx context.Context, w io.Writer, fetch func(afterID uint) ([]T, error), id func(T) uint) (int, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		records, err := fetch(afterID)
+		if err != nil {
+			return count, err
+		}
+
+		for _, record := range records {
+			data, err := json.Marshal(record)
+			if err != nil {
+				return count, err
+			}
+			if count > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return count, err
+				}
+			}
+			if _, err := w.Write(data); err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		if len(records) < backupBatchSize {
+			break
+		}
+		afterID = id(records[len(records)-1])
+	}
+
+	_, err := io.WriteString(w, "]")
+	return count, err
+}
+
+// prune deletes the oldest backups so that only the configured number remain
+func (uc *backupUseCase) prune(ctx context.Context) ([]string, error) {
+	objects, err := uc.storage.List(ctx, backupPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []string
+	for _, key := range expiredBackups(objects, uc.cfg.Backup.Retention) {
+		if err := uc.storage.Delete(ctx, key); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, key)
+	}
+	return pruned, nil
+}
+
+// expiredBackups returns the keys of all but the newest keep backups. Backup keys embed
+// their creation time, so key order is age order.
+func expiredBackups(objects []storage.Object, keep int) []string {
+	var keys []string
+	for _, object := range objects {
+		if strings.HasSuffix(object.Key, ".json") {
+			keys = append(keys, object.Key)
+		}
+	}
+	if len(keys) <= keep {
+		return nil
+	}
+	return keys[:len(keys)-keep]
+}
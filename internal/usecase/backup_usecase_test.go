@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+	"moon/pkg/storage"
+)
+
+// backupDocument is the shape of a backup file
+type backupDocument struct {
+	Posts []post.Post `json:"posts"`
+	Users []user.User `json:"users"`
+}
+
+func TestBackupContentExport(t *testing.T) {
+	// More posts than one batch, to cover paging through the table
+	posts := &fakePostRepo{}
+	for i := 0; i < backupBatchSize+2; i++ {
+		posts.Create(context.Background(), &post.Post{Title: fmt.Sprintf("Post %d", i)})
+	}
+	users := &fakeUserRepo{users: map[uint]*user.User{
+		1: {ID: 1, Email: "a@example.com", Password: "hash"},
+		2: {ID: 2, Email: "b@example.com", Password: "hash"},
+	}}
+
+	tests := []struct {
+		name         string
+		includeUsers bool
+		wantUsers    int
+	}{
+		{"posts only", false, 0},
+		{"with users", true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := &config.Config{}
+			cfg.Backup.Retention = 7
+			cfg.Backup.IncludeUsers = tt.includeUsers
+
+			result, err := NewBackupUseCase(posts, users, storage.NewLocal(dir), cfg).BackupContent(context.Background())
+			if err != nil {
+				t.Fatalf("backup: %v", err)
+			}
+			if result.Posts != len(posts.posts) || result.Users != tt.wantUsers {
+				t.Errorf("expected %d posts and %d users, got %+v", len(posts.posts), tt.wantUsers, result)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(result.Key)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int64(len(data)) != result.Size {
+				t.Errorf("expected the reported size %d to match the file, got %d", result.Size, len(data))
+			}
+
+			var doc backupDocument
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("expected a valid JSON document, got %v", err)
+			}
+			if len(doc.Posts) != len(posts.posts) || len(doc.Users) != tt.wantUsers {
+				t.Fatalf("expected %d posts and %d users in the file, got %d and %d", len(posts.posts), tt.wantUsers, len(doc.Posts), len(doc.Users))
+			}
+			for i, p := range doc.Posts {
+				if p.ID != uint(i+1) {
+					t.Fatalf("expected every post once in ID order, got ID %d at %d", p.ID, i)
+				}
+			}
+			for _, u := range doc.Users {
+				if u.Password != "" {
+					t.Errorf("expected password hashes to be left out, got %q", u.Password)
+				}
+			}
+		})
+	}
+}
+
+func TestExpiredBackups(t *testing.T) {
+	objects := []storage.Object{
+		{Key: "content/20260101T000000Z.json"},
+		{Key: "content/20260102T000000Z.json"},
+		{Key: "content/20260103T000000Z.json"},
+		{Key: "content/notes.txt"},
+	}
+
+	tests := []struct {
+		name string
+		keep int
+		want []string
+	}{
+		{"keep all", 3, nil},
+		{"keep more than exist", 5, nil},
+		{"keep newest two", 2, []string{"content/20260101T000000Z.json"}},
+		{"keep newest one", 1, []string{"content/20260101T000000Z.json", "content/20260102T000000Z.json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expiredBackups(objects, tt.keep); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBackupContentPrunesOldBackups(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewLocal(dir)
+	if err := os.MkdirAll(filepath.Join(dir, "content"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"content/20200101T000000Z.json", "content/20200102T000000Z.json"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(key)), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{}
+	cfg.Backup.Retention = 2
+	result, err := NewBackupUseCase(&fakePostRepo{}, &fakeUserRepo{}, store, cfg).BackupContent(context.Background())
+	if err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	if !slices.Equal(result.Pruned, []string{"content/20200101T000000Z.json"}) {
+		t.Errorf("expected the oldest backup to be pruned, got %v", result.Pruned)
+	}
+
+	objects, err := store.List(context.Background(), backupPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	if want := []string{"content/20200102T000000Z.json", result.Key}; !slices.Equal(keys, want) {
+		t.Errorf("expected %v to remain, got %v", want, keys)
+	}
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"moon/internal/domain/product"
+)
+
+type CategoryUseCase interface {
+	GetCategoriesWithPostCounts(ctx context.Context, nonEmpty bool) ([]*product.CategoryWithPostCount, error)
+}
+
+type categoryUseCase struct {
+	categoryRepo product.CategoryRepository
+}
+
+// NewCategoryUseCase creates a new category use case
+func NewCategoryUseCase(categoryRepo product.CategoryRepository) CategoryUseCase {
+	return &categoryUseCase{
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (uc *categoryUseCase) GetCategoriesWithPostCounts(ctx context.Context, nonEmpty bool) ([]*product.CategoryWithPostCount, error) {
+	categories, err := uc.categoryRepo.GetWithPostCounts(ctx, nonEmpty)
+	if err != nil {
+		return nil, wrapError("failed to fetch categories", err)
+	}
+	if err := checkResultCap(len(categories)); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
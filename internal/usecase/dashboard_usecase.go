@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"moon/internal/domain/dashboard"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+)
+
+const (
+	dashboardCacheKey = "admin:dashboard"
+	dashboardCacheTTL = time.Minute
+	dashboardRecent   = 5
+)
+
+type DashboardUseCase interface {
+	GetSummary(ctx context.Context) (*dashboard.SummaryResponse, error)
+}
+
+type dashboardUseCase struct {
+	userRepo user.Repository
+	postRepo post.Repository
+	cache    *cache.Cache
+}
+
+// NewDashboardUseCase creates a new dashboard use case
+func NewDashboardUseCase(userRepo user.Repository, postRepo post.Repository, cache *cache.Cache) DashboardUseCase {
+	return &dashboardUseCase{
+		userRepo: userRepo,
+		postRepo: postRepo,
+		cache:    cache,
+	}
+}
+
+func (uc *dashboardUseCase) GetSummary(ctx context.Context) (*dashboard.SummaryResponse, error) {
+	// Serve from cache when a recent summary exists
+	if cached, err := uc.cache.Get(ctx, dashboardCacheKey); err == nil {
+		var summary dashboard.SummaryResponse
+		if err := json.Unmarshal([]byte(cached), &summary); err == nil {
+			return &summary, nil
+		}
+	}
+
+	userCounts, err := uc.userRepo.CountByActive(ctx)
+	if err != nil {
+		return nil, wrapError("failed to count users", err)
+	}
+
+	postCounts, err := uc.postRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, wrapError("failed to count posts", err)
+	}
+
+	recentUsers, err := uc.userRepo.GetAll(ctx, dashboardRecent, 0)
+	if err != nil {
+		return nil, wrapError("failed to fetch recent users", err)
+	}
+
+	recentPosts, err := uc.postRepo.GetAll(ctx, post.PostFilter{}, dashboardRecent, 0)
+	if err != nil {
+		return nil, wrapError("failed to fetch recent posts", err)
+	}
+
+	summary := &dashboard.SummaryResponse{
+		Users: dashboard.UserStats{
+			Total:    userCounts[true] + userCounts[false],
+			Active:   userCounts[true],
+			Inactive: userCounts[false],
+		},
+		Posts: dashboard.PostStats{
+			ByStatus: postCounts,
+		},
+		RecentRegistrations: make([]dashboard.RecentUser, len(recentUsers)),
+		RecentPosts:         make([]dashboard.RecentPost, len(recentPosts)),
+		GeneratedAt:         time.Now(),
+	}
+
+	for _, count := range postCounts {
+		summary.Posts.Total += count
+	}
+
+	for i, u := range recentUsers {
+		summary.RecentRegistrations[i] = dashboard.RecentUser{
+			ID:        u.ID,
+			Email:     u.Email,
+			Name:      u.Name,
+			Role:      u.Role,
+			CreatedAt: u.CreatedAt,
+		}
+	}
+
+	for i, p := range recentPosts {
+		summary.RecentPosts[i] = dashboard.RecentPost{
+			ID:        p.ID,
+			Title:     p.Title,
+			Slug:      p.Slug,
+			Status:    p.Status,
+			AuthorID:  p.AuthorID,
+			CreatedAt: p.CreatedAt,
+		}
+	}
+
+	if data, err := json.Marshal(summary); err == nil {
+		uc.cache.Set(ctx, dashboardCacheKey, data, dashboardCacheTTL)
+	}
+
+	return summary, nil
+}
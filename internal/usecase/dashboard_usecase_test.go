@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+	"moon/pkg/cache/cachetest"
+)
+
+func newDashboardRepos() (*fakeUserRepo, *fakePostRepo) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	users := &fakeUserRepo{users: map[uint]*user.User{}}
+	for i := uint(1); i <= 7; i++ {
+		users.users[i] = &user.User{ID: i, Email: "u@example.com", IsActive: i != 3, CreatedAt: start.Add(time.Duration(i) * time.Hour)}
+	}
+
+	posts := &fakePostRepo{}
+	statuses := []string{"published", "published", "draft", "archived", "published", "draft"}
+	for i, status := range statuses {
+		posts.posts = append(posts.posts, &post.Post{ID: uint(i + 1), Status: status, CreatedAt: start.Add(time.Duration(i) * time.Hour)})
+	}
+	return users, posts
+}
+
+func TestDashboardSummary(t *testing.T) {
+	users, posts := newDashboardRepos()
+	uc := NewDashboardUseCase(users, posts, cache.New(nil, cache.Options{}))
+
+	summary, err := uc.GetSummary(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.Users.Total != 7 || summary.Users.Active != 6 || summary.Users.Inactive != 1 {
+		t.Errorf("unexpected user stats: %+v", summary.Users)
+	}
+	if summary.Posts.Total != 6 || summary.Posts.ByStatus["published"] != 3 || summary.Posts.ByStatus["draft"] != 2 || summary.Posts.ByStatus["archived"] != 1 {
+		t.Errorf("unexpected post stats: %+v", summary.Posts)
+	}
+
+	if len(summary.RecentRegistrations) != dashboardRecent || summary.RecentRegistrations[0].ID != 7 {
+		t.Errorf("expected the %d newest users, newest first, got %+v", dashboardRecent, summary.RecentRegistrations)
+	}
+	if len(summary.RecentPosts) != dashboardRecent || summary.RecentPosts[0].ID != 6 {
+		t.Errorf("expected the %d newest posts, newest first, got %+v", dashboardRecent, summary.RecentPosts)
+	}
+}
+
+func TestDashboardSummaryIsCached(t *testing.T) {
+	ctx := context.Background()
+	_, store := cachetest.NewServer(t)
+	users, posts := newDashboardRepos()
+	uc := NewDashboardUseCase(users, posts, store)
+
+	first, err := uc.GetSummary(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	posts.posts = append(posts.posts, &post.Post{ID: 7, Status: "published"})
+
+	second, err := uc.GetSummary(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Posts.Total != first.Posts.Total || !second.GeneratedAt.Equal(first.GeneratedAt) {
+		t.Errorf("expected the cached summary to be served, got %+v", second.Posts)
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+)
+
+// newDraftArchivalPosts returns one post per case the archival job must tell apart
+func newDraftArchivalPosts() []*post.Post {
+	old := time.Now().AddDate(0, 0, -100)
+	recent := time.Now().AddDate(0, 0, -10)
+	return []*post.Post{
+		{ID: 1, Status: "draft", UpdatedAt: old},
+		{ID: 2, Status: "draft", UpdatedAt: recent},
+		{ID: 3, Status: "published", UpdatedAt: old},
+		{ID: 4, Status: "archived", UpdatedAt: old},
+	}
+}
+
+func TestCleanupStaleDrafts(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		action       string
+		dryRun       bool
+		wantAffected int64
+		wantStatus   string
+		wantDeleted  bool
+	}{
+		{"archive", "archive", false, 1, "archived", false},
+		{"delete", "delete", false, 1, "draft", true},
+		{"dry run", "delete", true, 0, "draft", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Post.DraftArchival = config.DraftArchivalConfig{AfterDays: 90, Action: tt.action, DryRun: tt.dryRun}
+			repo := &fakePostRepo{posts: newDraftArchivalPosts()}
+			uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, cfg)
+
+			ids, affected, err := uc.CleanupStaleDrafts(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(ids, []uint{1}) {
+				t.Errorf("expected only the old draft to match, got %v", ids)
+			}
+			if affected != tt.wantAffected {
+				t.Errorf("expected %d affected, got %d", tt.wantAffected, affected)
+			}
+
+			stale := repo.posts[0]
+			if stale.Status != tt.wantStatus || stale.DeletedAt.Valid != tt.wantDeleted {
+				t.Errorf("expected status %q and deleted %v, got %q and %v", tt.wantStatus, tt.wantDeleted, stale.Status, stale.DeletedAt.Valid)
+			}
+			for _, p := range repo.posts[1:] {
+				if want := newDraftArchivalPosts()[p.ID-1]; p.Status != want.Status || p.DeletedAt.Valid {
+					t.Errorf("expected post %d to be left alone, got %+v", p.ID, p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+)
+
+// newEmailChangeTestUseCase returns an auth use case for user 1 (a@example.com) and user 2
+// (taken@example.com)
+func newEmailChangeTestUseCase() (AuthUseCase, *fakeUserRepo, *fakeMailer) {
+	cfg := &config.Config{}
+	cfg.Account.EmailChangeTTLMinutes = 60
+	cfg.Account.ConfirmEmailURL = "http://localhost/api/v1/auth/confirm-email"
+
+	repo := &fakeUserRepo{users: map[uint]*user.User{
+		1: {ID: 1, Email: "a@example.com", Name: "A", IsActive: true, TokenVersion: 1},
+		2: {ID: 2, Email: "taken@example.com", IsActive: true},
+	}}
+	mail := &fakeMailer{}
+	return NewAuthUseCase(repo, cache.New(nil, cache.Options{}), mail, cfg), repo, mail
+}
+
+var confirmTokenPattern = regexp.MustCompile(`\?token=(\S+)`)
+
+// confirmToken returns the token of the confirmation link in the last email sent
+func confirmToken(t *testing.T, mail *fakeMailer) string {
+	t.Helper()
+	if len(mail.sent) == 0 {
+		t.Fatal("expected a confirmation email")
+	}
+	match := confirmTokenPattern.FindStringSubmatch(mail.sent[len(mail.sent)-1].body)
+	if match == nil {
+		t.Fatal("expected a confirmation link in the email")
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestEmailChange(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, mail := newEmailChangeTestUseCase()
+
+	if err := uc.RequestEmailChange(ctx, 1, user.ChangeEmailRequest{Email: "new@example.com"}); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if mail.sent[0].to != "new@example.com" {
+		t.Errorf("expected the link sent to the new address, got %q", mail.sent[0].to)
+	}
+	// The current address stays in use until the change is confirmed
+	if repo.users[1].Email != "a@example.com" {
+		t.Errorf("expected the email unchanged before confirmation, got %q", repo.users[1].Email)
+	}
+
+	if err := uc.ConfirmEmailChange(ctx, confirmToken(t, mail)); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	u := repo.users[1]
+	if u.Email != "new@example.com" || u.PendingEmail != nil || u.EmailChangeTokenHash != nil {
+		t.Errorf("expected the pending change applied and cleared, got %+v", u)
+	}
+	if u.TokenVersion != 2 {
+		t.Errorf("expected the user signed out everywhere, got token version %d", u.TokenVersion)
+	}
+
+	// The link works once
+	if err := uc.ConfirmEmailChange(ctx, confirmToken(t, mail)); !errors.Is(err, user.ErrInvalidToken) {
+		t.Errorf("expected a used link to be rejected, got %v", err)
+	}
+}
+
+func TestEmailChangeRejected(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("same email", func(t *testing.T) {
+		uc, _, mail := newEmailChangeTestUseCase()
+		if err := uc.RequestEmailChange(ctx, 1, user.ChangeEmailRequest{Email: "A@example.com"}); !errors.Is(err, user.ErrSameEmail) {
+			t.Errorf("expected %v, got %v", user.ErrSameEmail, err)
+		}
+		if len(mail.sent) != 0 {
+			t.Error("expected no email sent")
+		}
+	})
+
+	t.Run("address in use", func(t *testing.T) {
+		uc, _, _ := newEmailChangeTestUseCase()
+		if err := uc.RequestEmailChange(ctx, 1, user.ChangeEmailRequest{Email: "taken@example.com"}); !errors.Is(err, user.ErrEmailInUse) {
+			t.Errorf("expected %v, got %v", user.ErrEmailInUse, err)
+		}
+	})
+
+	t.Run("address taken since the request", func(t *testing.T) {
+		uc, repo, mail := newEmailChangeTestUseCase()
+		if err := uc.RequestEmailChange(ctx, 1, user.ChangeEmailRequest{Email: "new@example.com"}); err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		repo.users[2].Email = "new@example.com"
+
+		if err := uc.ConfirmEmailChange(ctx, confirmToken(t, mail)); !errors.Is(err, user.ErrEmailInUse) {
+			t.Errorf("expected %v, got %v", user.ErrEmailInUse, err)
+		}
+		if repo.users[1].Email != "a@example.com" {
+			t.Errorf("expected the email unchanged, got %q", repo.users[1].Email)
+		}
+	})
+
+	t.Run("expired link", func(t *testing.T) {
+		uc, repo, mail := newEmailChangeTestUseCase()
+		if err := uc.RequestEmailChange(ctx, 1, user.ChangeEmailRequest{Email: "new@example.com"}); err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		expired := time.Now().Add(-time.Minute)
+		repo.users[1].EmailChangeExpiresAt = &expired
+
+		if err := uc.ConfirmEmailChange(ctx, confirmToken(t, mail)); !errors.Is(err, user.ErrInvalidToken) {
+			t.Errorf("expected %v, got %v", user.ErrInvalidToken, err)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		uc, _, _ := newEmailChangeTestUseCase()
+		if err := uc.ConfirmEmailChange(ctx, "unknown"); !errors.Is(err, user.ErrInvalidToken) {
+			t.Errorf("expected %v, got %v", user.ErrInvalidToken, err)
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import "errors"
+
+// ErrResultTooLarge is returned when a query would return or skip past more rows than
+// allowed; clients should narrow their filters instead of paging deeper
+var ErrResultTooLarge = errors.New("result too large, narrow your query")
+
+// causeError pairs a client-facing message with the underlying error. Error returns only
+// the message, so handlers can keep matching on it, while Unwrap exposes the cause.
+type causeError struct {
+	msg   string
+	cause error
+}
+
+func (e *causeError) Error() string {
+	return e.msg
+}
+
+func (e *causeError) Unwrap() error {
+	return e.cause
+}
+
+// wrapError returns an error with message msg that wraps cause
+func wrapError(msg string, cause error) error {
+	return &causeError{msg: msg, cause: cause}
+}
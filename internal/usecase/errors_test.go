@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestWrapError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("outer: %w", wrapError("failed to fetch post", cause))
+
+	if got := errors.Unwrap(err).Error(); got != "failed to fetch post" {
+		t.Errorf("expected the friendly message, got %q", got)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected %v to wrap %v", err, cause)
+	}
+}
+
+// failingCreateRepo fails every insert with err
+type failingCreateRepo struct {
+	*fakePostRepo
+	err error
+}
+
+func (r *failingCreateRepo) Create(ctx context.Context, p *post.Post) error {
+	return r.err
+}
+
+func TestCreatePostKeepsTheCause(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.GetConfig()
+
+	dbErr := errors.New("duplicate key value violates unique constraint")
+	repo := &failingCreateRepo{fakePostRepo: &fakePostRepo{}, err: dbErr}
+	users := &fakeUserRepo{users: map[uint]*user.User{}}
+	uc := NewPostUseCase(repo, users, nil, &fakeTransactor{posts: repo, users: users}, &cfg)
+
+	req := post.CreatePostRequest{Title: "A new post", Content: "Some content for the post"}
+	_, err := uc.CreatePost(context.Background(), req, authorID, user.RoleAdmin)
+	if err == nil {
+		t.Fatal("expected the insert to fail")
+	}
+	if err.Error() != "failed to create post" {
+		t.Errorf("expected the friendly message, got %q", err.Error())
+	}
+	if !errors.Is(err, dbErr) {
+		t.Errorf("expected the database error to be kept, got %v", err)
+	}
+}
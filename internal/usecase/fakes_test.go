@@ -0,0 +1,1047 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+	"moon/internal/domain/report"
+	"moon/internal/domain/transaction"
+	"moon/internal/domain/user"
+
+	"gorm.io/gorm"
+)
+
+// fakeTransactor runs fn against the fake repositories without isolation or rollback
+type fakeTransactor struct {
+	posts   post.Repository
+	users   user.Repository
+	reports report.Repository
+}
+
+func (t *fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context, repos transaction.Repositories) error) error {
+	return fn(ctx, transaction.Repositories{Posts: t.posts, Users: t.users, Reports: t.reports})
+}
+
+// fakePostRepo keeps posts in memory. It embeds the interface so tests only implement the
+// methods they exercise; calling any other method panics.
+type fakePostRepo struct {
+	post.Repository
+	posts         []*post.Post
+	collaborators map[uint][]uint // post ID to collaborator user IDs
+	editors       map[uint][]uint // post ID to collaborators with edit permission
+	tags          map[uint][]string
+	tagNames      []string // tag ID - 1 to name
+	tagUsage      []post.TagUsage
+	// tagErr fails tag lookups; tagQueries counts them
+	tagErr     error
+	tagQueries int
+	// attentionFilter is the filter of the last attention query
+	attentionFilter post.AttentionFilter
+	lastModified    *time.Time
+	publishedSort   string // order of the last published listing
+	listFilter      post.PostFilter
+	// updated lists the IDs passed to Update. GetByID hands out the stored posts, so changes
+	// show before they are saved.
+	updated   []uint
+	updateErr error
+}
+
+func (r *fakePostRepo) Create(ctx context.Context, p *post.Post) error {
+	p.ID = uint(len(r.posts) + 1)
+	r.posts = append(r.posts, p)
+	return nil
+}
+
+func (r *fakePostRepo) Update(ctx context.Context, p *post.Post) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	r.updated = append(r.updated, p.ID)
+	for i, stored := range r.posts {
+		if stored.ID == p.ID {
+			r.posts[i] = p
+			return nil
+		}
+	}
+	return post.ErrNotFound
+}
+
+// UpsertTags numbers new tags in the order they are first seen and reuses existing ones
+func (r *fakePostRepo) UpsertTags(ctx context.Context, names []string) ([]post.Tag, error) {
+	tags := make([]post.Tag, len(names))
+	for i, name := range names {
+		id := uint(slices.Index(r.tagNames, name) + 1)
+		if id == 0 {
+			r.tagNames = append(r.tagNames, name)
+			id = uint(len(r.tagNames))
+		}
+		tags[i] = post.Tag{ID: id, Name: name}
+	}
+	return tags, nil
+}
+
+func (r *fakePostRepo) SetPostTags(ctx context.Context, postID uint, tagIDs []uint) error {
+	if r.tags == nil {
+		r.tags = map[uint][]string{}
+	}
+	names := make([]string, len(tagIDs))
+	for i, id := range tagIDs {
+		names[i] = r.tagNames[id-1]
+	}
+	r.tags[postID] = names
+	return nil
+}
+
+func (r *fakePostRepo) GetByID(ctx context.Context, id uint) (*post.Post, error) {
+	for _, p := range r.posts {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, post.ErrNotFound
+}
+
+func (r *fakePostRepo) GetBySlug(ctx context.Context, slug string) (*post.Post, error) {
+	for _, p := range r.posts {
+		if p.Slug == slug && !p.DeletedAt.Valid {
+			return p, nil
+		}
+	}
+	return nil, post.ErrNotFound
+}
+
+func (r *fakePostRepo) GetAfterID(ctx context.Context, afterID uint, limit int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if p.ID > afterID && len(posts) < limit {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+func (r *fakePostRepo) GetBySlugForUpdate(ctx context.Context, slug string) (*post.Post, error) {
+	return r.GetBySlug(ctx, slug)
+}
+
+func (r *fakePostRepo) GetBySlugs(ctx context.Context, slugs []string) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if slices.Contains(slugs, p.Slug) {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+// GetChanges orders posts by change time and ID like the real query, where a soft-deleted
+// post changed when it was deleted
+func (r *fakePostRepo) GetChanges(ctx context.Context, filter post.ChangesFilter, limit int) ([]*post.Post, error) {
+	changedAt := func(p *post.Post) time.Time {
+		if p.DeletedAt.Valid {
+			return p.DeletedAt.Time
+		}
+		return p.UpdatedAt
+	}
+
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if p.DeletedAt.Valid && !filter.IncludeDeleted {
+			continue
+		}
+		at := changedAt(p)
+		if !at.After(filter.Since) {
+			continue
+		}
+		if filter.After != nil && !(at.After(filter.After.ChangedAt) || (at.Equal(filter.After.ChangedAt) && p.ID > filter.After.ID)) {
+			continue
+		}
+		posts = append(posts, p)
+	}
+	slices.SortFunc(posts, func(a, b *post.Post) int {
+		if c := changedAt(a).Compare(changedAt(b)); c != 0 {
+			return c
+		}
+		return int(a.ID) - int(b.ID)
+	})
+	return posts[:min(limit, len(posts))], nil
+}
+
+func (r *fakePostRepo) GetByAuthor(ctx context.Context, authorID uint, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if p.AuthorID == authorID && !p.DeletedAt.Valid {
+			posts = append(posts, p)
+		}
+	}
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	return posts[offset:min(offset+limit, len(posts))], nil
+}
+
+func (r *fakePostRepo) CountByAuthor(ctx context.Context, authorID uint) (int64, error) {
+	posts, err := r.GetByAuthor(ctx, authorID, len(r.posts), 0)
+	return int64(len(posts)), err
+}
+
+func (r *fakePostRepo) CountPublishedByAuthor(ctx context.Context, authorID uint) (int64, error) {
+	var count int64
+	for _, p := range r.posts {
+		if p.AuthorID == authorID && p.Status == "published" && !p.DeletedAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetPublished records the order and returns the public published posts unsorted
+func (r *fakePostRepo) GetPublished(ctx context.Context, sort, language string, limit, offset int) ([]*post.Post, error) {
+	r.publishedSort = sort
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if p.Status == "published" && p.IsPublic && !p.DeletedAt.Valid {
+			posts = append(posts, p)
+		}
+	}
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	return posts[offset:min(offset+limit, len(posts))], nil
+}
+
+func (r *fakePostRepo) GetPublishedLastModified(ctx context.Context) (*time.Time, error) {
+	return r.lastModified, nil
+}
+
+// Touch bumps updated_at and nothing else, like the real repository
+func (r *fakePostRepo) Touch(ctx context.Context, id uint) error {
+	for _, p := range r.posts {
+		if p.ID == id {
+			p.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *fakePostRepo) GetByTitleLength(ctx context.Context, minLength, maxLength int, viewer *post.Viewer, limit int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if viewer != nil && !r.readableBy(p, viewer) {
+			continue
+		}
+		if length := utf8.RuneCountInString(p.Title); length >= minLength && length <= maxLength && len(posts) < limit {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+// readableBy mirrors the visibility condition of the real repository
+func (r *fakePostRepo) readableBy(p *post.Post, viewer *post.Viewer) bool {
+	if p.Status == "published" && (p.IsPublic || viewer.PrivatePublished) {
+		return true
+	}
+	if viewer.UserID == 0 {
+		return false
+	}
+	return p.AuthorID == viewer.UserID || slices.Contains(r.editors[p.ID], viewer.UserID) || slices.Contains(r.collaborators[p.ID], viewer.UserID)
+}
+
+func (r *fakePostRepo) GetCollaborator(ctx context.Context, postID, userID uint) (*post.Collaborator, error) {
+	if slices.Contains(r.editors[postID], userID) {
+		return &post.Collaborator{PostID: postID, UserID: userID, Permission: post.CollaboratorEdit}, nil
+	}
+	if slices.Contains(r.collaborators[postID], userID) {
+		return &post.Collaborator{PostID: postID, UserID: userID, Permission: "view"}, nil
+	}
+	return nil, post.ErrNotFound
+}
+
+func (r *fakePostRepo) SaveCollaborator(ctx context.Context, collaborator *post.Collaborator) error {
+	r.DeleteCollaborator(ctx, collaborator.PostID, collaborator.UserID)
+	if collaborator.CanEdit() {
+		if r.editors == nil {
+			r.editors = map[uint][]uint{}
+		}
+		r.editors[collaborator.PostID] = append(r.editors[collaborator.PostID], collaborator.UserID)
+		return nil
+	}
+	if r.collaborators == nil {
+		r.collaborators = map[uint][]uint{}
+	}
+	r.collaborators[collaborator.PostID] = append(r.collaborators[collaborator.PostID], collaborator.UserID)
+	return nil
+}
+
+func (r *fakePostRepo) DeleteCollaborator(ctx context.Context, postID, userID uint) error {
+	isUser := func(id uint) bool { return id == userID }
+	if ids, ok := r.editors[postID]; ok {
+		r.editors[postID] = slices.DeleteFunc(ids, isUser)
+	}
+	if ids, ok := r.collaborators[postID]; ok {
+		r.collaborators[postID] = slices.DeleteFunc(ids, isUser)
+	}
+	return nil
+}
+
+// ReassignAuthor moves posts, trashed ones included, like the unscoped update
+func (r *fakePostRepo) ReassignAuthor(ctx context.Context, fromUserID, toUserID uint) (int64, error) {
+	var moved int64
+	for _, p := range r.posts {
+		if p.AuthorID == fromUserID {
+			p.AuthorID = toUserID
+			moved++
+		}
+		if p.ScheduledBy != nil && *p.ScheduledBy == fromUserID {
+			p.ScheduledBy = &toUserID
+		}
+	}
+	return moved, nil
+}
+
+// ReassignCollaborations drops fromUserID's collaborations on posts toUserID authors or
+// already collaborates on, then moves the rest
+func (r *fakePostRepo) ReassignCollaborations(ctx context.Context, fromUserID, toUserID uint) (int64, error) {
+	conflict := func(postID uint) bool {
+		if slices.Contains(r.editors[postID], toUserID) || slices.Contains(r.collaborators[postID], toUserID) {
+			return true
+		}
+		return slices.ContainsFunc(r.posts, func(p *post.Post) bool { return p.ID == postID && p.AuthorID == toUserID })
+	}
+
+	var moved int64
+	for _, lists := range []map[uint][]uint{r.editors, r.collaborators} {
+		for postID, ids := range lists {
+			i := slices.Index(ids, fromUserID)
+			if i < 0 {
+				continue
+			}
+			if conflict(postID) {
+				lists[postID] = slices.Delete(ids, i, i+1)
+				continue
+			}
+			ids[i] = toUserID
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+func (r *fakePostRepo) GetPublishedInMonth(ctx context.Context, year, month, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if p.Status == "published" && p.IsPublic && !p.DeletedAt.Valid && p.PublishedAt != nil &&
+			p.PublishedAt.Year() == year && int(p.PublishedAt.Month()) == month {
+			posts = append(posts, p)
+		}
+	}
+	slices.SortStableFunc(posts, func(a, b *post.Post) int { return b.PublishedAt.Compare(*a.PublishedAt) })
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	return posts[offset:min(offset+limit, len(posts))], nil
+}
+
+func (r *fakePostRepo) CountPublishedInMonth(ctx context.Context, year, month int) (int64, error) {
+	posts, _ := r.GetPublishedInMonth(ctx, year, month, len(r.posts), 0)
+	return int64(len(posts)), nil
+}
+
+func (r *fakePostRepo) GetSharedWith(ctx context.Context, userID uint, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if p.DeletedAt.Valid {
+			continue
+		}
+		if slices.Contains(r.editors[p.ID], userID) || slices.Contains(r.collaborators[p.ID], userID) {
+			posts = append(posts, p)
+		}
+	}
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	return posts[offset:min(offset+limit, len(posts))], nil
+}
+
+func (r *fakePostRepo) CountSharedWith(ctx context.Context, userID uint) (int64, error) {
+	posts, _ := r.GetSharedWith(ctx, userID, len(r.posts), 0)
+	return int64(len(posts)), nil
+}
+
+func (r *fakePostRepo) GetTagNames(ctx context.Context, postIDs []uint) (map[uint][]string, error) {
+	r.tagQueries++
+	if r.tagErr != nil {
+		return nil, r.tagErr
+	}
+	names := make(map[uint][]string)
+	for _, id := range postIDs {
+		if tags, ok := r.tags[id]; ok {
+			names[id] = tags
+		}
+	}
+	return names, nil
+}
+
+// GetTotalCount honours only the author filter
+func (r *fakePostRepo) GetTotalCount(ctx context.Context, filter post.PostFilter) (int64, error) {
+	var count int64
+	for _, p := range r.posts {
+		if filter.AuthorID == nil || p.AuthorID == *filter.AuthorID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakePostRepo) GetTagUsage(ctx context.Context, slugs []string) ([]post.TagUsage, error) {
+	var usage []post.TagUsage
+	for _, u := range r.tagUsage {
+		if slices.Contains(slugs, u.Slug) {
+			usage = append(usage, u)
+		}
+	}
+	return usage, nil
+}
+
+// GetAuthorTags counts the tags of the author's published public posts, most used first
+func (r *fakePostRepo) GetAuthorTags(ctx context.Context, authorID uint, limit int) ([]post.TagUsage, error) {
+	usage := []post.TagUsage{}
+	for _, p := range r.posts {
+		if p.AuthorID != authorID || p.Status != "published" || !p.IsPublic || p.DeletedAt.Valid {
+			continue
+		}
+		for _, name := range r.tags[p.ID] {
+			i := slices.IndexFunc(usage, func(u post.TagUsage) bool { return u.Name == name })
+			if i < 0 {
+				usage = append(usage, post.TagUsage{Name: name, Slug: post.TagSlug(name)})
+				i = len(usage) - 1
+			}
+			usage[i].Posts++
+		}
+	}
+	slices.SortStableFunc(usage, func(a, b post.TagUsage) int {
+		if a.Posts != b.Posts {
+			return int(b.Posts - a.Posts)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	if limit > 0 && len(usage) > limit {
+		usage = usage[:limit]
+	}
+	return usage, nil
+}
+
+// GetDuePosts hands out copies, like rows read from the database
+func (r *fakePostRepo) GetDuePosts(ctx context.Context, now time.Time, limit int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if p.Status == "draft" && p.PublishAt != nil && !p.PublishAt.After(now) && len(posts) < limit {
+			stored := *p
+			posts = append(posts, &stored)
+		}
+	}
+	return posts, nil
+}
+
+// PublishScheduled mirrors the guarded update: only a due draft is published
+func (r *fakePostRepo) PublishScheduled(ctx context.Context, id uint, now time.Time) (bool, error) {
+	for _, p := range r.posts {
+		if p.ID == id && p.Status == "draft" && p.PublishAt != nil && !p.PublishAt.After(now) {
+			p.Status = "published"
+			p.PublishedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakePostRepo) Unschedule(ctx context.Context, id uint, now time.Time) error {
+	for _, p := range r.posts {
+		if p.ID == id && p.Status == "draft" && p.PublishAt != nil && !p.PublishAt.After(now) {
+			p.PublishAt = nil
+		}
+	}
+	return nil
+}
+
+// GetAll pages through live posts, newest first, ignoring the filter
+// GetAll records the filter and returns every live post, newest first
+func (r *fakePostRepo) GetAll(ctx context.Context, filter post.PostFilter, limit, offset int) ([]*post.Post, error) {
+	r.listFilter = filter
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if !p.DeletedAt.Valid {
+			posts = append(posts, p)
+		}
+	}
+	slices.SortFunc(posts, func(a, b *post.Post) int { return b.CreatedAt.Compare(a.CreatedAt) })
+
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	return posts[offset:min(offset+limit, len(posts))], nil
+}
+
+func (r *fakePostRepo) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	for _, p := range r.posts {
+		if !p.DeletedAt.Valid {
+			counts[p.Status]++
+		}
+	}
+	return counts, nil
+}
+
+// GetScheduled returns drafts due after now, soonest first, ignoring the filter
+func (r *fakePostRepo) GetScheduled(ctx context.Context, filter post.ScheduledFilter, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if p.Status == "draft" && p.PublishAt != nil && p.PublishAt.After(time.Now()) {
+			posts = append(posts, p)
+		}
+	}
+	slices.SortFunc(posts, func(a, b *post.Post) int { return a.PublishAt.Compare(*b.PublishAt) })
+
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	return posts[offset:min(offset+limit, len(posts))], nil
+}
+
+func (r *fakePostRepo) GetScheduledCount(ctx context.Context, filter post.ScheduledFilter) (int64, error) {
+	posts, err := r.GetScheduled(ctx, filter, len(r.posts), 0)
+	return int64(len(posts)), err
+}
+
+// GetNeedingAttention records the filter. Stale drafts are matched like the real query,
+// every other rule returns all posts.
+func (r *fakePostRepo) GetNeedingAttention(ctx context.Context, filter post.AttentionFilter, limit, offset int) ([]*post.Post, error) {
+	r.attentionFilter = filter
+	if filter.Rule != post.AttentionStaleDrafts {
+		return r.posts, nil
+	}
+
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if isStaleDraft(p, filter.Before) && len(posts) < limit {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+func (r *fakePostRepo) GetNeedingAttentionCount(ctx context.Context, filter post.AttentionFilter) (int64, error) {
+	posts, err := r.GetNeedingAttention(ctx, filter, len(r.posts), 0)
+	return int64(len(posts)), err
+}
+
+// isStaleDraft reports whether a post is a live draft last updated before the cutoff
+func isStaleDraft(p *post.Post, before time.Time) bool {
+	return !p.DeletedAt.Valid && p.Status == "draft" && p.UpdatedAt.Before(before)
+}
+
+func (r *fakePostRepo) ArchiveStaleDrafts(ctx context.Context, ids []uint, before time.Time) (int64, error) {
+	var affected int64
+	for _, p := range r.posts {
+		if slices.Contains(ids, p.ID) && isStaleDraft(p, before) {
+			p.Status = "archived"
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+func (r *fakePostRepo) DeleteStaleDrafts(ctx context.Context, ids []uint, before time.Time) (int64, error) {
+	var affected int64
+	for _, p := range r.posts {
+		if slices.Contains(ids, p.ID) && isStaleDraft(p, before) {
+			p.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+// GetDeleted returns soft-deleted posts, most recently deleted first
+func (r *fakePostRepo) GetDeleted(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range r.posts {
+		if p.DeletedAt.Valid {
+			posts = append(posts, p)
+		}
+	}
+	slices.SortFunc(posts, func(a, b *post.Post) int { return b.DeletedAt.Time.Compare(a.DeletedAt.Time) })
+
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	return posts[offset:min(offset+limit, len(posts))], nil
+}
+
+func (r *fakePostRepo) GetDeletedByAuthor(ctx context.Context, authorID uint, limit, offset int) ([]*post.Post, error) {
+	deleted, err := r.GetDeleted(ctx, len(r.posts), 0)
+	var posts []*post.Post
+	for _, p := range deleted {
+		if p.AuthorID == authorID {
+			posts = append(posts, p)
+		}
+	}
+	if offset >= len(posts) {
+		return nil, err
+	}
+	return posts[offset:min(offset+limit, len(posts))], err
+}
+
+func (r *fakePostRepo) GetDeletedByAuthorCount(ctx context.Context, authorID uint) (int64, error) {
+	posts, err := r.GetDeletedByAuthor(ctx, authorID, len(r.posts), 0)
+	return int64(len(posts)), err
+}
+
+func (r *fakePostRepo) GetDeletedByID(ctx context.Context, id uint) (*post.Post, error) {
+	for _, p := range r.posts {
+		if p.ID == id && p.DeletedAt.Valid {
+			return p, nil
+		}
+	}
+	return nil, post.ErrNotFound
+}
+
+func (r *fakePostRepo) Restore(ctx context.Context, id uint, slug string) error {
+	for _, p := range r.posts {
+		if p.ID == id && p.DeletedAt.Valid {
+			p.DeletedAt = gorm.DeletedAt{}
+			p.Slug = slug
+		}
+	}
+	return nil
+}
+
+func (r *fakePostRepo) GetDeletedCount(ctx context.Context) (int64, error) {
+	var count int64
+	for _, p := range r.posts {
+		if p.DeletedAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakePostRepo) ResetViewCounts(ctx context.Context, ids []uint) (int64, error) {
+	var reset int64
+	for _, p := range r.posts {
+		if slices.Contains(ids, p.ID) {
+			p.ViewCount = 0
+			reset++
+		}
+	}
+	return reset, nil
+}
+
+func (r *fakePostRepo) IncrementViewCount(ctx context.Context, id uint) error {
+	return nil
+}
+
+// fakeUserRepo keeps users in memory and hands out copies, like rows read from the
+// database. With no users, post authors are reported as "Unknown".
+type fakeUserRepo struct {
+	user.Repository
+	users         map[uint]*user.User
+	refreshTokens []*user.RefreshToken
+	locked        []uint // users loaded for update
+	batchQueries  int    // calls to GetByIDs
+	notifications map[uint]*user.NotificationPreferences
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, u *user.User) error {
+	if r.users == nil {
+		r.users = map[uint]*user.User{}
+	}
+	u.ID = uint(len(r.users) + 1)
+	for r.users[u.ID] != nil {
+		u.ID++
+	}
+	stored := *u
+	r.users[u.ID] = &stored
+	return nil
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uint) (*user.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, user.ErrNotFound
+	}
+	stored := *u
+	return &stored, nil
+}
+
+func (r *fakeUserRepo) GetByIDs(ctx context.Context, ids []uint) ([]*user.User, error) {
+	r.batchQueries++
+	var users []*user.User
+	for _, id := range ids {
+		if u, ok := r.users[id]; ok {
+			stored := *u
+			users = append(users, &stored)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeUserRepo) GetAfterID(ctx context.Context, afterID uint, limit int) ([]*user.User, error) {
+	var users []*user.User
+	for _, u := range r.users {
+		if u.ID > afterID {
+			stored := *u
+			users = append(users, &stored)
+		}
+	}
+	slices.SortFunc(users, func(a, b *user.User) int { return int(a.ID) - int(b.ID) })
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+// GetByEmail ignores case, like the collation of the email column
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	for _, u := range r.users {
+		if strings.EqualFold(u.Email, email) {
+			stored := *u
+			return &stored, nil
+		}
+	}
+	return nil, user.ErrNotFound
+}
+
+func (r *fakeUserRepo) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.Username != nil && *u.Username == username {
+			stored := *u
+			return &stored, nil
+		}
+	}
+	return nil, user.ErrNotFound
+}
+
+func (r *fakeUserRepo) GetByEmailChangeToken(ctx context.Context, tokenHash string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.EmailChangeTokenHash != nil && *u.EmailChangeTokenHash == tokenHash {
+			stored := *u
+			return &stored, nil
+		}
+	}
+	return nil, user.ErrNotFound
+}
+
+func (r *fakeUserRepo) GetByIDForUpdate(ctx context.Context, id uint) (*user.User, error) {
+	r.locked = append(r.locked, id)
+	return r.GetByID(ctx, id)
+}
+
+// Update stores every field except the token version, like the real repository
+func (r *fakeUserRepo) Update(ctx context.Context, u *user.User) error {
+	stored, ok := r.users[u.ID]
+	if !ok {
+		return user.ErrNotFound
+	}
+	updated := *u
+	updated.TokenVersion = stored.TokenVersion
+	r.users[u.ID] = &updated
+	return nil
+}
+
+func (r *fakeUserRepo) Delete(ctx context.Context, id uint) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeUserRepo) IncrementTokenVersion(ctx context.Context, id uint) error {
+	u, ok := r.users[id]
+	if !ok {
+		return user.ErrNotFound
+	}
+	u.TokenVersion++
+	return nil
+}
+
+// GetByRole pages through the users of a role in ID order
+func (r *fakeUserRepo) GetByRole(ctx context.Context, role string, limit, offset int) ([]*user.User, error) {
+	var users []*user.User
+	for _, u := range r.users {
+		if u.Role == role {
+			users = append(users, u)
+		}
+	}
+	slices.SortFunc(users, func(a, b *user.User) int { return int(a.ID) - int(b.ID) })
+
+	if offset >= len(users) {
+		return nil, nil
+	}
+	return users[offset:min(offset+limit, len(users))], nil
+}
+
+func (r *fakeUserRepo) GetCountByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	for _, u := range r.users {
+		if u.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetPendingApproval pages through the users awaiting approval in ID order
+func (r *fakeUserRepo) GetPendingApproval(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	var users []*user.User
+	for _, u := range r.users {
+		if u.PendingApproval {
+			users = append(users, u)
+		}
+	}
+	slices.SortFunc(users, func(a, b *user.User) int { return int(a.ID) - int(b.ID) })
+
+	if offset >= len(users) {
+		return nil, nil
+	}
+	return users[offset:min(offset+limit, len(users))], nil
+}
+
+func (r *fakeUserRepo) CountPendingApproval(ctx context.Context) (int64, error) {
+	var count int64
+	for _, u := range r.users {
+		if u.PendingApproval {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetAll pages through users, newest first like the real repository
+func (r *fakeUserRepo) GetAll(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	var users []*user.User
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	slices.SortFunc(users, func(a, b *user.User) int { return b.CreatedAt.Compare(a.CreatedAt) })
+
+	if offset >= len(users) {
+		return nil, nil
+	}
+	return users[offset:min(offset+limit, len(users))], nil
+}
+
+func (r *fakeUserRepo) CountByActive(ctx context.Context) (map[bool]int64, error) {
+	counts := make(map[bool]int64)
+	for _, u := range r.users {
+		counts[u.IsActive]++
+	}
+	return counts, nil
+}
+
+func (r *fakeUserRepo) GetTotalCount(ctx context.Context) (int64, error) {
+	return int64(len(r.users)), nil
+}
+
+func (r *fakeUserRepo) CreateRefreshToken(ctx context.Context, token *user.RefreshToken) error {
+	token.ID = uint(len(r.refreshTokens) + 1)
+	r.refreshTokens = append(r.refreshTokens, token)
+	return nil
+}
+
+func (r *fakeUserRepo) GetRefreshToken(ctx context.Context, tokenHash string) (*user.RefreshToken, error) {
+	for _, token := range r.refreshTokens {
+		if token.TokenHash == tokenHash {
+			stored := *token
+			return &stored, nil
+		}
+	}
+	return nil, user.ErrInvalidRefresh
+}
+
+// RevokeRefreshToken revokes the token unless it already was, like the conditional update
+// of the real repository
+func (r *fakeUserRepo) RevokeRefreshToken(ctx context.Context, id uint) (bool, error) {
+	for _, token := range r.refreshTokens {
+		if token.ID == id && !token.Revoked {
+			token.Revoked = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetActiveRefreshTokens keeps the stored order, which is the order of creation
+func (r *fakeUserRepo) GetActiveRefreshTokens(ctx context.Context, userID uint, tokenVersion int, now time.Time) ([]*user.RefreshToken, error) {
+	var tokens []*user.RefreshToken
+	for _, token := range r.refreshTokens {
+		if token.UserID == userID && !token.Revoked && token.TokenVersion == tokenVersion && token.ExpiresAt.After(now) {
+			stored := *token
+			tokens = append(tokens, &stored)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakeUserRepo) RevokeRefreshTokens(ctx context.Context, userID uint) error {
+	for _, token := range r.refreshTokens {
+		if token.UserID == userID {
+			token.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) GetNotificationPreferences(ctx context.Context, userID uint) (*user.NotificationPreferences, error) {
+	prefs, ok := r.notifications[userID]
+	if !ok {
+		return user.DefaultNotificationPreferences(userID), nil
+	}
+	stored := *prefs
+	return &stored, nil
+}
+
+func (r *fakeUserRepo) SaveNotificationPreferences(ctx context.Context, prefs *user.NotificationPreferences) error {
+	if r.notifications == nil {
+		r.notifications = map[uint]*user.NotificationPreferences{}
+	}
+	stored := *prefs
+	r.notifications[prefs.UserID] = &stored
+	return nil
+}
+
+// fakeMailer records the emails it is asked to send
+type fakeMailer struct {
+	sent []sentMail
+}
+
+type sentMail struct {
+	to, subject, body string
+}
+
+func (m *fakeMailer) Send(to, subject, body string) error {
+	m.sent = append(m.sent, sentMail{to: to, subject: subject, body: body})
+	return nil
+}
+
+// fakeProductRepo keeps products in memory. DecrementStock checks and updates the stock
+// under a lock, like the guarded UPDATE of the real repository.
+type fakeProductRepo struct {
+	product.Repository
+	mu       sync.Mutex
+	products map[uint]*product.Product
+}
+
+func (r *fakeProductRepo) GetByID(ctx context.Context, id uint) (*product.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.products[id]
+	if !ok {
+		return nil, product.ErrNotFound
+	}
+	stored := *p
+	return &stored, nil
+}
+
+func (r *fakeProductRepo) DecrementStock(ctx context.Context, id uint, qty int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.products[id]
+	if !ok || !p.IsActive || p.Stock < qty {
+		return false, nil
+	}
+	p.Stock -= qty
+	return true, nil
+}
+
+// fakeCategoryRepo keeps categories in memory
+type fakeCategoryRepo struct {
+	product.CategoryRepository
+	categories map[uint]*product.Category
+}
+
+func (r *fakeCategoryRepo) GetBySlug(ctx context.Context, slug string) (*product.Category, error) {
+	for _, c := range r.categories {
+		if c.Slug == slug {
+			return c, nil
+		}
+	}
+	return nil, product.ErrCategoryNotFound
+}
+
+func (r *fakeCategoryRepo) GetWithPostCounts(ctx context.Context, nonEmpty bool) ([]*product.CategoryWithPostCount, error) {
+	var categories []*product.CategoryWithPostCount
+	for _, c := range r.categories {
+		categories = append(categories, &product.CategoryWithPostCount{ID: c.ID, Name: c.Name, Slug: c.Slug})
+	}
+	return categories, nil
+}
+
+func (r *fakeCategoryRepo) GetByID(ctx context.Context, id uint) (*product.Category, error) {
+	c, ok := r.categories[id]
+	if !ok {
+		return nil, product.ErrCategoryNotFound
+	}
+	return c, nil
+}
+
+// fakeReportRepo keeps reports in memory
+type fakeReportRepo struct {
+	report.Repository
+	reports []*report.Report
+}
+
+func (r *fakeReportRepo) Create(ctx context.Context, rep *report.Report) error {
+	rep.ID = uint(len(r.reports) + 1)
+	r.reports = append(r.reports, rep)
+	return nil
+}
+
+func (r *fakeReportRepo) GetByID(ctx context.Context, id uint) (*report.Report, error) {
+	for _, rep := range r.reports {
+		if rep.ID == id {
+			return rep, nil
+		}
+	}
+	return nil, report.ErrNotFound
+}
+
+func (r *fakeReportRepo) GetByReporterAndTarget(ctx context.Context, reporterID uint, targetType string, targetID uint) (*report.Report, error) {
+	for _, rep := range r.reports {
+		if rep.ReporterID == reporterID && rep.TargetType == targetType && rep.TargetID == targetID {
+			return rep, nil
+		}
+	}
+	return nil, report.ErrNotFound
+}
+
+func (r *fakeReportRepo) Update(ctx context.Context, rep *report.Report) error {
+	return nil
+}
+
+// ReassignReporter drops fromUserID's reports of targets toUserID already reported, then
+// moves the rest
+func (r *fakeReportRepo) ReassignReporter(ctx context.Context, fromUserID, toUserID uint) (int64, error) {
+	r.reports = slices.DeleteFunc(r.reports, func(rep *report.Report) bool {
+		if rep.ReporterID != fromUserID {
+			return false
+		}
+		_, err := r.GetByReporterAndTarget(ctx, toUserID, rep.TargetType, rep.TargetID)
+		return err == nil
+	})
+
+	var moved int64
+	for _, rep := range r.reports {
+		if rep.ReporterID == fromUserID {
+			rep.ReporterID = toUserID
+			moved++
+		}
+	}
+	return moved, nil
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"moon/internal/domain/user"
+	"moon/pkg/mailer"
+)
+
+// Notifier emails users about activity in optional categories (see user.Notify*),
+// skipping users who opted out of the category
+type Notifier interface {
+	Notify(ctx context.Context, userID uint, category, subject, body string) error
+}
+
+type notifier struct {
+	userRepo user.Repository
+	mailer   mailer.Mailer
+}
+
+// NewNotifier creates a notifier that checks preferences before sending through mailer
+func NewNotifier(userRepo user.Repository, mailer mailer.Mailer) Notifier {
+	return &notifier{
+		userRepo: userRepo,
+		mailer:   mailer,
+	}
+}
+
+func (n *notifier) Notify(ctx context.Context, userID uint, category, subject, body string) error {
+	prefs, err := n.userRepo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return wrapError("failed to fetch notification preferences", err)
+	}
+	if !prefs.Allows(category) {
+		return nil
+	}
+
+	u, err := n.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !u.IsActive {
+		return nil
+	}
+
+	if err := n.mailer.Send(u.Email, subject, body); err != nil {
+		return wrapError("failed to send notification", err)
+	}
+	return nil
+}
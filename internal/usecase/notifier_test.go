@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"moon/internal/domain/user"
+)
+
+func TestNotifierRespectsPreferences(t *testing.T) {
+	off := false
+
+	tests := []struct {
+		name     string
+		update   user.UpdateNotificationPreferencesRequest
+		category string
+		wantSent bool
+	}{
+		{"comment replies by default", user.UpdateNotificationPreferencesRequest{}, user.NotifyCommentReplies, true},
+		{"published posts by default", user.UpdateNotificationPreferencesRequest{}, user.NotifyPostPublished, true},
+		{"no marketing by default", user.UpdateNotificationPreferencesRequest{}, user.NotifyMarketing, false},
+		{"comment replies disabled", user.UpdateNotificationPreferencesRequest{CommentReplies: &off}, user.NotifyCommentReplies, false},
+		{"published posts disabled", user.UpdateNotificationPreferencesRequest{PostPublished: &off}, user.NotifyPostPublished, false},
+		{"other category disabled", user.UpdateNotificationPreferencesRequest{PostPublished: &off}, user.NotifyCommentReplies, true},
+		{"transactional mail", user.UpdateNotificationPreferencesRequest{CommentReplies: &off, PostPublished: &off}, "security", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := &fakeUserRepo{users: map[uint]*user.User{
+				authorID: {ID: authorID, Email: "author@example.com", IsActive: true},
+			}}
+			mail := &fakeMailer{}
+			if _, err := NewUserUseCase(repo, nil, nil).UpdateNotificationPreferences(ctx, authorID, tt.update); err != nil {
+				t.Fatalf("update preferences: %v", err)
+			}
+
+			if err := NewNotifier(repo, mail).Notify(ctx, authorID, tt.category, "Subject", "Body"); err != nil {
+				t.Fatalf("notify: %v", err)
+			}
+			if sent := len(mail.sent) == 1; sent != tt.wantSent {
+				t.Errorf("expected sent %v, got %v", tt.wantSent, mail.sent)
+			}
+		})
+	}
+}
+
+func TestNotifierSkipsInactiveUsers(t *testing.T) {
+	repo := &fakeUserRepo{users: map[uint]*user.User{
+		authorID: {ID: authorID, Email: "author@example.com", IsActive: false},
+	}}
+	mail := &fakeMailer{}
+
+	if err := NewNotifier(repo, mail).Notify(context.Background(), authorID, user.NotifyCommentReplies, "Subject", "Body"); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if len(mail.sent) != 0 {
+		t.Errorf("expected no mail for an inactive user, got %v", mail.sent)
+	}
+}
+
+// A partial update keeps the categories it leaves out
+func TestUpdateNotificationPreferencesIsPartial(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeUserRepo{}
+	uc := NewUserUseCase(repo, nil, nil)
+	on, off := true, false
+
+	if _, err := uc.UpdateNotificationPreferences(ctx, authorID, user.UpdateNotificationPreferencesRequest{Marketing: &on}); err != nil {
+		t.Fatal(err)
+	}
+	prefs, err := uc.UpdateNotificationPreferences(ctx, authorID, user.UpdateNotificationPreferencesRequest{CommentReplies: &off})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefs.CommentReplies || !prefs.PostPublished || !prefs.Marketing {
+		t.Errorf("expected only comment replies to be turned off, got %+v", prefs)
+	}
+	if stored, _ := uc.GetNotificationPreferences(ctx, authorID); *stored != *prefs {
+		t.Errorf("expected the saved preferences %+v, got %+v", prefs, stored)
+	}
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import "moon/internal/config"
+
+// normalizePagination resets invalid values to their defaults and clamps
+// oversized limits to the configured maximum instead of discarding them
+func normalizePagination(page, limit int) (int, int) {
+	cfg := config.GetConfig().Pagination
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = cfg.DefaultLimit
+	}
+	if limit > cfg.MaxLimit {
+		limit = cfg.MaxLimit
+	}
+	return page, limit
+}
+
+// pageOffset returns the offset of a normalized page. Pages reaching past the configured
+// result cap are rejected, since the database still has to walk every skipped row.
+func pageOffset(page, limit int) (int, error) {
+	offset := (page - 1) * limit
+	if err := checkResultCap(offset + limit); err != nil {
+		return 0, err
+	}
+	return offset, nil
+}
+
+// checkResultCap rejects results of more than the configured maximum number of rows.
+// Streaming endpoints such as the content backup are not subject to the cap.
+func checkResultCap(rows int) error {
+	maxResults := config.GetConfig().Pagination.MaxResults
+	if maxResults > 0 && rows > maxResults {
+		return ErrResultTooLarge
+	}
+	return nil
+}
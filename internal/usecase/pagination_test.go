@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+	"moon/internal/domain/user"
+)
+
+// withResultCap sets pagination.max_results for the duration of the test
+func withResultCap(t *testing.T, maxResults int) {
+	t.Helper()
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.GetConfig()
+	saved := cfg.Pagination.MaxResults
+	t.Cleanup(func() { cfg.Pagination.MaxResults = saved })
+	cfg.Pagination.MaxResults = maxResults
+}
+
+func TestPageOffset(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxResults int
+		page       int
+		limit      int
+		wantOffset int
+		wantErr    error
+	}{
+		{"first page", 100, 1, 10, 0, nil},
+		{"last page within the cap", 100, 10, 10, 90, nil},
+		{"page reaching past the cap", 100, 11, 10, 0, ErrResultTooLarge},
+		{"partly past the cap", 100, 4, 30, 0, ErrResultTooLarge},
+		{"cap disabled", 0, 1000, 100, 99900, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withResultCap(t, tt.maxResults)
+
+			offset, err := pageOffset(tt.page, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if offset != tt.wantOffset {
+				t.Errorf("expected offset %d, got %d", tt.wantOffset, offset)
+			}
+		})
+	}
+}
+
+// A listing paged past the cap is rejected before the database is queried
+func TestListingPastResultCap(t *testing.T) {
+	uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1}, nil)
+	withResultCap(t, 100)
+	status := "draft"
+
+	if _, err := uc.GetAllPosts(context.Background(), post.PostFilter{Status: &status}, authorID, user.RoleUser, 11, 10); !errors.Is(err, ErrResultTooLarge) {
+		t.Errorf("expected %v, got %v", ErrResultTooLarge, err)
+	}
+	if repo.listFilter.Status != nil {
+		t.Error("expected the repository not to be queried")
+	}
+	if _, err := uc.GetAllPosts(context.Background(), post.PostFilter{Status: &status}, authorID, user.RoleUser, 10, 10); err != nil {
+		t.Errorf("expected the last page within the cap to be served, got %v", err)
+	}
+}
+
+// Unpaginated listings are capped on their size
+func TestCategoryListingResultCap(t *testing.T) {
+	categories := &fakeCategoryRepo{categories: map[uint]*product.Category{
+		1: {ID: 1, Slug: "a"},
+		2: {ID: 2, Slug: "b"},
+		3: {ID: 3, Slug: "c"},
+	}}
+	uc := NewCategoryUseCase(categories)
+
+	tests := []struct {
+		name       string
+		maxResults int
+		wantErr    error
+	}{
+		{"within the cap", 3, nil},
+		{"over the cap", 2, ErrResultTooLarge},
+		{"cap disabled", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withResultCap(t, tt.maxResults)
+			if _, err := uc.GetCategoriesWithPostCounts(context.Background(), false); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"moon/internal/domain/post"
+)
+
+func TestGetArchivePosts(t *testing.T) {
+	at := func(month time.Month, day int) *time.Time {
+		published := time.Date(2024, month, day, 12, 0, 0, 0, time.UTC)
+		return &published
+	}
+	uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, Status: "published", IsPublic: true, PublishedAt: at(3, 1)}, nil)
+	repo.posts = append(repo.posts,
+		&post.Post{ID: 2, Status: "published", IsPublic: true, PublishedAt: at(3, 20)},
+		&post.Post{ID: 3, Status: "published", IsPublic: true, PublishedAt: at(3, 10)},
+		&post.Post{ID: 4, Status: "published", IsPublic: false, PublishedAt: at(3, 15)},
+		&post.Post{ID: 5, Status: "archived", IsPublic: true, PublishedAt: at(3, 16)},
+		&post.Post{ID: 6, Status: "published", IsPublic: true, PublishedAt: at(4, 1)},
+	)
+
+	tests := []struct {
+		name      string
+		year      int
+		month     int
+		page      int
+		limit     int
+		wantIDs   []uint
+		wantTotal int64
+		wantErr   error
+	}{
+		{"month newest first", 2024, 3, 1, 10, []uint{2, 3, 1}, 3, nil},
+		{"second page", 2024, 3, 2, 2, []uint{1}, 3, nil},
+		{"other month", 2024, 4, 1, 10, []uint{6}, 1, nil},
+		{"empty month", 2023, 3, 1, 10, nil, 0, nil},
+		{"month out of range", 2024, 13, 1, 10, nil, 0, post.ErrInvalidArchiveMonth},
+		{"month zero", 2024, 0, 1, 10, nil, 0, post.ErrInvalidArchiveMonth},
+		{"year out of range", 10000, 3, 1, 10, nil, 0, post.ErrInvalidArchiveMonth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := uc.GetArchivePosts(context.Background(), tt.year, tt.month, tt.page, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			var ids []uint
+			for _, p := range resp.Posts {
+				ids = append(ids, p.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) || resp.Total != tt.wantTotal {
+				t.Errorf("expected posts %v of %d, got %v of %d", tt.wantIDs, tt.wantTotal, ids, resp.Total)
+			}
+		})
+	}
+}
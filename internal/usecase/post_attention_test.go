@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+)
+
+func TestGetPostsNeedingAttention(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		rule     string
+		days     int
+		wantDays int
+		wantErr  error
+	}{
+		{"stale drafts default", post.AttentionStaleDrafts, 0, 30, nil},
+		{"stale drafts", post.AttentionStaleDrafts, 10, 10, nil},
+		{"no views default", post.AttentionNoViews, -1, 7, nil},
+		{"incomplete", post.AttentionIncomplete, 0, 0, nil},
+		{"flagged", post.AttentionFlagged, 0, 0, nil},
+		{"unknown rule", "popular", 0, 0, post.ErrInvalidAttentionRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePostRepo{posts: []*post.Post{{ID: 1, Status: "draft"}}}
+			uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, &config.Config{})
+
+			resp, err := uc.GetPostsNeedingAttention(context.Background(), tt.rule, tt.days, 1, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if resp.Total != 1 || len(resp.Posts) != 1 {
+				t.Errorf("expected the matching post, got %+v", resp)
+			}
+			if repo.attentionFilter.Rule != tt.rule {
+				t.Errorf("expected rule %q, got %q", tt.rule, repo.attentionFilter.Rule)
+			}
+			if tt.wantDays > 0 {
+				want := time.Now().AddDate(0, 0, -tt.wantDays)
+				if diff := repo.attentionFilter.Before.Sub(want); diff < -time.Minute || diff > time.Minute {
+					t.Errorf("expected a cutoff %d days ago, got %v", tt.wantDays, repo.attentionFilter.Before)
+				}
+			}
+		})
+	}
+}
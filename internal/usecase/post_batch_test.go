@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestGetPostsBySlugs(t *testing.T) {
+	repo := &fakePostRepo{posts: []*post.Post{
+		{ID: 1, Slug: "alpha", Status: "published", IsPublic: true, AuthorID: authorID},
+		{ID: 2, Slug: "beta", Status: "published", IsPublic: true, AuthorID: authorID},
+		{ID: 3, Slug: "gamma", Status: "published", IsPublic: true, AuthorID: authorID},
+		{ID: 4, Slug: "draft", Status: "draft", AuthorID: authorID},
+	}}
+	cfg := &config.Config{}
+	cfg.Post.PrivateVisibility = "authenticated"
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, cfg)
+
+	tests := []struct {
+		name       string
+		slugs      []string
+		viewerID   uint
+		viewerRole string
+		want       []string
+	}{
+		{"requested order", []string{"gamma", "alpha", "beta"}, 0, "", []string{"gamma", "alpha", "beta"}},
+		{"missing slugs omitted", []string{"missing", "beta", "also-missing", "alpha"}, 0, "", []string{"beta", "alpha"}},
+		{"repeated slugs once", []string{"beta", "alpha", "beta"}, 0, "", []string{"beta", "alpha"}},
+		{"nothing found", []string{"missing"}, 0, "", []string{}},
+		{"hidden posts omitted", []string{"draft", "alpha"}, otherUserID, user.RoleUser, []string{"alpha"}},
+		{"own drafts included", []string{"draft", "alpha"}, authorID, user.RoleUser, []string{"draft", "alpha"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := uc.GetPostsBySlugs(context.Background(), tt.slugs, tt.viewerID, tt.viewerRole)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := make([]string, len(resp.Posts))
+			for i, p := range resp.Posts {
+				got[i] = p.Slug
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostResponsesLoadAuthorsInOneQuery(t *testing.T) {
+	repo := &fakePostRepo{}
+	for i, author := range []uint{authorID, collaboratorID, authorID, 99} {
+		repo.posts = append(repo.posts, &post.Post{ID: uint(i + 1), Slug: fmt.Sprintf("post-%d", i+1), Status: "published", IsPublic: true, AuthorID: author})
+	}
+	users := &fakeUserRepo{users: map[uint]*user.User{
+		authorID:       {ID: authorID, Name: "Author"},
+		collaboratorID: {ID: collaboratorID, Name: "Collaborator"},
+	}}
+	uc := NewPostUseCase(repo, users, nil, nil, &config.Config{})
+
+	resp, err := uc.GetPostsBySlugs(context.Background(), []string{"post-1", "post-2", "post-3", "post-4"}, 0, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users.batchQueries != 1 {
+		t.Errorf("expected the authors to be loaded in one query, got %d", users.batchQueries)
+	}
+
+	want := map[uint]string{1: "Author", 2: "Collaborator", 3: "Author", 4: "Unknown"}
+	if len(resp.Posts) != len(want) {
+		t.Fatalf("expected %d posts, got %d", len(want), len(resp.Posts))
+	}
+	for _, p := range resp.Posts {
+		if p.AuthorName != want[p.ID] {
+			t.Errorf("post %d: expected author %q, got %q", p.ID, want[p.ID], p.AuthorName)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func newBulkVisibilityTestUseCase(t *testing.T) (PostUseCase, *fakePostRepo) {
+	t.Helper()
+	uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, AuthorID: authorID}, nil)
+	repo.posts = append(repo.posts,
+		&post.Post{ID: 2, AuthorID: authorID, IsPublic: true},
+		&post.Post{ID: 3, AuthorID: otherUserID},
+	)
+	return uc, repo
+}
+
+func TestBulkUpdateVisibilityPartialFailure(t *testing.T) {
+	uc, repo := newBulkVisibilityTestUseCase(t)
+
+	// Post 2 is already public, 3 belongs to someone else and 99 doesn't exist
+	resp, err := uc.BulkUpdateVisibility(context.Background(), []uint{1, 2, 3, 99, 1}, true, authorID, user.RoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []post.BulkVisibilityResult{
+		{PostID: 1, Changed: true},
+		{PostID: 2},
+		{PostID: 3, Error: post.ErrPermissionDenied.Error()},
+		{PostID: 99, Error: post.ErrNotFound.Error()},
+	}
+	if !slices.Equal(resp.Results, want) {
+		t.Errorf("expected one result per distinct ID %+v, got %+v", want, resp.Results)
+	}
+	if resp.Changed != 1 || resp.Failed != 2 {
+		t.Errorf("expected 1 changed and 2 failed, got %d and %d", resp.Changed, resp.Failed)
+	}
+
+	// Only the changed post is saved, which moves its updated_at and so the listings' Last-Modified
+	if !slices.Equal(repo.updated, []uint{1}) {
+		t.Errorf("expected only post 1 to be saved, got %v", repo.updated)
+	}
+	if !repo.posts[0].IsPublic || repo.posts[2].IsPublic {
+		t.Errorf("expected only the caller's post to become public, got %+v", repo.posts)
+	}
+}
+
+func TestBulkUpdateVisibilityAsAdmin(t *testing.T) {
+	uc, repo := newBulkVisibilityTestUseCase(t)
+
+	resp, err := uc.BulkUpdateVisibility(context.Background(), []uint{1, 2, 3}, false, 99, user.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Changed != 1 || resp.Failed != 0 || !slices.Equal(repo.updated, []uint{2}) {
+		t.Errorf("expected the admin to make post 2 private, got %+v and updates %v", resp, repo.updated)
+	}
+}
+
+func TestBulkUpdateVisibilitySaveError(t *testing.T) {
+	uc, repo := newBulkVisibilityTestUseCase(t)
+	repo.updateErr = errors.New("connection lost")
+
+	// A failed save aborts the batch instead of being reported per post
+	if _, err := uc.BulkUpdateVisibility(context.Background(), []uint{1, 3}, true, 99, user.RoleAdmin); !errors.Is(err, repo.updateErr) {
+		t.Errorf("expected the save error, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+	"moon/internal/domain/user"
+)
+
+func newCategoryPolicyUseCase(t *testing.T, requireCategory bool, posts ...*post.Post) PostUseCase {
+	t.Helper()
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.GetConfig()
+	cfg.Post.RequireCategory = requireCategory
+
+	repo := &fakePostRepo{posts: posts}
+	categories := &fakeCategoryRepo{categories: map[uint]*product.Category{
+		1: {ID: 1, Name: "News", IsActive: true},
+		2: {ID: 2, Name: "Retired", IsActive: false},
+	}}
+	return NewPostUseCase(repo, &fakeUserRepo{}, categories, &fakeTransactor{posts: repo}, &cfg)
+}
+
+func TestCreatePostCategoryPolicy(t *testing.T) {
+	active, inactive, unknown := uint(1), uint(2), uint(99)
+
+	tests := []struct {
+		name            string
+		requireCategory bool
+		categoryID      *uint
+		wantErr         error
+	}{
+		{"required and missing", true, nil, post.ErrCategoryRequired},
+		{"required and active", true, &active, nil},
+		{"optional and missing", false, nil, nil},
+		{"inactive", false, &inactive, post.ErrInvalidCategory},
+		{"unknown", true, &unknown, post.ErrInvalidCategory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newCategoryPolicyUseCase(t, tt.requireCategory)
+
+			_, err := uc.CreatePost(context.Background(), post.CreatePostRequest{Title: "A post", Content: "Some content", CategoryID: tt.categoryID}, authorID, user.RoleUser)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// Posts created before the policy was enabled cannot be published without a category
+func TestPublishUncategorizedPost(t *testing.T) {
+	ctx := context.Background()
+	p := &post.Post{ID: 1, Title: "A post", Content: "Some content", Slug: "a-post", Status: "draft", AuthorID: authorID}
+	uc := newCategoryPolicyUseCase(t, true, p)
+
+	if _, err := uc.PublishPost(ctx, p.ID, authorID, user.RoleUser); !errors.Is(err, post.ErrCategoryRequired) {
+		t.Fatalf("expected %v, got %v", post.ErrCategoryRequired, err)
+	}
+
+	category := uint(1)
+	if _, err := uc.UpdatePost(ctx, p.ID, post.UpdatePostRequest{CategoryID: &category}, authorID, user.RoleUser); err != nil {
+		t.Fatalf("categorize: %v", err)
+	}
+	if _, err := uc.PublishPost(ctx, p.ID, authorID, user.RoleUser); err != nil {
+		t.Errorf("expected the categorized post to publish, got %v", err)
+	}
+}
+
+func TestGetPostsByCategorySlug(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.GetConfig()
+	categories := &fakeCategoryRepo{categories: map[uint]*product.Category{
+		1: {ID: 1, Name: "Go Lang", Slug: "go-lang", IsActive: true},
+		2: {ID: 2, Name: "Retired", Slug: "retired", IsActive: false},
+	}}
+
+	tests := []struct {
+		name    string
+		slug    string
+		wantErr error
+	}{
+		{"active", "go-lang", nil},
+		{"inactive", "retired", product.ErrCategoryNotFound},
+		{"unknown", "rust", product.ErrCategoryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePostRepo{}
+			uc := NewPostUseCase(repo, &fakeUserRepo{}, categories, nil, &cfg)
+
+			_, err := uc.GetPostsByCategorySlug(context.Background(), tt.slug, 1, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			filter := repo.listFilter
+			if filter.CategoryID == nil || *filter.CategoryID != 1 {
+				t.Errorf("expected the posts of category 1, got %v", filter.CategoryID)
+			}
+			if filter.Status == nil || *filter.Status != "published" || filter.IsPublic == nil || !*filter.IsPublic {
+				t.Errorf("expected only public published posts, got %+v", filter)
+			}
+		})
+	}
+}
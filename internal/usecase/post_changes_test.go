@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+
+	"gorm.io/gorm"
+)
+
+func TestGetPostChanges(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	repo := &fakePostRepo{posts: []*post.Post{
+		{ID: 1, Slug: "before-since", UpdatedAt: at(-10)},
+		{ID: 2, Slug: "updated", UpdatedAt: at(20)},
+		{ID: 3, Slug: "same-time-lower-id", UpdatedAt: at(5)},
+		{ID: 4, Slug: "same-time-higher-id", UpdatedAt: at(5)},
+		{ID: 5, Slug: "deleted", UpdatedAt: at(-30), DeletedAt: gorm.DeletedAt{Time: at(10), Valid: true}},
+	}}
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, &config.Config{})
+	ctx := context.Background()
+
+	// readFeed pages through the whole feed two changes at a time
+	readFeed := func(includeDeleted bool) []post.PostChange {
+		t.Helper()
+		var changes []post.PostChange
+		filter := post.ChangesFilter{Since: base, IncludeDeleted: includeDeleted}
+		for page := 0; ; page++ {
+			resp, err := uc.GetPostChanges(ctx, filter, 2)
+			if err != nil {
+				t.Fatalf("page %d: %v", page, err)
+			}
+			changes = append(changes, resp.Changes...)
+			if !resp.HasMore {
+				return changes
+			}
+			if filter.After, err = post.DecodeChangesCursor(resp.NextCursor); err != nil {
+				t.Fatalf("page %d: invalid cursor %q: %v", page, resp.NextCursor, err)
+			}
+		}
+	}
+	slugs := func(changes []post.PostChange) []string {
+		var s []string
+		for _, change := range changes {
+			s = append(s, change.Slug)
+		}
+		return s
+	}
+
+	t.Run("since, in change order", func(t *testing.T) {
+		changes := readFeed(false)
+		want := []string{"same-time-lower-id", "same-time-higher-id", "updated"}
+		if got := slugs(changes); !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		for _, change := range changes {
+			if change.Deleted {
+				t.Errorf("unexpected tombstone for %q", change.Slug)
+			}
+		}
+	})
+
+	t.Run("with tombstones", func(t *testing.T) {
+		changes := readFeed(true)
+		want := []string{"same-time-lower-id", "same-time-higher-id", "deleted", "updated"}
+		if got := slugs(changes); !slices.Equal(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+
+		tombstone := changes[2]
+		if !tombstone.Deleted || tombstone.DeletedAt == nil || !tombstone.DeletedAt.Equal(at(10)) {
+			t.Errorf("expected a tombstone deleted at %v, got %+v", at(10), tombstone)
+		}
+	})
+
+	t.Run("cursor resumes after a tombstone", func(t *testing.T) {
+		resp, err := uc.GetPostChanges(ctx, post.ChangesFilter{Since: at(6), IncludeDeleted: true}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cursor, err := post.DecodeChangesCursor(resp.NextCursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cursor.ID != 5 || !cursor.ChangedAt.Equal(at(10)) {
+			t.Errorf("expected the cursor at the deletion time, got %+v", cursor)
+		}
+	})
+
+	t.Run("empty page", func(t *testing.T) {
+		resp, err := uc.GetPostChanges(ctx, post.ChangesFilter{Since: at(60)}, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Changes) != 0 || resp.HasMore || resp.NextCursor != "" {
+			t.Errorf("expected an empty final page without a cursor, got %+v", resp)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+
+	"gorm.io/gorm"
+)
+
+func TestCollaboratorEditAccess(t *testing.T) {
+	newContent := "Co-edited content"
+	published := "published"
+	public := true
+	publishAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		permission string // "" for a user who is not a collaborator
+		req        post.UpdatePostRequest
+		wantErr    error
+	}{
+		{"edit collaborator changes content", post.CollaboratorEdit, post.UpdatePostRequest{Content: &newContent}, nil},
+		{"edit collaborator publishes", post.CollaboratorEdit, post.UpdatePostRequest{Status: &published}, post.ErrPermissionDenied},
+		{"edit collaborator schedules", post.CollaboratorEdit, post.UpdatePostRequest{PublishAt: &publishAt}, post.ErrPermissionDenied},
+		{"edit collaborator changes visibility", post.CollaboratorEdit, post.UpdatePostRequest{IsPublic: &public}, post.ErrPermissionDenied},
+		{"view collaborator changes content", post.CollaboratorView, post.UpdatePostRequest{Content: &newContent}, post.ErrPermissionDenied},
+		{"stranger changes content", "", post.UpdatePostRequest{Content: &newContent}, post.ErrPermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &post.Post{ID: 1, Slug: "shared", Content: "Original", AuthorID: authorID, Status: "draft"}
+			uc, repo, _ := newStatusTestUseCase(t, p, nil)
+			if tt.permission != "" {
+				repo.SaveCollaborator(context.Background(), &post.Collaborator{PostID: 1, UserID: collaboratorID, Permission: tt.permission})
+			}
+
+			_, err := uc.UpdatePost(context.Background(), 1, tt.req, collaboratorID, user.RoleUser)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			stored := repo.posts[0]
+			wantContent := "Original"
+			if tt.wantErr == nil {
+				wantContent = newContent
+			}
+			if stored.Content != wantContent || stored.Status != "draft" || stored.IsPublic || stored.PublishAt != nil {
+				t.Errorf("expected content %q and nothing else changed, got %+v", wantContent, stored)
+			}
+		})
+	}
+}
+
+func TestCollaboratorsCannotDeleteOrPublish(t *testing.T) {
+	ctx := context.Background()
+	p := &post.Post{ID: 1, Slug: "shared", AuthorID: authorID, Status: "draft"}
+	uc, repo, _ := newStatusTestUseCase(t, p, nil)
+	repo.SaveCollaborator(ctx, &post.Collaborator{PostID: 1, UserID: collaboratorID, Permission: post.CollaboratorEdit})
+
+	if _, err := uc.PublishPost(ctx, 1, collaboratorID, user.RoleUser); !errors.Is(err, post.ErrPermissionDenied) {
+		t.Errorf("publish: expected %v, got %v", post.ErrPermissionDenied, err)
+	}
+	if err := uc.DeletePost(ctx, 1, collaboratorID, user.RoleUser); !errors.Is(err, post.ErrPermissionDenied) {
+		t.Errorf("delete: expected %v, got %v", post.ErrPermissionDenied, err)
+	}
+	if p.Status != "draft" || len(repo.posts) != 1 {
+		t.Errorf("expected the draft to be untouched, got %+v", repo.posts)
+	}
+}
+
+func TestManageCollaborators(t *testing.T) {
+	ctx := context.Background()
+	p := &post.Post{ID: 1, Slug: "shared", AuthorID: authorID}
+	uc, repo, users := newStatusTestUseCase(t, p, nil)
+	users.users[collaboratorID] = &user.User{ID: collaboratorID, Name: "Collaborator"}
+	users.users[otherUserID] = &user.User{ID: otherUserID, Name: "Other"}
+
+	added, err := uc.AddCollaborator(ctx, 1, post.AddCollaboratorRequest{UserID: collaboratorID, Permission: post.CollaboratorEdit}, authorID, user.RoleUser)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if added.UserID != collaboratorID || added.Name != "Collaborator" || added.Permission != post.CollaboratorEdit {
+		t.Errorf("unexpected collaborator %+v", added)
+	}
+
+	tests := []struct {
+		name    string
+		req     post.AddCollaboratorRequest
+		userID  uint
+		role    string
+		wantErr error
+	}{
+		{"collaborator shares further", post.AddCollaboratorRequest{UserID: otherUserID, Permission: post.CollaboratorView}, collaboratorID, user.RoleUser, post.ErrPermissionDenied},
+		{"stranger shares", post.AddCollaboratorRequest{UserID: otherUserID, Permission: post.CollaboratorView}, otherUserID, user.RoleUser, post.ErrPermissionDenied},
+		{"author as collaborator", post.AddCollaboratorRequest{UserID: authorID, Permission: post.CollaboratorView}, authorID, user.RoleUser, post.ErrAuthorIsCollaborator},
+		{"unknown user", post.AddCollaboratorRequest{UserID: 99, Permission: post.CollaboratorView}, authorID, user.RoleUser, user.ErrNotFound},
+		{"admin shares", post.AddCollaboratorRequest{UserID: otherUserID, Permission: post.CollaboratorView}, 99, user.RoleAdmin, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.AddCollaborator(ctx, 1, tt.req, tt.userID, tt.role); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Collaborators may leave, but not remove anyone else
+	if err := uc.RemoveCollaborator(ctx, 1, otherUserID, collaboratorID, user.RoleUser); !errors.Is(err, post.ErrPermissionDenied) {
+		t.Errorf("expected removing another collaborator to be denied, got %v", err)
+	}
+	if err := uc.RemoveCollaborator(ctx, 1, collaboratorID, collaboratorID, user.RoleUser); err != nil {
+		t.Fatalf("leave: %v", err)
+	}
+	if collaborator, _ := repo.GetCollaborator(ctx, 1, collaboratorID); collaborator != nil {
+		t.Errorf("expected the collaborator to be removed, got %+v", collaborator)
+	}
+	if collaborator, _ := repo.GetCollaborator(ctx, 1, otherUserID); collaborator == nil {
+		t.Error("expected the other collaborator to remain")
+	}
+}
+
+func TestGetSharedWithMe(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, Slug: "edit", AuthorID: authorID}, nil)
+	repo.posts = append(repo.posts,
+		&post.Post{ID: 2, Slug: "view", AuthorID: authorID},
+		&post.Post{ID: 3, Slug: "private", AuthorID: authorID},
+		&post.Post{ID: 4, Slug: "trashed", AuthorID: authorID, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+		&post.Post{ID: 5, Slug: "someone-else", AuthorID: otherUserID},
+	)
+	repo.SaveCollaborator(ctx, &post.Collaborator{PostID: 1, UserID: collaboratorID, Permission: post.CollaboratorEdit})
+	repo.SaveCollaborator(ctx, &post.Collaborator{PostID: 2, UserID: collaboratorID, Permission: post.CollaboratorView})
+	repo.SaveCollaborator(ctx, &post.Collaborator{PostID: 4, UserID: collaboratorID, Permission: post.CollaboratorEdit})
+	repo.SaveCollaborator(ctx, &post.Collaborator{PostID: 5, UserID: authorID, Permission: post.CollaboratorEdit})
+
+	resp, err := uc.GetSharedWithMe(ctx, collaboratorID, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 2 || len(resp.Posts) != 2 || resp.Posts[0].ID != 1 || resp.Posts[1].ID != 2 {
+		t.Errorf("expected the two live shared posts, got %+v", resp)
+	}
+
+	// Sharing works one way: the author's own posts aren't shared with them
+	resp, err = uc.GetSharedWithMe(ctx, authorID, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 1 || len(resp.Posts) != 1 || resp.Posts[0].ID != 5 {
+		t.Errorf("expected only the post shared with the author, got %+v", resp)
+	}
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestAllowComments(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name   string
+		create *bool
+		update *bool
+		want   bool
+	}{
+		{"default", nil, nil, true},
+		{"disabled on create", &disabled, nil, false},
+		{"disabled on update", nil, &disabled, false},
+		{"enabled again", &disabled, &enabled, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 100, Slug: "existing"}, nil)
+			ctx := context.Background()
+
+			req := post.CreatePostRequest{Title: "A new post", Content: "Some content for the post", AllowComments: tt.create}
+			created, err := uc.CreatePost(ctx, req, authorID, user.RoleUser)
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			title := "An edited post"
+			updated, err := uc.UpdatePost(ctx, created.ID, post.UpdatePostRequest{Title: &title, AllowComments: tt.update}, authorID, user.RoleUser)
+			if err != nil {
+				t.Fatalf("update: %v", err)
+			}
+
+			if updated.AllowComments != tt.want || repo.posts[len(repo.posts)-1].AllowComments != tt.want {
+				t.Errorf("expected allow_comments %v, got %v", tt.want, updated.AllowComments)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestMaxContentSize(t *testing.T) {
+	const maxBytes = 40
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{"at the limit", strings.Repeat("a", maxBytes), nil},
+		{"just over", strings.Repeat("a", maxBytes+1), post.ErrContentTooLarge},
+		// 20 two-byte runes fill the limit exactly; one more byte goes over
+		{"multi-byte at the limit", strings.Repeat("é", maxBytes/2), nil},
+		{"multi-byte just over", strings.Repeat("é", maxBytes/2) + "a", post.ErrContentTooLarge},
+		{"invalid UTF-8", "abc\xff", post.ErrContentNotUTF8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configure := func(cfg *config.Config) {
+				cfg.Post.MaxContentBytes = maxBytes
+			}
+			ctx := context.Background()
+
+			uc, _, _ := newStatusTestUseCase(t, &post.Post{ID: 1, Slug: "existing", AuthorID: authorID, Status: "draft"}, configure)
+			_, err := uc.CreatePost(ctx, post.CreatePostRequest{Title: "A new post", Content: tt.content}, authorID, user.RoleUser)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("create: expected %v, got %v", tt.wantErr, err)
+			}
+
+			uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, Slug: "existing", AuthorID: authorID, Status: "draft", Content: "Original"}, configure)
+			_, err = uc.UpdatePost(ctx, 1, post.UpdatePostRequest{Content: &tt.content}, authorID, user.RoleUser)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("update: expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && repo.posts[0].Content != "Original" {
+				t.Errorf("expected rejected content not to be stored, got %q", repo.posts[0].Content)
+			}
+		})
+	}
+}
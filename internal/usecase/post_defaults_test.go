@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestPostDefaultsPerRole(t *testing.T) {
+	published := "published"
+	private := false
+	draft := "draft"
+
+	tests := []struct {
+		name         string
+		role         string
+		req          post.CreatePostRequest
+		wantStatus   string
+		wantIsPublic bool
+	}{
+		{"global defaults", user.RoleUser, post.CreatePostRequest{}, "draft", true},
+		{"role override", user.RoleEditor, post.CreatePostRequest{}, "published", false},
+		{"partial role override", user.RoleAdmin, post.CreatePostRequest{}, "published", true},
+		{"request wins", user.RoleEditor, post.CreatePostRequest{Status: &draft}, "draft", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 100, Slug: "existing"}, func(cfg *config.Config) {
+				cfg.Post.Defaults = config.PostDefaultsConfig{
+					Status:   "draft",
+					IsPublic: true,
+					Roles: map[string]config.RolePostDefaults{
+						user.RoleEditor: {Status: &published, IsPublic: &private},
+						user.RoleAdmin:  {Status: &published},
+					},
+				}
+			})
+
+			req := tt.req
+			req.Title, req.Content = "A new post", "Some content for the post"
+			created, err := uc.CreatePost(context.Background(), req, authorID, tt.role)
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			stored := repo.posts[len(repo.posts)-1]
+			if created.Status != tt.wantStatus || stored.IsPublic != tt.wantIsPublic {
+				t.Errorf("expected %s and public %v, got %s and public %v", tt.wantStatus, tt.wantIsPublic, created.Status, stored.IsPublic)
+			}
+			if (stored.PublishedAt != nil) != (tt.wantStatus == "published") {
+				t.Errorf("expected a publish date only for published posts, got %v", stored.PublishedAt)
+			}
+		})
+	}
+}
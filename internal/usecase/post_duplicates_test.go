@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestFindDuplicateTitles(t *testing.T) {
+	repo := &fakePostRepo{
+		posts: []*post.Post{
+			{ID: 1, Title: "Getting Started with Go", Status: "published", IsPublic: true},
+			{ID: 2, Title: "Getting started with Go!", Status: "published", IsPublic: true},
+			{ID: 3, Title: "Getting Started with Rust", Status: "published", IsPublic: true},
+			{ID: 4, Title: "Gardening for Beginners", Status: "published", IsPublic: true},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		title    string
+		wantIDs  []uint // closest first
+		wantDist []int
+	}{
+		{"case and punctuation ignored", true, "getting started with go", []uint{1, 2}, []int{0, 0}},
+		{"small edits still match", true, "Geting Started with Goo", []uint{1, 2}, []int{2, 2}},
+		{"closest first", true, "Getting Started with Rus", []uint{3, 1, 2}, []int{1, 3, 3}},
+		{"too different", true, "Cooking for Beginners", nil, nil},
+		{"blank title", true, "!!!", nil, nil},
+		{"disabled", false, "Getting Started with Go", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Post.DuplicateTitles = config.DuplicateTitlesConfig{Enabled: tt.enabled, MaxDistance: 3, Candidates: 200}
+			uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, cfg)
+
+			duplicates, err := uc.FindDuplicateTitles(context.Background(), tt.title, authorID, user.RoleUser)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ids []uint
+			var distances []int
+			for _, d := range duplicates {
+				ids = append(ids, d.ID)
+				distances = append(distances, d.Distance)
+			}
+			if !slices.Equal(ids, tt.wantIDs) || !slices.Equal(distances, tt.wantDist) {
+				t.Errorf("got IDs %v at distances %v, want %v at %v", ids, distances, tt.wantIDs, tt.wantDist)
+			}
+		})
+	}
+}
+
+// Other users' drafts and hidden posts are never offered as duplicates
+func TestFindDuplicateTitlesOnlyReadablePosts(t *testing.T) {
+	repo := &fakePostRepo{
+		posts: []*post.Post{
+			{ID: 1, Title: "Weekly notes", Status: "published", IsPublic: true, AuthorID: otherUserID},
+			{ID: 2, Title: "Weekly notes", Status: "draft", AuthorID: otherUserID},
+			{ID: 3, Title: "Weekly notes", Status: "published", IsPublic: false, AuthorID: otherUserID},
+			{ID: 4, Title: "Weekly notes", Status: "draft", AuthorID: authorID},
+			{ID: 5, Title: "Weekly notes", Status: "draft", AuthorID: otherUserID},
+		},
+		collaborators: map[uint][]uint{5: {authorID}},
+	}
+
+	tests := []struct {
+		name              string
+		privateVisibility string
+		role              string
+		wantIDs           []uint
+	}{
+		{"user", "authenticated", user.RoleUser, []uint{1, 3, 4, 5}},
+		{"user, owner mode", "owner", user.RoleUser, []uint{1, 4, 5}},
+		{"admin", "owner", user.RoleAdmin, []uint{1, 2, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Post.PrivateVisibility = tt.privateVisibility
+			cfg.Post.DuplicateTitles = config.DuplicateTitlesConfig{Enabled: true, MaxDistance: 3, Candidates: 200}
+			uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, cfg)
+
+			duplicates, err := uc.FindDuplicateTitles(context.Background(), "Weekly notes", authorID, tt.role)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ids []uint
+			for _, d := range duplicates {
+				ids = append(ids, d.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("expected candidates %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
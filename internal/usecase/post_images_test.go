@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+	"moon/pkg/imagepolicy"
+)
+
+const externalImageContent = `<p>Hello</p><img src="https://other.com/a.png">`
+
+// newImagePolicyUseCase creates a published public post by the author with an external image
+func newImagePolicyUseCase(t *testing.T, action string) (PostUseCase, *fakePostRepo, *post.PostResponse) {
+	t.Helper()
+	uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 100, Slug: "existing"}, func(cfg *config.Config) {
+		cfg.Post.ExternalImages = config.ExternalImagesConfig{Action: action, ProxyURL: "https://img.example.com/proxy"}
+		cfg.Post.PublishRequirements = config.PublishRequirementsConfig{}
+	})
+
+	published, isPublic, format := "published", true, "html"
+	created, err := uc.CreatePost(context.Background(), post.CreatePostRequest{
+		Title:         "A post with an image",
+		Content:       externalImageContent,
+		ContentFormat: &format,
+		Status:        &published,
+		IsPublic:      &isPublic,
+	}, authorID, user.RoleUser)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	return uc, repo, created
+}
+
+func TestExternalImageRendering(t *testing.T) {
+	tests := []struct {
+		action   string
+		wantHTML string
+	}{
+		{imagepolicy.ActionAllow, ""},
+		{imagepolicy.ActionFlag, `<p>Hello</p><img src="https://other.com/a.png" data-external="true" referrerpolicy="no-referrer">`},
+		{imagepolicy.ActionStrip, `<p>Hello</p>`},
+		{imagepolicy.ActionProxy, `<p>Hello</p><img src="https://img.example.com/proxy?url=https%3A%2F%2Fother.com%2Fa.png">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			_, repo, created := newImagePolicyUseCase(t, tt.action)
+
+			stored := repo.posts[len(repo.posts)-1]
+			if stored.Content != externalImageContent {
+				t.Errorf("expected the raw content to be stored unchanged, got %q", stored.Content)
+			}
+			if created.ContentHTML != tt.wantHTML {
+				t.Errorf("expected content_html %q, got %q", tt.wantHTML, created.ContentHTML)
+			}
+		})
+	}
+}
+
+// The raw content still holds the original image URLs, so only editors get it
+func TestRawContentWithheldFromReaders(t *testing.T) {
+	const editorID, viewerID = 4, 5
+
+	tests := []struct {
+		name    string
+		action  string
+		viewer  uint
+		role    string
+		wantRaw bool
+	}{
+		{"anonymous", imagepolicy.ActionStrip, 0, "", false},
+		{"other user", imagepolicy.ActionStrip, otherUserID, user.RoleUser, false},
+		{"view collaborator", imagepolicy.ActionStrip, viewerID, user.RoleUser, false},
+		{"edit collaborator", imagepolicy.ActionStrip, editorID, user.RoleUser, true},
+		{"author", imagepolicy.ActionStrip, authorID, user.RoleUser, true},
+		{"editor", imagepolicy.ActionStrip, otherUserID, user.RoleEditor, false},
+		{"admin", imagepolicy.ActionProxy, otherUserID, user.RoleAdmin, true},
+		{"flagged for anonymous", imagepolicy.ActionFlag, 0, "", false},
+		{"policy off", imagepolicy.ActionAllow, 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, created := newImagePolicyUseCase(t, tt.action)
+			repo.collaborators = map[uint][]uint{created.ID: {viewerID}}
+			repo.editors = map[uint][]uint{created.ID: {editorID}}
+			ctx := context.Background()
+
+			wantContent := ""
+			if tt.wantRaw {
+				wantContent = externalImageContent
+			}
+
+			read, err := uc.GetPostBySlug(ctx, created.Slug, false, tt.viewer, tt.role)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if read.Content != wantContent {
+				t.Errorf("expected content %q, got %q", wantContent, read.Content)
+			}
+
+			bySlug, err := uc.GetPostsBySlugs(ctx, []string{created.Slug}, tt.viewer, tt.role)
+			if err != nil {
+				t.Fatalf("batch read: %v", err)
+			}
+			if len(bySlug.Posts) != 1 || bySlug.Posts[0].Content != wantContent {
+				t.Errorf("expected content %q in the batch read, got %+v", wantContent, bySlug.Posts)
+			}
+
+			listed, err := uc.GetAllPosts(ctx, post.PostFilter{}, tt.viewer, tt.role, 1, 10)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			i := slices.IndexFunc(listed.Posts, func(p post.PostResponse) bool { return p.ID == created.ID })
+			if i < 0 || listed.Posts[i].Content != wantContent {
+				t.Errorf("expected content %q in the listing, got %+v", wantContent, listed.Posts)
+			}
+		})
+	}
+}
+
+// Listings are public, so they never carry the raw content of a rewritten post
+func TestPublishedListingWithholdsRawContent(t *testing.T) {
+	uc, _, _ := newImagePolicyUseCase(t, imagepolicy.ActionStrip)
+
+	listing, err := uc.GetPublishedPosts(context.Background(), "", "", 1, 10)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(listing.Posts) != 1 {
+		t.Fatalf("expected the published post, got %+v", listing.Posts)
+	}
+	if got := listing.Posts[0]; got.Content != "" || got.ContentHTML != "<p>Hello</p>" {
+		t.Errorf("expected only the rewritten HTML, got content %q and content_html %q", got.Content, got.ContentHTML)
+	}
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestImportMarkdown(t *testing.T) {
+	uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, Slug: "taken", AuthorID: otherUserID}, nil)
+
+	files := []post.ImportFile{
+		{Name: "first.md", Data: []byte("---\ntitle: First Post\ntags: [go, web]\n---\nBody one")},
+		{Name: "kept-slug.md", Data: []byte("---\ntitle: Second\nslug: old-url\ndate: 2020-01-02T03:04:05Z\nstatus: published\ntags: [web, news]\n---\nBody two")},
+		{Name: "taken.md", Data: []byte("---\ntitle: Third\nslug: taken\n---\nBody")},
+		{Name: "invalid-slug.md", Data: []byte("---\ntitle: Fourth\nslug: Not A Slug\n---\nBody")},
+		{Name: "no-title.md", Data: []byte("---\nslug: untitled\n---\nBody")},
+		{Name: "bad-status.md", Data: []byte("---\ntitle: Fifth\nstatus: live\n---\nBody")},
+		{Name: "bad-tag.md", Data: []byte("---\ntitle: Sixth\ntags: [\"c++\"]\n---\nBody")},
+		{Name: "empty.md", Data: []byte("---\ntitle: Seventh\n---\n")},
+		{Name: "plain.md", Data: []byte("# No front matter")},
+	}
+
+	resp, err := uc.ImportMarkdown(context.Background(), files, authorID, user.RoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Imported != 2 || resp.Failed != len(files)-2 || len(resp.Results) != len(files) {
+		t.Fatalf("expected 2 imported and the rest failed, got %+v", resp)
+	}
+
+	// Results carry only the error message
+	wantErrs := map[string]string{
+		"taken.md":        post.ErrSlugInUse.Error(),
+		"invalid-slug.md": post.ErrInvalidSlug.Error(),
+		"no-title.md":     post.ErrTitleRequired.Error(),
+		"bad-status.md":   post.ErrInvalidStatus.Error(),
+		"bad-tag.md":      post.ErrInvalidTag.Error(),
+		"empty.md":        post.ErrContentRequired.Error(),
+		"plain.md":        "invalid front matter",
+	}
+	for i, result := range resp.Results {
+		if result.File != files[i].Name {
+			t.Errorf("expected results in upload order, got %q at %d", result.File, i)
+		}
+		if wantErr, ok := wantErrs[result.File]; ok && (result.PostID != 0 || !strings.HasPrefix(result.Error, wantErr)) {
+			t.Errorf("%s: expected %q, got %+v", result.File, wantErr, result)
+		}
+	}
+
+	first, second := repo.posts[1], repo.posts[2]
+	if first.Slug != "first-post" || first.AuthorID != authorID || first.ContentFormat != "markdown" || first.Status != "draft" {
+		t.Errorf("expected a markdown draft by the importer with a generated slug, got %+v", first)
+	}
+	wantDate := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if second.Slug != "old-url" || second.Status != "published" || second.PublishedAt == nil || !second.PublishedAt.Equal(wantDate) {
+		t.Errorf("expected the published post to keep its slug and date, got %+v", second)
+	}
+
+	// Tags are created once and reused by later files
+	if !slices.Equal(repo.tagNames, []string{"go", "web", "news"}) {
+		t.Errorf("expected each tag created once, got %v", repo.tagNames)
+	}
+	if !slices.Equal(repo.tags[first.ID], []string{"go", "web"}) || !slices.Equal(repo.tags[second.ID], []string{"web", "news"}) {
+		t.Errorf("expected the front matter tags on the posts, got %v", repo.tags)
+	}
+}
+
+func TestImportMarkdownFileCount(t *testing.T) {
+	uc, _, _ := newStatusTestUseCase(t, &post.Post{ID: 1}, func(cfg *config.Config) {
+		cfg.Post.Import.MaxFiles = 2
+	})
+	file := post.ImportFile{Name: "a.md", Data: []byte("---\ntitle: A\n---\nBody")}
+
+	tests := []struct {
+		name    string
+		files   []post.ImportFile
+		wantErr error
+	}{
+		{"none", nil, post.ErrNoMarkdownFiles},
+		{"at the cap", []post.ImportFile{file, file}, nil},
+		{"over the cap", []post.ImportFile{file, file, file}, post.ErrTooManyFiles},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.ImportMarkdown(context.Background(), tt.files, authorID, user.RoleUser); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
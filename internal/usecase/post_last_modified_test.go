@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+)
+
+func TestGetPublishedLastModified(t *testing.T) {
+	modified := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		defaultSort string
+		sort        string
+		wantTime    bool
+		wantErr     error
+	}{
+		{"published order", post.SortPublishedAt, post.SortPublishedAt, true, nil},
+		{"default published order", post.SortPublishedAt, "", true, nil},
+		{"view order", post.SortPublishedAt, post.SortViewCount, false, nil},
+		{"default view order", post.SortViewCount, "", false, nil},
+		{"unknown order", post.SortPublishedAt, "title", false, post.ErrInvalidSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1}, func(cfg *config.Config) {
+				cfg.Post.DefaultSort = tt.defaultSort
+			})
+			repo.lastModified = &modified
+
+			got, err := uc.GetPublishedLastModified(context.Background(), tt.sort)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantTime && (got == nil || !got.Equal(modified)) {
+				t.Errorf("expected %v, got %v", modified, got)
+			}
+			if !tt.wantTime && got != nil {
+				t.Errorf("expected no last modified time, got %v", got)
+			}
+		})
+	}
+}
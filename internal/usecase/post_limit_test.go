@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/transaction"
+	"moon/internal/domain/user"
+)
+
+func TestCheckPostLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		posts   int
+		role    string
+		wantErr error
+	}{
+		{"below the cap", 3, 2, user.RoleUser, nil},
+		{"at the cap", 3, 3, user.RoleUser, post.ErrPostLimitReached},
+		{"past the cap", 3, 4, user.RoleUser, post.ErrPostLimitReached},
+		{"no posts yet", 1, 0, user.RoleUser, nil},
+		{"admin exempt", 3, 5, user.RoleAdmin, nil},
+		{"editor exempt", 3, 5, user.RoleEditor, nil},
+		{"no cap", 0, 100, user.RoleUser, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Post.MaxPostsPerAuthor = tt.limit
+
+			repo := &fakePostRepo{}
+			for i := 0; i < tt.posts; i++ {
+				repo.posts = append(repo.posts, &post.Post{ID: uint(i + 1), AuthorID: authorID})
+			}
+			// Posts by someone else never count towards the cap
+			repo.posts = append(repo.posts, &post.Post{ID: 1000, AuthorID: otherUserID})
+
+			users := &fakeUserRepo{users: map[uint]*user.User{authorID: {ID: authorID}}}
+			repos := transaction.Repositories{Posts: repo, Users: users}
+
+			uc := &postUseCase{postRepo: repo, cfg: cfg}
+			if err := uc.checkPostLimit(context.Background(), repos, authorID, tt.role); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// The limit is checked on the transaction's repositories with the author locked, so
+// concurrent creates can't all pass on the same count
+func TestCreatePostChecksLimitInTransaction(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.GetConfig()
+	cfg.Post.MaxPostsPerAuthor = 1
+
+	repo := &fakePostRepo{posts: []*post.Post{{ID: 1, AuthorID: authorID}}}
+	txUsers := &fakeUserRepo{users: map[uint]*user.User{authorID: {ID: authorID}}}
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, &fakeTransactor{posts: repo, users: txUsers}, &cfg)
+
+	_, err := uc.CreatePost(context.Background(), post.CreatePostRequest{Title: "Second", Content: "content"}, authorID, user.RoleUser)
+	if !errors.Is(err, post.ErrPostLimitReached) {
+		t.Fatalf("expected %v, got %v", post.ErrPostLimitReached, err)
+	}
+	if len(txUsers.locked) != 1 || txUsers.locked[0] != authorID {
+		t.Errorf("expected the author locked in the transaction, got %v", txUsers.locked)
+	}
+	if len(repo.posts) != 1 {
+		t.Errorf("expected no post created, got %d posts", len(repo.posts))
+	}
+}
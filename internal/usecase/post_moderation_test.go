@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+	"moon/pkg/moderation"
+)
+
+func TestModeratePost(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      string
+		post        post.Post
+		wantErr     error
+		wantMasked  bool
+		wantFlagged bool
+		wantTitle   string
+		wantContent string
+	}{
+		{
+			name:        "reject",
+			action:      moderation.ActionReject,
+			post:        post.Post{Language: "en", Title: "Darn", Content: "clean"},
+			wantErr:     post.ErrBlockedWords,
+			wantTitle:   "Darn",
+			wantContent: "clean",
+		},
+		{
+			name:        "reject clean post",
+			action:      moderation.ActionReject,
+			post:        post.Post{Language: "en", Title: "Hello", Content: "Scunthorpe darned"},
+			wantTitle:   "Hello",
+			wantContent: "Scunthorpe darned",
+		},
+		{
+			name:        "mask",
+			action:      moderation.ActionMask,
+			post:        post.Post{Language: "en", Title: "Darn", Content: "oh heck, darned"},
+			wantMasked:  true,
+			wantTitle:   "****",
+			wantContent: "oh ****, darned",
+		},
+		{
+			name:        "flag",
+			action:      moderation.ActionFlag,
+			post:        post.Post{Language: "en", Title: "Hello", Content: "oh heck"},
+			wantFlagged: true,
+			wantTitle:   "Hello",
+			wantContent: "oh heck",
+		},
+		{
+			name:        "flag cleared once clean",
+			action:      moderation.ActionFlag,
+			post:        post.Post{Language: "en", Title: "Hello", Content: "fixed", Flagged: true},
+			wantTitle:   "Hello",
+			wantContent: "fixed",
+		},
+		{
+			name:        "language without a word list",
+			action:      moderation.ActionReject,
+			post:        post.Post{Language: "vi", Title: "darn", Content: "heck", Flagged: true},
+			wantTitle:   "darn",
+			wantContent: "heck",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Moderation.Enabled = true
+			cfg.Moderation.Action = tt.action
+			cfg.Moderation.Words = map[string][]string{"en": {"darn", "heck"}}
+			uc := NewPostUseCase(&fakePostRepo{}, &fakeUserRepo{}, nil, nil, cfg).(*postUseCase)
+
+			p := tt.post
+			masked, err := uc.moderate(&p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if masked != tt.wantMasked {
+				t.Errorf("expected masked %v, got %v", tt.wantMasked, masked)
+			}
+			if p.Flagged != tt.wantFlagged {
+				t.Errorf("expected flagged %v, got %v", tt.wantFlagged, p.Flagged)
+			}
+			if p.Title != tt.wantTitle || p.Content != tt.wantContent {
+				t.Errorf("got %q / %q, want %q / %q", p.Title, p.Content, tt.wantTitle, tt.wantContent)
+			}
+		})
+	}
+}
+
+// Masked words must not reach the URL, so slugs are generated from the moderated title
+func TestMaskedTitleSlug(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.GetConfig()
+	cfg.Moderation.Enabled = true
+	cfg.Moderation.Action = moderation.ActionMask
+	cfg.Moderation.Words = map[string][]string{"en": {"darn"}}
+	language := "en"
+	ctx := context.Background()
+
+	repo := &fakePostRepo{}
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, &fakeTransactor{posts: repo}, &cfg)
+
+	created, err := uc.CreatePost(ctx, post.CreatePostRequest{Title: "A darn good post", Content: "clean", Language: &language}, authorID, user.RoleUser)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.Title != "A **** good post" || created.Slug != "a-good-post" {
+		t.Errorf("expected the masked title and a slug without the word, got %q / %q", created.Title, created.Slug)
+	}
+
+	title := "Darn again"
+	updated, err := uc.UpdatePost(ctx, created.ID, post.UpdatePostRequest{Title: &title}, authorID, user.RoleUser)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Title != "**** again" || updated.Slug != "again" {
+		t.Errorf("expected the masked title and a slug without the word, got %q / %q", updated.Title, updated.Slug)
+	}
+}
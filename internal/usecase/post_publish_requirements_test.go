@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func requirePublishFields(cfg *config.Config) {
+	cfg.Post.PublishRequirements = config.PublishRequirementsConfig{MinContentLength: 20, Summary: true, FeaturedImage: true}
+}
+
+func TestPublishPostRequirements(t *testing.T) {
+	summary, image, blank := "A summary", "https://example.com/a.png", "  "
+	complete := strings.Repeat("x", 20)
+
+	tests := []struct {
+		name         string
+		content      string
+		summary      *string
+		featuredImg  *string
+		wantProblems []string // nil expects the post to be published
+	}{
+		{"complete", complete, &summary, &image, nil},
+		{"content too short", "too short", &summary, &image, []string{"content must be at least 20 characters"}},
+		{"whitespace does not count", "  " + strings.Repeat("x", 19) + "  ", &summary, &image, []string{"content must be at least 20 characters"}},
+		{"no summary", complete, nil, &image, []string{"summary is required"}},
+		{"blank summary", complete, &blank, &image, []string{"summary is required"}},
+		{"no featured image", complete, &summary, nil, []string{"featured image is required"}},
+		{"everything missing", "", nil, nil, []string{"content must be at least 20 characters", "summary is required", "featured image is required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &post.Post{ID: 1, Slug: "draft", AuthorID: authorID, Status: "draft", Content: tt.content, Summary: tt.summary, FeaturedImg: tt.featuredImg}
+			uc, repo, _ := newStatusTestUseCase(t, p, requirePublishFields)
+
+			_, err := uc.PublishPost(context.Background(), 1, authorID, user.RoleUser)
+			if tt.wantProblems == nil {
+				if err != nil {
+					t.Fatalf("expected the complete post to publish, got %v", err)
+				}
+				if p.Status != "published" {
+					t.Errorf("expected status published, got %q", p.Status)
+				}
+				return
+			}
+
+			if !errors.Is(err, post.ErrNotPublishable) {
+				t.Fatalf("expected %v, got %v", post.ErrNotPublishable, err)
+			}
+			for _, problem := range tt.wantProblems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("expected %q in %q", problem, err.Error())
+				}
+			}
+			if len(repo.updated) != 0 {
+				t.Errorf("expected the draft not to be saved, got updates of %v", repo.updated)
+			}
+		})
+	}
+}
+
+// Drafts may be saved incomplete; the requirements apply once a post is published
+func TestIncompleteDrafts(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, Slug: "existing", AuthorID: authorID, Status: "draft", Content: "x"}, requirePublishFields)
+
+	content := "still short"
+	if _, err := uc.UpdatePost(ctx, 1, post.UpdatePostRequest{Content: &content}, authorID, user.RoleUser); err != nil {
+		t.Errorf("expected an incomplete draft to be saved, got %v", err)
+	}
+	if _, err := uc.CreatePost(ctx, post.CreatePostRequest{Title: "New draft", Content: "short"}, authorID, user.RoleUser); err != nil {
+		t.Errorf("expected an incomplete draft to be created, got %v", err)
+	}
+
+	published := "published"
+	_, err := uc.CreatePost(ctx, post.CreatePostRequest{Title: "Straight to print", Content: "short", Status: &published}, authorID, user.RoleUser)
+	if !errors.Is(err, post.ErrNotPublishable) {
+		t.Errorf("expected creating an incomplete published post to fail with %v, got %v", post.ErrNotPublishable, err)
+	}
+	if len(repo.posts) != 2 {
+		t.Errorf("expected only the draft to be created, got %d posts", len(repo.posts))
+	}
+}
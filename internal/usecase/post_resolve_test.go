@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestResolvePost(t *testing.T) {
+	repo := &fakePostRepo{
+		posts: []*post.Post{
+			{ID: 5, Slug: "hello", Status: "published", IsPublic: true, AuthorID: authorID},
+			{ID: 7, Slug: "2024", Status: "published", IsPublic: true, AuthorID: authorID},
+			{ID: 8, Slug: "draft", Status: "draft", AuthorID: authorID},
+			{ID: 9, Slug: "8", Status: "published", IsPublic: true, AuthorID: authorID},
+		},
+	}
+	cfg := &config.Config{}
+	cfg.Post.PrivateVisibility = "authenticated"
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, cfg)
+
+	tests := []struct {
+		name       string
+		idOrSlug   string
+		viewerID   uint
+		viewerRole string
+		wantID     uint // 0 when not found
+	}{
+		{"numeric ID", "5", 0, "", 5},
+		{"slug", "hello", 0, "", 5},
+		{"numeric slug without matching ID", "2024", 0, "", 7},
+		{"unknown", "missing", 0, "", 0},
+		{"hidden ID falls back to numeric slug", "8", 0, "", 9},
+		{"visible ID wins over numeric slug", "8", authorID, user.RoleUser, 8},
+		{"draft by slug, anonymous", "draft", 0, "", 0},
+		{"draft by slug, other user", "draft", otherUserID, user.RoleUser, 0},
+		{"draft by slug, author", "draft", authorID, user.RoleUser, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := uc.ResolvePost(context.Background(), tt.idOrSlug, false, tt.viewerID, tt.viewerRole)
+			if tt.wantID == 0 {
+				if !errors.Is(err, post.ErrNotFound) {
+					t.Fatalf("expected ErrNotFound, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.ID != tt.wantID {
+				t.Errorf("resolved post %d, want %d", resp.ID, tt.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestScheduledPostsRecordWhoScheduledThem(t *testing.T) {
+	ctx := context.Background()
+	const adminID uint = 9
+	uc, repo, users := newStatusTestUseCase(t, &post.Post{ID: 1, Title: "Existing", Content: "Body", Slug: "existing", Status: "draft", AuthorID: authorID}, nil)
+	users.users[authorID] = &user.User{ID: authorID, Name: "Author", Role: user.RoleUser}
+	users.users[adminID] = &user.User{ID: adminID, Name: "Admin", Role: user.RoleAdmin}
+
+	soon := time.Now().Add(time.Hour)
+	later := time.Now().Add(48 * time.Hour)
+
+	// The author schedules a new post, an admin schedules an existing one
+	if _, err := uc.CreatePost(ctx, post.CreatePostRequest{Title: "Later", Content: "Body", PublishAt: &later}, authorID, user.RoleUser); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := uc.UpdatePost(ctx, 1, post.UpdatePostRequest{PublishAt: &soon}, adminID, user.RoleAdmin); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	// Published posts are not in the queue
+	repo.posts = append(repo.posts, &post.Post{ID: 10, Status: "published", PublishAt: &later})
+
+	resp, err := uc.GetScheduledPosts(ctx, post.ScheduledFilter{}, 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Total != 2 || len(resp.Posts) != 2 {
+		t.Fatalf("expected 2 scheduled posts, got %+v", resp)
+	}
+
+	first, second := resp.Posts[0], resp.Posts[1]
+	if first.ID != 1 || first.ScheduledBy == nil || *first.ScheduledBy != adminID || first.ScheduledByName != "Admin" || first.AuthorName != "Author" {
+		t.Errorf("expected the post scheduled by the admin first, got %+v", first)
+	}
+	if second.Title != "Later" || second.ScheduledBy == nil || *second.ScheduledBy != authorID || second.ScheduledByName != "Author" {
+		t.Errorf("expected the author's post second, got %+v", second)
+	}
+}
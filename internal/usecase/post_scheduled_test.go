@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+)
+
+func TestPublishDuePosts(t *testing.T) {
+	due := time.Now().Add(-time.Minute)
+	later := time.Now().Add(time.Hour)
+	active, inactive, missing := uint(1), uint(2), uint(3)
+	content := strings.Repeat("x", 20)
+
+	repo := &fakePostRepo{posts: []*post.Post{
+		{ID: 1, Status: "draft", PublishAt: &due, CategoryID: &active, Content: content},
+		{ID: 2, Status: "draft", PublishAt: &due, CategoryID: &inactive, Content: content},
+		{ID: 3, Status: "draft", PublishAt: &due, CategoryID: &missing, Content: content},
+		{ID: 4, Status: "draft", PublishAt: &due, Content: content},
+		{ID: 5, Status: "draft", PublishAt: &due, CategoryID: &active, Content: "short"},
+		{ID: 6, Status: "draft", PublishAt: &later, CategoryID: &active, Content: content},
+	}}
+	categories := &fakeCategoryRepo{categories: map[uint]*product.Category{
+		active:   {ID: active, IsActive: true},
+		inactive: {ID: inactive, IsActive: false},
+	}}
+	cfg := &config.Config{}
+	cfg.Post.RequireCategory = true
+	cfg.Post.PublishRequirements.MinContentLength = 10
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, categories, nil, cfg)
+
+	result, err := uc.PublishDuePosts(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(result.Published, []uint{1}) {
+		t.Errorf("expected only post 1 published, got %v", result.Published)
+	}
+	// Inactive, missing and required-but-absent categories are skipped like unpublishable content
+	if !slices.Equal(result.Skipped, []uint{2, 3, 4, 5}) {
+		t.Errorf("expected posts 2 to 5 skipped, got %v", result.Skipped)
+	}
+
+	for _, p := range repo.posts {
+		if p.ID != 1 && p.Status != "draft" {
+			t.Errorf("post %d: expected it to stay a draft, got %q", p.ID, p.Status)
+		}
+		if slices.Contains(result.Skipped, p.ID) && p.PublishAt != nil {
+			t.Errorf("post %d: expected a skipped post to be unscheduled", p.ID)
+		}
+	}
+	if repo.posts[5].PublishAt == nil {
+		t.Error("expected a post that isn't due yet to keep its schedule")
+	}
+}
+
+func TestPublishDuePostsSkippedDoNotBlockLaterPosts(t *testing.T) {
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now().Add(-time.Minute)
+
+	// A full batch of unpublishable posts is due before the publishable one
+	repo := &fakePostRepo{}
+	for i := 1; i <= scheduledPublishBatchSize+1; i++ {
+		repo.posts = append(repo.posts, &post.Post{ID: uint(i), Status: "draft", PublishAt: &older, Content: "short"})
+	}
+	last := uint(scheduledPublishBatchSize + 2)
+	repo.posts = append(repo.posts, &post.Post{ID: last, Status: "draft", PublishAt: &newer, Content: strings.Repeat("x", 20)})
+
+	cfg := &config.Config{}
+	cfg.Post.PublishRequirements.MinContentLength = 10
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, cfg)
+	ctx := context.Background()
+
+	first, err := uc.PublishDuePosts(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Skipped) != scheduledPublishBatchSize || len(first.Published) != 0 {
+		t.Fatalf("expected a full batch skipped, got %d skipped and %v published", len(first.Skipped), first.Published)
+	}
+
+	second, err := uc.PublishDuePosts(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(second.Skipped, []uint{scheduledPublishBatchSize + 1}) {
+		t.Errorf("expected only the remaining unpublishable post skipped, got %v", second.Skipped)
+	}
+	if !slices.Equal(second.Published, []uint{last}) {
+		t.Errorf("expected the newer post published, got %v", second.Published)
+	}
+
+	third, err := uc.PublishDuePosts(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(third.Skipped) != 0 || len(third.Published) != 0 {
+		t.Errorf("expected skipped posts not to be picked up again, got %+v", third)
+	}
+}
+
+// racingPostRepo publishes every due post right after handing it out, as a concurrent run would
+type racingPostRepo struct {
+	*fakePostRepo
+}
+
+func (r *racingPostRepo) GetDuePosts(ctx context.Context, now time.Time, limit int) ([]*post.Post, error) {
+	posts, err := r.fakePostRepo.GetDuePosts(ctx, now, limit)
+	for _, p := range posts {
+		if _, err := r.fakePostRepo.PublishScheduled(ctx, p.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return posts, err
+}
+
+func TestPublishDuePostsPublishesOnce(t *testing.T) {
+	due := time.Now().Add(-time.Minute)
+	newRepo := func() *fakePostRepo {
+		return &fakePostRepo{posts: []*post.Post{{ID: 1, Status: "draft", PublishAt: &due}}}
+	}
+	cfg := &config.Config{}
+
+	t.Run("second run", func(t *testing.T) {
+		uc := NewPostUseCase(newRepo(), &fakeUserRepo{}, nil, nil, cfg)
+
+		first, err := uc.PublishDuePosts(context.Background())
+		if err != nil || !slices.Equal(first.Published, []uint{1}) {
+			t.Fatalf("expected post 1 published, got %v (%v)", first, err)
+		}
+		second, err := uc.PublishDuePosts(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(second.Published) != 0 || len(second.Skipped) != 0 {
+			t.Errorf("expected nothing left to publish, got %+v", second)
+		}
+	})
+
+	t.Run("concurrent run", func(t *testing.T) {
+		uc := NewPostUseCase(&racingPostRepo{newRepo()}, &fakeUserRepo{}, nil, nil, cfg)
+
+		result, err := uc.PublishDuePosts(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Published) != 0 {
+			t.Errorf("expected a post published by another run not to be reported, got %v", result.Published)
+		}
+	})
+}
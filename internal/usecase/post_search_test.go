@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestGetAllPostsSearchTerms(t *testing.T) {
+	tests := []struct {
+		name      string
+		search    string
+		wantTerms []string
+		wantErr   error
+	}{
+		{"stop words dropped", "bánh và phở", []string{"bánh", "phở"}, nil},
+		{"only stop words", "và của", nil, post.ErrInvalidSearch},
+		{"term too short", "phở x", nil, post.ErrInvalidSearch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, AuthorID: authorID}, func(cfg *config.Config) {
+				cfg.Post.Search.StopWords = []string{"và", "của"}
+				cfg.Post.Search.MinTermLength = 2
+			})
+
+			search := tt.search
+			_, err := uc.GetAllPosts(context.Background(), post.PostFilter{Search: &search}, authorID, user.RoleUser, 1, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(repo.listFilter.SearchTerms, tt.wantTerms) {
+				t.Errorf("expected the repository to search %v, got %v", tt.wantTerms, repo.listFilter.SearchTerms)
+			}
+		})
+	}
+}
+
+func TestGetAllPostsBlankSearch(t *testing.T) {
+	blank, spaces := "", "  \t "
+
+	tests := []struct {
+		name      string
+		mode      string
+		search    *string
+		wantPosts int
+		wantErr   error
+	}{
+		{"all ignores empty", "all", &blank, 1, nil},
+		{"all ignores whitespace", "all", &spaces, 1, nil},
+		{"none matches nothing", "none", &blank, 0, nil},
+		{"none with whitespace", "none", &spaces, 0, nil},
+		{"reject", "reject", &blank, 0, post.ErrInvalidSearch},
+		{"reject with whitespace", "reject", &spaces, 0, post.ErrInvalidSearch},
+		{"absent under none", "none", nil, 1, nil},
+		{"absent under reject", "reject", nil, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, AuthorID: authorID}, func(cfg *config.Config) {
+				cfg.Post.Search.EmptyQuery = tt.mode
+			})
+
+			resp, err := uc.GetAllPosts(context.Background(), post.PostFilter{Search: tt.search}, authorID, user.RoleUser, 1, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(resp.Posts) != tt.wantPosts {
+				t.Errorf("expected %d posts, got %d", tt.wantPosts, len(resp.Posts))
+			}
+			if repo.listFilter.Search != nil || repo.listFilter.SearchTerms != nil {
+				t.Errorf("expected a blank search not to reach the repository, got %+v", repo.listFilter)
+			}
+		})
+	}
+}
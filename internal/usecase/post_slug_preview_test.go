@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/domain/post"
+)
+
+func TestPreviewSlug(t *testing.T) {
+	tests := []struct {
+		name          string
+		title         string
+		wantSlug      string
+		wantAvailable bool
+		wantErr       error
+	}{
+		{"free slug", "Hello World", "hello-world", true, nil},
+		{"surrounding whitespace", "  Hello World  ", "hello-world", true, nil},
+		{"taken slug", "Taken Title", "taken-title", false, nil},
+		{"empty title", "", "", false, post.ErrTitleRequired},
+		{"blank title", "   ", "", false, post.ErrTitleRequired},
+		{"title without slug characters", "!!!", "", false, post.ErrTitleRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, Slug: "taken-title", AuthorID: authorID}, nil)
+
+			preview, err := uc.PreviewSlug(context.Background(), tt.title)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if preview.Slug != tt.wantSlug || preview.Available != tt.wantAvailable {
+				t.Errorf("expected %q available %v, got %+v", tt.wantSlug, tt.wantAvailable, preview)
+			}
+			if len(repo.posts) != 1 {
+				t.Errorf("expected the preview not to create anything, got %d posts", len(repo.posts))
+			}
+		})
+	}
+}
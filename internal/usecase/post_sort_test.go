@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+)
+
+func TestPublishedPostsSort(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultSort string
+		sort        string
+		want        string
+		wantErr     error
+	}{
+		{"published_at default", post.SortPublishedAt, "", post.SortPublishedAt, nil},
+		{"view_count default", post.SortViewCount, "", post.SortViewCount, nil},
+		{"hot default", post.SortHot, "", post.SortHot, nil},
+		{"override", post.SortHot, post.SortPublishedAt, post.SortPublishedAt, nil},
+		{"override to views", post.SortPublishedAt, post.SortViewCount, post.SortViewCount, nil},
+		{"unknown", post.SortPublishedAt, "title", "", post.ErrInvalidSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, Status: "published", IsPublic: true}, func(cfg *config.Config) {
+				cfg.Post.DefaultSort = tt.defaultSort
+			})
+
+			resp, err := uc.GetPublishedPosts(context.Background(), tt.sort, "", 1, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if repo.publishedSort != tt.want {
+				t.Errorf("expected sort %q, got %q", tt.want, repo.publishedSort)
+			}
+			if len(resp.Posts) != 1 {
+				t.Errorf("expected the published post, got %+v", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+// newStatusTestUseCase returns a post use case over a repository holding p and an empty user
+// repository, with the repository's config as the base
+func newStatusTestUseCase(t *testing.T, p *post.Post, configure func(cfg *config.Config)) (PostUseCase, *fakePostRepo, *fakeUserRepo) {
+	t.Helper()
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.GetConfig()
+	if configure != nil {
+		configure(&cfg)
+	}
+
+	repo := &fakePostRepo{posts: []*post.Post{p}}
+	users := &fakeUserRepo{users: map[uint]*user.User{}}
+	return NewPostUseCase(repo, users, nil, &fakeTransactor{posts: repo, users: users}, &cfg), repo, users
+}
+
+func TestUnpublishPost(t *testing.T) {
+	publishedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		defaultStatus string
+		target        string
+		userID        uint
+		wantStatus    string
+		wantPublished bool
+		wantErr       error
+	}{
+		{"configured draft", "draft", "", authorID, "draft", false, nil},
+		{"configured archived", "archived", "", authorID, "archived", true, nil},
+		{"archived on request", "draft", "archived", authorID, "archived", true, nil},
+		{"draft on request", "archived", "draft", authorID, "draft", false, nil},
+		{"invalid target", "draft", "deleted", authorID, "published", true, post.ErrInvalidUnpublishStatus},
+		{"not the author", "draft", "", otherUserID, "published", true, post.ErrPermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			published := publishedAt
+			p := &post.Post{ID: 1, Title: "A post", Content: "Body", Slug: "a-post", Status: "published", PublishedAt: &published, AuthorID: authorID}
+			uc, _, _ := newStatusTestUseCase(t, p, func(cfg *config.Config) {
+				cfg.Post.UnpublishStatus = tt.defaultStatus
+			})
+
+			_, err := uc.UnpublishPost(context.Background(), p.ID, tt.userID, user.RoleUser, tt.target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if p.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, p.Status)
+			}
+			// Archived posts keep their publish date; drafts were never published
+			if (p.PublishedAt != nil) != tt.wantPublished {
+				t.Errorf("expected a publish date %v, got %v", tt.wantPublished, p.PublishedAt)
+			}
+		})
+	}
+}
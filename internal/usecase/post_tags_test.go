@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+
+	"gorm.io/gorm"
+)
+
+func TestSuggestTags(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Post.Search.StopWords = []string{"the", "and", "with"}
+	cfg.Post.Search.MinTermLength = 2
+	cfg.Post.Tags.MaxPerPost = 10
+	cfg.Post.Tags.MaxLength = 30
+
+	tests := []struct {
+		name     string
+		req      post.SuggestTagsRequest
+		tagUsage []post.TagUsage
+		want     []string
+	}{
+		{
+			name: "ranked by frequency, title words counting double",
+			req:  post.SuggestTagsRequest{Title: "Gardening", Content: "soil soil compost"},
+			want: []string{"gardening", "soil", "compost"},
+		},
+		{
+			name: "stop words excluded",
+			req:  post.SuggestTagsRequest{Title: "The soil", Content: "the the the and and with soil"},
+			want: []string{"soil"},
+		},
+		{
+			name: "markup, numbers and short words excluded",
+			req:  post.SuggestTagsRequest{Content: "<p>a 2024 compost</p>"},
+			want: []string{"compost"},
+		},
+		{
+			name:     "existing tags boosted by usage",
+			req:      post.SuggestTagsRequest{Content: "soil soil soil compost compost"},
+			tagUsage: []post.TagUsage{{Name: "Compost", Slug: "compost", Posts: 3}},
+			want:     []string{"Compost", "soil"},
+		},
+		{
+			name:     "unused existing tag outranked by a more frequent word",
+			req:      post.SuggestTagsRequest{Content: "soil soil soil compost"},
+			tagUsage: []post.TagUsage{{Name: "compost", Slug: "compost", Posts: 0}},
+			want:     []string{"soil", "compost"},
+		},
+		{
+			name: "limited",
+			req:  post.SuggestTagsRequest{Content: "soil soil compost mulch", Limit: 1},
+			want: []string{"soil"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewPostUseCase(&fakePostRepo{tagUsage: tt.tagUsage}, &fakeUserRepo{}, nil, nil, cfg)
+
+			resp, err := uc.SuggestTags(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := make([]string, len(resp.Tags))
+			for i, suggestion := range resp.Tags {
+				got[i] = suggestion.Tag
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got tags %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostTagsLoadedPerPage(t *testing.T) {
+	repo := &fakePostRepo{
+		posts: []*post.Post{
+			{ID: 1, Slug: "soil", Status: "published", IsPublic: true},
+			{ID: 2, Slug: "compost", Status: "published", IsPublic: true},
+			{ID: 3, Slug: "mulch", Status: "published", IsPublic: true},
+		},
+		tags: map[uint][]string{1: {"garden", "soil"}, 3: {"mulch"}},
+	}
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, &config.Config{})
+
+	resp, err := uc.GetPostsBySlugs(context.Background(), []string{"soil", "compost", "mulch"}, 0, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.tagQueries != 1 {
+		t.Errorf("expected the tags of the page in one query, got %d", repo.tagQueries)
+	}
+	want := map[string][]string{"soil": {"garden", "soil"}, "compost": {}, "mulch": {"mulch"}}
+	for _, p := range resp.Posts {
+		if !slices.Equal(p.Tags, want[p.Slug]) {
+			t.Errorf("post %q has tags %v, want %v", p.Slug, p.Tags, want[p.Slug])
+		}
+	}
+
+	repo.tagErr = errors.New("connection lost")
+	if _, err := uc.GetPostsBySlugs(context.Background(), []string{"soil"}, 0, ""); err == nil {
+		t.Error("expected the tag lookup error to be returned")
+	}
+}
+
+func TestGetAuthorTags(t *testing.T) {
+	repo := &fakePostRepo{
+		posts: []*post.Post{
+			{ID: 1, AuthorID: authorID, Status: "published", IsPublic: true},
+			{ID: 2, AuthorID: authorID, Status: "published", IsPublic: true},
+			{ID: 3, AuthorID: authorID, Status: "draft", IsPublic: true},
+			{ID: 4, AuthorID: authorID, Status: "published", IsPublic: false},
+			{ID: 5, AuthorID: authorID, Status: "published", IsPublic: true, DeletedAt: gorm.DeletedAt{Valid: true}},
+			{ID: 6, AuthorID: otherUserID, Status: "published", IsPublic: true},
+		},
+		tags: map[uint][]string{
+			1: {"Go", "Testing"},
+			2: {"Go"},
+			3: {"Go", "Unreleased"},
+			4: {"Testing", "Private"},
+			5: {"Testing"},
+			6: {"Go", "Other"},
+		},
+	}
+	users := &fakeUserRepo{users: map[uint]*user.User{authorID: {ID: authorID}}}
+	uc := NewPostUseCase(repo, users, nil, nil, &config.Config{})
+	ctx := context.Background()
+
+	resp, err := uc.GetAuthorTags(ctx, authorID, 0)
+	if err != nil {
+		t.Fatalf("author tags: %v", err)
+	}
+	// Drafts, private and deleted posts and other authors' posts are not counted
+	want := []post.TagUsage{{Name: "Go", Slug: "go", Posts: 2}, {Name: "Testing", Slug: "testing", Posts: 1}}
+	if resp.AuthorID != authorID || !slices.Equal(resp.Tags, want) {
+		t.Errorf("expected %v, got %+v", want, resp)
+	}
+
+	resp, err = uc.GetAuthorTags(ctx, authorID, 1)
+	if err != nil || !slices.Equal(resp.Tags, want[:1]) {
+		t.Errorf("expected only the most used tag, got %+v, %v", resp, err)
+	}
+
+	if _, err := uc.GetAuthorTags(ctx, 99, 0); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("expected %v for an unknown author, got %v", user.ErrNotFound, err)
+	}
+}
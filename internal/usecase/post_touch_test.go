@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestTouchPost(t *testing.T) {
+	updatedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		postID  uint
+		userID  uint
+		role    string
+		wantErr error
+	}{
+		{"admin", 1, otherUserID, user.RoleAdmin, nil},
+		{"author", 1, authorID, user.RoleUser, nil},
+		{"other user", 1, otherUserID, user.RoleUser, post.ErrPermissionDenied},
+		{"unknown post", 99, otherUserID, user.RoleAdmin, post.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := post.Post{
+				ID:        1,
+				Title:     "A post",
+				Content:   "Some content",
+				Status:    "published",
+				AuthorID:  authorID,
+				ViewCount: 42,
+				UpdatedAt: updatedAt,
+			}
+			p := original
+			uc, _, _ := newStatusTestUseCase(t, &p, nil)
+
+			resp, err := uc.TouchPost(context.Background(), tt.postID, tt.userID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				if p.UpdatedAt != updatedAt {
+					t.Errorf("expected a rejected touch to leave updated_at alone, got %v", p.UpdatedAt)
+				}
+				return
+			}
+
+			if !p.UpdatedAt.After(updatedAt) || !resp.UpdatedAt.Equal(p.UpdatedAt) {
+				t.Errorf("expected updated_at to be bumped, got %v in the response and %v stored", resp.UpdatedAt, p.UpdatedAt)
+			}
+			if p.Title != original.Title || p.Content != original.Content || p.Status != original.Status || p.ViewCount != original.ViewCount {
+				t.Errorf("expected only updated_at to change, got %+v", p)
+			}
+		})
+	}
+}
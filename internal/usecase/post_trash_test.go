@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+
+	"gorm.io/gorm"
+)
+
+func TestGetTrashedPostsIncludesDeletionTime(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	older := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+	repo := &fakePostRepo{posts: []*post.Post{
+		{ID: 1, Title: "Live", Status: "published"},
+		{ID: 2, Title: "Deleted first", DeletedAt: gorm.DeletedAt{Time: older, Valid: true}},
+		{ID: 3, Title: "Deleted last", DeletedAt: gorm.DeletedAt{Time: newer, Valid: true}},
+	}}
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, &config.Config{})
+
+	resp, err := uc.GetTrashedPosts(context.Background(), 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Total != 2 || len(resp.Posts) != 2 {
+		t.Fatalf("expected only the 2 deleted posts, got %+v", resp)
+	}
+	if resp.Posts[0].ID != 3 || !resp.Posts[0].DeletedAt.Equal(newer) {
+		t.Errorf("expected the most recently deleted post first with its deletion time, got %d at %v", resp.Posts[0].ID, resp.Posts[0].DeletedAt)
+	}
+	if resp.Posts[1].ID != 2 || !resp.Posts[1].DeletedAt.Equal(older) {
+		t.Errorf("expected the older deletion second with its deletion time, got %d at %v", resp.Posts[1].ID, resp.Posts[1].DeletedAt)
+	}
+}
+
+// newAuthorTrashPosts returns a trashed post of each author and a live post holding the
+// slug of the first one
+func newAuthorTrashPosts() []*post.Post {
+	deleted := gorm.DeletedAt{Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), Valid: true}
+	return []*post.Post{
+		{ID: 1, Slug: "mine", AuthorID: authorID, Status: "draft", DeletedAt: deleted},
+		{ID: 2, Slug: "theirs", AuthorID: otherUserID, Status: "draft", DeletedAt: deleted},
+		{ID: 3, Slug: "mine", AuthorID: otherUserID, Status: "draft"},
+	}
+}
+
+func TestGetMyTrashedPosts(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		authorTrash bool
+		wantErr     error
+	}{
+		{"enabled", true, nil},
+		{"disabled", false, post.ErrPermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Post.AuthorTrash = tt.authorTrash
+			uc := NewPostUseCase(&fakePostRepo{posts: newAuthorTrashPosts()}, &fakeUserRepo{}, nil, nil, cfg)
+
+			resp, err := uc.GetMyTrashedPosts(context.Background(), authorID, 1, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if resp.Total != 1 || len(resp.Posts) != 1 || resp.Posts[0].ID != 1 {
+				t.Errorf("expected only the author's own trashed post, got %+v", resp)
+			}
+		})
+	}
+}
+
+func TestRestorePost(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		postID      uint
+		userID      uint
+		role        string
+		authorTrash bool
+		wantErr     error
+	}{
+		{"own post", 1, authorID, user.RoleUser, true, nil},
+		{"another author's post", 2, authorID, user.RoleUser, true, post.ErrPermissionDenied},
+		{"author trash disabled", 1, authorID, user.RoleUser, false, post.ErrPermissionDenied},
+		{"admin", 2, authorID, user.RoleAdmin, false, nil},
+		{"live post", 3, otherUserID, user.RoleAdmin, true, post.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := *config.GetConfig()
+			cfg.Post.AuthorTrash = tt.authorTrash
+			repo := &fakePostRepo{posts: newAuthorTrashPosts()}
+			uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, &cfg)
+
+			resp, err := uc.RestorePost(context.Background(), tt.postID, tt.userID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			stored := repo.posts[tt.postID-1]
+			if err != nil {
+				if tt.postID != 3 && !stored.DeletedAt.Valid {
+					t.Error("expected a refused restore to leave the post trashed")
+				}
+				return
+			}
+
+			if stored.DeletedAt.Valid || resp.ID != tt.postID {
+				t.Errorf("expected post %d to be restored, got %+v", tt.postID, stored)
+			}
+		})
+	}
+}
+
+// A live post may have taken the slug while the post was in the trash
+func TestRestorePostKeepsSlugsUnique(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.GetConfig()
+	cfg.Post.AuthorTrash = true
+	repo := &fakePostRepo{posts: newAuthorTrashPosts()}
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, &cfg)
+
+	resp, err := uc.RestorePost(context.Background(), 1, authorID, user.RoleUser)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if resp.Slug == "mine" || !strings.HasPrefix(resp.Slug, "mine") {
+		t.Errorf("expected a new slug derived from %q, got %q", "mine", resp.Slug)
+	}
+	if repo.posts[2].Slug != "mine" {
+		t.Errorf("expected the live post to keep its slug, got %q", repo.posts[2].Slug)
+	}
+
+	// Without a clash the slug is kept
+	resp, err = uc.RestorePost(context.Background(), 2, otherUserID, user.RoleUser)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if resp.Slug != "theirs" {
+		t.Errorf("expected the slug to be kept, got %q", resp.Slug)
+	}
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+func TestUpsertPostBySlug(t *testing.T) {
+	tests := []struct {
+		name        string
+		slug        string
+		userID      uint
+		role        string
+		wantCreated bool
+		wantErr     error
+	}{
+		{"new slug", "a-new-post", otherUserID, user.RoleUser, true, nil},
+		{"own post", "synced", authorID, user.RoleUser, false, nil},
+		{"another author's post", "synced", otherUserID, user.RoleUser, false, post.ErrPermissionDenied},
+		{"another author's post as editor", "synced", otherUserID, user.RoleEditor, false, post.ErrPermissionDenied},
+		{"another author's post as admin", "synced", otherUserID, user.RoleAdmin, false, nil},
+		{"slug not in canonical form", "Synced Post", authorID, user.RoleUser, false, post.ErrInvalidSlug},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := &post.Post{ID: 1, Slug: "synced", Title: "Original", Content: "Original content", AuthorID: authorID, Status: "draft"}
+			uc, repo, _ := newStatusTestUseCase(t, existing, nil)
+
+			req := post.CreatePostRequest{Title: "Synced title", Content: "Synced content"}
+			resp, created, err := uc.UpsertPostBySlug(context.Background(), tt.slug, req, tt.userID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				if len(repo.posts) != 1 || existing.Content != "Original content" {
+					t.Errorf("expected a refused upsert to change nothing, got %+v", repo.posts)
+				}
+				return
+			}
+
+			if created != tt.wantCreated {
+				t.Errorf("expected created %v, got %v", tt.wantCreated, created)
+			}
+			if resp.Slug != tt.slug || resp.Title != "Synced title" || resp.Content != "Synced content" {
+				t.Errorf("expected the synced post under %q, got %+v", tt.slug, resp)
+			}
+
+			if tt.wantCreated {
+				if len(repo.posts) != 2 || resp.AuthorID != tt.userID {
+					t.Errorf("expected a new post owned by the caller, got %d posts and author %d", len(repo.posts), resp.AuthorID)
+				}
+				return
+			}
+			// Updating keeps the post and its author
+			if len(repo.posts) != 1 || resp.ID != existing.ID || resp.AuthorID != authorID {
+				t.Errorf("expected post %d by %d to be updated in place, got %+v", existing.ID, authorID, resp)
+			}
+		})
+	}
+}
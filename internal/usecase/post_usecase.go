@@ -5,115 +5,458 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"moon/internal/config"
 	"moon/internal/domain/post"
+	"moon/internal/domain/product"
+	"moon/internal/domain/transaction"
 	"moon/internal/domain/user"
+	"moon/pkg/imagepolicy"
+	"moon/pkg/markdown"
+	"moon/pkg/moderation"
+	"moon/pkg/similarity"
+	"moon/pkg/slug"
+	"moon/pkg/snippet"
 )
 
 type PostUseCase interface {
-	CreatePost(ctx context.Context, req post.CreatePostRequest, authorID uint) (*post.PostResponse, error)
-	GetPostByID(ctx context.Context, id uint, incrementView bool) (*post.PostResponse, error)
-	GetPostBySlug(ctx context.Context, slug string, incrementView bool) (*post.PostResponse, error)
+	CreatePost(ctx context.Context, req post.CreatePostRequest, authorID uint, authorRole string) (*post.PostResponse, error)
+	FindDuplicateTitles(ctx context.Context, title string, viewerID uint, viewerRole string) ([]post.DuplicateCandidate, error)
+	ImportMarkdown(ctx context.Context, files []post.ImportFile, authorID uint, authorRole string) (*post.ImportResponse, error)
+	PreviewSlug(ctx context.Context, title string) (*post.SlugPreview, error)
+	SuggestTags(ctx context.Context, req post.SuggestTagsRequest) (*post.SuggestTagsResponse, error)
+	GetPostByID(ctx context.Context, id uint, incrementView bool, viewerID uint, viewerRole string) (*post.PostResponse, error)
+	GetPostBySlug(ctx context.Context, slug string, incrementView bool, viewerID uint, viewerRole string) (*post.PostResponse, error)
+	ResolvePost(ctx context.Context, idOrSlug string, incrementView bool, viewerID uint, viewerRole string) (*post.PostResponse, error)
+	GetPostsBySlugs(ctx context.Context, slugs []string, viewerID uint, viewerRole string) (*post.PostsBySlugResponse, error)
 	UpdatePost(ctx context.Context, id uint, req post.UpdatePostRequest, userID uint, userRole string) (*post.PostResponse, error)
+	UpsertPostBySlug(ctx context.Context, postSlug string, req post.CreatePostRequest, userID uint, userRole string) (*post.PostResponse, bool, error)
 	DeletePost(ctx context.Context, id uint, userID uint, userRole string) error
-	GetAllPosts(ctx context.Context, filter post.PostFilter, page, limit int) (*post.PostsListResponse, error)
+	GetAllPosts(ctx context.Context, filter post.PostFilter, viewerID uint, viewerRole string, page, limit int) (*post.PostsListResponse, error)
 	GetMyPosts(ctx context.Context, authorID uint, page, limit int) (*post.PostsListResponse, error)
-	GetPublishedPosts(ctx context.Context, page, limit int) (*post.PostsListResponse, error)
+	GetAuthorStats(ctx context.Context, authorID uint) (*post.AuthorStats, error)
+	GetAuthorTags(ctx context.Context, authorID uint, limit int) (*post.AuthorTagsResponse, error)
+	GetPublishedPosts(ctx context.Context, sort, language string, page, limit int) (*post.PostsListResponse, error)
+	GetPublishedPostsCursor(ctx context.Context, cursorID uint, language string, limit int) (*post.PublishedCursorResponse, error)
+	GetPublishedLastModified(ctx context.Context, sort string) (*time.Time, error)
+	GetPostsByCategorySlug(ctx context.Context, categorySlug string, page, limit int) (*post.PostsListResponse, error)
+	GetArchive(ctx context.Context) ([]*post.ArchiveMonth, error)
+	GetArchivePosts(ctx context.Context, year, month, page, limit int) (*post.PostsListResponse, error)
 	PublishPost(ctx context.Context, id uint, userID uint, userRole string) (*post.PostResponse, error)
-	UnpublishPost(ctx context.Context, id uint, userID uint, userRole string) (*post.PostResponse, error)
+	UnpublishPost(ctx context.Context, id uint, userID uint, userRole string, targetStatus string) (*post.PostResponse, error)
+	GetTrashedPosts(ctx context.Context, page, limit int) (*post.TrashedPostsListResponse, error)
+	GetMyTrashedPosts(ctx context.Context, authorID uint, page, limit int) (*post.TrashedPostsListResponse, error)
+	RestorePost(ctx context.Context, id uint, userID uint, userRole string) (*post.PostResponse, error)
+	GetScheduledPosts(ctx context.Context, filter post.ScheduledFilter, page, limit int) (*post.ScheduledPostsListResponse, error)
+	GetPostChanges(ctx context.Context, filter post.ChangesFilter, limit int) (*post.PostChangesResponse, error)
+	TouchPost(ctx context.Context, id uint, userID uint, userRole string) (*post.PostResponse, error)
+	ResetViewCount(ctx context.Context, id uint) error
+	ResetViewCounts(ctx context.Context, ids []uint) (*post.ResetViewsResponse, error)
+	BulkUpdateVisibility(ctx context.Context, ids []uint, isPublic bool, userID uint, userRole string) (*post.BulkVisibilityResponse, error)
+	GetPostsNeedingAttention(ctx context.Context, rule string, days int, page, limit int) (*post.PostsListResponse, error)
+	CleanupStaleDrafts(ctx context.Context) ([]uint, int64, error)
+	PublishDuePosts(ctx context.Context) (*post.ScheduledPublishResult, error)
+	AddCollaborator(ctx context.Context, postID uint, req post.AddCollaboratorRequest, userID uint, userRole string) (*post.CollaboratorResponse, error)
+	RemoveCollaborator(ctx context.Context, postID, collaboratorID uint, userID uint, userRole string) error
+	GetCollaborators(ctx context.Context, postID uint, userID uint, userRole string) ([]post.CollaboratorResponse, error)
+	GetSharedWithMe(ctx context.Context, userID uint, page, limit int) (*post.PostsListResponse, error)
 }
 
 type postUseCase struct {
-	postRepo post.Repository
-	userRepo user.Repository
+	postRepo     post.Repository
+	userRepo     user.Repository
+	categoryRepo product.CategoryRepository
+	transactor   transaction.Transactor
+	cfg          *config.Config
+	// wordFilters holds the moderation word list of each language, when moderation is enabled
+	wordFilters map[string]*moderation.Filter
 }
 
 // NewPostUseCase creates a new post use case
-func NewPostUseCase(postRepo post.Repository, userRepo user.Repository) PostUseCase {
+func NewPostUseCase(postRepo post.Repository, userRepo user.Repository, categoryRepo product.CategoryRepository, transactor transaction.Transactor, cfg *config.Config) PostUseCase {
+	wordFilters := make(map[string]*moderation.Filter)
+	if cfg.Moderation.Enabled {
+		for language, words := range cfg.Moderation.Words {
+			wordFilters[language] = moderation.NewFilter(words)
+		}
+	}
+
 	return &postUseCase{
-		postRepo: postRepo,
-		userRepo: userRepo,
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+		categoryRepo: categoryRepo,
+		transactor:   transactor,
+		cfg:          cfg,
+		wordFilters:  wordFilters,
 	}
 }
 
-func (uc *postUseCase) CreatePost(ctx context.Context, req post.CreatePostRequest, authorID uint) (*post.PostResponse, error) {
-	// Generate slug from title
-	slug := uc.generateSlug(req.Title)
+func (uc *postUseCase) CreatePost(ctx context.Context, req post.CreatePostRequest, authorID uint, authorRole string) (*post.PostResponse, error) {
+	if err := uc.validateContent(req.Content); err != nil {
+		return nil, err
+	}
+
+	if err := uc.validateLanguage(req.Language); err != nil {
+		return nil, err
+	}
+
+	if err := uc.validateCategory(ctx, req.CategoryID); err != nil {
+		return nil, err
+	}
+
+	newPost := uc.newPost(req, "", authorID, authorRole)
+	if _, err := uc.moderate(newPost); err != nil {
+		return nil, err
+	}
+	if err := uc.validatePublishable(newPost); err != nil {
+		return nil, err
+	}
+
+	// Generate slug from the moderated title, so masked words stay out of the URL
+	newPost.Slug = uc.generateSlug(newPost.Title)
 
 	// Check if slug already exists
-	existingPost, _ := uc.postRepo.GetBySlug(ctx, slug)
+	existingPost, _ := uc.postRepo.GetBySlug(ctx, newPost.Slug)
 	if existingPost != nil {
-		slug = fmt.Sprintf("%s-%d", slug, time.Now().Unix())
+		newPost.Slug = uc.uniqueSlug(newPost.Slug)
 	}
 
-	// Set default values
-	status := "draft"
-	if req.Status != nil {
-		status = *req.Status
+	if err := uc.renderContent(newPost); err != nil {
+		return nil, wrapError("failed to render content", err)
 	}
 
-	isPublic := true
-	if req.IsPublic != nil {
-		isPublic = *req.IsPublic
+	if err := uc.createWithTags(ctx, newPost, req.Tags, authorRole); err != nil {
+		return nil, err
 	}
 
-	// Create post
-	newPost := &post.Post{
-		Title:       req.Title,
-		Content:     req.Content,
-		Summary:     req.Summary,
-		Slug:        slug,
-		Status:      status,
-		CategoryID:  req.CategoryID,
-		AuthorID:    authorID,
-		FeaturedImg: req.FeaturedImg,
-		IsPublic:    isPublic,
+	return uc.mapToPostResponse(ctx, newPost)
+}
+
+// createWithTags stores a new post and its tags in one transaction, so a failed tag write
+// doesn't leave the post behind untagged. The author's post limit is checked in the same
+// transaction.
+func (uc *postUseCase) createWithTags(ctx context.Context, p *post.Post, tags []string, authorRole string) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context, repos transaction.Repositories) error {
+		if err := uc.checkPostLimit(ctx, repos, p.AuthorID, authorRole); err != nil {
+			return err
+		}
+		tagIDs, err := uc.resolveTags(ctx, repos.Posts, tags)
+		if err != nil {
+			return err
+		}
+		if err := repos.Posts.Create(ctx, p); err != nil {
+			return wrapError("failed to create post", err)
+		}
+		if err := repos.Posts.SetPostTags(ctx, p.ID, tagIDs); err != nil {
+			return wrapError("failed to tag post", err)
+		}
+		return nil
+	})
+}
+
+// ImportMarkdown creates a markdown post owned by the importer from each file's front matter
+// and body. Files are imported independently, so one bad file doesn't stop the others.
+func (uc *postUseCase) ImportMarkdown(ctx context.Context, files []post.ImportFile, authorID uint, authorRole string) (*post.ImportResponse, error) {
+	if len(files) == 0 {
+		return nil, post.ErrNoMarkdownFiles
+	}
+	if len(files) > uc.cfg.Post.Import.MaxFiles {
+		return nil, post.ErrTooManyFiles
 	}
 
-	// Set published_at if status is published
-	if status == "published" {
-		now := time.Now()
-		newPost.PublishedAt = &now
+	response := &post.ImportResponse{Results: make([]post.ImportResult, 0, len(files))}
+	for _, file := range files {
+		result := post.ImportResult{File: file.Name}
+
+		p, err := uc.importMarkdownFile(ctx, file, authorID, authorRole)
+		if err != nil {
+			result.Error = err.Error()
+			response.Failed++
+		} else {
+			result.PostID = p.ID
+			result.Slug = p.Slug
+			response.Imported++
+		}
+
+		response.Results = append(response.Results, result)
 	}
 
-	if err := uc.postRepo.Create(ctx, newPost); err != nil {
-		return nil, errors.New("failed to create post")
+	return response, nil
+}
+
+func (uc *postUseCase) importMarkdownFile(ctx context.Context, file post.ImportFile, authorID uint, authorRole string) (*post.Post, error) {
+	meta, body, err := post.ParseMarkdownFile(file.Data)
+	if err != nil {
+		return nil, wrapError("invalid front matter", err)
 	}
 
-	return uc.mapToPostResponse(ctx, newPost)
+	title := strings.TrimSpace(meta.Title)
+	if title == "" {
+		return nil, post.ErrTitleRequired
+	}
+	if meta.Status != "" && meta.Status != "draft" && meta.Status != "published" && meta.Status != "archived" {
+		return nil, post.ErrInvalidStatus
+	}
+	if _, err := post.NormalizeTags(meta.Tags, uc.cfg.Post.Tags.MaxPerPost, uc.cfg.Post.Tags.MaxLength); err != nil {
+		return nil, err
+	}
+
+	if body == "" {
+		return nil, post.ErrContentRequired
+	}
+	if err := uc.validateContent(body); err != nil {
+		return nil, err
+	}
+	// A slug from the front matter keeps the old URL, so it is used as-is or not at all
+	if meta.Slug != "" {
+		if uc.generateSlug(meta.Slug) != meta.Slug {
+			return nil, post.ErrInvalidSlug
+		}
+		if existingPost, _ := uc.postRepo.GetBySlug(ctx, meta.Slug); existingPost != nil {
+			return nil, post.ErrSlugInUse
+		}
+	}
+
+	format := "markdown"
+	req := post.CreatePostRequest{
+		Title:         title,
+		Content:       body,
+		ContentFormat: &format,
+	}
+	if meta.Summary != "" {
+		req.Summary = &meta.Summary
+	}
+	if meta.Status != "" {
+		req.Status = &meta.Status
+	}
+
+	newPost := uc.newPost(req, meta.Slug, authorID, authorRole)
+	if _, err := uc.moderate(newPost); err != nil {
+		return nil, err
+	}
+	if err := uc.validatePublishable(newPost); err != nil {
+		return nil, err
+	}
+	// Without a front matter slug, it comes from the moderated title
+	if newPost.Slug == "" {
+		newPost.Slug = uc.generateSlug(newPost.Title)
+		if existingPost, _ := uc.postRepo.GetBySlug(ctx, newPost.Slug); existingPost != nil {
+			newPost.Slug = uc.uniqueSlug(newPost.Slug)
+		}
+	}
+	// Keep the original publication date of migrated posts
+	if meta.Date != nil && newPost.PublishedAt != nil {
+		newPost.PublishedAt = meta.Date
+	}
+
+	if err := uc.renderContent(newPost); err != nil {
+		return nil, wrapError("failed to render content", err)
+	}
+	if err := uc.createWithTags(ctx, newPost, meta.Tags, authorRole); err != nil {
+		return nil, err
+	}
+
+	return newPost, nil
+}
+
+// FindDuplicateTitles returns existing posts the viewer can read whose normalized title is
+// within the configured edit distance of title, closest first. Nothing is returned when the check is disabled.
+func (uc *postUseCase) FindDuplicateTitles(ctx context.Context, title string, viewerID uint, viewerRole string) ([]post.DuplicateCandidate, error) {
+	cfg := uc.cfg.Post.DuplicateTitles
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	normalized := similarity.Normalize(title)
+	if normalized == "" {
+		return nil, nil
+	}
+
+	// Titles differing in length by more than the distance cannot match. Punctuation is
+	// dropped by normalization, so the raw title length is widened by the same margin.
+	length := utf8.RuneCountInString(title)
+	// Only posts the viewer can read are candidates, so the warning doesn't reveal others
+	minLength, maxLength := max(length-2*cfg.MaxDistance, 0), length+2*cfg.MaxDistance
+	candidates, err := uc.postRepo.GetByTitleLength(ctx, minLength, maxLength, uc.listingViewer(viewerID, viewerRole), cfg.Candidates)
+	if err != nil {
+		return nil, wrapError("failed to fetch posts", err)
+	}
+
+	var duplicates []post.DuplicateCandidate
+	for _, p := range candidates {
+		distance := similarity.Distance(normalized, similarity.Normalize(p.Title))
+		if distance > cfg.MaxDistance {
+			continue
+		}
+		duplicates = append(duplicates, post.DuplicateCandidate{
+			ID:       p.ID,
+			Title:    p.Title,
+			Slug:     p.Slug,
+			Status:   p.Status,
+			Distance: distance,
+		})
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Distance < duplicates[j].Distance
+	})
+	return duplicates, nil
+}
+
+// tagSuggestionCandidates caps how many of a draft's most frequent words are matched
+// against existing tags
+const tagSuggestionCandidates = 100
+
+// SuggestTags proposes tags for a draft from its most frequent words, skipping the search
+// stop words, and ranks words that are already popular tags higher. At most limit tags are
+// returned, the configured tags per post by default.
+func (uc *postUseCase) SuggestTags(ctx context.Context, req post.SuggestTagsRequest) (*post.SuggestTagsResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = uc.cfg.Post.Tags.MaxPerPost
+	}
+
+	candidates := post.SuggestTags(req.Title, req.Content, uc.cfg.Post.Search.StopWords,
+		uc.cfg.Post.Search.MinTermLength, uc.cfg.Post.Tags.MaxLength, tagSuggestionCandidates)
+
+	slugs := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		slugs[i] = post.TagSlug(candidate.Tag)
+	}
+	usage, err := uc.postRepo.GetTagUsage(ctx, slugs)
+	if err != nil {
+		return nil, wrapError("failed to fetch tags", err)
+	}
+
+	tags := post.BoostExistingTags(candidates, usage, limit)
+	return &post.SuggestTagsResponse{Tags: tags}, nil
+}
+
+// PreviewSlug reports the slug CreatePost would generate for a title without creating anything.
+// When the slug is taken CreatePost falls back to a suffixed slug.
+func (uc *postUseCase) PreviewSlug(ctx context.Context, title string) (*post.SlugPreview, error) {
+	// CreatePost masks blocked words before generating the slug; new posts default to this word list
+	if filter := uc.wordFilters[uc.cfg.Post.Languages.Default]; filter != nil && uc.cfg.Moderation.Action == moderation.ActionMask {
+		title = filter.Mask(title)
+	}
+
+	postSlug := uc.generateSlug(strings.TrimSpace(title))
+	if postSlug == "" {
+		return nil, post.ErrTitleRequired
+	}
+
+	existingPost, _ := uc.postRepo.GetBySlug(ctx, postSlug)
+	return &post.SlugPreview{
+		Slug:      postSlug,
+		Available: existingPost == nil,
+	}, nil
 }
 
-func (uc *postUseCase) GetPostByID(ctx context.Context, id uint, incrementView bool) (*post.PostResponse, error) {
+// GetPostByID returns a post the viewer may read; viewerID is 0 for anonymous requests
+func (uc *postUseCase) GetPostByID(ctx context.Context, id uint, incrementView bool, viewerID uint, viewerRole string) (*post.PostResponse, error) {
 	p, err := uc.postRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if !uc.canViewPost(ctx, p, viewerID, viewerRole) {
+		return nil, post.ErrNotFound
+	}
+
 	// Increment view count if requested
 	if incrementView {
 		uc.postRepo.IncrementViewCount(ctx, id)
 		p.ViewCount++
 	}
 
-	return uc.mapToPostResponse(ctx, p)
+	return uc.mapToViewerResponse(ctx, p, viewerID, viewerRole)
 }
 
-func (uc *postUseCase) GetPostBySlug(ctx context.Context, slug string, incrementView bool) (*post.PostResponse, error) {
+// GetPostBySlug returns a post the viewer may read; viewerID is 0 for anonymous requests
+func (uc *postUseCase) GetPostBySlug(ctx context.Context, slug string, incrementView bool, viewerID uint, viewerRole string) (*post.PostResponse, error) {
 	p, err := uc.postRepo.GetBySlug(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
 
+	if !uc.canViewPost(ctx, p, viewerID, viewerRole) {
+		return nil, post.ErrNotFound
+	}
+
 	// Increment view count if requested
 	if incrementView {
 		uc.postRepo.IncrementViewCount(ctx, p.ID)
 		p.ViewCount++
 	}
 
-	return uc.mapToPostResponse(ctx, p)
+	return uc.mapToViewerResponse(ctx, p, viewerID, viewerRole)
+}
+
+// GetPostsBySlugs returns the posts with the given slugs in the requested order. Missing
+// slugs, and posts the viewer may not see, are left out; repeated slugs are returned once.
+// View counts are not incremented.
+func (uc *postUseCase) GetPostsBySlugs(ctx context.Context, slugs []string, viewerID uint, viewerRole string) (*post.PostsBySlugResponse, error) {
+	posts, err := uc.postRepo.GetBySlugs(ctx, slugs)
+	if err != nil {
+		return nil, wrapError("failed to fetch posts", err)
+	}
+
+	bySlug := make(map[string]*post.Post, len(posts))
+	for _, p := range posts {
+		bySlug[p.Slug] = p
+	}
+
+	visible := make([]*post.Post, 0, len(posts))
+	for _, slug := range slugs {
+		p, ok := bySlug[slug]
+		if !ok {
+			continue
+		}
+		// Drop it so a repeated slug isn't returned twice
+		delete(bySlug, slug)
+
+		if uc.canViewPost(ctx, p, viewerID, viewerRole) {
+			visible = append(visible, p)
+		}
+	}
+
+	postResponses, err := uc.mapToPostResponses(ctx, visible)
+	if err != nil {
+		return nil, err
+	}
+	uc.withholdRawContent(ctx, postResponses, viewerID, viewerRole)
+	return &post.PostsBySlugResponse{Posts: postResponses}, nil
+}
+
+// ResolvePost looks a post up by ID when the identifier is numeric, falling back to
+// slug otherwise, and applies the same visibility rules as GetPostByID and GetPostBySlug.
+// A numeric identifier also falls back to slug when the post with that ID can't be seen.
+func (uc *postUseCase) ResolvePost(ctx context.Context, idOrSlug string, incrementView bool, viewerID uint, viewerRole string) (*post.PostResponse, error) {
+	var p *post.Post
+	if id, err := strconv.ParseUint(idOrSlug, 10, 32); err == nil {
+		p, _ = uc.postRepo.GetByID(ctx, uint(id))
+	}
+	if p == nil || !uc.canViewPost(ctx, p, viewerID, viewerRole) {
+		p, _ = uc.postRepo.GetBySlug(ctx, idOrSlug)
+	}
+
+	if p == nil || !uc.canViewPost(ctx, p, viewerID, viewerRole) {
+		return nil, post.ErrNotFound
+	}
+
+	// Increment view count if requested
+	if incrementView {
+		uc.postRepo.IncrementViewCount(ctx, p.ID)
+		p.ViewCount++
+	}
+
+	return uc.mapToViewerResponse(ctx, p, viewerID, viewerRole)
 }
 
 func (uc *postUseCase) UpdatePost(ctx context.Context, id uint, req post.UpdatePostRequest, userID uint, userRole string) (*post.PostResponse, error) {
@@ -124,28 +467,157 @@ func (uc *postUseCase) UpdatePost(ctx context.Context, id uint, req post.UpdateP
 
 	// Check permissions
 	if !uc.canModifyPost(p, userID, userRole) {
-		return nil, errors.New("permission denied")
+		collaborator, _ := uc.postRepo.GetCollaborator(ctx, p.ID, userID)
+		if collaborator == nil || !collaborator.CanEdit() {
+			return nil, post.ErrPermissionDenied
+		}
+		// Collaborators edit the content; publishing, scheduling and visibility stay with the author
+		if req.Status != nil || req.PublishAt != nil || req.IsPublic != nil {
+			return nil, post.ErrPermissionDenied
+		}
 	}
 
 	// Update fields if provided
 	if req.Title != nil {
 		p.Title = *req.Title
-		// Regenerate slug if title changed
-		newSlug := uc.generateSlug(*req.Title)
+	}
+
+	if err := uc.applyUpdate(ctx, p, req, userID); err != nil {
+		return nil, err
+	}
+
+	// Regenerate slug if title changed, from the moderated title
+	if req.Title != nil {
+		newSlug := uc.generateSlug(p.Title)
 		if newSlug != p.Slug {
 			// Check if new slug exists
 			existingPost, _ := uc.postRepo.GetBySlug(ctx, newSlug)
 			if existingPost != nil && existingPost.ID != p.ID {
-				newSlug = fmt.Sprintf("%s-%d", newSlug, time.Now().Unix())
+				newSlug = uc.uniqueSlug(newSlug)
 			}
 			p.Slug = newSlug
 		}
 	}
 
+	// The post and its tags are saved together, so a failed tag write doesn't keep the edit
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context, repos transaction.Repositories) error {
+		var tagIDs []uint
+		if req.Tags != nil {
+			ids, err := uc.resolveTags(ctx, repos.Posts, *req.Tags)
+			if err != nil {
+				return err
+			}
+			tagIDs = ids
+		}
+
+		if err := repos.Posts.Update(ctx, p); err != nil {
+			return wrapError("failed to update post", err)
+		}
+
+		if req.Tags != nil {
+			if err := repos.Posts.SetPostTags(ctx, p.ID, tagIDs); err != nil {
+				return wrapError("failed to tag post", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.mapToPostResponse(ctx, p)
+}
+
+// newPost builds a post from a create request, filling in the defaults for the author's role
+func (uc *postUseCase) newPost(req post.CreatePostRequest, postSlug string, authorID uint, authorRole string) *post.Post {
+	// Set default values
+	status, isPublic := uc.postDefaults(authorRole)
+	if req.Status != nil {
+		status = *req.Status
+	}
+
+	if req.IsPublic != nil {
+		isPublic = *req.IsPublic
+	}
+
+	allowComments := true
+	if req.AllowComments != nil {
+		allowComments = *req.AllowComments
+	}
+
+	contentFormat := uc.cfg.Post.DefaultContentFormat
+	if req.ContentFormat != nil {
+		contentFormat = *req.ContentFormat
+	}
+
+	language := uc.cfg.Post.Languages.Default
+	if req.Language != nil {
+		language = *req.Language
+	}
+
+	newPost := &post.Post{
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: contentFormat,
+		Language:      language,
+		Summary:       req.Summary,
+		Slug:          postSlug,
+		Status:        status,
+		CategoryID:    req.CategoryID,
+		AuthorID:      authorID,
+		FeaturedImg:   req.FeaturedImg,
+		IsPublic:      isPublic,
+		AllowComments: allowComments,
+	}
+
+	// Record who scheduled the post for the editorial queue
+	if req.PublishAt != nil {
+		newPost.PublishAt = req.PublishAt
+		newPost.ScheduledBy = &authorID
+	}
+
+	// Set published_at if status is published
+	if status == "published" {
+		now := time.Now()
+		newPost.PublishedAt = &now
+	}
+
+	return newPost
+}
+
+// applyUpdate sets the fields present in req, except the title, on p and validates the
+// result. userID is recorded as the scheduler when a publish time is set.
+func (uc *postUseCase) applyUpdate(ctx context.Context, p *post.Post, req post.UpdatePostRequest, userID uint) error {
 	if req.Content != nil {
+		if err := uc.validateContent(*req.Content); err != nil {
+			return err
+		}
 		p.Content = *req.Content
 	}
 
+	if req.ContentFormat != nil {
+		p.ContentFormat = *req.ContentFormat
+	}
+
+	// The language picks the word list, so it is set before moderating
+	if req.Language != nil {
+		if err := uc.validateLanguage(req.Language); err != nil {
+			return err
+		}
+		p.Language = *req.Language
+	}
+
+	masked, err := uc.moderate(p)
+	if err != nil {
+		return err
+	}
+
+	if req.Content != nil || req.ContentFormat != nil || masked {
+		if err := uc.renderContent(p); err != nil {
+			return wrapError("failed to render content", err)
+		}
+	}
+
 	if req.Summary != nil {
 		p.Summary = req.Summary
 	}
@@ -162,6 +634,15 @@ func (uc *postUseCase) UpdatePost(ctx context.Context, id uint, req post.UpdateP
 		p.IsPublic = *req.IsPublic
 	}
 
+	if req.AllowComments != nil {
+		p.AllowComments = *req.AllowComments
+	}
+
+	if req.PublishAt != nil {
+		p.PublishAt = req.PublishAt
+		p.ScheduledBy = &userID
+	}
+
 	if req.Status != nil {
 		oldStatus := p.Status
 		p.Status = *req.Status
@@ -171,13 +652,125 @@ func (uc *postUseCase) UpdatePost(ctx context.Context, id uint, req post.UpdateP
 			now := time.Now()
 			p.PublishedAt = &now
 		}
+
+		// Drafts were never published; archived posts keep their publish date
+		if *req.Status == "draft" {
+			p.PublishedAt = nil
+		}
 	}
 
-	if err := uc.postRepo.Update(ctx, p); err != nil {
-		return nil, errors.New("failed to update post")
+	// Re-check the category policy so publishing an uncategorized post is rejected too
+	if req.CategoryID != nil || uc.cfg.Post.RequireCategory {
+		if err := uc.validateCategory(ctx, p.CategoryID); err != nil {
+			return err
+		}
 	}
 
-	return uc.mapToPostResponse(ctx, p)
+	return uc.validatePublishable(p)
+}
+
+// UpsertPostBySlug creates a post under the given slug, or updates the post that already
+// has it, in one transaction. An existing post is only updated when the user may modify it,
+// and its slug is kept even when the title changes. The returned bool is true on create.
+func (uc *postUseCase) UpsertPostBySlug(ctx context.Context, postSlug string, req post.CreatePostRequest, userID uint, userRole string) (*post.PostResponse, bool, error) {
+	// Only slugs in the form the configured generator produces are accepted
+	if postSlug == "" || uc.generateSlug(postSlug) != postSlug {
+		return nil, false, post.ErrInvalidSlug
+	}
+
+	if err := uc.validateContent(req.Content); err != nil {
+		return nil, false, err
+	}
+
+	if err := uc.validateLanguage(req.Language); err != nil {
+		return nil, false, err
+	}
+
+	var result *post.Post
+	created := false
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context, repos transaction.Repositories) error {
+		existing, err := repos.Posts.GetBySlugForUpdate(ctx, postSlug)
+		if err != nil && !errors.Is(err, post.ErrNotFound) {
+			return err
+		}
+
+		if existing == nil {
+			if err := uc.validateCategory(ctx, req.CategoryID); err != nil {
+				return err
+			}
+			if err := uc.checkPostLimit(ctx, repos, userID, userRole); err != nil {
+				return err
+			}
+
+			result = uc.newPost(req, postSlug, userID, userRole)
+			if _, err := uc.moderate(result); err != nil {
+				return err
+			}
+			if err := uc.validatePublishable(result); err != nil {
+				return err
+			}
+			if err := uc.renderContent(result); err != nil {
+				return wrapError("failed to render content", err)
+			}
+			tagIDs, err := uc.resolveTags(ctx, repos.Posts, req.Tags)
+			if err != nil {
+				return err
+			}
+			if err := repos.Posts.Create(ctx, result); err != nil {
+				return wrapError("failed to create post", err)
+			}
+			if err := repos.Posts.SetPostTags(ctx, result.ID, tagIDs); err != nil {
+				return wrapError("failed to tag post", err)
+			}
+			created = true
+			return nil
+		}
+
+		if !uc.canModifyPost(existing, userID, userRole) {
+			return post.ErrPermissionDenied
+		}
+
+		existing.Title = req.Title
+		update := post.UpdatePostRequest{
+			Content:       &req.Content,
+			ContentFormat: req.ContentFormat,
+			Language:      req.Language,
+			Summary:       req.Summary,
+			CategoryID:    req.CategoryID,
+			FeaturedImg:   req.FeaturedImg,
+			IsPublic:      req.IsPublic,
+			AllowComments: req.AllowComments,
+			Status:        req.Status,
+			PublishAt:     req.PublishAt,
+		}
+		if err := uc.applyUpdate(ctx, existing, update, userID); err != nil {
+			return err
+		}
+		if err := repos.Posts.Update(ctx, existing); err != nil {
+			return wrapError("failed to update post", err)
+		}
+		// Omitted tags are left alone; an explicit list, even an empty one, replaces them
+		if req.Tags != nil {
+			tagIDs, err := uc.resolveTags(ctx, repos.Posts, req.Tags)
+			if err != nil {
+				return err
+			}
+			if err := repos.Posts.SetPostTags(ctx, existing.ID, tagIDs); err != nil {
+				return wrapError("failed to tag post", err)
+			}
+		}
+		result = existing
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	response, err := uc.mapToPostResponse(ctx, result)
+	if err != nil {
+		return nil, false, err
+	}
+	return response, created, nil
 }
 
 func (uc *postUseCase) DeletePost(ctx context.Context, id uint, userID uint, userRole string) error {
@@ -188,43 +781,69 @@ func (uc *postUseCase) DeletePost(ctx context.Context, id uint, userID uint, use
 
 	// Check permissions
 	if !uc.canModifyPost(p, userID, userRole) {
-		return errors.New("permission denied")
+		return post.ErrPermissionDenied
 	}
 
 	if err := uc.postRepo.Delete(ctx, id); err != nil {
-		return errors.New("failed to delete post")
+		return wrapError("failed to delete post", err)
 	}
 
 	return nil
 }
 
-func (uc *postUseCase) GetAllPosts(ctx context.Context, filter post.PostFilter, page, limit int) (*post.PostsListResponse, error) {
-	if page < 1 {
-		page = 1
+// GetAllPosts lists the posts matching filter that the viewer may read, see canViewPost
+func (uc *postUseCase) GetAllPosts(ctx context.Context, filter post.PostFilter, viewerID uint, viewerRole string, page, limit int) (*post.PostsListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.validateLanguage(filter.Language); err != nil {
+		return nil, err
 	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+
+	if filter.Search != nil && strings.TrimSpace(*filter.Search) == "" {
+		switch uc.cfg.Post.Search.EmptyQuery {
+		case "none":
+			return &post.PostsListResponse{Posts: []post.PostResponse{}, Page: page, Limit: limit}, nil
+		case "reject":
+			return nil, fmt.Errorf("%w: search query is empty", post.ErrInvalidSearch)
+		default:
+			filter.Search = nil
+		}
 	}
 
-	offset := (page - 1) * limit
+	if filter.Search != nil {
+		search := uc.cfg.Post.Search
+		terms, err := post.SearchTerms(*filter.Search, search.StopWords, search.MinTermLength)
+		if err != nil {
+			return nil, err
+		}
+		filter.SearchTerms = terms
+	}
+	filter.VisibleTo = uc.listingViewer(viewerID, viewerRole)
 
 	posts, err := uc.postRepo.GetAll(ctx, filter, limit, offset)
 	if err != nil {
-		return nil, errors.New("failed to fetch posts")
+		return nil, wrapError("failed to fetch posts", err)
 	}
 
 	total, err := uc.postRepo.GetTotalCount(ctx, filter)
 	if err != nil {
-		return nil, errors.New("failed to count posts")
+		return nil, wrapError("failed to count posts", err)
 	}
 
-	postResponses := make([]post.PostResponse, len(posts))
-	for i, p := range posts {
-		response, err := uc.mapToPostResponse(ctx, p)
-		if err != nil {
-			continue // Skip if error mapping
+	postResponses, err := uc.mapToPostResponses(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	uc.withholdRawContent(ctx, postResponses, viewerID, viewerRole)
+	if len(filter.SearchTerms) > 0 {
+		for i, p := range posts {
+			postResponses[i].Snippet = snippet.Generate(p.Content, filter.SearchTerms[0], uc.cfg.Post.Search.SnippetRadius)
 		}
-		postResponses[i] = *response
 	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
@@ -239,25 +858,98 @@ func (uc *postUseCase) GetAllPosts(ctx context.Context, filter post.PostFilter,
 }
 
 func (uc *postUseCase) GetMyPosts(ctx context.Context, authorID uint, page, limit int) (*post.PostsListResponse, error) {
-	filter := post.PostFilter{
-		AuthorID: &authorID,
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := uc.postRepo.GetByAuthor(ctx, authorID, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch posts", err)
+	}
+
+	total, err := uc.postRepo.CountByAuthor(ctx, authorID)
+	if err != nil {
+		return nil, wrapError("failed to count posts", err)
+	}
+
+	postResponses, err := uc.mapToPostResponses(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &post.PostsListResponse{
+		Posts:      postResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (uc *postUseCase) GetAuthorStats(ctx context.Context, authorID uint) (*post.AuthorStats, error) {
+	total, err := uc.postRepo.CountByAuthor(ctx, authorID)
+	if err != nil {
+		return nil, wrapError("failed to count posts", err)
+	}
+
+	published, err := uc.postRepo.CountPublishedByAuthor(ctx, authorID)
+	if err != nil {
+		return nil, wrapError("failed to count published posts", err)
+	}
+
+	return &post.AuthorStats{
+		TotalPosts:     total,
+		PublishedPosts: published,
+	}, nil
+}
+
+// GetAuthorTags lists the tags of an author's published public posts with their usage
+// counts, most used first. Drafts and private posts don't count, so the list never reveals
+// unpublished work. A limit of 0 returns every tag.
+func (uc *postUseCase) GetAuthorTags(ctx context.Context, authorID uint, limit int) (*post.AuthorTagsResponse, error) {
+	if _, err := uc.userRepo.GetByID(ctx, authorID); err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil, err
+		}
+		return nil, wrapError("failed to fetch author", err)
+	}
+
+	tags, err := uc.postRepo.GetAuthorTags(ctx, authorID, limit)
+	if err != nil {
+		return nil, wrapError("failed to fetch author tags", err)
 	}
-	return uc.GetAllPosts(ctx, filter, page, limit)
+
+	return &post.AuthorTagsResponse{AuthorID: authorID, Tags: tags}, nil
 }
 
-func (uc *postUseCase) GetPublishedPosts(ctx context.Context, page, limit int) (*post.PostsListResponse, error) {
-	if page < 1 {
-		page = 1
+// GetPublishedPosts lists public published posts in the given order, or the configured
+// default order when sort is empty
+func (uc *postUseCase) GetPublishedPosts(ctx context.Context, sort, language string, page, limit int) (*post.PostsListResponse, error) {
+	sort, err := uc.publishedSort(sort)
+	if err != nil {
+		return nil, err
 	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+	if language != "" {
+		if err := uc.validateLanguage(&language); err != nil {
+			return nil, err
+		}
 	}
 
-	offset := (page - 1) * limit
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
 
-	posts, err := uc.postRepo.GetPublished(ctx, limit, offset)
+	posts, err := uc.postRepo.GetPublished(ctx, sort, language, limit, offset)
 	if err != nil {
-		return nil, errors.New("failed to fetch published posts")
+		return nil, wrapError("failed to fetch published posts", err)
 	}
 
 	// Get total count for published posts
@@ -267,20 +959,128 @@ func (uc *postUseCase) GetPublishedPosts(ctx context.Context, page, limit int) (
 		Status:   &publishedStatus,
 		IsPublic: &isPublic,
 	}
+	if language != "" {
+		filter.Language = &language
+	}
 	total, err := uc.postRepo.GetTotalCount(ctx, filter)
 	if err != nil {
-		return nil, errors.New("failed to count published posts")
+		return nil, wrapError("failed to count published posts", err)
 	}
 
-	postResponses := make([]post.PostResponse, len(posts))
-	for i, p := range posts {
-		response, err := uc.mapToPostResponse(ctx, p)
-		if err != nil {
-			continue
+	postResponses, err := uc.mapToPostResponses(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	uc.withholdRawContent(ctx, postResponses, 0, "")
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &post.PostsListResponse{
+		Posts:      postResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetPublishedPostsCursor returns the page of published posts after cursorID, newest first,
+// for infinite scrolling. One extra post is fetched to tell whether another page follows.
+func (uc *postUseCase) GetPublishedPostsCursor(ctx context.Context, cursorID uint, language string, limit int) (*post.PublishedCursorResponse, error) {
+	if language != "" {
+		if err := uc.validateLanguage(&language); err != nil {
+			return nil, err
 		}
-		postResponses[i] = *response
 	}
 
+	_, limit = normalizePagination(1, limit)
+
+	posts, err := uc.postRepo.GetPublishedAfter(ctx, cursorID, language, limit+1)
+	if err != nil {
+		return nil, wrapError("failed to fetch published posts", err)
+	}
+
+	hasMore := len(posts) > limit
+	if hasMore {
+		posts = posts[:limit]
+	}
+
+	postResponses, err := uc.mapToPostResponses(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	uc.withholdRawContent(ctx, postResponses, 0, "")
+
+	resp := &post.PublishedCursorResponse{
+		Posts: postResponses,
+		Limit: limit,
+	}
+	if hasMore {
+		next := posts[len(posts)-1].ID
+		resp.NextCursor = &next
+	}
+	return resp, nil
+}
+
+// GetPublishedLastModified returns when the published listing in the given order last
+// changed. It covers every post, not just those on one page, since adding, unpublishing or
+// deleting a post shifts the pages after it. Orders by views have no time, nil, as view
+// counts change without touching updated_at.
+func (uc *postUseCase) GetPublishedLastModified(ctx context.Context, sort string) (*time.Time, error) {
+	sort, err := uc.publishedSort(sort)
+	if err != nil {
+		return nil, err
+	}
+	if sort != post.SortPublishedAt {
+		return nil, nil
+	}
+
+	lastModified, err := uc.postRepo.GetPublishedLastModified(ctx)
+	if err != nil {
+		return nil, wrapError("failed to fetch last modified time", err)
+	}
+
+	return lastModified, nil
+}
+
+// GetArchive returns the number of published public posts per month, newest first
+func (uc *postUseCase) GetArchive(ctx context.Context) ([]*post.ArchiveMonth, error) {
+	months, err := uc.postRepo.GetArchiveCounts(ctx)
+	if err != nil {
+		return nil, wrapError("failed to fetch archive", err)
+	}
+	return months, nil
+}
+
+// GetArchivePosts returns the published public posts of one month
+func (uc *postUseCase) GetArchivePosts(ctx context.Context, year, month, page, limit int) (*post.PostsListResponse, error) {
+	if year < 1 || year > 9999 || month < 1 || month > 12 {
+		return nil, post.ErrInvalidArchiveMonth
+	}
+
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := uc.postRepo.GetPublishedInMonth(ctx, year, month, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch posts", err)
+	}
+
+	total, err := uc.postRepo.CountPublishedInMonth(ctx, year, month)
+	if err != nil {
+		return nil, wrapError("failed to count posts", err)
+	}
+
+	postResponses, err := uc.mapToPostResponses(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	uc.withholdRawContent(ctx, postResponses, 0, "")
+
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
 
 	return &post.PostsListResponse{
@@ -292,6 +1092,37 @@ func (uc *postUseCase) GetPublishedPosts(ctx context.Context, page, limit int) (
 	}, nil
 }
 
+// GetPostsByCategorySlug lists the public published posts of an active category
+func (uc *postUseCase) GetPostsByCategorySlug(ctx context.Context, categorySlug string, page, limit int) (*post.PostsListResponse, error) {
+	c, err := uc.categoryRepo.GetBySlug(ctx, categorySlug)
+	if err != nil {
+		return nil, err
+	}
+	if !c.IsActive {
+		return nil, product.ErrCategoryNotFound
+	}
+
+	publishedStatus := "published"
+	isPublic := true
+	filter := post.PostFilter{
+		Status:     &publishedStatus,
+		IsPublic:   &isPublic,
+		CategoryID: &c.ID,
+	}
+	return uc.GetAllPosts(ctx, filter, 0, "", page, limit)
+}
+
+// publishedSort resolves the requested published posts order against the configured default
+func (uc *postUseCase) publishedSort(sort string) (string, error) {
+	if sort == "" {
+		return uc.cfg.Post.DefaultSort, nil
+	}
+	if !post.IsValidSort(sort) {
+		return "", post.ErrInvalidSort
+	}
+	return sort, nil
+}
+
 func (uc *postUseCase) PublishPost(ctx context.Context, id uint, userID uint, userRole string) (*post.PostResponse, error) {
 	req := post.UpdatePostRequest{
 		Status: stringPtr("published"),
@@ -299,81 +1130,997 @@ func (uc *postUseCase) PublishPost(ctx context.Context, id uint, userID uint, us
 	return uc.UpdatePost(ctx, id, req, userID, userRole)
 }
 
-func (uc *postUseCase) UnpublishPost(ctx context.Context, id uint, userID uint, userRole string) (*post.PostResponse, error) {
+func (uc *postUseCase) UnpublishPost(ctx context.Context, id uint, userID uint, userRole string, targetStatus string) (*post.PostResponse, error) {
+	if targetStatus == "" {
+		targetStatus = uc.cfg.Post.UnpublishStatus
+	}
+	if targetStatus != "draft" && targetStatus != "archived" {
+		return nil, post.ErrInvalidUnpublishStatus
+	}
+
 	req := post.UpdatePostRequest{
-		Status: stringPtr("draft"),
+		Status: stringPtr(targetStatus),
 	}
 	return uc.UpdatePost(ctx, id, req, userID, userRole)
 }
 
-// Helper functions
-func (uc *postUseCase) generateSlug(title string) string {
-	// Convert to lowercase
-	slug := strings.ToLower(title)
+func (uc *postUseCase) GetTrashedPosts(ctx context.Context, page, limit int) (*post.TrashedPostsListResponse, error) {
+	page, limit = normalizePagination(page, limit)
 
-	// Replace spaces and special characters with hyphens
-	reg := regexp.MustCompile(`[^a-z0-9]+`)
-	slug = reg.ReplaceAllString(slug, "-")
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
 
-	// Remove leading and trailing hyphens
-	slug = strings.Trim(slug, "-")
+	posts, err := uc.postRepo.GetDeleted(ctx, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch trashed posts", err)
+	}
 
-	// Limit length
-	if len(slug) > 100 {
-		slug = slug[:100]
+	total, err := uc.postRepo.GetDeletedCount(ctx)
+	if err != nil {
+		return nil, wrapError("failed to count trashed posts", err)
 	}
 
-	return slug
+	return uc.mapToTrashedList(ctx, posts, total, page, limit)
 }
 
-func (uc *postUseCase) canModifyPost(p *post.Post, userID uint, userRole string) bool {
-	// Admin can modify any post
-	if userRole == "admin" {
-		return true
+// GetMyTrashedPosts lists the author's own soft-deleted posts
+func (uc *postUseCase) GetMyTrashedPosts(ctx context.Context, authorID uint, page, limit int) (*post.TrashedPostsListResponse, error) {
+	if !uc.cfg.Post.AuthorTrash {
+		return nil, post.ErrPermissionDenied
 	}
 
-	// Author can modify their own post
-	return p.AuthorID == userID
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := uc.postRepo.GetDeletedByAuthor(ctx, authorID, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch trashed posts", err)
+	}
+
+	total, err := uc.postRepo.GetDeletedByAuthorCount(ctx, authorID)
+	if err != nil {
+		return nil, wrapError("failed to count trashed posts", err)
+	}
+
+	return uc.mapToTrashedList(ctx, posts, total, page, limit)
 }
 
-func (uc *postUseCase) mapToPostResponse(ctx context.Context, p *post.Post) (*post.PostResponse, error) {
-	// Get author name
-	author, err := uc.userRepo.GetByID(ctx, p.AuthorID)
-	authorName := "Unknown"
-	if err == nil && author != nil {
-		authorName = author.Name
-	}
-
-	// Handle nil pointers
-	summary := ""
-	if p.Summary != nil {
-		summary = *p.Summary
-	}
-
-	featuredImg := ""
-	if p.FeaturedImg != nil {
-		featuredImg = *p.FeaturedImg
-	}
-
-	return &post.PostResponse{
-		ID:          p.ID,
-		Title:       p.Title,
-		Content:     p.Content,
-		Summary:     summary,
-		Slug:        p.Slug,
-		Status:      p.Status,
-		CategoryID:  p.CategoryID,
-		AuthorID:    p.AuthorID,
-		AuthorName:  authorName,
-		FeaturedImg: featuredImg,
-		ViewCount:   p.ViewCount,
-		IsPublic:    p.IsPublic,
-		PublishedAt: p.PublishedAt,
-		CreatedAt:   p.CreatedAt,
-		UpdatedAt:   p.UpdatedAt,
+// RestorePost brings back a soft-deleted post. Admins may restore any post, authors only
+// their own. The slug is re-checked since another post may have taken it meanwhile.
+func (uc *postUseCase) RestorePost(ctx context.Context, id uint, userID uint, userRole string) (*post.PostResponse, error) {
+	p, err := uc.postRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole != user.RoleAdmin && (!uc.cfg.Post.AuthorTrash || p.AuthorID != userID) {
+		return nil, post.ErrPermissionDenied
+	}
+
+	existingPost, _ := uc.postRepo.GetBySlug(ctx, p.Slug)
+	if existingPost != nil && existingPost.ID != p.ID {
+		p.Slug = uc.uniqueSlug(p.Slug)
+	}
+
+	if err := uc.postRepo.Restore(ctx, p.ID, p.Slug); err != nil {
+		return nil, wrapError("failed to restore post", err)
+	}
+
+	restored, err := uc.postRepo.GetByID(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.mapToPostResponse(ctx, restored)
+}
+
+// mapToTrashedList builds a paginated trash listing
+func (uc *postUseCase) mapToTrashedList(ctx context.Context, posts []*post.Post, total int64, page, limit int) (*post.TrashedPostsListResponse, error) {
+	responses, err := uc.mapToPostResponses(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	postResponses := make([]post.TrashedPostResponse, len(posts))
+	for i, p := range posts {
+		postResponses[i] = post.TrashedPostResponse{
+			PostResponse: responses[i],
+			DeletedAt:    p.DeletedAt.Time,
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &post.TrashedPostsListResponse{
+		Posts:      postResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetPostChanges returns one page of the incremental sync feed. One extra post is fetched
+// to tell whether another page follows; the cursor points at the last change returned.
+func (uc *postUseCase) GetPostChanges(ctx context.Context, filter post.ChangesFilter, limit int) (*post.PostChangesResponse, error) {
+	_, limit = normalizePagination(1, limit)
+
+	posts, err := uc.postRepo.GetChanges(ctx, filter, limit+1)
+	if err != nil {
+		return nil, wrapError("failed to fetch post changes", err)
+	}
+
+	hasMore := len(posts) > limit
+	if hasMore {
+		posts = posts[:limit]
+	}
+
+	responses, err := uc.mapToPostResponses(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]post.PostChange, 0, len(posts))
+	var cursor post.ChangesCursor
+	for i, p := range posts {
+		change := post.PostChange{PostResponse: responses[i]}
+		cursor = post.ChangesCursor{ChangedAt: p.UpdatedAt, ID: p.ID}
+		if p.DeletedAt.Valid {
+			deletedAt := p.DeletedAt.Time
+			change.Deleted = true
+			change.DeletedAt = &deletedAt
+			cursor.ChangedAt = deletedAt
+		}
+		changes = append(changes, change)
+	}
+
+	resp := &post.PostChangesResponse{
+		Changes: changes,
+		HasMore: hasMore,
+	}
+	if len(posts) > 0 {
+		resp.NextCursor = cursor.Encode()
+	}
+	return resp, nil
+}
+
+func (uc *postUseCase) GetScheduledPosts(ctx context.Context, filter post.ScheduledFilter, page, limit int) (*post.ScheduledPostsListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := uc.postRepo.GetScheduled(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch scheduled posts", err)
+	}
+
+	total, err := uc.postRepo.GetScheduledCount(ctx, filter)
+	if err != nil {
+		return nil, wrapError("failed to count scheduled posts", err)
+	}
+
+	var userIDs []uint
+	for _, p := range posts {
+		userIDs = append(userIDs, p.AuthorID)
+		if p.ScheduledBy != nil {
+			userIDs = append(userIDs, *p.ScheduledBy)
+		}
+	}
+	names, err := uc.userNames(ctx, userIDs)
+	if err != nil {
+		return nil, wrapError("failed to fetch post authors", err)
+	}
+
+	postResponses := make([]post.ScheduledPostResponse, len(posts))
+	for i, p := range posts {
+		postResponses[i] = post.ScheduledPostResponse{
+			ID:          p.ID,
+			Title:       p.Title,
+			Slug:        p.Slug,
+			AuthorID:    p.AuthorID,
+			AuthorName:  names[p.AuthorID],
+			PublishAt:   *p.PublishAt,
+			ScheduledBy: p.ScheduledBy,
+		}
+		if p.ScheduledBy != nil {
+			postResponses[i].ScheduledByName = names[*p.ScheduledBy]
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &post.ScheduledPostsListResponse{
+		Posts:      postResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
 	}, nil
 }
 
+// TouchPost bumps a post's updated_at so it resurfaces as recently updated, leaving its content untouched
+func (uc *postUseCase) TouchPost(ctx context.Context, id uint, userID uint, userRole string) (*post.PostResponse, error) {
+	p, err := uc.postRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check permissions
+	if !uc.canModifyPost(p, userID, userRole) {
+		return nil, post.ErrPermissionDenied
+	}
+
+	if err := uc.postRepo.Touch(ctx, id); err != nil {
+		return nil, wrapError("failed to touch post", err)
+	}
+
+	p, err = uc.postRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.mapToPostResponse(ctx, p)
+}
+
+func (uc *postUseCase) ResetViewCount(ctx context.Context, id uint) error {
+	if _, err := uc.postRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if _, err := uc.postRepo.ResetViewCounts(ctx, []uint{id}); err != nil {
+		return wrapError("failed to reset view count", err)
+	}
+
+	return nil
+}
+
+func (uc *postUseCase) ResetViewCounts(ctx context.Context, ids []uint) (*post.ResetViewsResponse, error) {
+	reset, err := uc.postRepo.ResetViewCounts(ctx, ids)
+	if err != nil {
+		return nil, wrapError("failed to reset view counts", err)
+	}
+
+	return &post.ResetViewsResponse{Reset: reset}, nil
+}
+
+// BulkUpdateVisibility sets is_public on each post in one transaction. Posts that are missing
+// or that the user may not modify are reported per ID without affecting the others; a
+// database error rolls the whole batch back.
+func (uc *postUseCase) BulkUpdateVisibility(ctx context.Context, ids []uint, isPublic bool, userID uint, userRole string) (*post.BulkVisibilityResponse, error) {
+	var response *post.BulkVisibilityResponse
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context, repos transaction.Repositories) error {
+		response = &post.BulkVisibilityResponse{Results: make([]post.BulkVisibilityResult, 0, len(ids))}
+		seen := make(map[uint]bool, len(ids))
+
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			result := post.BulkVisibilityResult{PostID: id}
+			p, err := repos.Posts.GetByID(ctx, id)
+			if err != nil && !errors.Is(err, post.ErrNotFound) {
+				return err
+			}
+
+			if p == nil {
+				result.Error = post.ErrNotFound.Error()
+			} else if !uc.canModifyPost(p, userID, userRole) {
+				result.Error = post.ErrPermissionDenied.Error()
+			} else if p.IsPublic != isPublic {
+				p.IsPublic = isPublic
+				if err := repos.Posts.Update(ctx, p); err != nil {
+					return wrapError("failed to update post", err)
+				}
+				result.Changed = true
+			}
+
+			if result.Error != "" {
+				response.Failed++
+			} else if result.Changed {
+				response.Changed++
+			}
+			response.Results = append(response.Results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetPostsNeedingAttention lists posts matching an editorial attention rule. days is the
+// age threshold for stale drafts and unviewed posts; non-positive values use the default.
+func (uc *postUseCase) GetPostsNeedingAttention(ctx context.Context, rule string, days int, page, limit int) (*post.PostsListResponse, error) {
+	switch rule {
+	case post.AttentionStaleDrafts:
+		if days < 1 {
+			days = 30
+		}
+	case post.AttentionNoViews:
+		if days < 1 {
+			days = 7
+		}
+	case post.AttentionIncomplete, post.AttentionFlagged:
+	default:
+		return nil, post.ErrInvalidAttentionRule
+	}
+
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := post.AttentionFilter{
+		Rule:   rule,
+		Before: time.Now().AddDate(0, 0, -days),
+	}
+
+	posts, err := uc.postRepo.GetNeedingAttention(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch posts", err)
+	}
+
+	total, err := uc.postRepo.GetNeedingAttentionCount(ctx, filter)
+	if err != nil {
+		return nil, wrapError("failed to count posts", err)
+	}
+
+	postResponses, err := uc.mapToPostResponses(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &post.PostsListResponse{
+		Posts:      postResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// staleDraftBatchSize caps how many drafts one cleanup run touches
+const staleDraftBatchSize = 100
+
+// CleanupStaleDrafts archives or soft-deletes a batch of drafts untouched for the configured
+// period. It returns the matched post IDs and how many were changed; in dry-run mode nothing
+// is changed.
+func (uc *postUseCase) CleanupStaleDrafts(ctx context.Context) ([]uint, int64, error) {
+	archival := uc.cfg.Post.DraftArchival
+
+	filter := post.AttentionFilter{
+		Rule:   post.AttentionStaleDrafts,
+		Before: time.Now().AddDate(0, 0, -archival.AfterDays),
+	}
+
+	drafts, err := uc.postRepo.GetNeedingAttention(ctx, filter, staleDraftBatchSize, 0)
+	if err != nil {
+		return nil, 0, wrapError("failed to fetch stale drafts", err)
+	}
+	if len(drafts) == 0 {
+		return nil, 0, nil
+	}
+
+	ids := make([]uint, len(drafts))
+	for i, p := range drafts {
+		ids[i] = p.ID
+	}
+
+	if archival.DryRun {
+		return ids, 0, nil
+	}
+
+	var affected int64
+	if archival.Action == "delete" {
+		affected, err = uc.postRepo.DeleteStaleDrafts(ctx, ids, filter.Before)
+	} else {
+		affected, err = uc.postRepo.ArchiveStaleDrafts(ctx, ids, filter.Before)
+	}
+	if err != nil {
+		return ids, 0, wrapError("failed to clean up stale drafts", err)
+	}
+
+	return ids, affected, nil
+}
+
+// scheduledPublishBatchSize caps how many due drafts one publishing run handles
+const scheduledPublishBatchSize = 100
+
+// PublishDuePosts publishes a batch of drafts whose publish_at has passed. Drafts that do
+// not meet the publish requirements or the category policy stay drafts, are unscheduled so
+// they don't hold up later runs, and are reported as skipped.
+func (uc *postUseCase) PublishDuePosts(ctx context.Context) (*post.ScheduledPublishResult, error) {
+	now := time.Now()
+
+	due, err := uc.postRepo.GetDuePosts(ctx, now, scheduledPublishBatchSize)
+	if err != nil {
+		return nil, wrapError("failed to fetch due posts", err)
+	}
+
+	result := &post.ScheduledPublishResult{}
+	for _, p := range due {
+		// The category may have been deactivated, or made required, since the post was scheduled
+		err := uc.validateCategory(ctx, p.CategoryID)
+		if err == nil {
+			p.Status = "published"
+			err = uc.validatePublishable(p)
+		}
+		// A failed category lookup is retried on the next run rather than unscheduling the post
+		var cause *causeError
+		if errors.As(err, &cause) {
+			return result, err
+		}
+		if err != nil {
+			if err := uc.postRepo.Unschedule(ctx, p.ID, now); err != nil {
+				return result, wrapError("failed to unschedule post", err)
+			}
+			result.Skipped = append(result.Skipped, p.ID)
+			continue
+		}
+
+		published, err := uc.postRepo.PublishScheduled(ctx, p.ID, now)
+		if err != nil {
+			return result, wrapError("failed to publish scheduled post", err)
+		}
+		if published {
+			result.Published = append(result.Published, p.ID)
+		}
+	}
+
+	return result, nil
+}
+
+// AddCollaborator gives a user view or edit access to a post, or changes the access of an
+// existing collaborator. Only the author, editors and admins manage collaborators.
+func (uc *postUseCase) AddCollaborator(ctx context.Context, postID uint, req post.AddCollaboratorRequest, userID uint, userRole string) (*post.CollaboratorResponse, error) {
+	p, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !uc.canModifyPost(p, userID, userRole) {
+		return nil, post.ErrPermissionDenied
+	}
+
+	if req.UserID == p.AuthorID {
+		return nil, post.ErrAuthorIsCollaborator
+	}
+
+	u, err := uc.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, user.ErrNotFound
+	}
+
+	collaborator := &post.Collaborator{
+		PostID:     p.ID,
+		UserID:     u.ID,
+		Permission: req.Permission,
+	}
+	if err := uc.postRepo.SaveCollaborator(ctx, collaborator); err != nil {
+		return nil, wrapError("failed to save collaborator", err)
+	}
+
+	// Re-read so an updated collaborator reports when it was first added
+	saved, err := uc.postRepo.GetCollaborator(ctx, p.ID, u.ID)
+	if err != nil {
+		return nil, wrapError("failed to fetch collaborator", err)
+	}
+
+	return &post.CollaboratorResponse{
+		UserID:     u.ID,
+		Name:       u.Name,
+		Permission: saved.Permission,
+		CreatedAt:  saved.CreatedAt,
+	}, nil
+}
+
+// RemoveCollaborator revokes a collaborator's access. Collaborators may also remove themselves.
+func (uc *postUseCase) RemoveCollaborator(ctx context.Context, postID, collaboratorID uint, userID uint, userRole string) error {
+	p, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	if collaboratorID != userID && !uc.canModifyPost(p, userID, userRole) {
+		return post.ErrPermissionDenied
+	}
+
+	return uc.postRepo.DeleteCollaborator(ctx, p.ID, collaboratorID)
+}
+
+// GetCollaborators lists a post's collaborators to its author, editors, admins and collaborators
+func (uc *postUseCase) GetCollaborators(ctx context.Context, postID uint, userID uint, userRole string) ([]post.CollaboratorResponse, error) {
+	p, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators, err := uc.postRepo.GetCollaborators(ctx, p.ID)
+	if err != nil {
+		return nil, wrapError("failed to fetch collaborators", err)
+	}
+
+	if !uc.canModifyPost(p, userID, userRole) {
+		isCollaborator := false
+		for _, collaborator := range collaborators {
+			if collaborator.UserID == userID {
+				isCollaborator = true
+				break
+			}
+		}
+		if !isCollaborator {
+			return nil, post.ErrPermissionDenied
+		}
+	}
+
+	ids := make([]uint, len(collaborators))
+	for i, collaborator := range collaborators {
+		ids[i] = collaborator.UserID
+	}
+	users, err := uc.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, wrapError("failed to fetch users", err)
+	}
+	names := make(map[uint]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+
+	responses := make([]post.CollaboratorResponse, len(collaborators))
+	for i, collaborator := range collaborators {
+		responses[i] = post.CollaboratorResponse{
+			UserID:     collaborator.UserID,
+			Name:       names[collaborator.UserID],
+			Permission: collaborator.Permission,
+			CreatedAt:  collaborator.CreatedAt,
+		}
+	}
+
+	return responses, nil
+}
+
+// GetSharedWithMe lists the posts the user has been added to as a collaborator
+func (uc *postUseCase) GetSharedWithMe(ctx context.Context, userID uint, page, limit int) (*post.PostsListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := uc.postRepo.GetSharedWith(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch posts", err)
+	}
+
+	total, err := uc.postRepo.CountSharedWith(ctx, userID)
+	if err != nil {
+		return nil, wrapError("failed to count posts", err)
+	}
+
+	postResponses, err := uc.mapToPostResponses(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	uc.withholdRawContent(ctx, postResponses, userID, "")
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &post.PostsListResponse{
+		Posts:      postResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Helper functions
+func (uc *postUseCase) generateSlug(title string) string {
+	return slug.Generate(title, slug.Options{
+		Separator: uc.cfg.Post.Slug.Separator,
+		Lowercase: uc.cfg.Post.Slug.Lowercase,
+		MaxLength: 100,
+	})
+}
+
+// validateContent rejects content that is not valid UTF-8 or exceeds the configured size
+func (uc *postUseCase) validateContent(content string) error {
+	if !utf8.ValidString(content) {
+		return post.ErrContentNotUTF8
+	}
+	if len(content) > uc.cfg.Post.MaxContentBytes {
+		return post.ErrContentTooLarge
+	}
+	return nil
+}
+
+// validatePublishable checks a published post against post.publish_requirements. Drafts and
+// archived posts may be incomplete.
+func (uc *postUseCase) validatePublishable(p *post.Post) error {
+	if p.Status != "published" {
+		return nil
+	}
+
+	requirements := uc.cfg.Post.PublishRequirements
+	var problems []string
+	if length := utf8.RuneCountInString(strings.TrimSpace(p.Content)); length < requirements.MinContentLength {
+		problems = append(problems, fmt.Sprintf("content must be at least %d characters", requirements.MinContentLength))
+	}
+	if requirements.Summary && (p.Summary == nil || strings.TrimSpace(*p.Summary) == "") {
+		problems = append(problems, "summary is required")
+	}
+	if requirements.FeaturedImage && (p.FeaturedImg == nil || strings.TrimSpace(*p.FeaturedImg) == "") {
+		problems = append(problems, "featured image is required")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", post.ErrNotPublishable, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// moderate applies the configured action to blocked words in the post's title and content,
+// using the word list of the post's language. It reports whether the text was masked.
+func (uc *postUseCase) moderate(p *post.Post) (bool, error) {
+	filter := uc.wordFilters[p.Language]
+	if filter == nil {
+		p.Flagged = false
+		return false, nil
+	}
+
+	switch uc.cfg.Moderation.Action {
+	case moderation.ActionMask:
+		title, content := filter.Mask(p.Title), filter.Mask(p.Content)
+		masked := title != p.Title || content != p.Content
+		p.Title, p.Content = title, content
+		return masked, nil
+	case moderation.ActionFlag:
+		p.Flagged = len(filter.Find(p.Title)) > 0 || len(filter.Find(p.Content)) > 0
+		return false, nil
+	default:
+		if words := append(filter.Find(p.Title), filter.Find(p.Content)...); len(words) > 0 {
+			slices.Sort(words)
+			return false, fmt.Errorf("%w: %s", post.ErrBlockedWords, strings.Join(slices.Compact(words), ", "))
+		}
+		return false, nil
+	}
+}
+
+// validateLanguage checks that a language, when given, is one of the supported languages
+func (uc *postUseCase) validateLanguage(language *string) error {
+	if language != nil && !slices.Contains(uc.cfg.Post.Languages.Supported, *language) {
+		return post.ErrUnsupportedLanguage
+	}
+	return nil
+}
+
+// validateCategory enforces the category policy: when set, the category must exist and be
+// active, and when the policy requires a category it must be set
+func (uc *postUseCase) validateCategory(ctx context.Context, categoryID *uint) error {
+	if categoryID == nil {
+		if uc.cfg.Post.RequireCategory {
+			return post.ErrCategoryRequired
+		}
+		return nil
+	}
+
+	c, err := uc.categoryRepo.GetByID(ctx, *categoryID)
+	if errors.Is(err, product.ErrCategoryNotFound) {
+		return post.ErrInvalidCategory
+	}
+	if err != nil {
+		return wrapError("failed to fetch category", err)
+	}
+	if !c.IsActive {
+		return post.ErrInvalidCategory
+	}
+
+	return nil
+}
+
+// resolveTags validates tag names against the configured limits and returns the IDs of
+// the matching tags, creating missing ones
+func (uc *postUseCase) resolveTags(ctx context.Context, posts post.Repository, names []string) ([]uint, error) {
+	names, err := post.NormalizeTags(names, uc.cfg.Post.Tags.MaxPerPost, uc.cfg.Post.Tags.MaxLength)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	tags, err := posts.UpsertTags(ctx, names)
+	if err != nil {
+		return nil, wrapError("failed to save tags", err)
+	}
+
+	ids := make([]uint, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	return ids, nil
+}
+
+// checkPostLimit enforces post.max_posts_per_author for users other than editors and admins.
+// It runs in the transaction creating the post and locks the author's row, so concurrent
+// creates by one author are counted one after the other.
+func (uc *postUseCase) checkPostLimit(ctx context.Context, repos transaction.Repositories, authorID uint, authorRole string) error {
+	limit := uc.cfg.Post.MaxPostsPerAuthor
+	if limit == 0 || authorRole == user.RoleAdmin || authorRole == user.RoleEditor {
+		return nil
+	}
+
+	if _, err := repos.Users.GetByIDForUpdate(ctx, authorID); err != nil {
+		return wrapError("failed to lock author", err)
+	}
+
+	count, err := repos.Posts.GetTotalCount(ctx, post.PostFilter{AuthorID: &authorID})
+	if err != nil {
+		return wrapError("failed to count posts", err)
+	}
+	if count >= int64(limit) {
+		return post.ErrPostLimitReached
+	}
+
+	return nil
+}
+
+// postDefaults returns the configured status and visibility for a new post by the given role
+func (uc *postUseCase) postDefaults(role string) (string, bool) {
+	defaults := uc.cfg.Post.Defaults
+	status, isPublic := defaults.Status, defaults.IsPublic
+
+	if roleDefaults, ok := defaults.Roles[role]; ok {
+		if roleDefaults.Status != nil {
+			status = *roleDefaults.Status
+		}
+		if roleDefaults.IsPublic != nil {
+			isPublic = *roleDefaults.IsPublic
+		}
+	}
+
+	return status, isPublic
+}
+
+// uniqueSlug appends a timestamp to a slug that is already taken
+func (uc *postUseCase) uniqueSlug(s string) string {
+	return fmt.Sprintf("%s%s%d", s, uc.cfg.Post.Slug.Separator, time.Now().Unix())
+}
+
+// canViewPost applies read visibility. Anonymous viewers only see published public posts;
+// published private posts follow the post.private_visibility policy. Drafts and archived
+// posts are only visible to those who can modify the post and its collaborators.
+func (uc *postUseCase) canViewPost(ctx context.Context, p *post.Post, viewerID uint, viewerRole string) bool {
+	if p.Status == "published" && p.IsPublic {
+		return true
+	}
+	if viewerID == 0 {
+		return false
+	}
+	if p.Status == "published" && uc.cfg.Post.PrivateVisibility != "owner" {
+		return true
+	}
+	if uc.canModifyPost(p, viewerID, viewerRole) {
+		return true
+	}
+	collaborator, _ := uc.postRepo.GetCollaborator(ctx, p.ID, viewerID)
+	return collaborator != nil
+}
+
+// listingViewer returns the restriction that gives a post listing the read visibility of
+// canViewPost. Admins read every post.
+func (uc *postUseCase) listingViewer(viewerID uint, viewerRole string) *post.Viewer {
+	if viewerRole == user.RoleAdmin {
+		return nil
+	}
+	return &post.Viewer{
+		UserID:           viewerID,
+		PrivatePublished: viewerID != 0 && uc.cfg.Post.PrivateVisibility != "owner",
+	}
+}
+
+func (uc *postUseCase) canModifyPost(p *post.Post, userID uint, userRole string) bool {
+	// Admin can modify any post
+	if userRole == user.RoleAdmin {
+		return true
+	}
+
+	// Author can modify their own post
+	return p.AuthorID == userID
+}
+
+// userNames loads the display names of the given users in one query. Users that no longer
+// exist are reported as "Unknown".
+func (uc *postUseCase) userNames(ctx context.Context, userIDs []uint) (map[uint]string, error) {
+	names := make(map[uint]string, len(userIDs))
+	var missing []uint
+	for _, id := range userIDs {
+		if _, ok := names[id]; !ok {
+			names[id] = "Unknown"
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return names, nil
+	}
+
+	users, err := uc.userRepo.GetByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+	return names, nil
+}
+
+func (uc *postUseCase) mapToPostResponse(ctx context.Context, p *post.Post) (*post.PostResponse, error) {
+	responses, err := uc.mapToPostResponses(ctx, []*post.Post{p})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// mapToViewerResponse maps a post read by a viewer, see withholdRawContent
+func (uc *postUseCase) mapToViewerResponse(ctx context.Context, p *post.Post, viewerID uint, viewerRole string) (*post.PostResponse, error) {
+	responses, err := uc.mapToPostResponses(ctx, []*post.Post{p})
+	if err != nil {
+		return nil, err
+	}
+	uc.withholdRawContent(ctx, responses, viewerID, viewerRole)
+	return &responses[0], nil
+}
+
+// withholdRawContent clears the raw content of posts served under an active external image
+// policy from viewers who can't edit them, since it still holds the original image URLs.
+// They read content_html instead. Editors are those UpdatePost lets change the content;
+// viewerID is 0 for anonymous viewers.
+func (uc *postUseCase) withholdRawContent(ctx context.Context, responses []post.PostResponse, viewerID uint, viewerRole string) {
+	action := uc.cfg.Post.ExternalImages.Action
+	if action == "" || action == imagepolicy.ActionAllow {
+		return
+	}
+	if viewerRole == user.RoleAdmin {
+		return
+	}
+
+	for i := range responses {
+		r := &responses[i]
+		if r.ContentHTML == "" {
+			continue
+		}
+		if viewerID != 0 {
+			if r.AuthorID == viewerID {
+				continue
+			}
+			if collaborator, _ := uc.postRepo.GetCollaborator(ctx, r.ID, viewerID); collaborator != nil && collaborator.CanEdit() {
+				continue
+			}
+		}
+		r.Content = ""
+	}
+}
+
+// mapToPostResponses maps a page of posts, loading the tags of all of them in one query
+func (uc *postUseCase) mapToPostResponses(ctx context.Context, posts []*post.Post) ([]post.PostResponse, error) {
+	ids := make([]uint, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	tagNames, err := uc.postRepo.GetTagNames(ctx, ids)
+	if err != nil {
+		return nil, wrapError("failed to fetch post tags", err)
+	}
+
+	authorIDs := make([]uint, len(posts))
+	for i, p := range posts {
+		authorIDs[i] = p.AuthorID
+	}
+	authorNames, err := uc.userNames(ctx, authorIDs)
+	if err != nil {
+		return nil, wrapError("failed to fetch post authors", err)
+	}
+
+	responses := make([]post.PostResponse, len(posts))
+	for i, p := range posts {
+		// Handle nil pointers
+		summary := ""
+		if p.Summary != nil {
+			summary = *p.Summary
+		}
+
+		featuredImg := ""
+		if p.FeaturedImg != nil {
+			featuredImg = *p.FeaturedImg
+		}
+
+		contentHTML := ""
+		if p.ContentHTML != nil {
+			contentHTML = *p.ContentHTML
+		}
+
+		tags := tagNames[p.ID]
+		if tags == nil {
+			tags = []string{}
+		}
+
+		responses[i] = post.PostResponse{
+			ID:            p.ID,
+			Title:         p.Title,
+			Content:       p.Content,
+			ContentFormat: p.ContentFormat,
+			ContentHTML:   contentHTML,
+			Language:      p.Language,
+			Summary:       summary,
+			Slug:          p.Slug,
+			Status:        p.Status,
+			CategoryID:    p.CategoryID,
+			AuthorID:      p.AuthorID,
+			AuthorName:    authorNames[p.AuthorID],
+			FeaturedImg:   featuredImg,
+			ViewCount:     p.ViewCount,
+			IsPublic:      p.IsPublic,
+			AllowComments: p.AllowComments,
+			Flagged:       p.Flagged,
+			PublishedAt:   p.PublishedAt,
+			PublishAt:     p.PublishAt,
+			CreatedAt:     p.CreatedAt,
+			UpdatedAt:     p.UpdatedAt,
+			Tags:          tags,
+		}
+	}
+	return responses, nil
+}
+
+// renderContent caches the HTML served for a post so reads don't render: the sanitized
+// rendering of markdown posts, and for HTML posts the content with the external image policy
+// applied when that changed anything
+func (uc *postUseCase) renderContent(p *post.Post) error {
+	source := p.Content
+	if p.ContentFormat == "markdown" {
+		rendered, err := markdown.ToHTML(p.Content)
+		if err != nil {
+			return err
+		}
+		source = rendered
+	}
+
+	images := uc.cfg.Post.ExternalImages
+	html, found, err := imagepolicy.Process(source, imagepolicy.Options{
+		Action:       images.Action,
+		AllowedHosts: images.AllowedHosts,
+		ProxyURL:     images.ProxyURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	if p.ContentFormat != "markdown" && found == 0 {
+		p.ContentHTML = nil
+		return nil
+	}
+	p.ContentHTML = &html
+	return nil
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+)
+
+func TestResetViewCounts(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakePostRepo{posts: []*post.Post{
+		{ID: 1, ViewCount: 10},
+		{ID: 2, ViewCount: 20},
+		{ID: 3, ViewCount: 30},
+	}}
+	uc := NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, &config.Config{})
+
+	if err := uc.ResetViewCount(ctx, 1); err != nil {
+		t.Fatalf("reset one: %v", err)
+	}
+	if repo.posts[0].ViewCount != 0 || repo.posts[1].ViewCount != 20 {
+		t.Errorf("expected only post 1 to be reset, got %d and %d", repo.posts[0].ViewCount, repo.posts[1].ViewCount)
+	}
+	if err := uc.ResetViewCount(ctx, 99); !errors.Is(err, post.ErrNotFound) {
+		t.Errorf("expected %v for a missing post, got %v", post.ErrNotFound, err)
+	}
+
+	// Unknown IDs are skipped rather than failing the batch
+	resp, err := uc.ResetViewCounts(ctx, []uint{2, 3, 99})
+	if err != nil {
+		t.Fatalf("reset many: %v", err)
+	}
+	if resp.Reset != 2 || repo.posts[1].ViewCount != 0 || repo.posts[2].ViewCount != 0 {
+		t.Errorf("expected posts 2 and 3 to be reset, got %+v", resp)
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/user"
+)
+
+const (
+	authorID       uint = 1
+	collaboratorID uint = 2
+	otherUserID    uint = 3
+)
+
+func newVisibilityUseCase(privateVisibility string, p *post.Post) PostUseCase {
+	cfg := &config.Config{}
+	cfg.Post.PrivateVisibility = privateVisibility
+
+	repo := &fakePostRepo{
+		posts:         []*post.Post{p},
+		collaborators: map[uint][]uint{p.ID: {collaboratorID}},
+	}
+	return NewPostUseCase(repo, &fakeUserRepo{}, nil, nil, cfg)
+}
+
+func TestPostVisibility(t *testing.T) {
+	tests := []struct {
+		name              string
+		status            string
+		isPublic          bool
+		privateVisibility string
+		viewerID          uint
+		viewerRole        string
+		visible           bool
+	}{
+		{"public post, anonymous", "published", true, "authenticated", 0, "", true},
+		{"private post, anonymous", "published", false, "authenticated", 0, "", false},
+		{"private post, anonymous, owner mode", "published", false, "owner", 0, "", false},
+		{"private post, other user", "published", false, "authenticated", otherUserID, user.RoleUser, true},
+		{"private post, other user, owner mode", "published", false, "owner", otherUserID, user.RoleUser, false},
+		{"private post, author, owner mode", "published", false, "owner", authorID, user.RoleUser, true},
+		{"private post, collaborator, owner mode", "published", false, "owner", collaboratorID, user.RoleUser, true},
+		{"private post, editor, owner mode", "published", false, "owner", otherUserID, user.RoleEditor, false},
+		{"draft, anonymous", "draft", true, "authenticated", 0, "", false},
+		{"draft, other user", "draft", true, "authenticated", otherUserID, user.RoleUser, false},
+		{"draft, author", "draft", true, "authenticated", authorID, user.RoleUser, true},
+		{"draft, collaborator", "draft", false, "authenticated", collaboratorID, user.RoleUser, true},
+		{"draft, admin", "draft", false, "authenticated", otherUserID, user.RoleAdmin, true},
+		{"archived, other user", "archived", true, "authenticated", otherUserID, user.RoleUser, false},
+		{"archived, author", "archived", true, "authenticated", authorID, user.RoleUser, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &post.Post{ID: 10, Slug: "a-post", Status: tt.status, IsPublic: tt.isPublic, AuthorID: authorID}
+			uc := newVisibilityUseCase(tt.privateVisibility, p)
+			ctx := context.Background()
+
+			_, errByID := uc.GetPostByID(ctx, p.ID, false, tt.viewerID, tt.viewerRole)
+			_, errBySlug := uc.GetPostBySlug(ctx, p.Slug, false, tt.viewerID, tt.viewerRole)
+
+			_, errResolved := uc.ResolvePost(ctx, p.Slug, false, tt.viewerID, tt.viewerRole)
+
+			for lookup, err := range map[string]error{"id": errByID, "slug": errBySlug, "resolve": errResolved} {
+				if tt.visible && err != nil {
+					t.Errorf("by %s: expected post to be visible, got %v", lookup, err)
+				}
+				if !tt.visible && !errors.Is(err, post.ErrNotFound) {
+					t.Errorf("by %s: expected ErrNotFound, got %v", lookup, err)
+				}
+			}
+		})
+	}
+}
+
+// Listings hide what canViewPost hides, whatever the filter asks for
+func TestGetAllPostsVisibleToViewer(t *testing.T) {
+	draft, author := "draft", authorID
+
+	tests := []struct {
+		name              string
+		privateVisibility string
+		viewerID          uint
+		viewerRole        string
+		want              *post.Viewer
+	}{
+		{"anonymous", "authenticated", 0, "", &post.Viewer{}},
+		{"user", "authenticated", otherUserID, user.RoleUser, &post.Viewer{UserID: otherUserID, PrivatePublished: true}},
+		{"user, owner mode", "owner", otherUserID, user.RoleUser, &post.Viewer{UserID: otherUserID}},
+		{"editor, owner mode", "owner", otherUserID, user.RoleEditor, &post.Viewer{UserID: otherUserID}},
+		{"admin", "owner", otherUserID, user.RoleAdmin, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newStatusTestUseCase(t, &post.Post{ID: 1, AuthorID: authorID, Status: draft}, func(cfg *config.Config) {
+				cfg.Post.PrivateVisibility = tt.privateVisibility
+			})
+
+			filter := post.PostFilter{Status: &draft, AuthorID: &author}
+			if _, err := uc.GetAllPosts(context.Background(), filter, tt.viewerID, tt.viewerRole, 1, 10); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := repo.listFilter.VisibleTo
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("expected the listing visible to %+v, got %+v", tt.want, got)
+			}
+			if repo.listFilter.Status == nil || repo.listFilter.AuthorID == nil {
+				t.Errorf("expected the requested filters to be kept, got %+v", repo.listFilter)
+			}
+		})
+	}
+}
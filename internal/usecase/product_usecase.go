@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"moon/internal/config"
+	"moon/internal/domain/product"
+	"moon/pkg/money"
+)
+
+type ProductUseCase interface {
+	CreateProduct(ctx context.Context, req product.CreateProductRequest) (*product.ProductResponse, error)
+	GetProduct(ctx context.Context, id uint, includeInactive bool) (*product.ProductResponse, error)
+	UpdateProduct(ctx context.Context, id uint, req product.UpdateProductRequest) (*product.ProductResponse, error)
+	DeleteProduct(ctx context.Context, id uint) error
+	ReserveStock(ctx context.Context, productID uint, qty int) error
+	GetProducts(ctx context.Context, filter product.ProductFilter, page, limit int) (*product.ProductsListResponse, error)
+}
+
+type productUseCase struct {
+	productRepo  product.Repository
+	categoryRepo product.CategoryRepository
+	cfg          *config.Config
+}
+
+// NewProductUseCase creates a new product use case
+func NewProductUseCase(productRepo product.Repository, categoryRepo product.CategoryRepository, cfg *config.Config) ProductUseCase {
+	return &productUseCase{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		cfg:          cfg,
+	}
+}
+
+func (uc *productUseCase) CreateProduct(ctx context.Context, req product.CreateProductRequest) (*product.ProductResponse, error) {
+	currency := uc.cfg.Product.DefaultCurrency
+	if req.Currency != "" {
+		currency = req.Currency
+	}
+	currency, err := normalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := uc.activeCategory(ctx, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &product.Product{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Price:       req.Price,
+		Currency:    currency,
+		Stock:       req.Stock,
+		CategoryID:  category.ID,
+		IsActive:    true,
+	}
+
+	if err := uc.productRepo.Create(ctx, p); err != nil {
+		return nil, wrapError("failed to create product", err)
+	}
+	p.Category = *category
+
+	resp := p.ToResponse()
+	return &resp, nil
+}
+
+// GetProduct returns a product by ID. Inactive products are reported as not found unless
+// includeInactive is set, so they stay hidden from the storefront.
+func (uc *productUseCase) GetProduct(ctx context.Context, id uint, includeInactive bool) (*product.ProductResponse, error) {
+	p, err := uc.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsActive && !includeInactive {
+		return nil, product.ErrNotFound
+	}
+
+	resp := p.ToResponse()
+	return &resp, nil
+}
+
+func (uc *productUseCase) UpdateProduct(ctx context.Context, id uint, req product.UpdateProductRequest) (*product.ProductResponse, error) {
+	p, err := uc.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		p.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		p.Description = *req.Description
+	}
+	if req.Price != nil {
+		p.Price = *req.Price
+	}
+	if req.Currency != nil {
+		currency, err := normalizeCurrency(*req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		p.Currency = currency
+	}
+	if req.Stock != nil {
+		p.Stock = *req.Stock
+	}
+	if req.IsActive != nil {
+		p.IsActive = *req.IsActive
+	}
+	if req.CategoryID != nil && *req.CategoryID != p.CategoryID {
+		category, err := uc.activeCategory(ctx, *req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		p.CategoryID = category.ID
+		p.Category = *category
+	}
+
+	if err := uc.productRepo.Update(ctx, p); err != nil {
+		return nil, wrapError("failed to update product", err)
+	}
+
+	resp := p.ToResponse()
+	return &resp, nil
+}
+
+func (uc *productUseCase) DeleteProduct(ctx context.Context, id uint) error {
+	if _, err := uc.productRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.productRepo.Delete(ctx, id); err != nil {
+		return wrapError("failed to delete product", err)
+	}
+	return nil
+}
+
+// ReserveStock takes qty units of an active product out of stock, failing with
+// ErrInsufficientStock rather than letting stock go negative
+func (uc *productUseCase) ReserveStock(ctx context.Context, productID uint, qty int) error {
+	if qty <= 0 {
+		return product.ErrInvalidQuantity
+	}
+
+	reserved, err := uc.productRepo.DecrementStock(ctx, productID, qty)
+	if err != nil {
+		return wrapError("failed to reserve stock", err)
+	}
+	if reserved {
+		return nil
+	}
+
+	// Nothing was updated: report a missing or inactive product as not found
+	p, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if !p.IsActive {
+		return product.ErrNotFound
+	}
+	return product.ErrInsufficientStock
+}
+
+func (uc *productUseCase) GetProducts(ctx context.Context, filter product.ProductFilter, page, limit int) (*product.ProductsListResponse, error) {
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, product.ErrInvalidPriceRange
+	}
+	if filter.Currency != nil {
+		currency, err := normalizeCurrency(*filter.Currency)
+		if err != nil {
+			return nil, err
+		}
+		filter.Currency = &currency
+	}
+
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := uc.productRepo.GetAll(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch products", err)
+	}
+
+	total, err := uc.productRepo.GetTotalCount(ctx, filter)
+	if err != nil {
+		return nil, wrapError("failed to count products", err)
+	}
+
+	productList := make([]product.ProductResponse, len(products))
+	for i, p := range products {
+		productList[i] = p.ToResponse()
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &product.ProductsListResponse{
+		Products:   productList,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// activeCategory loads a category products can be filed under; inactive categories count as missing
+func (uc *productUseCase) activeCategory(ctx context.Context, id uint) (*product.Category, error) {
+	category, err := uc.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !category.IsActive {
+		return nil, product.ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// normalizeCurrency upper-cases an ISO 4217 code and rejects currencies money cannot format
+func normalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !money.IsSupported(code) {
+		return "", product.ErrUnsupportedCurrency
+	}
+	return code, nil
+}
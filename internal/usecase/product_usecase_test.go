@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/product"
+)
+
+func TestReserveStockConcurrently(t *testing.T) {
+	const stock, buyers = 10, 50
+
+	repo := &fakeProductRepo{products: map[uint]*product.Product{
+		1: {ID: 1, Stock: stock, IsActive: true},
+	}}
+	uc := NewProductUseCase(repo, nil, &config.Config{})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved, soldOut := 0, 0
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := uc.ReserveStock(context.Background(), 1, 1)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				reserved++
+			case errors.Is(err, product.ErrInsufficientStock):
+				soldOut++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reserved != stock || soldOut != buyers-stock {
+		t.Errorf("expected %d reservations and %d sold out, got %d and %d", stock, buyers-stock, reserved, soldOut)
+	}
+	if left := repo.products[1].Stock; left != 0 {
+		t.Errorf("expected the stock used up without going negative, got %d", left)
+	}
+}
+
+func TestReserveStockRejected(t *testing.T) {
+	repo := &fakeProductRepo{products: map[uint]*product.Product{
+		1: {ID: 1, Stock: 2, IsActive: true},
+		2: {ID: 2, Stock: 5, IsActive: false},
+	}}
+	uc := NewProductUseCase(repo, nil, &config.Config{})
+
+	tests := []struct {
+		name      string
+		productID uint
+		qty       int
+		wantErr   error
+	}{
+		{"more than in stock", 1, 3, product.ErrInsufficientStock},
+		{"zero quantity", 1, 0, product.ErrInvalidQuantity},
+		{"inactive product", 2, 1, product.ErrNotFound},
+		{"unknown product", 3, 1, product.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := uc.ReserveStock(context.Background(), tt.productID, tt.qty); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if repo.products[1].Stock != 2 {
+		t.Errorf("expected the stock untouched, got %d", repo.products[1].Stock)
+	}
+}
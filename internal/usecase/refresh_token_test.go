@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+	"moon/pkg/cache/cachetest"
+	"moon/pkg/jwt"
+)
+
+// newRefreshTestUseCase returns an auth use case for an active user holding the refresh
+// token "issued". A nil store is a cache without Redis.
+func newRefreshTestUseCase(u *user.User, expiresAt time.Time, store *cache.Cache) (AuthUseCase, *fakeUserRepo) {
+	cfg := &config.Config{}
+	cfg.JWT.Secret = "test-secret"
+	cfg.JWT.ExpiresIn = 1
+	cfg.JWT.RefreshExpiresIn = 24
+
+	repo := &fakeUserRepo{users: map[uint]*user.User{u.ID: u}}
+	repo.CreateRefreshToken(context.Background(), &user.RefreshToken{
+		UserID:       u.ID,
+		TokenHash:    jwt.HashRefreshToken("issued"),
+		TokenVersion: 2,
+		ExpiresAt:    expiresAt,
+	})
+	if store == nil {
+		store = cache.New(nil, cache.Options{})
+	}
+	return NewAuthUseCase(repo, store, nil, cfg), repo
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	uc, repo := newRefreshTestUseCase(&user.User{ID: 1, Email: "a@example.com", IsActive: true, TokenVersion: 2}, time.Now().Add(time.Hour), nil)
+
+	resp, err := uc.RefreshToken(ctx, "issued")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if resp.Token == "" || resp.RefreshToken == "" || resp.RefreshToken == "issued" {
+		t.Fatalf("expected a new access token and a rotated refresh token, got %+v", resp)
+	}
+	if claims, err := jwt.ParseToken(resp.Token, "test-secret"); err != nil || claims.UserID != 1 || claims.TokenVersion != 2 {
+		t.Errorf("expected an access token for the user, got %+v, %v", claims, err)
+	}
+	if !repo.refreshTokens[0].Revoked {
+		t.Error("expected the presented refresh token to be revoked")
+	}
+
+	// The rotated token works once
+	rotated, err := uc.RefreshToken(ctx, resp.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh with the rotated token: %v", err)
+	}
+
+	// Replaying a revoked token is rejected and signs the user out everywhere, including
+	// the session holding the latest token
+	if _, err := uc.RefreshToken(ctx, "issued"); !errors.Is(err, user.ErrInvalidRefresh) {
+		t.Fatalf("expected the replayed token to be rejected, got %v", err)
+	}
+	if _, err := uc.RefreshToken(ctx, rotated.RefreshToken); !errors.Is(err, user.ErrInvalidRefresh) {
+		t.Errorf("expected every refresh token of the user revoked after reuse, got %v", err)
+	}
+}
+
+func TestRefreshTokenRejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		user      user.User
+		expiresAt time.Time
+		token     string
+		wantErr   error
+	}{
+		{"unknown", user.User{ID: 1, IsActive: true, TokenVersion: 2}, time.Now().Add(time.Hour), "unknown", user.ErrInvalidRefresh},
+		{"expired", user.User{ID: 1, IsActive: true, TokenVersion: 2}, time.Now().Add(-time.Minute), "issued", user.ErrInvalidRefresh},
+		{"tokens revoked since issue", user.User{ID: 1, IsActive: true, TokenVersion: 3}, time.Now().Add(time.Hour), "issued", user.ErrInvalidRefresh},
+		{"deactivated", user.User{ID: 1, IsActive: false, TokenVersion: 2}, time.Now().Add(time.Hour), "issued", user.ErrDeactivated},
+		{"pending approval", user.User{ID: 1, PendingApproval: true, TokenVersion: 2}, time.Now().Add(time.Hour), "issued", user.ErrPendingApproval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := tt.user
+			uc, _ := newRefreshTestUseCase(&u, tt.expiresAt, nil)
+
+			resp, err := uc.RefreshToken(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if resp != nil {
+				t.Errorf("expected no tokens, got %+v", resp)
+			}
+		})
+	}
+}
+
+func TestLogoutRevokesRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	t.Run("own session", func(t *testing.T) {
+		server, store := cachetest.NewServer(t)
+		uc, repo := newRefreshTestUseCase(&user.User{ID: 1, IsActive: true, TokenVersion: 2}, expiresAt, store)
+
+		if err := uc.Logout(ctx, 1, "jti-1", expiresAt, "issued"); err != nil {
+			t.Fatalf("logout: %v", err)
+		}
+		if !repo.refreshTokens[0].Revoked {
+			t.Error("expected the refresh token to be revoked")
+		}
+		if server.Get(tokenBlacklistKey("jti-1")) == "" {
+			t.Error("expected the access token to be blacklisted")
+		}
+		if _, err := uc.RefreshToken(ctx, "issued"); !errors.Is(err, user.ErrInvalidRefresh) {
+			t.Errorf("expected refreshing after logout to fail, got %v", err)
+		}
+	})
+
+	t.Run("another user's refresh token", func(t *testing.T) {
+		server, store := cachetest.NewServer(t)
+		uc, repo := newRefreshTestUseCase(&user.User{ID: 1, IsActive: true, TokenVersion: 2}, expiresAt, store)
+
+		if err := uc.Logout(ctx, 2, "jti-2", expiresAt, "issued"); !errors.Is(err, user.ErrInvalidRefresh) {
+			t.Errorf("expected %v, got %v", user.ErrInvalidRefresh, err)
+		}
+		if repo.refreshTokens[0].Revoked || server.Get(tokenBlacklistKey("jti-2")) != "" {
+			t.Error("expected nothing revoked")
+		}
+	})
+}
+
+func TestIsTokenBlacklisted(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("redis up", func(t *testing.T) {
+		_, store := cachetest.NewServer(t)
+		uc := NewAuthUseCase(&fakeUserRepo{}, store, nil, &config.Config{})
+
+		if err := store.Set(ctx, tokenBlacklistKey("revoked"), 1, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+		if blacklisted, err := uc.IsTokenBlacklisted(ctx, "revoked"); err != nil || !blacklisted {
+			t.Errorf("expected the logged out token to be blacklisted, got %v, %v", blacklisted, err)
+		}
+		if blacklisted, err := uc.IsTokenBlacklisted(ctx, "other"); err != nil || blacklisted {
+			t.Errorf("expected another token not to be blacklisted, got %v, %v", blacklisted, err)
+		}
+	})
+
+	// Failing closed reports the outage rather than calling every token logged out
+	tests := []struct {
+		name     string
+		failOpen bool
+		wantErr  error
+	}{
+		{"redis down, fail closed", false, cache.ErrUnavailable},
+		{"redis down, fail open", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Redis.FailOpen.TokenBlacklist = tt.failOpen
+			// A cache without Redis is always unavailable
+			uc := NewAuthUseCase(&fakeUserRepo{}, cache.New(nil, cache.Options{}), nil, cfg)
+
+			blacklisted, err := uc.IsTokenBlacklisted(ctx, "any")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if blacklisted {
+				t.Error("expected the token not to be reported as logged out")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/report"
+	"moon/pkg/cache"
+)
+
+const (
+	reportRateLimit  = 10
+	reportRateWindow = time.Hour
+)
+
+type ReportUseCase interface {
+	ReportPost(ctx context.Context, postID uint, req report.CreateReportRequest, reporterID uint) (*report.Report, bool, error)
+	GetReports(ctx context.Context, filter report.ReportFilter, page, limit int) (*report.ReportsListResponse, error)
+	UpdateReportStatus(ctx context.Context, id uint, req report.UpdateReportStatusRequest, reviewerID uint) (*report.Report, error)
+}
+
+type reportUseCase struct {
+	reportRepo report.Repository
+	postRepo   post.Repository
+	cache      *cache.Cache
+	cfg        *config.Config
+}
+
+// NewReportUseCase creates a new report use case
+func NewReportUseCase(reportRepo report.Repository, postRepo post.Repository, cache *cache.Cache, cfg *config.Config) ReportUseCase {
+	return &reportUseCase{
+		reportRepo: reportRepo,
+		postRepo:   postRepo,
+		cache:      cache,
+		cfg:        cfg,
+	}
+}
+
+// ReportPost flags a post. The returned bool is true when the reporter had already reported it.
+func (uc *reportUseCase) ReportPost(ctx context.Context, postID uint, req report.CreateReportRequest, reporterID uint) (*report.Report, bool, error) {
+	if _, err := uc.postRepo.GetByID(ctx, postID); err != nil {
+		return nil, false, err
+	}
+
+	// Dedupe repeated reports of the same post by the same user
+	existingReport, _ := uc.reportRepo.GetByReporterAndTarget(ctx, reporterID, "post", postID)
+	if existingReport != nil {
+		return existingReport, true, nil
+	}
+
+	if err := uc.checkRateLimit(ctx, reporterID); err != nil {
+		return nil, false, err
+	}
+
+	newReport := &report.Report{
+		TargetType: "post",
+		TargetID:   postID,
+		ReporterID: reporterID,
+		Reason:     req.Reason,
+		Status:     "open",
+	}
+
+	if err := uc.reportRepo.Create(ctx, newReport); err != nil {
+		return nil, false, wrapError("failed to create report", err)
+	}
+
+	return newReport, false, nil
+}
+
+func (uc *reportUseCase) GetReports(ctx context.Context, filter report.ReportFilter, page, limit int) (*report.ReportsListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := uc.reportRepo.GetAll(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch reports", err)
+	}
+
+	total, err := uc.reportRepo.GetTotalCount(ctx, filter)
+	if err != nil {
+		return nil, wrapError("failed to count reports", err)
+	}
+
+	reportList := make([]report.Report, len(reports))
+	for i, r := range reports {
+		reportList[i] = *r
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &report.ReportsListResponse{
+		Reports:    reportList,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (uc *reportUseCase) UpdateReportStatus(ctx context.Context, id uint, req report.UpdateReportStatusRequest, reviewerID uint) (*report.Report, error) {
+	r, err := uc.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Status = req.Status
+	if req.Status == "open" {
+		r.ReviewedBy = nil
+		r.ReviewedAt = nil
+	} else {
+		now := time.Now()
+		r.ReviewedBy = &reviewerID
+		r.ReviewedAt = &now
+	}
+
+	if err := uc.reportRepo.Update(ctx, r); err != nil {
+		return nil, wrapError("failed to update report", err)
+	}
+
+	return r, nil
+}
+
+// checkRateLimit caps how many reports a user can file per window. While Redis is
+// unavailable the limit is skipped or enforced as configured in redis.fail_open.
+func (uc *reportUseCase) checkRateLimit(ctx context.Context, reporterID uint) error {
+	key := fmt.Sprintf("report:rate:%d", reporterID)
+
+	count, err := uc.cache.IncrWithExpiry(ctx, key, reportRateWindow)
+	if err != nil {
+		if uc.cfg.Redis.FailOpen.ReportRateLimit {
+			return nil
+		}
+		return report.ErrUnavailable
+	}
+
+	if count > reportRateLimit {
+		return report.ErrTooManyReports
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/post"
+	"moon/internal/domain/report"
+	"moon/pkg/cache"
+	"moon/pkg/cache/cachetest"
+)
+
+func newReportTestUseCase(store *cache.Cache, failOpen bool) (ReportUseCase, *fakeReportRepo) {
+	cfg := &config.Config{}
+	cfg.Redis.FailOpen.ReportRateLimit = failOpen
+
+	// Enough posts to reach the rate limit with one report each
+	reports := &fakeReportRepo{}
+	posts := &fakePostRepo{}
+	for i := uint(1); i <= reportRateLimit+1; i++ {
+		posts.posts = append(posts.posts, &post.Post{ID: i, Status: "published"})
+	}
+	return NewReportUseCase(reports, posts, store, cfg), reports
+}
+
+func TestReportPost(t *testing.T) {
+	ctx := context.Background()
+	_, store := cachetest.NewServer(t)
+	uc, reports := newReportTestUseCase(store, false)
+	req := report.CreateReportRequest{Reason: "spam"}
+
+	created, duplicate, err := uc.ReportPost(ctx, 1, req, 5)
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if duplicate || created.Status != "open" || created.TargetType != "post" || created.TargetID != 1 || created.ReporterID != 5 {
+		t.Errorf("expected a new open report of post 1, got %+v (duplicate %v)", created, duplicate)
+	}
+
+	again, duplicate, err := uc.ReportPost(ctx, 1, req, 5)
+	if err != nil || !duplicate || again.ID != created.ID {
+		t.Errorf("expected the existing report back, got %+v (duplicate %v), %v", again, duplicate, err)
+	}
+	if len(reports.reports) != 1 {
+		t.Errorf("expected repeated reports to be deduplicated, got %d", len(reports.reports))
+	}
+
+	if _, _, err := uc.ReportPost(ctx, 99, req, 5); !errors.Is(err, post.ErrNotFound) {
+		t.Errorf("expected %v for a missing post, got %v", post.ErrNotFound, err)
+	}
+}
+
+func TestReportRateLimit(t *testing.T) {
+	ctx := context.Background()
+	_, store := cachetest.NewServer(t)
+	uc, _ := newReportTestUseCase(store, false)
+	req := report.CreateReportRequest{Reason: "spam"}
+
+	for i := uint(1); i <= reportRateLimit; i++ {
+		if _, _, err := uc.ReportPost(ctx, i, req, 5); err != nil {
+			t.Fatalf("report %d: %v", i, err)
+		}
+	}
+	if _, _, err := uc.ReportPost(ctx, reportRateLimit+1, req, 5); !errors.Is(err, report.ErrTooManyReports) {
+		t.Errorf("expected %v past the limit, got %v", report.ErrTooManyReports, err)
+	}
+	// Other users have their own budget
+	if _, _, err := uc.ReportPost(ctx, 1, req, 6); err != nil {
+		t.Errorf("expected another user to be able to report, got %v", err)
+	}
+}
+
+func TestReportRateLimitWithoutRedis(t *testing.T) {
+	tests := []struct {
+		name     string
+		failOpen bool
+		wantErr  error
+	}{
+		{"fail closed", false, report.ErrUnavailable},
+		{"fail open", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newReportTestUseCase(cache.New(nil, cache.Options{}), tt.failOpen)
+
+			if _, _, err := uc.ReportPost(context.Background(), 1, report.CreateReportRequest{Reason: "spam"}, 5); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUpdateReportStatus(t *testing.T) {
+	ctx := context.Background()
+	uc, reports := newReportTestUseCase(cache.New(nil, cache.Options{}), true)
+	reports.Create(ctx, &report.Report{TargetType: "post", TargetID: 1, ReporterID: 5, Status: "open"})
+
+	reviewed, err := uc.UpdateReportStatus(ctx, 1, report.UpdateReportStatusRequest{Status: "reviewed"}, 9)
+	if err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if reviewed.Status != "reviewed" || reviewed.ReviewedBy == nil || *reviewed.ReviewedBy != 9 || reviewed.ReviewedAt == nil {
+		t.Errorf("expected the reviewer to be recorded, got %+v", reviewed)
+	}
+
+	reopened, err := uc.UpdateReportStatus(ctx, 1, report.UpdateReportStatusRequest{Status: "open"}, 9)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if reopened.ReviewedBy != nil || reopened.ReviewedAt != nil {
+		t.Errorf("expected reopening to clear the review, got %+v", reopened)
+	}
+
+	if _, err := uc.UpdateReportStatus(ctx, 99, report.UpdateReportStatusRequest{Status: "reviewed"}, 9); !errors.Is(err, report.ErrNotFound) {
+		t.Errorf("expected %v, got %v", report.ErrNotFound, err)
+	}
+}
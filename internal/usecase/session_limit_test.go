@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+	"moon/pkg/hash"
+)
+
+// newSessionLimitTestUseCase returns an auth use case for a user who already holds active
+// sessions, capped at maxSessions per user
+func newSessionLimitTestUseCase(t *testing.T, mode string, maxSessions, active int) (AuthUseCase, *fakeUserRepo) {
+	t.Helper()
+	password, err := hash.HashPassword("secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.JWT.Secret = "test-secret"
+	cfg.JWT.ExpiresIn = 1
+	cfg.JWT.RefreshExpiresIn = 24
+	cfg.Account.MaxSessions = maxSessions
+	cfg.Account.SessionLimitMode = mode
+
+	repo := &fakeUserRepo{users: map[uint]*user.User{
+		1: {ID: 1, Email: "a@example.com", Password: password, IsActive: true, TokenVersion: 2},
+	}}
+	ctx := context.Background()
+	// Revoked, expired and superseded tokens are not sessions and never count
+	repo.CreateRefreshToken(ctx, &user.RefreshToken{UserID: 1, TokenVersion: 2, ExpiresAt: time.Now().Add(time.Hour), Revoked: true})
+	repo.CreateRefreshToken(ctx, &user.RefreshToken{UserID: 1, TokenVersion: 2, ExpiresAt: time.Now().Add(-time.Minute)})
+	repo.CreateRefreshToken(ctx, &user.RefreshToken{UserID: 1, TokenVersion: 1, ExpiresAt: time.Now().Add(time.Hour)})
+	for i := 0; i < active; i++ {
+		repo.CreateRefreshToken(ctx, &user.RefreshToken{UserID: 1, TokenVersion: 2, ExpiresAt: time.Now().Add(time.Hour)})
+	}
+	return NewAuthUseCase(repo, cache.New(nil, cache.Options{}), nil, cfg), repo
+}
+
+func TestLoginSessionLimit(t *testing.T) {
+	const firstActive = 3 // index of the oldest active session among the stored tokens
+
+	tests := []struct {
+		name        string
+		mode        string
+		maxSessions int
+		active      int
+		wantErr     error
+		wantEvicted int
+	}{
+		{"evict below the cap", "evict_oldest", 2, 1, nil, 0},
+		{"evict at the cap", "evict_oldest", 2, 2, nil, 1},
+		{"evict over the cap", "evict_oldest", 2, 3, nil, 2},
+		{"reject below the cap", "reject", 2, 1, nil, 0},
+		{"reject at the cap", "reject", 2, 2, user.ErrSessionLimit, 0},
+		{"unlimited", "reject", 0, 5, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo := newSessionLimitTestUseCase(t, tt.mode, tt.maxSessions, tt.active)
+			stored := len(repo.refreshTokens)
+
+			resp, err := uc.Login(context.Background(), user.LoginRequest{Email: "a@example.com", Password: "secret123"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				if len(repo.refreshTokens) != stored {
+					t.Error("expected a rejected login not to start a session")
+				}
+				for _, token := range repo.refreshTokens[firstActive:] {
+					if token.Revoked {
+						t.Errorf("expected a rejected login to keep session %d", token.ID)
+					}
+				}
+				return
+			}
+
+			if resp.EvictedSessions != tt.wantEvicted {
+				t.Errorf("expected %d sessions evicted, got %d", tt.wantEvicted, resp.EvictedSessions)
+			}
+			// The oldest sessions go first and the new one always survives
+			for i, token := range repo.refreshTokens[firstActive:] {
+				if wantRevoked := i < tt.wantEvicted; token.Revoked != wantRevoked {
+					t.Errorf("session %d: expected revoked %v, got %v", token.ID, wantRevoked, token.Revoked)
+				}
+			}
+
+			active, _ := repo.GetActiveRefreshTokens(context.Background(), 1, 2, time.Now())
+			if tt.maxSessions > 0 && len(active) > tt.maxSessions {
+				t.Errorf("expected at most %d active sessions after login, got %d", tt.maxSessions, len(active))
+			}
+		})
+	}
+}
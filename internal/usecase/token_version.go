@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+)
+
+const tokenStateCacheTTL = time.Hour
+
+func tokenStateKey(userID uint) string {
+	return fmt.Sprintf("user:%d:token_state", userID)
+}
+
+func tokenBlacklistKey(tokenID string) string {
+	return "auth:blacklist:" + tokenID
+}
+
+// tokenState is the per-user data needed to validate an access token
+type tokenState struct {
+	Version int  `json:"version"`
+	Active  bool `json:"active"`
+}
+
+// getTokenState returns the user's current token version and active flag, reading through
+// the cache. When Redis is unavailable the state is read straight from the database.
+func getTokenState(ctx context.Context, userRepo user.Repository, store *cache.Cache, userID uint) (*tokenState, error) {
+	key := tokenStateKey(userID)
+
+	if value, err := store.Get(ctx, key); err == nil {
+		var state tokenState
+		if err := json.Unmarshal([]byte(value), &state); err == nil {
+			return &state, nil
+		}
+	}
+
+	u, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &tokenState{Version: u.TokenVersion, Active: u.IsActive}
+	if data, err := json.Marshal(state); err == nil {
+		store.Set(ctx, key, data, tokenStateCacheTTL)
+	}
+	return state, nil
+}
+
+// invalidateTokenState drops the cached token state after the user's version or active flag
+// changed. Without Redis nothing is cached; while Redis is unreachable the error is returned,
+// as the stale state would be served again once it recovers.
+func invalidateTokenState(ctx context.Context, store *cache.Cache, userID uint) error {
+	if !store.Configured() {
+		return nil
+	}
+	return store.Del(ctx, tokenStateKey(userID))
+}
+
+// revokeTokens bumps the user's token version, invalidating every token issued so far
+func revokeTokens(ctx context.Context, userRepo user.Repository, store *cache.Cache, userID uint) error {
+	if err := userRepo.IncrementTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+
+	return invalidateTokenState(ctx, store, userID)
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+	"moon/pkg/cache/cachetest"
+	"moon/pkg/hash"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestTokenRevocation(t *testing.T) {
+	const userID uint = 1
+
+	passwordHash, err := hash.HashPassword("old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		revoke func(ctx context.Context, auth AuthUseCase, users UserUseCase) error
+	}{
+		{"force logout", func(ctx context.Context, auth AuthUseCase, users UserUseCase) error {
+			return users.ForceLogout(ctx, userID)
+		}},
+		{"password change", func(ctx context.Context, auth AuthUseCase, users UserUseCase) error {
+			return auth.ChangePassword(ctx, userID, "old-password", "new-password")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{users: map[uint]*user.User{
+				userID: {ID: userID, Email: "a@example.com", Password: passwordHash, IsActive: true, TokenVersion: 3},
+			}}
+			// A cache without Redis is always unavailable, so token state is read from the repository
+			store := cache.New(nil, cache.Options{})
+			auth := NewAuthUseCase(repo, store, nil, &config.Config{})
+			users := NewUserUseCase(repo, nil, store)
+			ctx := context.Background()
+
+			if err := auth.ValidateTokenVersion(ctx, userID, 3); err != nil {
+				t.Fatalf("token valid before revocation: %v", err)
+			}
+
+			if err := tt.revoke(ctx, auth, users); err != nil {
+				t.Fatalf("revoke: %v", err)
+			}
+
+			if err := auth.ValidateTokenVersion(ctx, userID, 3); !errors.Is(err, user.ErrTokenRevoked) {
+				t.Errorf("expected old token to be revoked, got %v", err)
+			}
+			if err := auth.ValidateTokenVersion(ctx, userID, 4); err != nil {
+				t.Errorf("expected token of the new version to be valid, got %v", err)
+			}
+		})
+	}
+	// A deleted user's tokens stop working right away rather than when the cached state expires
+	t.Run("delete", func(t *testing.T) {
+		server, store := cachetest.NewServer(t)
+		repo := &fakeUserRepo{users: map[uint]*user.User{
+			userID: {ID: userID, Email: "a@example.com", IsActive: true, TokenVersion: 3},
+		}}
+		auth := NewAuthUseCase(repo, store, nil, &config.Config{})
+		users := NewUserUseCase(repo, nil, store)
+		ctx := context.Background()
+
+		if err := auth.ValidateTokenVersion(ctx, userID, 3); err != nil {
+			t.Fatalf("token valid before deletion: %v", err)
+		}
+		if server.Get(tokenStateKey(userID)) == "" {
+			t.Fatal("expected the token state to be cached")
+		}
+
+		if err := users.DeleteUser(ctx, userID); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+
+		if server.Get(tokenStateKey(userID)) != "" {
+			t.Error("expected the cached token state to be dropped")
+		}
+		if err := auth.ValidateTokenVersion(ctx, userID, 3); err == nil {
+			t.Error("expected the deleted user's token to be rejected")
+		}
+	})
+
+	// The cached state can't be dropped, so revocation must fail rather than leave the
+	// old version to be served once Redis recovers
+	t.Run("redis unreachable", func(t *testing.T) {
+		repo := &fakeUserRepo{users: map[uint]*user.User{
+			userID: {ID: userID, Email: "a@example.com", IsActive: true, TokenVersion: 3},
+		}}
+		client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+		t.Cleanup(func() { client.Close() })
+		users := NewUserUseCase(repo, nil, cache.New(client, cache.Options{}))
+
+		if err := users.ForceLogout(context.Background(), userID); err == nil {
+			t.Error("expected revocation to fail while the cached token state can't be dropped")
+		}
+	})
+}
+
+func TestDeactivatedUserTokens(t *testing.T) {
+	const userID uint = 1
+	ctx := context.Background()
+
+	t.Run("deactivated by an admin", func(t *testing.T) {
+		_, store := cachetest.NewServer(t)
+		repo := &fakeUserRepo{users: map[uint]*user.User{
+			userID: {ID: userID, Email: "a@example.com", IsActive: true, TokenVersion: 3},
+		}}
+		cfg := &config.Config{}
+		cfg.JWT.CheckUserActive = true
+		auth := NewAuthUseCase(repo, store, nil, cfg)
+		users := NewUserUseCase(repo, nil, store)
+
+		// Caches the active state
+		if err := auth.ValidateTokenVersion(ctx, userID, 3); err != nil {
+			t.Fatalf("token valid before deactivation: %v", err)
+		}
+
+		inactive := false
+		if _, err := users.UpdateUser(ctx, userID, user.AdminUpdateUserRequest{IsActive: &inactive}); err != nil {
+			t.Fatalf("deactivate: %v", err)
+		}
+		if err := auth.ValidateTokenVersion(ctx, userID, 3); err == nil {
+			t.Error("expected the existing token to be rejected")
+		}
+	})
+
+	// A user deactivated without revoking their tokens, e.g. straight in the database, is
+	// only caught by the active check
+	tests := []struct {
+		name        string
+		checkActive bool
+		wantErr     error
+	}{
+		{"check_user_active on", true, user.ErrDeactivated},
+		{"check_user_active off", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{users: map[uint]*user.User{
+				userID: {ID: userID, Email: "a@example.com", IsActive: false, TokenVersion: 3},
+			}}
+			cfg := &config.Config{}
+			cfg.JWT.CheckUserActive = tt.checkActive
+			auth := NewAuthUseCase(repo, cache.New(nil, cache.Options{}), nil, cfg)
+
+			if err := auth.ValidateTokenVersion(ctx, userID, 3); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
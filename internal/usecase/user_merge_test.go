@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/domain/post"
+	"moon/internal/domain/report"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+)
+
+const (
+	sourceID uint = 10
+	targetID uint = 20
+)
+
+// newMergeTestUseCase returns a user use case over in-memory repositories holding a
+// duplicate account (sourceID) and the account it is merged into (targetID)
+func newMergeTestUseCase(sourceRole, targetRole string) (UserUseCase, *fakeUserRepo, *fakePostRepo, *fakeReportRepo) {
+	users := &fakeUserRepo{users: map[uint]*user.User{
+		sourceID: {ID: sourceID, Email: "dup@example.com", Role: sourceRole, IsActive: true},
+		targetID: {ID: targetID, Email: "main@example.com", Role: targetRole, IsActive: true},
+	}}
+	posts := &fakePostRepo{}
+	reports := &fakeReportRepo{}
+	transactor := &fakeTransactor{posts: posts, users: users, reports: reports}
+	return NewUserUseCase(users, transactor, cache.New(nil, cache.Options{})), users, posts, reports
+}
+
+func TestMergeUsers(t *testing.T) {
+	ctx := context.Background()
+	uc, users, posts, reports := newMergeTestUseCase(user.RoleUser, user.RoleUser)
+
+	scheduledBy := sourceID
+	posts.posts = []*post.Post{
+		{ID: 1, AuthorID: sourceID, ScheduledBy: &scheduledBy},
+		{ID: 2, AuthorID: sourceID},
+		{ID: 3, AuthorID: targetID},
+		{ID: 4, AuthorID: otherUserID},
+		{ID: 5, AuthorID: otherUserID},
+	}
+	// The source collaborates on a post the target authors, one both share and one of its own
+	posts.collaborators = map[uint][]uint{3: {sourceID}, 4: {sourceID, targetID}}
+	posts.editors = map[uint][]uint{5: {sourceID}}
+	reports.reports = []*report.Report{
+		{ID: 1, ReporterID: sourceID, TargetType: "post", TargetID: 4},
+		{ID: 2, ReporterID: sourceID, TargetType: "post", TargetID: 5},
+		{ID: 3, ReporterID: targetID, TargetType: "post", TargetID: 4},
+	}
+	users.refreshTokens = []*user.RefreshToken{{ID: 1, UserID: sourceID}, {ID: 2, UserID: targetID}}
+
+	resp, err := uc.MergeUsers(ctx, user.MergeUsersRequest{SourceID: sourceID, TargetID: targetID})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	want := user.MergeUsersResponse{SourceID: sourceID, TargetID: targetID, PostsMoved: 2, CollaborationsMoved: 1, ReportsMoved: 1}
+	if *resp != want {
+		t.Errorf("expected %+v, got %+v", want, *resp)
+	}
+
+	for _, p := range posts.posts[:2] {
+		if p.AuthorID != targetID {
+			t.Errorf("post %d: expected author %d, got %d", p.ID, targetID, p.AuthorID)
+		}
+	}
+	if got := posts.posts[0].ScheduledBy; got == nil || *got != targetID {
+		t.Errorf("expected the schedule to move to the target, got %v", got)
+	}
+
+	// Conflicting collaborations are dropped in favour of the target's access
+	wantCollaborators := map[uint][]uint{3: {}, 4: {targetID}}
+	for postID, ids := range wantCollaborators {
+		if got := posts.collaborators[postID]; len(got) != len(ids) || (len(ids) > 0 && got[0] != ids[0]) {
+			t.Errorf("post %d: expected collaborators %v, got %v", postID, ids, got)
+		}
+	}
+	if got := posts.editors[5]; len(got) != 1 || got[0] != targetID {
+		t.Errorf("expected the edit permission to move to the target, got %v", got)
+	}
+
+	// A duplicate report keeps the target's copy
+	if len(reports.reports) != 2 {
+		t.Fatalf("expected the duplicate report to be dropped, got %d reports", len(reports.reports))
+	}
+	for _, rep := range reports.reports {
+		if rep.ReporterID != targetID {
+			t.Errorf("report %d: expected reporter %d, got %d", rep.ID, targetID, rep.ReporterID)
+		}
+		if rep.TargetID == 4 && rep.ID != 3 {
+			t.Errorf("expected the target's own report to survive, got report %d", rep.ID)
+		}
+	}
+
+	if _, ok := users.users[sourceID]; ok {
+		t.Error("expected the source account to be deleted")
+	}
+	if !users.refreshTokens[0].Revoked || users.refreshTokens[1].Revoked {
+		t.Errorf("expected only the source's refresh tokens to be revoked, got %+v", users.refreshTokens)
+	}
+	if _, ok := users.users[targetID]; !ok {
+		t.Error("expected the target account to remain")
+	}
+}
+
+func TestMergeUsersPreconditions(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		sourceRole string
+		targetRole string
+		otherAdmin bool
+		req        user.MergeUsersRequest
+		wantErr    error
+	}{
+		{"same user", user.RoleUser, user.RoleUser, false, user.MergeUsersRequest{SourceID: sourceID, TargetID: sourceID}, user.ErrMergeSameUser},
+		{"unknown source", user.RoleUser, user.RoleUser, false, user.MergeUsersRequest{SourceID: 99, TargetID: targetID}, user.ErrNotFound},
+		{"unknown target", user.RoleUser, user.RoleUser, false, user.MergeUsersRequest{SourceID: sourceID, TargetID: 99}, user.ErrNotFound},
+		{"last admin", user.RoleAdmin, user.RoleUser, false, user.MergeUsersRequest{SourceID: sourceID, TargetID: targetID}, user.ErrLastAdmin},
+		{"admin into admin", user.RoleAdmin, user.RoleAdmin, false, user.MergeUsersRequest{SourceID: sourceID, TargetID: targetID}, nil},
+		{"another admin remains", user.RoleAdmin, user.RoleUser, true, user.MergeUsersRequest{SourceID: sourceID, TargetID: targetID}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, users, _, _ := newMergeTestUseCase(tt.sourceRole, tt.targetRole)
+			if tt.otherAdmin {
+				users.users[otherUserID] = &user.User{ID: otherUserID, Role: user.RoleAdmin}
+			}
+
+			_, err := uc.MergeUsers(ctx, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if _, ok := users.users[sourceID]; ok == (tt.wantErr == nil) {
+				t.Errorf("expected the source to be deleted only by a successful merge")
+			}
+		})
+	}
+}
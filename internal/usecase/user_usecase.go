@@ -2,66 +2,68 @@ package usecase
 
 import (
 	"context"
-	"errors"
 	"math"
+	"strings"
 
+	"moon/internal/domain/transaction"
 	"moon/internal/domain/user"
+	"moon/pkg/cache"
 )
 
 type UserUseCase interface {
 	GetAllUsers(ctx context.Context, page, limit int) (*user.UsersListResponse, error)
 	GetUserByID(ctx context.Context, id uint) (*user.UserResponse, error)
 	UpdateUser(ctx context.Context, id uint, req user.AdminUpdateUserRequest) (*user.UserResponse, error)
+	UpdateProfile(ctx context.Context, id uint, req user.UpdateProfileRequest) (*user.UserResponse, error)
 	DeleteUser(ctx context.Context, id uint) error
 	GetUsersByRole(ctx context.Context, role string, page, limit int) (*user.UsersListResponse, error)
+	GetPendingUsers(ctx context.Context, page, limit int) (*user.UsersListResponse, error)
+	ApproveUser(ctx context.Context, id uint) (*user.UserResponse, error)
+	RejectUser(ctx context.Context, id uint) error
+	ForceLogout(ctx context.Context, id uint) error
+	MergeUsers(ctx context.Context, req user.MergeUsersRequest) (*user.MergeUsersResponse, error)
+	GetPermissions(ctx context.Context, id uint) (*user.PermissionsResponse, error)
+	GetNames(ctx context.Context, ids []uint) (map[uint]string, error)
+	GetNotificationPreferences(ctx context.Context, id uint) (*user.NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, id uint, req user.UpdateNotificationPreferencesRequest) (*user.NotificationPreferences, error)
 }
 
 type userUseCase struct {
-	userRepo user.Repository
+	userRepo   user.Repository
+	transactor transaction.Transactor
+	cache      *cache.Cache
 }
 
 // NewUserUseCase creates a new user use case
-func NewUserUseCase(userRepo user.Repository) UserUseCase {
+func NewUserUseCase(userRepo user.Repository, transactor transaction.Transactor, cache *cache.Cache) UserUseCase {
 	return &userUseCase{
-		userRepo: userRepo,
+		userRepo:   userRepo,
+		transactor: transactor,
+		cache:      cache,
 	}
 }
 
 func (uc *userUseCase) GetAllUsers(ctx context.Context, page, limit int) (*user.UsersListResponse, error) {
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
-	}
+	page, limit = normalizePagination(page, limit)
 
-	offset := (page - 1) * limit
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
 
 	users, err := uc.userRepo.GetAll(ctx, limit, offset)
 	if err != nil {
-		return nil, errors.New("failed to fetch users")
+		return nil, wrapError("failed to fetch users", err)
 	}
 
 	total, err := uc.userRepo.GetTotalCount(ctx)
 	if err != nil {
-		return nil, errors.New("failed to count users")
+		return nil, wrapError("failed to count users", err)
 	}
 
 	userResponses := make([]user.UserResponse, len(users))
 	for i, u := range users {
-		userResponses[i] = user.UserResponse{
-			ID:        u.ID,
-			Email:     u.Email,
-			Name:      u.Name,
-			Phone:     getStringValue(u.Phone),
-			Address:   getStringValue(u.Address),
-			Lat:       getFloat64Value(u.Lat),
-			Lng:       getFloat64Value(u.Lng),
-			Role:      u.Role,
-			IsActive:  u.IsActive,
-			CreatedAt: u.CreatedAt,
-			UpdatedAt: u.UpdatedAt,
-		}
+		userResponses[i] = toUserResponse(u)
 	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
@@ -78,31 +80,37 @@ func (uc *userUseCase) GetAllUsers(ctx context.Context, page, limit int) (*user.
 func (uc *userUseCase) GetUserByID(ctx context.Context, id uint) (*user.UserResponse, error) {
 	u, err := uc.userRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, errors.New("user not found")
-	}
-
-	return &user.UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		Phone:     getStringValue(u.Phone),
-		Address:   getStringValue(u.Address),
-		Lat:       getFloat64Value(u.Lat),
-		Lng:       getFloat64Value(u.Lng),
-		Role:      u.Role,
-		IsActive:  u.IsActive,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
-	}, nil
+		return nil, user.ErrNotFound
+	}
+
+	response := toUserResponse(u)
+	return &response, nil
 }
 
 func (uc *userUseCase) UpdateUser(ctx context.Context, id uint, req user.AdminUpdateUserRequest) (*user.UserResponse, error) {
 	u, err := uc.userRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, user.ErrNotFound
 	}
 
 	// Update fields if provided
+	emailChanged := false
+	if req.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*req.Email))
+		if !strings.EqualFold(email, u.Email) {
+			// The email column uses a case-insensitive collation, so this also catches case variants
+			existingUser, _ := uc.userRepo.GetByEmail(ctx, email)
+			if existingUser != nil && existingUser.ID != u.ID {
+				return nil, user.ErrEmailInUse
+			}
+			emailChanged = true
+		}
+		u.Email = email
+		// An admin-set address replaces any change the user had pending
+		u.PendingEmail = nil
+		u.EmailChangeTokenHash = nil
+		u.EmailChangeExpiresAt = nil
+	}
 	if req.Name != nil {
 		u.Name = *req.Name
 	}
@@ -118,82 +126,288 @@ func (uc *userUseCase) UpdateUser(ctx context.Context, id uint, req user.AdminUp
 	if req.Lng != nil {
 		u.Lng = req.Lng
 	}
+	activeChanged := false
 	if req.IsActive != nil {
+		activeChanged = u.IsActive != *req.IsActive
 		u.IsActive = *req.IsActive
+		// Activating a pending user approves them
+		if u.IsActive {
+			u.PendingApproval = false
+		}
 	}
+	roleChanged := false
 	if req.Role != nil {
-		u.Role = *req.Role
+		role, ok := user.NormalizeRole(*req.Role)
+		if !ok {
+			return nil, user.ErrInvalidRole
+		}
+		roleChanged = u.Role != role
+		u.Role = role
 	}
 
 	if err := uc.userRepo.Update(ctx, u); err != nil {
-		return nil, errors.New("failed to update user")
-	}
-
-	return &user.UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		Phone:     getStringValue(u.Phone),
-		Address:   getStringValue(u.Address),
-		Lat:       getFloat64Value(u.Lat),
-		Lng:       getFloat64Value(u.Lng),
-		Role:      u.Role,
-		IsActive:  u.IsActive,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
-	}, nil
+		return nil, wrapError("failed to update user", err)
+	}
+
+	// Existing tokens carry the old role and email, and deactivated users must lose access,
+	// so revoke them
+	if roleChanged || emailChanged || (activeChanged && !u.IsActive) {
+		if err := revokeTokens(ctx, uc.userRepo, uc.cache, u.ID); err != nil {
+			return nil, wrapError("failed to revoke user tokens", err)
+		}
+	} else if activeChanged {
+		if err := invalidateTokenState(ctx, uc.cache, u.ID); err != nil {
+			return nil, wrapError("failed to invalidate token state", err)
+		}
+	}
+
+	response := toUserResponse(u)
+	return &response, nil
+}
+
+// UpdateProfile applies the self-service profile fields of the given user
+func (uc *userUseCase) UpdateProfile(ctx context.Context, id uint, req user.UpdateProfileRequest) (*user.UserResponse, error) {
+	u, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, user.ErrNotFound
+	}
+
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if username == "" {
+			u.Username = nil
+		} else {
+			if !user.IsValidUsername(username) {
+				return nil, user.ErrInvalidUsername
+			}
+			existingUser, _ := uc.userRepo.GetByUsername(ctx, username)
+			if existingUser != nil && existingUser.ID != u.ID {
+				return nil, user.ErrUsernameInUse
+			}
+			u.Username = &username
+		}
+	}
+	if req.Name != nil {
+		u.Name = *req.Name
+	}
+	if req.Phone != nil {
+		u.Phone = req.Phone
+	}
+	if req.Address != nil {
+		u.Address = req.Address
+	}
+	if req.Lat != nil {
+		u.Lat = req.Lat
+	}
+	if req.Lng != nil {
+		u.Lng = req.Lng
+	}
+
+	if err := uc.userRepo.Update(ctx, u); err != nil {
+		return nil, wrapError("failed to update profile", err)
+	}
+
+	response := toUserResponse(u)
+	return &response, nil
 }
 
+// DeleteUser signs the user out everywhere and soft-deletes the account
 func (uc *userUseCase) DeleteUser(ctx context.Context, id uint) error {
 	// Check if user exists
 	_, err := uc.userRepo.GetByID(ctx, id)
 	if err != nil {
-		return errors.New("user not found")
+		return user.ErrNotFound
+	}
+
+	// Revoke first: the version of a deleted user can no longer be bumped
+	if err := revokeTokens(ctx, uc.userRepo, uc.cache, id); err != nil {
+		return wrapError("failed to revoke user tokens", err)
 	}
 
 	if err := uc.userRepo.Delete(ctx, id); err != nil {
-		return errors.New("failed to delete user")
+		return wrapError("failed to delete user", err)
 	}
 
 	return nil
 }
 
-func (uc *userUseCase) GetUsersByRole(ctx context.Context, role string, page, limit int) (*user.UsersListResponse, error) {
-	if page < 1 {
-		page = 1
+func (uc *userUseCase) ForceLogout(ctx context.Context, id uint) error {
+	// Check if user exists
+	_, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return user.ErrNotFound
+	}
+
+	if err := revokeTokens(ctx, uc.userRepo, uc.cache, id); err != nil {
+		return wrapError("failed to revoke user tokens", err)
+	}
+
+	return nil
+}
+
+// MergeUsers folds a duplicate account into a primary one: the source's posts,
+// collaborations and reports move to the target in one transaction, then the source is
+// signed out and soft-deleted. Where both accounts hold the same collaboration or report,
+// the target's is kept.
+func (uc *userUseCase) MergeUsers(ctx context.Context, req user.MergeUsersRequest) (*user.MergeUsersResponse, error) {
+	if req.SourceID == req.TargetID {
+		return nil, user.ErrMergeSameUser
+	}
+
+	source, err := uc.userRepo.GetByID(ctx, req.SourceID)
+	if err != nil {
+		return nil, user.ErrNotFound
+	}
+	target, err := uc.userRepo.GetByID(ctx, req.TargetID)
+	if err != nil {
+		return nil, user.ErrNotFound
 	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+
+	// Merging an admin into a non-admin removes an admin, so make sure another one remains
+	if source.Role == user.RoleAdmin && target.Role != user.RoleAdmin {
+		admins, err := uc.userRepo.GetCountByRole(ctx, user.RoleAdmin)
+		if err != nil {
+			return nil, wrapError("failed to count admins", err)
+		}
+		if admins <= 1 {
+			return nil, user.ErrLastAdmin
+		}
 	}
 
-	offset := (page - 1) * limit
+	response := &user.MergeUsersResponse{SourceID: source.ID, TargetID: target.ID}
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context, repos transaction.Repositories) error {
+		var err error
+		if response.PostsMoved, err = repos.Posts.ReassignAuthor(ctx, source.ID, target.ID); err != nil {
+			return wrapError("failed to reassign posts", err)
+		}
+		if response.CollaborationsMoved, err = repos.Posts.ReassignCollaborations(ctx, source.ID, target.ID); err != nil {
+			return wrapError("failed to reassign collaborations", err)
+		}
+		if response.ReportsMoved, err = repos.Reports.ReassignReporter(ctx, source.ID, target.ID); err != nil {
+			return wrapError("failed to reassign reports", err)
+		}
+
+		if err := repos.Users.RevokeRefreshTokens(ctx, source.ID); err != nil {
+			return wrapError("failed to revoke refresh tokens", err)
+		}
+		if err := repos.Users.IncrementTokenVersion(ctx, source.ID); err != nil {
+			return wrapError("failed to revoke user tokens", err)
+		}
+		if err := repos.Users.Delete(ctx, source.ID); err != nil {
+			return wrapError("failed to delete merged user", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := invalidateTokenState(ctx, uc.cache, source.ID); err != nil {
+		return nil, wrapError("failed to invalidate token state", err)
+	}
+
+	return response, nil
+}
+
+// GetPendingUsers lists registrations awaiting approval, oldest first
+func (uc *userUseCase) GetPendingUsers(ctx context.Context, page, limit int) (*user.UsersListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := uc.userRepo.GetPendingApproval(ctx, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to fetch pending users", err)
+	}
+
+	total, err := uc.userRepo.CountPendingApproval(ctx)
+	if err != nil {
+		return nil, wrapError("failed to count pending users", err)
+	}
+
+	userResponses := make([]user.UserResponse, len(users))
+	for i, u := range users {
+		userResponses[i] = toUserResponse(u)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &user.UsersListResponse{
+		Users:      userResponses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ApproveUser activates a user whose registration is pending approval
+func (uc *userUseCase) ApproveUser(ctx context.Context, id uint) (*user.UserResponse, error) {
+	u, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, user.ErrNotFound
+	}
+
+	if !u.PendingApproval {
+		return nil, user.ErrNotPendingApproval
+	}
+
+	u.IsActive = true
+	u.PendingApproval = false
+	if err := uc.userRepo.Update(ctx, u); err != nil {
+		return nil, wrapError("failed to approve user", err)
+	}
+
+	if err := invalidateTokenState(ctx, uc.cache, u.ID); err != nil {
+		return nil, wrapError("failed to invalidate token state", err)
+	}
+
+	response := toUserResponse(u)
+	return &response, nil
+}
+
+// RejectUser deletes a registration that is pending approval
+func (uc *userUseCase) RejectUser(ctx context.Context, id uint) error {
+	u, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return user.ErrNotFound
+	}
+
+	if !u.PendingApproval {
+		return user.ErrNotPendingApproval
+	}
+
+	if err := uc.userRepo.Delete(ctx, id); err != nil {
+		return wrapError("failed to reject user", err)
+	}
+
+	return nil
+}
+
+func (uc *userUseCase) GetUsersByRole(ctx context.Context, role string, page, limit int) (*user.UsersListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return nil, err
+	}
 
 	users, err := uc.userRepo.GetByRole(ctx, role, limit, offset)
 	if err != nil {
-		return nil, errors.New("failed to fetch users by role")
+		return nil, wrapError("failed to fetch users by role", err)
 	}
 
-	// Count users by role (you might want to add this method to repository)
-	total, err := uc.userRepo.GetTotalCount(ctx)
+	total, err := uc.userRepo.GetCountByRole(ctx, role)
 	if err != nil {
-		return nil, errors.New("failed to count users")
+		return nil, wrapError("failed to count users by role", err)
 	}
 
 	userResponses := make([]user.UserResponse, len(users))
 	for i, u := range users {
-		userResponses[i] = user.UserResponse{
-			ID:        u.ID,
-			Email:     u.Email,
-			Name:      u.Name,
-			Phone:     getStringValue(u.Phone),
-			Address:   getStringValue(u.Address),
-			Lat:       getFloat64Value(u.Lat),
-			Lng:       getFloat64Value(u.Lng),
-			Role:      u.Role,
-			IsActive:  u.IsActive,
-			CreatedAt: u.CreatedAt,
-			UpdatedAt: u.UpdatedAt,
-		}
+		userResponses[i] = toUserResponse(u)
 	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
@@ -206,3 +420,80 @@ func (uc *userUseCase) GetUsersByRole(ctx context.Context, role string, page, li
 		TotalPages: totalPages,
 	}, nil
 }
+
+func (uc *userUseCase) GetPermissions(ctx context.Context, id uint) (*user.PermissionsResponse, error) {
+	// Use the stored role rather than the token claim so changes apply immediately
+	u, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, user.ErrNotFound
+	}
+
+	return &user.PermissionsResponse{
+		Role:        u.Role,
+		Permissions: user.PermissionsForRole(u.Role),
+	}, nil
+}
+
+// GetNames maps user IDs to display names. Deleted or unknown users are left out.
+func (uc *userUseCase) GetNames(ctx context.Context, ids []uint) (map[uint]string, error) {
+	users, err := uc.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, wrapError("failed to fetch users", err)
+	}
+
+	names := make(map[uint]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+	return names, nil
+}
+
+func (uc *userUseCase) GetNotificationPreferences(ctx context.Context, id uint) (*user.NotificationPreferences, error) {
+	prefs, err := uc.userRepo.GetNotificationPreferences(ctx, id)
+	if err != nil {
+		return nil, wrapError("failed to fetch notification preferences", err)
+	}
+	return prefs, nil
+}
+
+// UpdateNotificationPreferences changes the categories present in req, keeping the others
+func (uc *userUseCase) UpdateNotificationPreferences(ctx context.Context, id uint, req user.UpdateNotificationPreferencesRequest) (*user.NotificationPreferences, error) {
+	prefs, err := uc.userRepo.GetNotificationPreferences(ctx, id)
+	if err != nil {
+		return nil, wrapError("failed to fetch notification preferences", err)
+	}
+
+	if req.CommentReplies != nil {
+		prefs.CommentReplies = *req.CommentReplies
+	}
+	if req.PostPublished != nil {
+		prefs.PostPublished = *req.PostPublished
+	}
+	if req.Marketing != nil {
+		prefs.Marketing = *req.Marketing
+	}
+
+	if err := uc.userRepo.SaveNotificationPreferences(ctx, prefs); err != nil {
+		return nil, wrapError("failed to save notification preferences", err)
+	}
+	return prefs, nil
+}
+
+// toUserResponse converts a user into its API representation
+func toUserResponse(u *user.User) user.UserResponse {
+	return user.UserResponse{
+		ID:              u.ID,
+		Email:           u.Email,
+		Username:        getStringValue(u.Username),
+		Name:            u.Name,
+		Phone:           getStringValue(u.Phone),
+		Address:         getStringValue(u.Address),
+		Lat:             getFloat64Value(u.Lat),
+		Lng:             getFloat64Value(u.Lng),
+		Role:            u.Role,
+		IsActive:        u.IsActive,
+		PendingApproval: u.PendingApproval,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+)
+
+func TestGetUsersByRoleCountsOnlyThatRole(t *testing.T) {
+	if err := config.LoadConfig("../../configs/config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &fakeUserRepo{users: map[uint]*user.User{
+		1: {ID: 1, Role: "admin"},
+		2: {ID: 2, Role: "user"},
+		3: {ID: 3, Role: "admin"},
+		4: {ID: 4, Role: "user"},
+		5: {ID: 5, Role: "user"},
+		6: {ID: 6, Role: "editor"},
+	}}
+	uc := NewUserUseCase(repo, nil, nil)
+
+	resp, err := uc.GetUsersByRole(context.Background(), "admin", 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Total != 2 {
+		t.Errorf("expected a total of 2 admins, got %d", resp.Total)
+	}
+	if resp.TotalPages != 2 {
+		t.Errorf("expected 2 pages, got %d", resp.TotalPages)
+	}
+	if len(resp.Users) != 1 || resp.Users[0].ID != 1 {
+		t.Errorf("expected the first admin on page 1, got %+v", resp.Users)
+	}
+}
+
+func TestGetPermissionsUsesStoredRole(t *testing.T) {
+	ctx := context.Background()
+	// The user was promoted after their token was issued
+	repo := &fakeUserRepo{users: map[uint]*user.User{1: {ID: 1, Role: user.RoleEditor}}}
+	uc := NewUserUseCase(repo, nil, nil)
+
+	resp, err := uc.GetPermissions(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Role != user.RoleEditor || !slices.Equal(resp.Permissions, user.PermissionsForRole(user.RoleEditor)) {
+		t.Errorf("expected the editor permissions, got %+v", resp)
+	}
+
+	if _, err := uc.GetPermissions(ctx, 99); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("expected %v for an unknown user, got %v", user.ErrNotFound, err)
+	}
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        string
+		wantRole    string
+		wantErr     error
+		wantRevoked bool
+	}{
+		{"normalized", " Editor ", user.RoleEditor, nil, true},
+		{"unchanged", "USER", user.RoleUser, nil, false},
+		{"unknown", "superuser", user.RoleUser, user.ErrInvalidRole, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{users: map[uint]*user.User{1: {ID: 1, Role: user.RoleUser, TokenVersion: 1}}}
+			uc := NewUserUseCase(repo, nil, cache.New(nil, cache.Options{}))
+
+			role := tt.role
+			_, err := uc.UpdateUser(context.Background(), 1, user.AdminUpdateUserRequest{Role: &role})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			stored := repo.users[1]
+			if stored.Role != tt.wantRole {
+				t.Errorf("expected role %q, got %q", tt.wantRole, stored.Role)
+			}
+			// Tokens carry the role, so a change revokes them
+			if revoked := stored.TokenVersion != 1; revoked != tt.wantRevoked {
+				t.Errorf("expected tokens revoked %v, got version %d", tt.wantRevoked, stored.TokenVersion)
+			}
+		})
+	}
+}
+
+func TestGetNames(t *testing.T) {
+	repo := &fakeUserRepo{users: map[uint]*user.User{
+		1: {ID: 1, Name: "Alice"},
+		2: {ID: 2, Name: "Bob"},
+		3: {ID: 3, Name: "Carol"},
+	}}
+	uc := NewUserUseCase(repo, nil, nil)
+
+	names, err := uc.GetNames(context.Background(), []uint{1, 3, 99})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[uint]string{1: "Alice", 3: "Carol"}
+	if len(names) != len(want) || names[1] != want[1] || names[3] != want[3] {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+	if repo.batchQueries != 1 {
+		t.Errorf("expected one batched query, got %d", repo.batchQueries)
+	}
+}
+
+func TestUpdateUserEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		wantEmail   string
+		wantErr     error
+		wantRevoked bool
+	}{
+		{"new address", " New@Example.com ", "new@example.com", nil, true},
+		{"case change only", "OLD@example.com", "old@example.com", nil, false},
+		{"taken address", "taken@example.com", "old@example.com", user.ErrEmailInUse, false},
+		{"taken address in another case", "Taken@Example.COM", "old@example.com", user.ErrEmailInUse, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pending := "pending@example.com"
+			repo := &fakeUserRepo{users: map[uint]*user.User{
+				1: {ID: 1, Email: "old@example.com", PendingEmail: &pending, TokenVersion: 1},
+				2: {ID: 2, Email: "taken@example.com"},
+			}}
+			uc := NewUserUseCase(repo, nil, cache.New(nil, cache.Options{}))
+
+			email := tt.email
+			_, err := uc.UpdateUser(context.Background(), 1, user.AdminUpdateUserRequest{Email: &email})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			stored := repo.users[1]
+			if stored.Email != tt.wantEmail {
+				t.Errorf("expected email %q, got %q", tt.wantEmail, stored.Email)
+			}
+			// A rejected change leaves the user's own pending change alone
+			if (stored.PendingEmail == nil) != (err == nil) {
+				t.Errorf("expected the pending change cleared only on success, got %v", stored.PendingEmail)
+			}
+			if revoked := stored.TokenVersion != 1; revoked != tt.wantRevoked {
+				t.Errorf("expected tokens revoked %v, got version %d", tt.wantRevoked, stored.TokenVersion)
+			}
+		})
+	}
+}
+
+func TestToUserResponse(t *testing.T) {
+	username, phone, lat := "moon", "0900000000", 10.5
+	u := &user.User{
+		ID: 1, Email: "a@example.com", Username: &username, Name: "A", Phone: &phone, Lat: &lat,
+		Role: user.RoleUser, PendingApproval: true,
+	}
+
+	got := toUserResponse(u)
+	want := user.UserResponse{
+		ID: 1, Email: "a@example.com", Username: "moon", Name: "A", Phone: "0900000000", Lat: 10.5,
+		Role: user.RoleUser, PendingApproval: true,
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	// Unset optional fields come out as zero values
+	if got := toUserResponse(&user.User{ID: 2}); got.Username != "" || got.Address != "" || got.Lng != 0 {
+		t.Errorf("expected empty optional fields, got %+v", got)
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"moon/internal/config"
+	"moon/internal/domain/user"
+	"moon/pkg/cache"
+	"moon/pkg/hash"
+)
+
+func TestLoginByUsernameOrEmail(t *testing.T) {
+	ctx := context.Background()
+	password, err := hash.HashPassword("secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, bob := "alice", "bob"
+
+	tests := []struct {
+		name          string
+		usernameLogin bool
+		req           user.LoginRequest
+		wantID        uint
+		wantErr       error
+	}{
+		{"username", true, user.LoginRequest{Identifier: "alice", Password: "secret123"}, 1, nil},
+		{"username with spaces", true, user.LoginRequest{Identifier: "  bob ", Password: "secret123"}, 2, nil},
+		{"email as identifier", true, user.LoginRequest{Identifier: "bob@example.com", Password: "secret123"}, 2, nil},
+		{"email field", true, user.LoginRequest{Email: "alice@example.com", Password: "secret123"}, 1, nil},
+		{"identifier wins over email", true, user.LoginRequest{Identifier: "bob", Email: "alice@example.com", Password: "secret123"}, 2, nil},
+		{"username with wrong password", true, user.LoginRequest{Identifier: "alice", Password: "wrong"}, 0, user.ErrInvalidCredentials},
+		{"unknown username", true, user.LoginRequest{Identifier: "carol", Password: "secret123"}, 0, user.ErrInvalidCredentials},
+		{"username login disabled", false, user.LoginRequest{Identifier: "alice", Password: "secret123"}, 0, user.ErrInvalidCredentials},
+		{"email with username login disabled", false, user.LoginRequest{Identifier: "alice@example.com", Password: "secret123"}, 1, nil},
+		{"no identifier", true, user.LoginRequest{Password: "secret123"}, 0, user.ErrIdentifierRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{users: map[uint]*user.User{
+				1: {ID: 1, Email: "alice@example.com", Username: &alice, Password: password, IsActive: true},
+				2: {ID: 2, Email: "bob@example.com", Username: &bob, Password: password, IsActive: true},
+			}}
+			cfg := &config.Config{}
+			cfg.JWT.Secret = "test-secret"
+			cfg.JWT.ExpiresIn = 1
+			cfg.JWT.RefreshExpiresIn = 24
+			cfg.Account.UsernameLogin = tt.usernameLogin
+			uc := NewAuthUseCase(repo, cache.New(nil, cache.Options{}), nil, cfg)
+
+			resp, err := uc.Login(ctx, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && resp.User.ID != tt.wantID {
+				t.Errorf("expected user %d to be logged in, got %d", tt.wantID, resp.User.ID)
+			}
+		})
+	}
+}
+
+// Usernames are unique and can never look like an email address, so an identifier is
+// never ambiguous
+func TestUsernameCollision(t *testing.T) {
+	ctx := context.Background()
+	alice := "alice"
+
+	tests := []struct {
+		name     string
+		userID   uint
+		username string
+		wantErr  error
+	}{
+		{"taken by another user", 2, "alice", user.ErrUsernameInUse},
+		{"kept by its owner", 1, "alice", nil},
+		{"free", 2, "bob", nil},
+		{"shaped like an email", 2, "alice@example.com", user.ErrInvalidUsername},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{users: map[uint]*user.User{
+				1: {ID: 1, Email: "alice@example.com", Username: &alice, IsActive: true},
+				2: {ID: 2, Email: "bob@example.com", IsActive: true},
+			}}
+			uc := NewUserUseCase(repo, nil, cache.New(nil, cache.Options{}))
+
+			username := tt.username
+			_, err := uc.UpdateProfile(ctx, tt.userID, user.UpdateProfileRequest{Username: &username})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && tt.userID == 2 && repo.users[2].Username != nil {
+				t.Errorf("expected the rejected username not to be stored, got %q", *repo.users[2].Username)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+-- Add token version to users for instant token invalidation
+ALTER TABLE users ADD COLUMN token_version INT NOT NULL DEFAULT 0 AFTER is_active;
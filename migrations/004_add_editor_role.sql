@@ -0,0 +1,2 @@
+-- Add editor role to users
+ALTER TABLE users MODIFY role ENUM('user', 'editor', 'admin') DEFAULT 'user';
@@ -0,0 +1,5 @@
+-- Add scheduled publishing to posts
+ALTER TABLE posts
+    ADD COLUMN publish_at TIMESTAMP NULL AFTER published_at,
+    ADD COLUMN scheduled_by INT NULL AFTER publish_at,
+    ADD INDEX idx_posts_publish_at (publish_at);
@@ -0,0 +1,19 @@
+-- Create reports table
+CREATE TABLE IF NOT EXISTS reports (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    target_type VARCHAR(50) NOT NULL,
+    target_id INT NOT NULL,
+    reporter_id INT NOT NULL,
+    reason TEXT NOT NULL,
+    status ENUM('open', 'reviewed', 'dismissed') DEFAULT 'open',
+    reviewed_by INT NULL,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE,
+
+    -- One report per user per target
+    UNIQUE INDEX idx_reports_reporter_target (target_type, target_id, reporter_id),
+    INDEX idx_reports_status (status)
+);
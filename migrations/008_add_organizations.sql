@@ -0,0 +1,29 @@
+-- Create organizations table
+CREATE TABLE IF NOT EXISTS organizations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Scope tenant-owned tables to an organization
+ALTER TABLE users
+    ADD COLUMN org_id INT NULL AFTER id,
+    ADD INDEX idx_users_org_id (org_id),
+    ADD CONSTRAINT fk_users_org FOREIGN KEY (org_id) REFERENCES organizations(id);
+
+ALTER TABLE posts
+    ADD COLUMN org_id INT NULL AFTER id,
+    ADD INDEX idx_posts_org_id (org_id),
+    ADD CONSTRAINT fk_posts_org FOREIGN KEY (org_id) REFERENCES organizations(id);
+
+ALTER TABLE categories
+    ADD COLUMN org_id INT NULL AFTER id,
+    ADD INDEX idx_categories_org_id (org_id),
+    ADD CONSTRAINT fk_categories_org FOREIGN KEY (org_id) REFERENCES organizations(id);
+
+ALTER TABLE reports
+    ADD COLUMN org_id INT NULL AFTER id,
+    ADD INDEX idx_reports_org_id (org_id),
+    ADD CONSTRAINT fk_reports_org FOREIGN KEY (org_id) REFERENCES organizations(id);
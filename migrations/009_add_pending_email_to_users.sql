@@ -0,0 +1,6 @@
+-- Pending email changes awaiting confirmation
+ALTER TABLE users
+    ADD COLUMN pending_email VARCHAR(255) NULL,
+    ADD COLUMN email_change_token_hash VARCHAR(64) NULL,
+    ADD COLUMN email_change_expires_at TIMESTAMP NULL,
+    ADD INDEX idx_users_email_change_token_hash (email_change_token_hash);
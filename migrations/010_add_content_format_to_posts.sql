@@ -0,0 +1,4 @@
+-- Content format and cached rendering of markdown posts
+ALTER TABLE posts
+    ADD COLUMN content_format VARCHAR(20) NOT NULL DEFAULT 'html' AFTER content,
+    ADD COLUMN content_html TEXT NULL AFTER content_format;
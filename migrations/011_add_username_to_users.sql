@@ -0,0 +1,4 @@
+-- Optional unique username usable as a login identifier
+ALTER TABLE users
+    ADD COLUMN username VARCHAR(30) NULL,
+    ADD UNIQUE INDEX idx_users_username (username);
@@ -0,0 +1,3 @@
+-- Per-post toggle for accepting new comments
+ALTER TABLE posts
+    ADD COLUMN allow_comments BOOLEAN NOT NULL DEFAULT TRUE AFTER is_public;
@@ -0,0 +1,4 @@
+-- Allow post content up to the configured post.max_content_bytes (at most 16 MiB)
+ALTER TABLE posts
+    MODIFY COLUMN content MEDIUMTEXT NOT NULL,
+    MODIFY COLUMN content_html MEDIUMTEXT NULL;
@@ -0,0 +1,19 @@
+-- SEO-friendly category slugs derived from the name
+ALTER TABLE categories
+    ADD COLUMN slug VARCHAR(120) NULL AFTER name;
+
+UPDATE categories
+SET slug = TRIM(BOTH '-' FROM LOWER(REGEXP_REPLACE(name, '[^A-Za-z0-9]+', '-')));
+
+-- Disambiguate categories sharing a name by appending their id
+UPDATE categories c
+JOIN (
+    SELECT slug, MIN(id) AS first_id
+    FROM categories
+    GROUP BY slug
+    HAVING COUNT(*) > 1
+) dup ON dup.slug = c.slug AND c.id <> dup.first_id
+SET c.slug = CONCAT(c.slug, '-', c.id);
+
+ALTER TABLE categories
+    ADD UNIQUE INDEX idx_categories_slug (slug);
@@ -0,0 +1,12 @@
+-- Users other than the author who may view or edit a post
+CREATE TABLE IF NOT EXISTS post_collaborators (
+    post_id INT NOT NULL,
+    user_id INT NOT NULL,
+    permission VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (post_id, user_id),
+    INDEX idx_post_collaborators_user_id (user_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+-- Registrations awaiting admin approval when account.require_approval is on
+ALTER TABLE users
+    ADD COLUMN pending_approval BOOLEAN NOT NULL DEFAULT FALSE AFTER is_active,
+    ADD INDEX idx_users_pending_approval (pending_approval);
@@ -0,0 +1,4 @@
+-- Language of each post as an ISO 639-1 code; existing posts get the default site language
+ALTER TABLE posts
+    ADD COLUMN language VARCHAR(2) NOT NULL DEFAULT 'vi' AFTER content_html,
+    ADD INDEX idx_posts_language (language);
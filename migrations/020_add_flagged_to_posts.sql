@@ -0,0 +1,4 @@
+-- Posts containing blocked words when moderation.action is flag
+ALTER TABLE posts
+    ADD COLUMN flagged BOOLEAN NOT NULL DEFAULT FALSE AFTER allow_comments,
+    ADD INDEX idx_posts_flagged (flagged);
@@ -0,0 +1,21 @@
+-- Create products table; prices are stored in minor units of the currency
+CREATE TABLE IF NOT EXISTS products (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    org_id INT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    price BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    stock INT DEFAULT 0,
+    category_id INT NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+
+    INDEX idx_products_org_id (org_id),
+    INDEX idx_products_category_id (category_id),
+    INDEX idx_products_deleted_at (deleted_at),
+    CONSTRAINT fk_products_org FOREIGN KEY (org_id) REFERENCES organizations(id),
+    CONSTRAINT fk_products_category FOREIGN KEY (category_id) REFERENCES categories(id)
+);
@@ -0,0 +1,7 @@
+-- Tags are matched on their lowercased name rather than an ASCII slug, so letters outside
+-- ASCII are kept. Compare slugs byte for byte so accented spellings stay distinct tags.
+ALTER TABLE tags
+    MODIFY slug VARCHAR(120) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL;
+
+UPDATE tags
+SET slug = TRIM(BOTH '-' FROM LOWER(REGEXP_REPLACE(TRIM(name), '[[:space:]_-]+', '-')));
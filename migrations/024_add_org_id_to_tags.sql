@@ -0,0 +1,26 @@
+-- Scope tags to an organization; slugs are unique within one
+ALTER TABLE tags
+    ADD COLUMN org_id INT NULL AFTER id,
+    ADD CONSTRAINT fk_tags_org FOREIGN KEY (org_id) REFERENCES organizations(id),
+    DROP INDEX slug,
+    ADD UNIQUE INDEX idx_tags_org_slug (org_id, slug);
+
+-- Give each organization its own copy of the tags its posts use
+INSERT INTO tags (org_id, name, slug, created_at)
+SELECT DISTINCT p.org_id, t.name, t.slug, t.created_at
+FROM tags t
+JOIN post_tags pt ON pt.tag_id = t.id
+JOIN posts p ON p.id = pt.post_id
+WHERE t.org_id IS NULL AND p.org_id IS NOT NULL;
+
+UPDATE post_tags pt
+JOIN posts p ON p.id = pt.post_id
+JOIN tags shared ON shared.id = pt.tag_id AND shared.org_id IS NULL
+JOIN tags own ON own.slug = shared.slug AND own.org_id = p.org_id
+SET pt.tag_id = own.id;
+
+-- Drop the shared tags that were copied and are no longer linked
+DELETE FROM tags
+WHERE org_id IS NULL
+  AND id NOT IN (SELECT tag_id FROM post_tags)
+  AND slug IN (SELECT slug FROM (SELECT slug FROM tags WHERE org_id IS NOT NULL) copied);
@@ -0,0 +1,15 @@
+-- Post and category slugs, emails and usernames are unique within an organization, like
+-- tags, so each organization can reuse them
+ALTER TABLE users
+    DROP INDEX email,
+    DROP INDEX idx_users_username,
+    ADD UNIQUE INDEX idx_users_org_email (org_id, email),
+    ADD UNIQUE INDEX idx_users_org_username (org_id, username);
+
+ALTER TABLE posts
+    DROP INDEX slug,
+    ADD UNIQUE INDEX idx_posts_org_slug (org_id, slug);
+
+ALTER TABLE categories
+    DROP INDEX idx_categories_slug,
+    ADD UNIQUE INDEX idx_categories_org_slug (org_id, slug);
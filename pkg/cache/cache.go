@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"moon/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrMiss is returned when a key does not exist
+	ErrMiss = errors.New("cache miss")
+	// ErrUnavailable is returned while Redis is unreachable or the circuit is open
+	ErrUnavailable = errors.New("cache unavailable")
+)
+
+// Options controls the circuit breaker
+type Options struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before a request is let through again
+	Cooldown time.Duration
+}
+
+// Cache wraps a Redis client with a circuit breaker so callers can degrade
+// gracefully instead of failing requests when Redis is down
+type Cache struct {
+	client *redis.Client
+	opts   Options
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// New creates a cache around client. A nil client yields a cache that is always unavailable.
+func New(client *redis.Client, opts Options) *Cache {
+	if opts.FailureThreshold < 1 {
+		opts.FailureThreshold = 3
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 30 * time.Second
+	}
+	return &Cache{
+		client: client,
+		opts:   opts,
+	}
+}
+
+// Configured reports whether the cache has a Redis client, reachable or not
+func (c *Cache) Configured() bool {
+	return c != nil && c.client != nil
+}
+
+// Available reports whether requests are currently sent to Redis
+func (c *Cache) Available() bool {
+	if c == nil || c.client == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Now().After(c.openUntil)
+}
+
+// Ping checks the connection to Redis
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.do(func() error {
+		return c.client.Ping(ctx).Err()
+	})
+}
+
+// Get returns the value stored at key
+func (c *Cache) Get(ctx context.Context, key string) (string, error) {
+	var value string
+	err := c.do(func() error {
+		var err error
+		value, err = c.client.Get(ctx, key).Result()
+		return err
+	})
+	return value, err
+}
+
+// Set stores value at key. A zero ttl means no expiration.
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.do(func() error {
+		return c.client.Set(ctx, key, value, ttl).Err()
+	})
+}
+
+// SetNX stores value at key only if it does not exist yet and reports whether it was set
+func (c *Cache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	var ok bool
+	err := c.do(func() error {
+		var err error
+		ok, err = c.client.SetNX(ctx, key, value, ttl).Result()
+		return err
+	})
+	return ok, err
+}
+
+// Del removes the given keys
+func (c *Cache) Del(ctx context.Context, keys ...string) error {
+	return c.do(func() error {
+		return c.client.Del(ctx, keys...).Err()
+	})
+}
+
+// Incr increments the integer stored at key and returns the new value
+func (c *Cache) Incr(ctx context.Context, key string) (int64, error) {
+	var value int64
+	err := c.do(func() error {
+		var err error
+		value, err = c.client.Incr(ctx, key).Result()
+		return err
+	})
+	return value, err
+}
+
+// IncrWithExpiry increments the counter at key and sets ttl on it unless it already has a
+// timeout, in one transaction. Setting the timeout with every increment means a counter
+// never outlives its window, even when an earlier request failed halfway.
+func (c *Cache) IncrWithExpiry(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	err := c.do(func() error {
+		_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			incr = pipe.Incr(ctx, key)
+			pipe.ExpireNX(ctx, key, ttl)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// Expire sets a timeout on key
+func (c *Cache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return c.do(func() error {
+		return c.client.Expire(ctx, key, ttl).Err()
+	})
+}
+
+// TTL returns the remaining time to live of key
+func (c *Cache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	var ttl time.Duration
+	err := c.do(func() error {
+		var err error
+		ttl, err = c.client.TTL(ctx, key).Result()
+		return err
+	})
+	return ttl, err
+}
+
+// Close closes the underlying client
+func (c *Cache) Close() error {
+	if c == nil || c.client == nil {
+		return nil
+	}
+	return c.client.Close()
+}
+
+// do runs fn through the circuit breaker. Context errors are returned as they are and don't
+// count as Redis failures.
+func (c *Cache) do(fn func() error) error {
+	if !c.Available() {
+		return ErrUnavailable
+	}
+
+	err := fn()
+	if errors.Is(err, redis.Nil) {
+		c.recordSuccess()
+		return ErrMiss
+	}
+	// The caller gave up, e.g. the client disconnected; that says nothing about Redis
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if err != nil {
+		c.recordFailure(err)
+		return ErrUnavailable
+	}
+
+	c.recordSuccess()
+	return nil
+}
+
+func (c *Cache) recordSuccess() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failures >= c.opts.FailureThreshold {
+		logger.Info("Redis recovered, leaving degraded mode")
+	}
+	c.failures = 0
+}
+
+func (c *Cache) recordFailure(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures++
+	if c.failures >= c.opts.FailureThreshold {
+		c.openUntil = time.Now().Add(c.opts.Cooldown)
+		logger.Warn("Redis unavailable, running in degraded mode",
+			zap.Error(err),
+			zap.Int("consecutive_failures", c.failures),
+			zap.Duration("retry_in", c.opts.Cooldown),
+		)
+	}
+}
@@ -0,0 +1,87 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"moon/pkg/cache"
+	"moon/pkg/cache/cachetest"
+)
+
+func TestDegradedMode(t *testing.T) {
+	server, store := cachetest.NewServer(t)
+	ctx := context.Background()
+
+	if err := store.Set(ctx, "key", "value", time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, cache.ErrMiss) {
+		t.Errorf("expected a miss, got %v", err)
+	}
+
+	server.Close()
+
+	// The default threshold is 3 consecutive failures
+	for i := 0; i < 3; i++ {
+		if !store.Available() {
+			t.Fatalf("expected the circuit closed before failure %d", i+1)
+		}
+		if _, err := store.Get(ctx, "key"); !errors.Is(err, cache.ErrUnavailable) {
+			t.Fatalf("expected Redis reported unavailable, got %v", err)
+		}
+	}
+
+	if store.Available() {
+		t.Error("expected the circuit open after repeated failures")
+	}
+	if err := store.Set(ctx, "key", "value", time.Minute); !errors.Is(err, cache.ErrUnavailable) {
+		t.Errorf("expected calls to fail fast while degraded, got %v", err)
+	}
+}
+
+func TestDegradedModeRecovers(t *testing.T) {
+	server, _ := cachetest.NewServer(t)
+	ctx := context.Background()
+
+	// A cache with its own breaker settings around a client of the fake server
+	store := cachetest.NewCache(t, server, cache.Options{FailureThreshold: 1, Cooldown: 20 * time.Millisecond})
+
+	server.Close()
+	if _, err := store.Get(ctx, "key"); !errors.Is(err, cache.ErrUnavailable) {
+		t.Fatalf("expected Redis reported unavailable, got %v", err)
+	}
+	if store.Available() {
+		t.Fatal("expected the circuit open")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !store.Available() {
+		t.Error("expected a request let through again after the cooldown")
+	}
+}
+
+func TestContextErrorsDoNotOpenCircuit(t *testing.T) {
+	server, _ := cachetest.NewServer(t)
+	store := cachetest.NewCache(t, server, cache.Options{FailureThreshold: 1})
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := store.Get(canceled, "key"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := store.Incr(expired, "key"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+
+	if !store.Available() {
+		t.Error("expected callers giving up not to open the circuit")
+	}
+	if err := store.Set(context.Background(), "key", "value", time.Minute); err != nil {
+		t.Errorf("expected Redis still in use, got %v", err)
+	}
+}
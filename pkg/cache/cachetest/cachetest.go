@@ -0,0 +1,265 @@
+// Package cachetest provides an in-memory Redis server for tests of code using the cache
+package cachetest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"moon/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Server speaks just enough RESP2 for the cache: PING, GET, SET (with EX, PX and NX),
+// SETNX, DEL, INCR, EXPIRE (with NX), TTL and MULTI/EXEC. Expiry is recorded but never
+// elapses.
+type Server struct {
+	listener net.Listener
+
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]int
+	conns  map[net.Conn]struct{}
+}
+
+// NewServer starts a server for the test and returns a cache connected to it. The server
+// is stopped when the test ends.
+func NewServer(t testing.TB) (*Server, *cache.Cache) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &Server{
+		listener: listener,
+		values:   make(map[string]string),
+		ttls:     make(map[string]int),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	t.Cleanup(server.Close)
+	go server.accept()
+
+	return server, NewCache(t, server, cache.Options{})
+}
+
+// NewCache returns a cache with the given circuit breaker options connected to server
+func NewCache(t testing.TB, server *Server, opts cache.Options) *cache.Cache {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{
+		Addr:             server.listener.Addr().String(),
+		Protocol:         2,
+		DisableIndentity: true,
+		MaxRetries:       -1,
+	})
+	t.Cleanup(func() { client.Close() })
+	return cache.New(client, opts)
+}
+
+// Get returns the value stored at key, or "" when it does not exist
+func (s *Server) Get(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key]
+}
+
+// TTL returns the expiry set on key in seconds, or -1 when it has none
+func (s *Server) TTL(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ttl, ok := s.ttls[key]; ok {
+		return ttl
+	}
+	return -1
+}
+
+// Close stops the server and drops its connections, so the cache sees Redis go down
+func (s *Server) Close() {
+	s.listener.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	var queued [][]string // commands of an open MULTI block; nil outside one
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		var reply string
+		switch command := strings.ToUpper(args[0]); {
+		case command == "MULTI":
+			queued = [][]string{}
+			reply = "+OK\r\n"
+		case command == "EXEC" && queued != nil:
+			reply = fmt.Sprintf("*%d\r\n", len(queued))
+			for _, args := range queued {
+				reply += s.exec(args)
+			}
+			queued = nil
+		case queued != nil:
+			queued = append(queued, args)
+			reply = "+QUEUED\r\n"
+		default:
+			reply = s.exec(args)
+		}
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) exec(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		value, ok := s.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "SET":
+		return s.set(args[1], args[2], args[3:])
+	case "SETNX":
+		if _, ok := s.values[args[1]]; ok {
+			return ":0\r\n"
+		}
+		s.values[args[1]] = args[2]
+		return ":1\r\n"
+	case "DEL":
+		var deleted int
+		for _, key := range args[1:] {
+			if _, ok := s.values[key]; ok {
+				deleted++
+			}
+			delete(s.values, key)
+			delete(s.ttls, key)
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	case "INCR":
+		count, _ := strconv.ParseInt(s.values[args[1]], 10, 64)
+		count++
+		s.values[args[1]] = strconv.FormatInt(count, 10)
+		return fmt.Sprintf(":%d\r\n", count)
+	case "EXPIRE":
+		if _, ok := s.values[args[1]]; !ok {
+			return ":0\r\n"
+		}
+		if _, ok := s.ttls[args[1]]; ok && len(args) > 3 && strings.EqualFold(args[3], "NX") {
+			return ":0\r\n"
+		}
+		s.ttls[args[1]], _ = strconv.Atoi(args[2])
+		return ":1\r\n"
+	case "TTL":
+		if _, ok := s.values[args[1]]; !ok {
+			return ":-2\r\n"
+		}
+		ttl, ok := s.ttls[args[1]]
+		if !ok {
+			return ":-1\r\n"
+		}
+		return fmt.Sprintf(":%d\r\n", ttl)
+	default:
+		// Includes HELLO, which makes the client fall back to RESP2
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// set handles SET with its EX, PX and NX options
+func (s *Server) set(key, value string, options []string) string {
+	ttl := -1
+	nx := false
+	for i := 0; i < len(options); i++ {
+		switch strings.ToUpper(options[i]) {
+		case "NX":
+			nx = true
+		case "EX":
+			i++
+			ttl, _ = strconv.Atoi(options[i])
+		case "PX":
+			i++
+			ms, _ := strconv.Atoi(options[i])
+			ttl = (ms + 999) / 1000
+		}
+	}
+
+	if _, ok := s.values[key]; ok && nx {
+		return "$-1\r\n"
+	}
+	s.values[key] = value
+	delete(s.ttls, key)
+	if ttl >= 0 {
+		s.ttls[key] = ttl
+	}
+	return "+OK\r\n"
+}
+
+// readCommand reads one command sent as a RESP array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("unexpected argument %q", header)
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
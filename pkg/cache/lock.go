@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// unlockScript deletes the lock only if it is still held by the caller's token
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// TryLock acquires a distributed lock on key for ttl. It returns the token needed to
+// release the lock and whether the lock was acquired.
+func (c *Cache) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, err
+	}
+	token := hex.EncodeToString(buf)
+
+	ok, err := c.SetNX(ctx, key, token, ttl)
+	if err != nil {
+		return "", false, err
+	}
+	return token, ok, nil
+}
+
+// Unlock releases a lock acquired with TryLock
+func (c *Cache) Unlock(ctx context.Context, key, token string) error {
+	return c.do(func() error {
+		return unlockScript.Run(ctx, c.client, []string{key}, token).Err()
+	})
+}
@@ -0,0 +1,138 @@
+package imagepolicy
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Actions applied to images hosted outside the allowed hosts
+const (
+	ActionAllow = "allow" // leave them untouched
+	ActionFlag  = "flag"  // mark them with data-external and no-referrer
+	ActionStrip = "strip" // remove the <img> element
+	ActionProxy = "proxy" // route the source through the image proxy
+)
+
+// IsValidAction reports whether action is a known external image action
+func IsValidAction(action string) bool {
+	switch action {
+	case ActionAllow, ActionFlag, ActionStrip, ActionProxy:
+		return true
+	default:
+		return false
+	}
+}
+
+// Options controls how external images are handled
+type Options struct {
+	Action string
+	// AllowedHosts are treated as local; subdomains of an entry match too
+	AllowedHosts []string
+	// ProxyURL receives the original source in its "url" query parameter
+	ProxyURL string
+}
+
+// Process applies the configured action to every <img> whose src points to an external
+// host and returns the rewritten HTML with the number of external images found. Markup
+// other than the affected <img> tags is copied through unchanged.
+func Process(source string, opts Options) (string, int, error) {
+	if opts.Action == ActionAllow || opts.Action == "" {
+		return source, 0, nil
+	}
+
+	var out bytes.Buffer
+	found := 0
+	z := html.NewTokenizer(strings.NewReader(source))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() == io.EOF {
+				break
+			}
+			return "", 0, z.Err()
+		}
+
+		raw := z.Raw()
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			out.Write(raw)
+			continue
+		}
+
+		token := z.Token()
+		if token.Data != "img" {
+			out.Write(raw)
+			continue
+		}
+
+		src := attr(token, "src")
+		if !isExternal(src, opts.AllowedHosts) {
+			out.Write(raw)
+			continue
+		}
+		found++
+
+		switch opts.Action {
+		case ActionStrip:
+			continue
+		case ActionFlag:
+			token.Attr = setAttr(token.Attr, "data-external", "true")
+			token.Attr = setAttr(token.Attr, "referrerpolicy", "no-referrer")
+		case ActionProxy:
+			token.Attr = setAttr(token.Attr, "src", proxied(opts.ProxyURL, src))
+		}
+		out.WriteString(token.String())
+	}
+
+	return out.String(), found, nil
+}
+
+// isExternal reports whether src is an absolute URL on a host outside allowed
+func isExternal(src string, allowed []string) bool {
+	u, err := url.Parse(strings.TrimSpace(src))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, h := range allowed {
+		h = strings.ToLower(h)
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return false
+		}
+	}
+	return true
+}
+
+func proxied(proxyURL, src string) string {
+	separator := "?"
+	if strings.Contains(proxyURL, "?") {
+		separator = "&"
+	}
+	return proxyURL + separator + "url=" + url.QueryEscape(src)
+}
+
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(attrs []html.Attribute, name, value string) []html.Attribute {
+	for i, a := range attrs {
+		if a.Key == name {
+			attrs[i].Val = value
+			return attrs
+		}
+	}
+	return append(attrs, html.Attribute{Key: name, Val: value})
+}
@@ -0,0 +1,74 @@
+package imagepolicy
+
+import "testing"
+
+func TestProcess(t *testing.T) {
+	const content = `<p>Intro</p><img src="https://other.com/a.png" alt="a"><img src="/local.png"><img src="https://cdn.example.com/b.png">`
+	opts := func(action string) Options {
+		return Options{Action: action, AllowedHosts: []string{"example.com"}, ProxyURL: "https://img.example.com/proxy"}
+	}
+
+	tests := []struct {
+		name      string
+		action    string
+		want      string
+		wantFound int
+	}{
+		{"allow", ActionAllow, content, 0},
+		{"unset", "", content, 0},
+		{"flag", ActionFlag, `<p>Intro</p><img src="https://other.com/a.png" alt="a" data-external="true" referrerpolicy="no-referrer"><img src="/local.png"><img src="https://cdn.example.com/b.png">`, 1},
+		{"strip", ActionStrip, `<p>Intro</p><img src="/local.png"><img src="https://cdn.example.com/b.png">`, 1},
+		{"proxy", ActionProxy, `<p>Intro</p><img src="https://img.example.com/proxy?url=https%3A%2F%2Fother.com%2Fa.png" alt="a"><img src="/local.png"><img src="https://cdn.example.com/b.png">`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := Process(content, opts(tt.action))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected\n%s\ngot\n%s", tt.want, got)
+			}
+			if found != tt.wantFound {
+				t.Errorf("expected %d external images, got %d", tt.wantFound, found)
+			}
+		})
+	}
+}
+
+func TestIsExternal(t *testing.T) {
+	allowed := []string{"example.com"}
+
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"https://other.com/a.png", true},
+		{"http://other.com/a.png", true},
+		{"//other.com/a.png", true},
+		{"  https://other.com/a.png  ", true},
+		{"https://example.com/a.png", false},
+		{"https://cdn.EXAMPLE.com/a.png", false},
+		{"https://notexample.com/a.png", true},
+		{"/uploads/a.png", false},
+		{"a.png", false},
+		{"data:image/png;base64,AAAA", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			if got := isExternal(tt.src, allowed); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProxiedKeepsExistingQuery(t *testing.T) {
+	got := proxied("https://img.example.com/proxy?size=large", "https://other.com/a.png")
+	if want := "https://img.example.com/proxy?size=large&url=https%3A%2F%2Fother.com%2Fa.png"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
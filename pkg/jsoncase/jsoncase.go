@@ -0,0 +1,60 @@
+package jsoncase
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ToCamel rewrites every object key of a JSON document from snake_case to camelCase,
+// leaving values untouched. Numbers are preserved exactly.
+func ToCamel(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(convertKeys(value)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// Camel converts a snake_case key to camelCase, e.g. "total_pages" to "totalPages"
+func Camel(key string) string {
+	parts := strings.Split(key, "_")
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
+
+func convertKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[Camel(key)] = convertKeys(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertKeys(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
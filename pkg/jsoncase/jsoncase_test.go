@@ -0,0 +1,57 @@
+package jsoncase
+
+import "testing"
+
+func TestCamel(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"id", "id"},
+		{"total_pages", "totalPages"},
+		{"content_html", "contentHtml"},
+		{"published_at_utc", "publishedAtUtc"},
+		{"trailing_", "trailing"},
+		{"double__underscore", "doubleUnderscore"},
+		{"alreadyCamel", "alreadyCamel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := Camel(tt.key); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestToCamel(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"nested keys", `{"total_pages":2,"data":{"author_name":"A","tags":[{"tag_name":"go"}]}}`, `{"data":{"authorName":"A","tags":[{"tagName":"go"}]},"totalPages":2}`},
+		{"values untouched", `{"slug":"snake_case_value","html":"<p>a & b</p>"}`, `{"html":"<p>a & b</p>","slug":"snake_case_value"}`},
+		{"large numbers kept exactly", `{"view_count":12345678901234567890,"ratio":0.1}`, `{"ratio":0.1,"viewCount":12345678901234567890}`},
+		{"top-level array", `[{"is_public":true},null]`, `[{"isPublic":true},null]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToCamel([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestToCamelRejectsInvalidJSON(t *testing.T) {
+	if _, err := ToCamel([]byte(`{"broken"`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
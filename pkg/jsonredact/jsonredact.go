@@ -0,0 +1,47 @@
+package jsonredact
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Remove deletes every object key listed in fields from a JSON document, at any depth.
+// Numbers are preserved exactly.
+func Remove(data []byte, fields map[string]bool) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(removeKeys(value, fields)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func removeKeys(value interface{}, fields map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if fields[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = removeKeys(item, fields)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = removeKeys(item, fields)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -8,19 +8,30 @@ import (
 )
 
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID       uint   `json:"user_id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	TokenVersion int    `json:"token_version"`
+	OrgID        uint   `json:"org_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates a new JWT token
-func GenerateToken(userID uint, email, role, secret string, expiresIn int) (string, error) {
+// GenerateToken creates a new JWT token. Each token gets a random ID (the jti claim,
+// Claims.ID) so it can be revoked on its own.
+func GenerateToken(userID uint, email, role string, tokenVersion int, orgID uint, secret string, expiresIn int) (string, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:       userID,
+		Email:        email,
+		Role:         role,
+		TokenVersion: tokenVersion,
+		OrgID:        orgID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expiresIn) * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -31,8 +42,13 @@ func GenerateToken(userID uint, email, role, secret string, expiresIn int) (stri
 	return token.SignedString([]byte(secret))
 }
 
-// ValidateToken validates and parses a JWT token
-func ValidateToken(tokenString, secret string) (*Claims, error) {
+var (
+	ErrTokenExpired = errors.New("token has expired")
+	ErrTokenInvalid = errors.New("invalid token")
+)
+
+// ParseToken verifies the signature and expiry of a JWT token and returns its claims
+func ParseToken(tokenString, secret string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
@@ -41,12 +57,22 @@ func ValidateToken(tokenString, secret string) (*Claims, error) {
 	})
 
 	if err != nil {
-		return nil, err
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrTokenInvalid
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
 		return claims, nil
 	}
 
-	return nil, errors.New("invalid token")
+	return nil, ErrTokenInvalid
+}
+
+// ValidateToken validates and parses a JWT token
+//
+// Deprecated: use ParseToken
+func ValidateToken(tokenString, secret string) (*Claims, error) {
+	return ParseToken(tokenString, secret)
 }
@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func TestParseToken(t *testing.T) {
+	valid, err := GenerateToken(7, "a@example.com", "editor", 3, 42, testSecret, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := GenerateToken(7, "a@example.com", "editor", 3, 42, testSecret, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An unsigned token must not be accepted whatever its claims
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, Claims{UserID: 7}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		secret  string
+		wantErr error
+	}{
+		{"valid", valid, testSecret, nil},
+		{"expired", expired, testSecret, ErrTokenExpired},
+		{"wrong secret", valid, "other-secret", ErrTokenInvalid},
+		{"tampered", valid + "x", testSecret, ErrTokenInvalid},
+		{"malformed", "not-a-token", testSecret, ErrTokenInvalid},
+		{"empty", "", testSecret, ErrTokenInvalid},
+		{"unsigned", unsigned, testSecret, ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseToken(tt.token, tt.secret)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if claims != nil {
+					t.Errorf("expected no claims on error, got %+v", claims)
+				}
+				return
+			}
+
+			if claims.UserID != 7 || claims.Email != "a@example.com" || claims.Role != "editor" ||
+				claims.TokenVersion != 3 || claims.OrgID != 42 {
+				t.Errorf("unexpected claims %+v", claims)
+			}
+			if claims.ID == "" {
+				t.Error("expected a token ID")
+			}
+		})
+	}
+}
+
+func TestGenerateTokenIDsAreUnique(t *testing.T) {
+	first, err := GenerateToken(7, "a@example.com", "user", 0, 0, testSecret, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GenerateToken(7, "a@example.com", "user", 0, 0, testSecret, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := ParseToken(first, testSecret)
+	b, _ := ParseToken(second, testSecret)
+	if a.ID == b.ID {
+		t.Errorf("expected distinct token IDs, both are %q", a.ID)
+	}
+}
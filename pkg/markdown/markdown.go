@@ -0,0 +1,25 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	renderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	// policy allows the formatting produced by markdown but strips scripts, event
+	// handlers and unsafe URLs, including any raw HTML embedded in the source
+	policy = bluemonday.UGCPolicy()
+)
+
+// ToHTML renders markdown to sanitized HTML
+func ToHTML(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,41 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToHTMLSanitizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    string
+		notWant []string
+	}{
+		{"script tag", "Hello\n\n<script>alert(1)</script>", "<p>Hello</p>", []string{"<script", "alert(1)"}},
+		{"inline script", "Hi <script>alert(1)</script> there", "Hi", []string{"<script"}},
+		{"event handler", `<img src="a.png" onerror="alert(1)">`, "", []string{"onerror", "alert(1)"}},
+		{"event handler inline", `Look <a href="/x" onclick="steal()">here</a>`, "here", []string{"onclick", "steal()"}},
+		{"javascript link", "[click](javascript:alert(1))", "click", []string{"javascript:"}},
+		{"javascript link in HTML", `<a href="javascript:alert(1)">click</a>`, "", []string{"javascript:"}},
+		{"javascript image", "![x](javascript:alert(1))", "", []string{"javascript:"}},
+		{"formatting kept", "**bold** and [link](https://example.com)", `<strong>bold</strong> and <a href="https://example.com" rel="nofollow">link</a>`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := ToHTML(tt.source)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(html, tt.want) {
+				t.Errorf("expected %q in %q", tt.want, html)
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(html, notWant) {
+					t.Errorf("expected %q stripped from %q", notWant, html)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package moderation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Actions taken when text contains a blocked word
+const (
+	ActionReject = "reject"
+	ActionMask   = "mask"
+	ActionFlag   = "flag"
+)
+
+// IsValidAction reports whether action is reject, mask or flag
+func IsValidAction(action string) bool {
+	switch action {
+	case ActionReject, ActionMask, ActionFlag:
+		return true
+	}
+	return false
+}
+
+// Filter finds blocked words in text. Only whole words match, case-insensitively, so a
+// blocked word inside a longer word (the Scunthorpe problem) is not reported.
+type Filter struct {
+	words map[string]bool
+}
+
+// NewFilter creates a filter for the given words; blank entries are ignored
+func NewFilter(words []string) *Filter {
+	f := &Filter{words: make(map[string]bool, len(words))}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			f.words[word] = true
+		}
+	}
+	return f
+}
+
+// Find returns the distinct blocked words in text, lowercased, in order of appearance
+func (f *Filter) Find(text string) []string {
+	var found []string
+	seen := make(map[string]bool)
+	f.scan(text, func(start, end int) {
+		word := strings.ToLower(text[start:end])
+		if !seen[word] {
+			seen[word] = true
+			found = append(found, word)
+		}
+	})
+	return found
+}
+
+// Mask replaces every letter of each blocked word in text with an asterisk
+func (f *Filter) Mask(text string) string {
+	var b strings.Builder
+	last := 0
+	f.scan(text, func(start, end int) {
+		b.WriteString(text[last:start])
+		b.WriteString(strings.Repeat("*", len([]rune(text[start:end]))))
+		last = end
+	})
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// scan calls match with the byte range of every blocked word in text. Words are runs of
+// letters, digits and combining marks, so accented words stay whole.
+func (f *Filter) scan(text string, match func(start, end int)) {
+	if len(f.words) == 0 {
+		return
+	}
+
+	start := -1
+	for i, r := range text + " " {
+		inWord := unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r)
+		if inWord && start < 0 {
+			start = i
+		} else if !inWord && start >= 0 {
+			if f.words[strings.ToLower(text[start:i])] {
+				match(start, i)
+			}
+			start = -1
+		}
+	}
+}
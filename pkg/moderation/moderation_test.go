@@ -0,0 +1,50 @@
+package moderation
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	filter := NewFilter([]string{"darn", " Heck ", "", "ngốc"})
+
+	tests := []struct {
+		name      string
+		text      string
+		wantFound []string
+		wantMask  string
+	}{
+		{"clean", "a pleasant post", nil, "a pleasant post"},
+		{"whole word", "well darn it", []string{"darn"}, "well **** it"},
+		{"case insensitive", "DARN and Heck", []string{"darn", "heck"}, "**** and ****"},
+		{"repeated once in found", "darn, darn!", []string{"darn"}, "****, ****!"},
+		{"inside a longer word", "darned heckle", nil, "darned heckle"},
+		{"punctuation boundaries", "(darn)heck.", []string{"darn", "heck"}, "(****)****."},
+		{"accented word kept whole", "ngốc ngốcx", []string{"ngốc"}, "**** ngốcx"},
+		{"digits part of the word", "darn2", nil, "darn2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Find(tt.text); !slices.Equal(got, tt.wantFound) {
+				t.Errorf("Find(%q) = %v, want %v", tt.text, got, tt.wantFound)
+			}
+			if got := filter.Mask(tt.text); got != tt.wantMask {
+				t.Errorf("Mask(%q) = %q, want %q", tt.text, got, tt.wantMask)
+			}
+		})
+	}
+}
+
+func TestIsValidAction(t *testing.T) {
+	for _, action := range []string{ActionReject, ActionMask, ActionFlag} {
+		if !IsValidAction(action) {
+			t.Errorf("expected %q to be valid", action)
+		}
+	}
+	for _, action := range []string{"", "block", "Reject"} {
+		if IsValidAction(action) {
+			t.Errorf("expected %q to be invalid", action)
+		}
+	}
+}
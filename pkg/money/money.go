@@ -0,0 +1,112 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Currency describes how amounts in an ISO 4217 currency are stored and displayed
+type Currency struct {
+	Code string
+	// Digits is the number of minor units, e.g. 2 for cents, 0 for currencies without them
+	Digits      int
+	Symbol      string
+	SymbolAfter bool
+}
+
+var currencies = map[string]Currency{
+	"VND": {Code: "VND", Digits: 0, Symbol: "₫", SymbolAfter: true},
+	"USD": {Code: "USD", Digits: 2, Symbol: "$"},
+	"EUR": {Code: "EUR", Digits: 2, Symbol: "€"},
+	"GBP": {Code: "GBP", Digits: 2, Symbol: "£"},
+	"JPY": {Code: "JPY", Digits: 0, Symbol: "¥"},
+	"KRW": {Code: "KRW", Digits: 0, Symbol: "₩"},
+	"SGD": {Code: "SGD", Digits: 2, Symbol: "S$"},
+}
+
+// Lookup returns a supported currency by its ISO 4217 code, case-insensitively
+func Lookup(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(code)]
+	return c, ok
+}
+
+// IsSupported reports whether code is a supported currency
+func IsSupported(code string) bool {
+	_, ok := Lookup(code)
+	return ok
+}
+
+// ToMinor converts an amount in major units to minor units, rounding half away from zero.
+// Rounding works on the shortest decimal form of amount, so 1.005 USD is 101 cents even
+// though the float is slightly below 1.005.
+func ToMinor(amount float64, code string) (int64, error) {
+	c, ok := Lookup(code)
+	if !ok {
+		return 0, fmt.Errorf("unsupported currency %q", code)
+	}
+
+	whole, frac, _ := strings.Cut(strconv.FormatFloat(math.Abs(amount), 'f', -1, 64), ".")
+	frac += strings.Repeat("0", c.Digits+1)
+	minor, err := strconv.ParseInt(whole+frac[:c.Digits], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("amount %v is out of range", amount)
+	}
+	if frac[c.Digits] >= '5' {
+		minor++
+	}
+	if amount < 0 {
+		minor = -minor
+	}
+	return minor, nil
+}
+
+// Format renders an amount in minor units for display, e.g. "$1,234.50" or "150,000 ₫".
+// Unsupported currencies are shown as the raw amount followed by the code.
+func Format(minor int64, code string) string {
+	c, ok := Lookup(code)
+	if !ok {
+		return fmt.Sprintf("%d %s", minor, code)
+	}
+
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	unit := int64(1)
+	for i := 0; i < c.Digits; i++ {
+		unit *= 10
+	}
+
+	amount := groupThousands(strconv.FormatInt(minor/unit, 10))
+	if c.Digits > 0 {
+		amount += fmt.Sprintf(".%0*d", c.Digits, minor%unit)
+	}
+
+	if c.SymbolAfter {
+		return sign + amount + " " + c.Symbol
+	}
+	return sign + c.Symbol + amount
+}
+
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package money
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToMinor(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  float64
+		code    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "whole dollars", amount: 12, code: "USD", want: 1200},
+		{name: "cents", amount: 19.99, code: "USD", want: 1999},
+		{name: "half cent rounds up", amount: 0.125, code: "USD", want: 13},
+		{name: "float just below the half", amount: 1.005, code: "USD", want: 101},
+		{name: "below the half rounds down", amount: 0.124, code: "EUR", want: 12},
+		{name: "negative half rounds away from zero", amount: -0.125, code: "USD", want: -13},
+		{name: "no minor units", amount: 150000, code: "VND", want: 150000},
+		{name: "no minor units rounds", amount: 1500.5, code: "JPY", want: 1501},
+		{name: "lowercase code", amount: 1.5, code: "gbp", want: 150},
+		{name: "unsupported currency", amount: 1, code: "XYZ", wantErr: true},
+		{name: "out of range", amount: 1e20, code: "USD", wantErr: true},
+		{name: "not a number", amount: math.NaN(), code: "USD", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToMinor(tt.amount, tt.code)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %d", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ToMinor(%v, %q) = %d, want %d", tt.amount, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		minor int64
+		code  string
+		want  string
+	}{
+		{name: "dollars and cents", minor: 123450, code: "USD", want: "$1,234.50"},
+		{name: "cents only", minor: 5, code: "USD", want: "$0.05"},
+		{name: "euro", minor: 99, code: "EUR", want: "€0.99"},
+		{name: "symbol after", minor: 150000, code: "VND", want: "150,000 ₫"},
+		{name: "no grouping under a thousand", minor: 999, code: "JPY", want: "¥999"},
+		{name: "millions", minor: 1234567, code: "KRW", want: "₩1,234,567"},
+		{name: "multi-character symbol", minor: 1000000, code: "SGD", want: "S$10,000.00"},
+		{name: "negative", minor: -2550, code: "USD", want: "-$25.50"},
+		{name: "negative symbol after", minor: -1000, code: "VND", want: "-1,000 ₫"},
+		{name: "zero", minor: 0, code: "USD", want: "$0.00"},
+		{name: "lowercase code", minor: 100, code: "usd", want: "$1.00"},
+		{name: "unsupported currency", minor: 1234, code: "XYZ", want: "1234 XYZ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.minor, tt.code); got != tt.want {
+				t.Errorf("Format(%d, %q) = %q, want %q", tt.minor, tt.code, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package slug
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	lowerInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+	mixedInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+)
+
+// Options controls the style of generated slugs
+type Options struct {
+	Separator string
+	Lowercase bool
+	MaxLength int
+}
+
+// IsValidSeparator reports whether sep is a single URL-safe separator character
+func IsValidSeparator(sep string) bool {
+	switch sep {
+	case "-", "_", ".", "~":
+		return true
+	default:
+		return false
+	}
+}
+
+// Generate creates a URL-friendly slug from the given text
+func Generate(text string, opts Options) string {
+	separator := opts.Separator
+	if !IsValidSeparator(separator) {
+		separator = "-"
+	}
+
+	// Replace spaces and special characters with the separator
+	slug := text
+	if opts.Lowercase {
+		slug = strings.ToLower(slug)
+		slug = lowerInvalidChars.ReplaceAllString(slug, separator)
+	} else {
+		slug = mixedInvalidChars.ReplaceAllString(slug, separator)
+	}
+
+	// Remove leading and trailing separators
+	slug = strings.Trim(slug, separator)
+
+	// Limit length
+	if opts.MaxLength > 0 && len(slug) > opts.MaxLength {
+		slug = strings.TrimRight(slug[:opts.MaxLength], separator)
+	}
+
+	return slug
+}
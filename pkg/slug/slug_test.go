@@ -0,0 +1,45 @@
+package slug
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		opts Options
+		want string
+	}{
+		{"default style", "Hello, World!", Options{Separator: "-", Lowercase: true}, "hello-world"},
+		{"underscore separator", "Hello, World!", Options{Separator: "_", Lowercase: true}, "hello_world"},
+		{"casing kept", "Hello, World!", Options{Separator: "-"}, "Hello-World"},
+		{"invalid separator falls back to a hyphen", "Hello World", Options{Separator: "/", Lowercase: true}, "hello-world"},
+		{"empty separator falls back to a hyphen", "Hello World", Options{Lowercase: true}, "hello-world"},
+		{"runs of special characters collapsed", "Go  --  is __ fun", Options{Separator: "-", Lowercase: true}, "go-is-fun"},
+		{"leading and trailing separators trimmed", "  ...Hello...  ", Options{Separator: ".", Lowercase: true}, "hello"},
+		{"truncated", "hello wonderful world", Options{Separator: "-", Lowercase: true, MaxLength: 10}, "hello-wond"},
+		{"truncation does not end on a separator", "hello wonderful world", Options{Separator: "-", Lowercase: true, MaxLength: 6}, "hello"},
+		{"no limit", "hello wonderful world", Options{Separator: "-", Lowercase: true}, "hello-wonderful-world"},
+		{"nothing left", "!!!", Options{Separator: "-", Lowercase: true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Generate(tt.text, tt.opts); got != tt.want {
+				t.Errorf("Generate(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidSeparator(t *testing.T) {
+	for _, sep := range []string{"-", "_", ".", "~"} {
+		if !IsValidSeparator(sep) {
+			t.Errorf("expected %q to be valid", sep)
+		}
+	}
+	for _, sep := range []string{"", "/", " ", "--", "?"} {
+		if IsValidSeparator(sep) {
+			t.Errorf("expected %q to be invalid", sep)
+		}
+	}
+}
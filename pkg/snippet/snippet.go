@@ -0,0 +1,103 @@
+package snippet
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// Generate returns an HTML-escaped excerpt of content around the best match of term,
+// with every occurrence wrapped in <mark>. radius is the number of characters kept on
+// each side of the match. An empty string is returned when term does not occur.
+func Generate(content, term string, radius int) string {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return ""
+	}
+
+	// Strip markup so tags never end up in the excerpt
+	text := []rune(strings.Join(strings.Fields(tagPattern.ReplaceAllString(content, " ")), " "))
+	needle := toLower([]rune(term))
+
+	matches := findMatches(toLower(text), needle)
+	if len(matches) == 0 {
+		return ""
+	}
+
+	// Pick the window that contains the most matches
+	bestStart, bestEnd, bestCount := 0, 0, -1
+	for _, m := range matches {
+		start := max(m-radius, 0)
+		end := min(m+len(needle)+radius, len(text))
+
+		count := 0
+		for _, other := range matches {
+			if other >= start && other+len(needle) <= end {
+				count++
+			}
+		}
+
+		if count > bestCount {
+			bestStart, bestEnd, bestCount = start, end, count
+		}
+	}
+
+	var b strings.Builder
+	if bestStart > 0 {
+		b.WriteString("…")
+	}
+
+	pos := bestStart
+	for _, m := range matches {
+		if m < bestStart || m+len(needle) > bestEnd {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(text[pos:m])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(text[m : m+len(needle)])))
+		b.WriteString("</mark>")
+		pos = m + len(needle)
+	}
+	b.WriteString(html.EscapeString(string(text[pos:bestEnd])))
+
+	if bestEnd < len(text) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+// findMatches returns the non-overlapping rune offsets of needle in haystack
+func findMatches(haystack, needle []rune) []int {
+	var matches []int
+	for i := 0; i+len(needle) <= len(haystack); {
+		if equalRunes(haystack[i:i+len(needle)], needle) {
+			matches = append(matches, i)
+			i += len(needle)
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// toLower lowercases rune by rune so offsets stay aligned with the original text
+func toLower(runes []rune) []rune {
+	lowered := make([]rune, len(runes))
+	for i, r := range runes {
+		lowered[i] = unicode.ToLower(r)
+	}
+	return lowered
+}
@@ -0,0 +1,31 @@
+package snippet
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		term    string
+		radius  int
+		want    string
+	}{
+		{"match in the middle", "The quick brown fox jumps", "brown", 4, "…ick <mark>brown</mark> fox…"},
+		{"case-insensitive, original casing kept", "Go is fun. GO!", "go", 20, "<mark>Go</mark> is fun. <mark>GO</mark>!"},
+		{"window with the most matches", "cat and a long filler text here then cat cat", "cat", 4, "…hen <mark>cat</mark> <mark>cat</mark>"},
+		{"markup stripped", "<p>Hello <b>world</b></p>", "world", 10, "Hello <mark>world</mark>"},
+		{"text escaped", "a < b & world", "world", 10, "a &lt; b &amp; <mark>world</mark>"},
+		{"whitespace collapsed", "hello\n\n   world", "world", 10, "hello <mark>world</mark>"},
+		{"non-latin", "Xin chào thế giới", "thế", 5, "…chào <mark>thế</mark> giới"},
+		{"no match", "The quick brown fox", "cat", 10, ""},
+		{"blank term", "The quick brown fox", "  ", 10, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Generate(tt.content, tt.term, tt.radius); got != tt.want {
+				t.Errorf("Generate(%q, %q) = %q, want %q", tt.content, tt.term, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+// Command printversion prints the resolved build version and commit, for checking that
+// -ldflags reach pkg/version
+package main
+
+import (
+	"fmt"
+
+	"moon/pkg/version"
+)
+
+func main() {
+	fmt.Print(version.Resolve("fallback"), " ", version.Commit)
+}
@@ -0,0 +1,26 @@
+package version
+
+import "time"
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X moon/pkg/version.Version=v1.2.0 -X moon/pkg/version.Commit=abc123"
+var (
+	Version = ""
+	Commit  = "unknown"
+)
+
+var startedAt = time.Now()
+
+// Resolve returns the build version, or fallback when the binary was built without one
+func Resolve(fallback string) string {
+	if Version == "" {
+		return fallback
+	}
+	return Version
+}
+
+// Uptime returns how long the process has been running
+func Uptime() time.Duration {
+	return time.Since(startedAt)
+}
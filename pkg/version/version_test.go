@@ -0,0 +1,64 @@
+package version
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	saved := Version
+	t.Cleanup(func() { Version = saved })
+
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{"stamped", "v1.2.0", "v1.2.0"},
+		{"unstamped", "", "1.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Version = tt.version
+			if got := Resolve("1.0.0"); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// The variables are set the way the Makefile and Dockerfile stamp them
+func TestLdflagsInjection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a binary")
+	}
+
+	tests := []struct {
+		name    string
+		ldflags string
+		want    string
+	}{
+		{"stamped", "-X moon/pkg/version.Version=v1.2.0 -X moon/pkg/version.Commit=abc123", "v1.2.0 abc123"},
+		{"unstamped", "", "fallback unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := exec.Command("go", "run", "-ldflags", tt.ldflags, "./testdata/printversion").CombinedOutput()
+			if err != nil {
+				t.Fatalf("go run: %v: %s", err, out)
+			}
+			if string(out) != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestUptime(t *testing.T) {
+	first := Uptime()
+	if first < 0 || Uptime() < first {
+		t.Errorf("expected a growing uptime, got %v", first)
+	}
+}